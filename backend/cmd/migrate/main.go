@@ -4,11 +4,17 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	_ "github.com/lib/pq"
 	"leave-management-system/internal/logger"
 )
 
+// migrationsDir holds numbered <version>.up.sql / <version>.down.sql pairs
+const migrationsDir = "migrations"
+
 func main() {
 	// Get database connection from environment or use defaults
 	dbHost := getEnv("DB_HOST", "localhost")
@@ -137,25 +143,60 @@ func runMigrationsUp(host, port, user, password, dbName, sslMode string) {
 		os.Exit(1)
 	}
 
-	// Read migration file
-	migrationFile := "migrations/001_create_leave_requests.up.sql"
-	sql, err := os.ReadFile(migrationFile)
+	applied, err := appliedMigrations(db)
 	if err != nil {
-		log.Errorf("migration_read_failed file=%s error=%v", migrationFile, err)
+		log.Errorf("migration_state_failed error=%v", err)
 		os.Exit(1)
 	}
 
-	// Execute migration
-	log.Info("migration_start")
-	_, err = db.Exec(string(sql))
+	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.up.sql"))
 	if err != nil {
-		log.Errorf("migration_failed file=%s error=%v", migrationFile, err)
+		log.Errorf("migration_list_failed dir=%s error=%v", migrationsDir, err)
 		os.Exit(1)
 	}
+	sort.Strings(files)
+
+	log.Info("migration_start")
+	for _, migrationFile := range files {
+		version := strings.TrimSuffix(filepath.Base(migrationFile), ".up.sql")
+		if applied[version] {
+			continue
+		}
+
+		sql, err := os.ReadFile(migrationFile)
+		if err != nil {
+			log.Errorf("migration_read_failed file=%s error=%v", migrationFile, err)
+			os.Exit(1)
+		}
+
+		// Execute migration and record it atomically
+		tx, err := db.Begin()
+		if err != nil {
+			log.Errorf("migration_failed file=%s error=%v", migrationFile, err)
+			os.Exit(1)
+		}
+		if _, err := tx.Exec(string(sql)); err != nil {
+			tx.Rollback()
+			log.Errorf("migration_failed file=%s error=%v", migrationFile, err)
+			os.Exit(1)
+		}
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES ($1)", version); err != nil {
+			tx.Rollback()
+			log.Errorf("migration_failed file=%s error=%v", migrationFile, err)
+			os.Exit(1)
+		}
+		if err := tx.Commit(); err != nil {
+			log.Errorf("migration_failed file=%s error=%v", migrationFile, err)
+			os.Exit(1)
+		}
+
+		log.Infof("migration_applied version=%s", version)
+	}
 
 	log.Info("migration_complete")
 }
 
+// runMigrationsDown rolls back the most recently applied migration
 func runMigrationsDown(host, port, user, password, dbName, sslMode string) {
 	log := logger.New().With("operation", "migrate_down")
 
@@ -169,8 +210,24 @@ func runMigrationsDown(host, port, user, password, dbName, sslMode string) {
 	}
 	defer db.Close()
 
+	if _, err := appliedMigrations(db); err != nil {
+		log.Errorf("migration_state_failed error=%v", err)
+		os.Exit(1)
+	}
+
+	var version string
+	err = db.QueryRow("SELECT version FROM schema_migrations ORDER BY version DESC LIMIT 1").Scan(&version)
+	if err == sql.ErrNoRows {
+		log.Info("migration_rollback_skipped reason=nothing_applied")
+		return
+	}
+	if err != nil {
+		log.Errorf("migration_state_failed error=%v", err)
+		os.Exit(1)
+	}
+
 	// Read migration file
-	migrationFile := "migrations/001_create_leave_requests.down.sql"
+	migrationFile := filepath.Join(migrationsDir, version+".down.sql")
 	sql, err := os.ReadFile(migrationFile)
 	if err != nil {
 		log.Errorf("migration_read_failed file=%s error=%v", migrationFile, err)
@@ -178,13 +235,56 @@ func runMigrationsDown(host, port, user, password, dbName, sslMode string) {
 	}
 
 	// Execute migration
-	log.Info("migration_rollback_start")
-	_, err = db.Exec(string(sql))
+	log.Infof("migration_rollback_start version=%s", version)
+	tx, err := db.Begin()
 	if err != nil {
 		log.Errorf("migration_rollback_failed file=%s error=%v", migrationFile, err)
 		os.Exit(1)
 	}
+	if _, err := tx.Exec(string(sql)); err != nil {
+		tx.Rollback()
+		log.Errorf("migration_rollback_failed file=%s error=%v", migrationFile, err)
+		os.Exit(1)
+	}
+	if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = $1", version); err != nil {
+		tx.Rollback()
+		log.Errorf("migration_rollback_failed file=%s error=%v", migrationFile, err)
+		os.Exit(1)
+	}
+	if err := tx.Commit(); err != nil {
+		log.Errorf("migration_rollback_failed file=%s error=%v", migrationFile, err)
+		os.Exit(1)
+	}
 
 	log.Info("migration_rollback_complete")
 }
 
+// appliedMigrations ensures the schema_migrations table exists and returns the applied versions
+func appliedMigrations(db *sql.DB) (map[string]bool, error) {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version VARCHAR(255) PRIMARY KEY,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	rows, err := db.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
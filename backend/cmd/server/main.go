@@ -14,9 +14,15 @@ import (
 	"leave-management-system/internal/database"
 	"leave-management-system/internal/handlers"
 	authMiddleware "leave-management-system/internal/middleware"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/notify"
+	"leave-management-system/internal/openapi"
 	"leave-management-system/internal/repository"
 	"leave-management-system/internal/services"
+	"leave-management-system/internal/storage"
+	"leave-management-system/internal/templates"
 	"leave-management-system/internal/logger"
+	"leave-management-system/internal/utils"
 )
 
 func main() {
@@ -40,46 +46,152 @@ func main() {
 
 	log.Info("database_connected")
 
+	// Initialize attachment storage
+	store, err := storage.New(cfg)
+	if err != nil {
+		log.Errorf("startup_failed reason=storage_init error=%v", err)
+		os.Exit(1)
+	}
+
 	// Initialize repository
-	leaveRepo := repository.NewLeaveRepository(database.DB)
+	uow := repository.NewUnitOfWork(database.DB, cfg.Database.QueryTimeout)
+	attachmentRepo := repository.NewAttachmentRepository(database.DB, cfg.Database.QueryTimeout)
+	idempotencyRepo := repository.NewIdempotencyRepository(database.DB, cfg.Database.QueryTimeout)
+	outboxRepo := repository.NewOutboxRepository(database.DB, cfg.Database.QueryTimeout)
+	templateRepo := repository.NewTemplateRepository(database.DB, cfg.Database.QueryTimeout)
+	calendarFeedRepo := repository.NewCalendarFeedRepository(database.DB, cfg.Database.QueryTimeout)
+	notificationRepo := repository.NewNotificationRepository(database.DB, cfg.Database.QueryTimeout)
+
+	// Notifications go to the channels routed to each event, and a retry
+	// skips the channels and recipients already reached
+	notifier, err := notify.New(cfg, notificationRepo)
+	if err != nil {
+		log.Errorf("startup_failed reason=notifier_init error=%v", err)
+		os.Exit(1)
+	}
+	log.Infof("notifier_initialized routes=%s", notifier)
+
+	// Notifications are rendered in each recipient's locale, preferring admin overrides
+	renderer, err := templates.NewRenderer(templateRepo, cfg.Notification.DefaultLocale)
+	if err != nil {
+		log.Errorf("startup_failed reason=template_init error=%v", err)
+		os.Exit(1)
+	}
 
 	// Initialize services
-	leaveService := services.NewLeaveService(leaveRepo)
-	emailService := services.NewEmailService(cfg)
+	leavePolicy, err := services.NewLeavePolicy(cfg)
+	if err != nil {
+		log.Errorf("startup_failed reason=leave_policy error=%v", err)
+		os.Exit(1)
+	}
+	leaveService := services.NewLeaveService(uow, leavePolicy)
+	emailActionService := services.NewEmailActionService(leaveService, cfg.PublicURL, cfg.Email)
+	preferenceService, err := services.NewNotificationPreferenceService(uow.Preferences(), leavePolicy, cfg.Notification.DigestTime)
+	if err != nil {
+		log.Errorf("startup_failed reason=notification_preferences error=%v", err)
+		os.Exit(1)
+	}
+	// Every notification lands in its recipients' in-app inboxes; their
+	// preferences decide whether and where else they are notified
+	inboxNotifier := services.NewInboxNotifier(services.NewPreferenceNotifier(notifier, uow.Preferences()), notificationRepo)
+	notificationService := services.NewNotificationService(inboxNotifier, renderer, cfg.Email.ApproverAddress, cfg.Email.From, emailActionService)
+	digestService := services.NewDigestService(uow, preferenceService, leaveService, notificationService, cfg.Notification.DigestDays)
+	inboxService := services.NewInboxService(notificationRepo)
+	eventBroker := services.NewLeaveEventBroker()
+	// Leave changes committed by any server reach this one's subscribers
+	changeListener, err := repository.NewLeaveChangeListener(database.DSN(cfg))
+	if err != nil {
+		log.Errorf("startup_failed reason=leave_change_listener error=%v", err)
+		os.Exit(1)
+	}
+	defer changeListener.Close()
+	attachmentService := services.NewAttachmentService(attachmentRepo, leaveService, store, cfg.Attachment.MaxSizeBytes)
+	hrService := services.NewHRLeaveService(leaveService)
+	adminService := services.NewAdminService(leaveService)
+	commentService := services.NewCommentService(uow, leaveService, notificationService, cfg.Comment.EditWindow)
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo, cfg.Idempotency.TTL, cfg.Idempotency.Lease)
+	outboxService := services.NewOutboxService(outboxRepo)
+	templateService := services.NewTemplateService(templateRepo)
+	calendarService, err := services.NewCalendarService(calendarFeedRepo, leaveService, cfg.PublicURL, cfg.Calendar)
+	if err != nil {
+		log.Errorf("startup_failed reason=calendar_init error=%v", err)
+		os.Exit(1)
+	}
+
+	// Notifications are queued in the outbox with the change they announce
+	// and delivered in the background
+	dispatcher := services.NewOutboxDispatcher(outboxRepo, cfg.Outbox)
+	dispatcher.Handle(models.OutboxTopicLeaveDecided, notificationService.DeliverLeaveDecisions)
+	dispatcher.Handle(models.OutboxTopicLeaveActivity, notificationService.DeliverLeaveActivity)
+	dispatcher.Handle(models.OutboxTopicLeaveCalendar, notificationService.DeliverCalendarChanges)
+	dispatcher.Handle(models.OutboxTopicLeaveComment, notificationService.DeliverComments)
+	dispatcher.Handle(models.OutboxTopicDigest, digestService.DeliverDigests)
 
 	// Initialize handlers
 	leaveHandler := handlers.NewLeaveHandler(leaveService)
-	managerHandler := handlers.NewManagerHandler(leaveService, emailService)
+	managerHandler := handlers.NewManagerHandler(leaveService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	hrHandler := handlers.NewHRHandler(hrService)
+	adminHandler := handlers.NewAdminHandler(adminService, leaveService)
+	outboxHandler := handlers.NewOutboxHandler(outboxService)
+	templateHandler := handlers.NewTemplateHandler(templateService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	emailActionHandler := handlers.NewEmailActionHandler(emailActionService)
+	preferenceHandler := handlers.NewNotificationPreferenceHandler(preferenceService)
+	inboxHandler := handlers.NewInboxHandler(inboxService)
+	eventHandler := handlers.NewEventHandler(eventBroker)
+
+	// Load the API description, served to clients and checked against outside production
+	apiDoc, err := openapi.Load()
+	if err != nil {
+		log.Errorf("startup_failed reason=openapi_load error=%v", err)
+		os.Exit(1)
+	}
+	docsHandler, err := handlers.NewDocsHandler(apiDoc)
+	if err != nil {
+		log.Errorf("startup_failed reason=openapi_load error=%v", err)
+		os.Exit(1)
+	}
 
 	// Create Echo instance
 	e := echo.New()
+	e.Validator = utils.NewRequestValidator(leavePolicy.Location)
+	e.HTTPErrorHandler = authMiddleware.ErrorHandler(cfg.IsProduction())
 
 	// Middleware (order matters)
 	e.Use(authMiddleware.RequestLogger()) // Must be first to set up request ID
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORSWithConfig(authMiddleware.CORSConfig()))
+	if cfg.OpenAPI.Validate {
+		validator, err := authMiddleware.OpenAPIValidator(apiDoc, true)
+		if err != nil {
+			log.Errorf("startup_failed reason=openapi_validator error=%v", err)
+			os.Exit(1)
+		}
+		e.Use(validator)
+		log.Info("openapi_validation_enabled")
+	}
 
-	// Health check endpoint
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
-	})
-
-	// API routes
-	api := e.Group("/api/v1")
-
-	// Employee leave routes (require authentication)
-	leave := api.Group("/leave", authMiddleware.AuthMiddleware())
-	leave.POST("", leaveHandler.CreateLeaveRequest)
-	leave.GET("", leaveHandler.GetLeaveRequests)
-	leave.GET("/:id", leaveHandler.GetLeaveRequest)
-	leave.PUT("/:id", leaveHandler.UpdateLeaveRequest)
-	leave.DELETE("/:id", leaveHandler.CancelLeaveRequest)
+	registerRoutes(e, routeHandlers{
+		leave:       leaveHandler,
+		manager:     managerHandler,
+		attachment:  attachmentHandler,
+		comment:     commentHandler,
+		hr:          hrHandler,
+		admin:       adminHandler,
+		outbox:      outboxHandler,
+		template:    templateHandler,
+		calendar:    calendarHandler,
+		emailAction: emailActionHandler,
+		preference:  preferenceHandler,
+		inbox:       inboxHandler,
+		events:      eventHandler,
+		docs:        docsHandler,
+		idempotency: idempotencyService,
 
-	// Manager routes (require authentication and manager role)
-	manager := api.Group("/manager/leave", authMiddleware.AuthMiddleware(), authMiddleware.RequireRole("manager", "admin"))
-	manager.GET("", managerHandler.GetPendingLeaveRequests)
-	manager.PUT("/:id/approve", managerHandler.ApproveLeaveRequest)
-	manager.PUT("/:id/reject", managerHandler.RejectLeaveRequest)
+		maxUploadBytes: cfg.Attachment.MaxSizeBytes,
+	})
 
 	// Start server
 	port := fmt.Sprintf(":%s", cfg.Port)
@@ -92,6 +204,45 @@ func main() {
 
 	log.Infof("server_started port=%s env=%s", cfg.Port, cfg.Env)
 
+	// Deliver queued notifications until shutdown
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	dispatchDone := make(chan struct{})
+	go func() {
+		defer close(dispatchDone)
+		dispatcher.Run(dispatchCtx)
+	}()
+
+	// Prune idempotency keys that can no longer be replayed, and the
+	// delivery records of notifications the outbox can no longer retry,
+	// until shutdown
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-dispatchCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			if removed, err := idempotencyService.PruneExpired(dispatchCtx); err != nil {
+				log.Errorf("idempotency_prune_failed error=%v", err)
+			} else {
+				log.Infof("idempotency_prune_success removed=%d", removed)
+			}
+			if removed, err := inboxService.PruneDeliveries(dispatchCtx, dispatcher.RetryHorizon()); err != nil {
+				log.Errorf("notification_delivery_prune_failed error=%v", err)
+			} else {
+				log.Infof("notification_delivery_prune_success removed=%d", removed)
+			}
+		}
+	}()
+
+	// Queue daily digests as their time comes; the dispatcher delivers them
+	go digestService.Run(dispatchCtx)
+
+	// Stream committed leave changes to subscribers until shutdown
+	go changeListener.Run(dispatchCtx, eventBroker.Publish, eventBroker.Reset)
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
@@ -102,11 +253,22 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// End the event streams, which would otherwise keep the server waiting
+	eventBroker.Close()
 	if err := e.Shutdown(ctx); err != nil {
 		log.Errorf("server_shutdown_failed error=%v", err)
 		os.Exit(1)
 	}
 
+	// Let the dispatcher record the deliveries it has started; anything left
+	// is still in the outbox and is sent after the restart
+	stopDispatch()
+	select {
+	case <-dispatchDone:
+	case <-ctx.Done():
+		log.Warn("outbox_dispatcher_shutdown_timeout")
+	}
+
 	log.Info("server_shutdown_complete")
 }
 
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/handlers"
+	authMiddleware "leave-management-system/internal/middleware"
+	"leave-management-system/internal/services"
+)
+
+// routeHandlers holds what the routes are wired to
+type routeHandlers struct {
+	leave       *handlers.LeaveHandler
+	manager     *handlers.ManagerHandler
+	attachment  *handlers.AttachmentHandler
+	comment     *handlers.CommentHandler
+	hr          *handlers.HRHandler
+	admin       *handlers.AdminHandler
+	outbox      *handlers.OutboxHandler
+	template    *handlers.TemplateHandler
+	calendar    *handlers.CalendarHandler
+	emailAction *handlers.EmailActionHandler
+	preference  *handlers.NotificationPreferenceHandler
+	inbox       *handlers.InboxHandler
+	events      *handlers.EventHandler
+	docs        *handlers.DocsHandler
+	idempotency *services.IdempotencyService
+	// maxUploadBytes is the largest attachment accepted for upload
+	maxUploadBytes int64
+}
+
+// registerRoutes registers every route of the API on e. Each must be
+// described in internal/openapi/openapi.yaml.
+func registerRoutes(e *echo.Echo, h routeHandlers) {
+	// Health check endpoint
+	e.GET("/health", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	})
+
+	// Calendar feeds (public; the token in the path authorizes the request)
+	e.GET("/ical/:feed", h.calendar.GetFeed)
+
+	// Approve and reject links of approver emails (public; the signed token in
+	// the path authorizes the request)
+	e.GET("/email-actions/:token", h.emailAction.GetAction)
+	e.POST("/email-actions/:token", h.emailAction.PerformAction)
+
+	// API routes
+	api := e.Group("/api/v1")
+
+	// API description (public)
+	api.GET("/openapi.json", h.docs.GetSpec)
+	api.GET("/docs", h.docs.GetDocs)
+
+	// Employee leave routes (require authentication)
+	leave := api.Group("/leave", authMiddleware.AuthMiddleware())
+	leave.POST("", h.leave.CreateLeaveRequest, authMiddleware.Idempotency(h.idempotency))
+	leave.GET("", h.leave.GetLeaveRequests)
+	leave.GET("/:id", h.leave.GetLeaveRequest)
+	leave.PUT("/:id", h.leave.UpdateLeaveRequest)
+	leave.DELETE("/:id", h.leave.CancelLeaveRequest)
+	leave.POST("/:id/submit", h.leave.SubmitLeaveRequest)
+	leave.POST("/:id/attachments", h.attachment.UploadAttachment, handlers.UploadBodyLimit(h.maxUploadBytes))
+	leave.GET("/:id/attachments", h.attachment.GetAttachments)
+	leave.GET("/:id/attachments/:attachmentId", h.attachment.DownloadAttachment)
+	leave.DELETE("/:id/attachments/:attachmentId", h.attachment.DeleteAttachment)
+	leave.POST("/:id/comments", h.comment.AddComment)
+	leave.GET("/:id/comments", h.comment.GetComments)
+	leave.PUT("/:id/comments/:commentId", h.comment.UpdateComment)
+
+	// Real-time leave events (require authentication)
+	api.GET("/events", h.events.StreamEvents, authMiddleware.AuthMiddleware())
+
+	// Calendar feed subscriptions (require authentication)
+	calendar := api.Group("/calendar/feeds", authMiddleware.AuthMiddleware())
+	calendar.GET("", h.calendar.GetFeeds)
+	calendar.PUT("/:kind", h.calendar.UpdateFeed)
+	calendar.POST("/:kind/regenerate", h.calendar.RegenerateFeed)
+	calendar.DELETE("/:kind", h.calendar.DeleteFeed)
+
+	// In-app notifications and notification preferences (require authentication)
+	notifications := api.Group("/notifications", authMiddleware.AuthMiddleware())
+	notifications.GET("", h.inbox.GetNotifications)
+	notifications.GET("/unread-count", h.inbox.GetUnreadCount)
+	notifications.POST("/read-all", h.inbox.MarkAllRead)
+	notifications.POST("/:id/read", h.inbox.MarkRead)
+	notifications.GET("/preferences", h.preference.GetPreferences)
+	notifications.PUT("/preferences", h.preference.UpdatePreferences)
+
+	// Manager routes (require authentication and manager role)
+	manager := api.Group("/manager/leave", authMiddleware.AuthMiddleware(), authMiddleware.RequireRole("manager", "admin"))
+	manager.GET("", h.manager.GetPendingLeaveRequests)
+	manager.PUT("/:id/approve", h.manager.ApproveLeaveRequest)
+	manager.PUT("/:id/reject", h.manager.RejectLeaveRequest)
+	manager.POST("/bulk", h.manager.BulkReviewLeaveRequests)
+
+	// HR routes for acting on behalf of employees (require authentication and HR role)
+	hr := api.Group("/hr/leave", authMiddleware.AuthMiddleware(), authMiddleware.RequireRole("hr", "admin"))
+	hr.POST("", h.hr.CreateLeaveRequest)
+	hr.PUT("/:id", h.hr.UpdateLeaveRequest)
+	hr.DELETE("/:id", h.hr.CancelLeaveRequest)
+	hr.GET("/:id/history", h.hr.GetHistory)
+
+	// Admin routes (require authentication and admin role)
+	admin := api.Group("/admin", authMiddleware.AuthMiddleware(), authMiddleware.RequireRole("admin"))
+	admin.GET("/leave", h.admin.SearchLeaveRequests)
+	admin.GET("/leave/:id", h.admin.GetLeaveRequest)
+	admin.PUT("/leave/:id/status", h.admin.ForceStatus)
+	admin.PUT("/leave/:id/approver", h.admin.ReassignApprover)
+	admin.PUT("/leave/:id/days", h.admin.CorrectDays)
+	admin.GET("/leave/:id/history", h.admin.GetHistory)
+	admin.GET("/audit", h.admin.GetAuditTrail)
+	admin.GET("/outbox", h.outbox.GetMessages)
+	admin.POST("/outbox/:id/replay", h.outbox.ReplayMessage)
+	admin.GET("/templates", h.template.GetTemplates)
+	admin.GET("/templates/:locale/:name", h.template.GetTemplate)
+	admin.PUT("/templates/:locale/:name", h.template.UpdateTemplate)
+	admin.DELETE("/templates/:locale/:name", h.template.ResetTemplate)
+}
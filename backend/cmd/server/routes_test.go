@@ -0,0 +1,58 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/openapi"
+)
+
+// pathParam matches an OpenAPI path parameter such as {id}
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+func TestRoutesMatchOpenAPIDocument(t *testing.T) {
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("failed to load OpenAPI document: %v", err)
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			documented[method+" "+pathParam.ReplaceAllString(path, ":$1")] = true
+		}
+	}
+
+	e := echo.New()
+	registerRoutes(e, routeHandlers{})
+
+	registered := make(map[string]bool)
+	for _, route := range e.Routes() {
+		if route.Method == echo.RouteNotFound {
+			continue
+		}
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	for _, route := range sortedKeys(registered) {
+		if !documented[route] {
+			t.Errorf("%s is registered but missing from the OpenAPI document", route)
+		}
+	}
+	for _, route := range sortedKeys(documented) {
+		if !registered[route] {
+			t.Errorf("%s is documented but not registered", route)
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
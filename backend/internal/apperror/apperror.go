@@ -0,0 +1,154 @@
+// Package apperror defines the errors the API reports to clients. Each carries
+// a stable, machine-readable code that clients can branch on, independent of
+// the human-readable message.
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/utils"
+)
+
+// Code identifies the kind of error. Codes are part of the API contract and
+// must not change once published.
+type Code string
+
+const (
+	CodeBadRequest              Code = "BAD_REQUEST"
+	CodeInvalidID               Code = "INVALID_ID"
+	CodeInvalidBody             Code = "INVALID_BODY"
+	CodeInvalidQuery            Code = "INVALID_QUERY"
+	CodeValidationFailed        Code = "VALIDATION_FAILED"
+	CodeUnauthorized            Code = "UNAUTHORIZED"
+	CodeForbidden               Code = "FORBIDDEN"
+	CodeNotFound                Code = "NOT_FOUND"
+	CodeLeaveNotFound           Code = "LEAVE_NOT_FOUND"
+	CodeCommentNotFound         Code = "COMMENT_NOT_FOUND"
+	CodeAttachmentNotFound      Code = "ATTACHMENT_NOT_FOUND"
+	CodeOutboxMessageNotFound   Code = "OUTBOX_MESSAGE_NOT_FOUND"
+	CodeTemplateNotFound        Code = "TEMPLATE_NOT_FOUND"
+	CodeCalendarFeedNotFound    Code = "CALENDAR_FEED_NOT_FOUND"
+	CodeNotificationNotFound    Code = "NOTIFICATION_NOT_FOUND"
+	CodeMethodNotAllowed        Code = "METHOD_NOT_ALLOWED"
+	CodeInvalidStatusTransition Code = "INVALID_STATUS_TRANSITION"
+	CodeEditWindowExpired       Code = "EDIT_WINDOW_EXPIRED"
+	CodeMessageNotReplayable    Code = "MESSAGE_NOT_REPLAYABLE"
+	CodeConflict                Code = "CONFLICT"
+	CodePreconditionFailed      Code = "PRECONDITION_FAILED"
+	CodePreconditionRequired    Code = "PRECONDITION_REQUIRED"
+	CodePayloadTooLarge         Code = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMediaType    Code = "UNSUPPORTED_MEDIA_TYPE"
+	CodeInvalidIdempotencyKey   Code = "INVALID_IDEMPOTENCY_KEY"
+	CodeIdempotencyKeyReused    Code = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyKeyInUse     Code = "IDEMPOTENCY_KEY_IN_PROGRESS"
+	CodeTooManyRequests         Code = "TOO_MANY_REQUESTS"
+	CodeInternal                Code = "INTERNAL_ERROR"
+)
+
+// statusCodes gives the code for errors that are known only by their status,
+// such as echo.HTTPErrors raised by middleware or the router
+var statusCodes = map[int]Code{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusMethodNotAllowed:      CodeMethodNotAllowed,
+	http.StatusConflict:              CodeConflict,
+	http.StatusPreconditionFailed:    CodePreconditionFailed,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMediaType,
+	http.StatusUnprocessableEntity:   CodeValidationFailed,
+	http.StatusPreconditionRequired:  CodePreconditionRequired,
+	http.StatusTooManyRequests:       CodeTooManyRequests,
+}
+
+// Error is an error reported to the client. Message is shown to the client;
+// Err, the underlying cause, is only logged and never shown in production.
+type Error struct {
+	Status  int
+	Code    Code
+	Message string
+	// Fields lists the invalid fields of a request that failed validation
+	Fields []utils.FieldError
+	Err    error
+}
+
+// New creates an error with the given status, code and client-facing message
+func New(status int, code Code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// Internal wraps an unexpected error. Its cause is hidden from clients in
+// production.
+func Internal(err error) *Error {
+	return &Error{Status: http.StatusInternalServerError, Code: CodeInternal, Message: "Internal server error", Err: err}
+}
+
+// Unauthorized reports a request without an authenticated user
+func Unauthorized(err error) *Error {
+	return &Error{Status: http.StatusUnauthorized, Code: CodeUnauthorized, Message: "Authentication required", Err: err}
+}
+
+// InvalidID reports a malformed identifier in the path, e.g. "Invalid leave
+// request ID"
+func InvalidID(message string) *Error {
+	return New(http.StatusBadRequest, CodeInvalidID, message)
+}
+
+// InvalidBody reports a request body that could not be read or decoded
+func InvalidBody(err error) *Error {
+	return &Error{Status: http.StatusBadRequest, Code: CodeInvalidBody, Message: "Invalid request body", Err: err}
+}
+
+// InvalidQuery reports a malformed query parameter
+func InvalidQuery(message string) *Error {
+	return New(http.StatusBadRequest, CodeInvalidQuery, message)
+}
+
+// Validation reports the fields of a request that failed validation
+func Validation(fields utils.FieldErrors) *Error {
+	return &Error{Status: http.StatusBadRequest, Code: CodeValidationFailed, Message: "Validation failed", Fields: fields}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// From converts an error returned by a handler or middleware to an Error.
+// echo.HTTPErrors keep their status and message and get the code for their
+// status; any other error is internal.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		code, ok := statusCodes[httpErr.Code]
+		if !ok {
+			if httpErr.Code < http.StatusInternalServerError {
+				code = CodeBadRequest
+			} else {
+				code = CodeInternal
+			}
+		}
+		message := http.StatusText(httpErr.Code)
+		if httpErr.Message != nil {
+			message = fmt.Sprint(httpErr.Message)
+		}
+		return &Error{Status: httpErr.Code, Code: code, Message: message, Err: httpErr.Internal}
+	}
+
+	return Internal(err)
+}
@@ -0,0 +1,97 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestFrom(t *testing.T) {
+	notFound := New(http.StatusNotFound, CodeLeaveNotFound, "Leave request not found")
+
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    Code
+		wantMessage string
+	}{
+		{
+			name:        "application error",
+			err:         notFound,
+			wantStatus:  http.StatusNotFound,
+			wantCode:    CodeLeaveNotFound,
+			wantMessage: "Leave request not found",
+		},
+		{
+			name:        "wrapped application error",
+			err:         fmt.Errorf("get leave: %w", notFound),
+			wantStatus:  http.StatusNotFound,
+			wantCode:    CodeLeaveNotFound,
+			wantMessage: "Leave request not found",
+		},
+		{
+			name:        "invalid id",
+			err:         InvalidID("Invalid leave request ID"),
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    CodeInvalidID,
+			wantMessage: "Invalid leave request ID",
+		},
+		{
+			name:        "unauthorized",
+			err:         Unauthorized(errors.New("user not authenticated")),
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    CodeUnauthorized,
+			wantMessage: "Authentication required",
+		},
+		{
+			name:        "echo error",
+			err:         echo.NewHTTPError(http.StatusUnauthorized, "Invalid token"),
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    CodeUnauthorized,
+			wantMessage: "Invalid token",
+		},
+		{
+			name:        "echo error with an unmapped status",
+			err:         echo.NewHTTPError(http.StatusTeapot),
+			wantStatus:  http.StatusTeapot,
+			wantCode:    CodeBadRequest,
+			wantMessage: http.StatusText(http.StatusTeapot),
+		},
+		{
+			name:        "plain error",
+			err:         errors.New("pq: connection refused"),
+			wantStatus:  http.StatusInternalServerError,
+			wantCode:    CodeInternal,
+			wantMessage: "Internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := From(tt.err)
+			if got.Status != tt.wantStatus || got.Code != tt.wantCode || got.Message != tt.wantMessage {
+				t.Errorf("expected %d %s %q, got %d %s %q", tt.wantStatus, tt.wantCode, tt.wantMessage, got.Status, got.Code, got.Message)
+			}
+		})
+	}
+}
+
+func TestNewProblem_HidesInternalCause(t *testing.T) {
+	err := Internal(errors.New("failed to query leave requests: pq: relation does not exist"))
+
+	if problem := NewProblem(err, false); problem.Detail != "Internal server error" {
+		t.Errorf("expected the cause to be hidden, got %q", problem.Detail)
+	}
+	if problem := NewProblem(err, true); problem.Detail != err.Error() {
+		t.Errorf("expected the cause to be shown, got %q", problem.Detail)
+	}
+
+	problem := NewProblem(New(http.StatusConflict, CodeConflict, "leave request was changed by another request"), false)
+	if problem.Title != "Conflict" || problem.Status != http.StatusConflict || problem.Detail != "leave request was changed by another request" {
+		t.Errorf("unexpected problem: %+v", problem)
+	}
+}
@@ -0,0 +1,42 @@
+package apperror
+
+import (
+	"net/http"
+
+	"leave-management-system/internal/utils"
+)
+
+// MIMEProblemJSON is the media type of RFC 7807 problem details
+const MIMEProblemJSON = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object, extended with the error code,
+// the request ID and, for validation failures, the invalid fields
+type Problem struct {
+	Type      string             `json:"type"`
+	Title     string             `json:"title"`
+	Status    int                `json:"status"`
+	Detail    string             `json:"detail,omitempty"`
+	Instance  string             `json:"instance,omitempty"`
+	Code      Code               `json:"code"`
+	RequestID string             `json:"requestId,omitempty"`
+	Errors    []utils.FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem describes e as problem details. The cause of a server error is
+// included in the detail only when showInternal is set.
+func NewProblem(e *Error, showInternal bool) *Problem {
+	detail := e.Message
+	if showInternal && e.Status >= http.StatusInternalServerError && e.Err != nil {
+		detail = e.Error()
+	}
+	return &Problem{
+		// Problems are told apart by Code, so the type is the generic one
+		// whose title is the status text
+		Type:   "about:blank",
+		Title:  http.StatusText(e.Status),
+		Status: e.Status,
+		Detail: detail,
+		Code:   e.Code,
+		Errors: e.Fields,
+	}
+}
@@ -3,17 +3,30 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Port         string
-	Env          string
+	Port string
+	Env  string
+	// PublicURL is where clients reach the server, for links such as calendar feeds
+	PublicURL    string
 	Database     DatabaseConfig
 	JWT          JWTConfig
 	Email        EmailConfig
 	Keycloak     KeycloakConfig
+	Storage      StorageConfig
+	Attachment   AttachmentConfig
+	Leave        LeavePolicyConfig
+	Comment      CommentConfig
+	Idempotency  IdempotencyConfig
+	OpenAPI      OpenAPIConfig
+	Outbox       OutboxConfig
+	Notification NotificationConfig
+	Calendar     CalendarConfig
 }
 
 type DatabaseConfig struct {
@@ -23,6 +36,8 @@ type DatabaseConfig struct {
 	Password string
 	Name     string
 	SSLMode  string
+	// QueryTimeout bounds each repository call; zero disables the limit
+	QueryTimeout time.Duration
 }
 
 type JWTConfig struct {
@@ -36,6 +51,14 @@ type EmailConfig struct {
 	User     string
 	Password string
 	From     string
+	// ApproverAddress receives notifications meant for approvers when no
+	// individual approver is known yet (e.g. the first comment on a request)
+	ApproverAddress string
+	// ActionSecret signs the approve and reject links in approver emails;
+	// the links are left out while it is empty
+	ActionSecret string
+	// ActionTTL is how long those links work
+	ActionTTL time.Duration
 }
 
 type KeycloakConfig struct {
@@ -43,6 +66,84 @@ type KeycloakConfig struct {
 	ClientID string
 }
 
+type StorageConfig struct {
+	Driver    string // "local" or "s3"
+	LocalPath string
+	S3        S3Config
+}
+
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+type AttachmentConfig struct {
+	MaxSizeBytes int64
+}
+
+type CommentConfig struct {
+	EditWindow time.Duration // How long after posting a comment its author may edit it
+}
+
+type IdempotencyConfig struct {
+	TTL time.Duration // How long the response to an Idempotency-Key is replayed to retries
+	// Lease is how long a key stays claimed by a request that has not finished,
+	// after which it is taken to have been abandoned, e.g. by a crashed server
+	Lease time.Duration
+}
+
+type OpenAPIConfig struct {
+	// Validate checks requests and responses against the OpenAPI document;
+	// on by default outside production
+	Validate bool
+}
+
+type OutboxConfig struct {
+	PollInterval time.Duration // How often the dispatcher looks for due messages
+	BatchSize    int           // Messages claimed per poll
+	MaxAttempts  int           // Attempts before a message is dead-lettered
+	// Retries back off exponentially from BaseBackoff, capped at MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+type NotificationConfig struct {
+	// Routes picks the channels each event is delivered on, e.g.
+	// "leave.approved=email,slack;*=email"; see notify.ParseRoutes
+	Routes          string
+	SlackWebhookURL string
+	TeamsWebhookURL string
+	// WebhookURL receives every routed event as JSON, signed with
+	// WebhookSecret when one is set
+	WebhookURL    string
+	WebhookSecret string
+	// DefaultLocale is the language of messages to approvers and to
+	// employees whose token carries no supported locale
+	DefaultLocale string
+	// DigestTime is the local time of day, as "15:04", daily digests are
+	// sent at unless the recipient picked another
+	DigestTime string
+	// DigestDays is how many days ahead a digest lists team absences
+	DigestDays int
+}
+
+type CalendarConfig struct {
+	// Holidays are the company holidays as "2024-01-01=New Year's Day;...";
+	// see services.ParseHolidays
+	Holidays string
+	// HistoryDays is how far back leave feeds go
+	HistoryDays int
+}
+
+type LeavePolicyConfig struct {
+	DefaultTimezone    string // IANA zone used when the token carries no zoneinfo
+	AnnualNoticeDays   int
+	PersonalNoticeDays int
+}
+
 func Load() (*Config, error) {
 	// Load .env file if it exists (optional in production)
 	_ = godotenv.Load()
@@ -57,9 +158,17 @@ func Load() (*Config, error) {
 		smtpPort = 587
 	}
 
+	maxAttachmentMB, _ := strconv.Atoi(os.Getenv("ATTACHMENT_MAX_SIZE_MB"))
+	if maxAttachmentMB <= 0 {
+		maxAttachmentMB = 10
+	}
+
+	env := getEnv("ENV", "development")
+
 	return &Config{
-		Port: port,
-		Env:  getEnv("ENV", "development"),
+		Port:      port,
+		Env:       env,
+		PublicURL: strings.TrimSuffix(getEnv("PUBLIC_URL", "http://localhost:"+port), "/"),
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "5432"),
@@ -67,6 +176,8 @@ func Load() (*Config, error) {
 			Password: getEnv("DB_PASSWORD", "postgres"),
 			Name:     getEnv("DB_NAME", "leave_management"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			QueryTimeout: time.Duration(getEnvInt("DB_QUERY_TIMEOUT_SECONDS", 5)) * time.Second,
 		},
 		JWT: JWTConfig{
 			Secret:      getEnv("JWT_SECRET", ""),
@@ -78,14 +189,74 @@ func Load() (*Config, error) {
 			User:     getEnv("SMTP_USER", ""),
 			Password: getEnv("SMTP_PASSWORD", ""),
 			From:     getEnv("SMTP_FROM", "noreply@company.com"),
+
+			ApproverAddress: getEnv("APPROVER_NOTIFICATION_EMAIL", ""),
+			ActionSecret:    getEnv("EMAIL_ACTION_SECRET", ""),
+			ActionTTL:       time.Duration(getEnvInt("EMAIL_ACTION_TTL_HOURS", 72)) * time.Hour,
 		},
 		Keycloak: KeycloakConfig{
 			Issuer:   getEnv("KEYCLOAK_ISSUER", "http://localhost:8080/realms/next"),
 			ClientID: getEnv("KEYCLOAK_CLIENT_ID", "next"),
 		},
+		Storage: StorageConfig{
+			Driver:    getEnv("STORAGE_DRIVER", "local"),
+			LocalPath: getEnv("STORAGE_LOCAL_PATH", "./data/attachments"),
+			S3: S3Config{
+				Endpoint:        getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+				Region:          getEnv("S3_REGION", "us-east-1"),
+				Bucket:          getEnv("S3_BUCKET", ""),
+				AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+				SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+			},
+		},
+		Attachment: AttachmentConfig{
+			MaxSizeBytes: int64(maxAttachmentMB) * 1024 * 1024,
+		},
+		Leave: LeavePolicyConfig{
+			DefaultTimezone:    getEnv("OFFICE_TIMEZONE", "UTC"),
+			AnnualNoticeDays:   getEnvInt("ANNUAL_LEAVE_NOTICE_DAYS", 0),
+			PersonalNoticeDays: getEnvInt("PERSONAL_LEAVE_NOTICE_DAYS", 0),
+		},
+		Comment: CommentConfig{
+			EditWindow: time.Duration(getEnvInt("COMMENT_EDIT_WINDOW_MINUTES", 15)) * time.Minute,
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:   time.Duration(getEnvInt("IDEMPOTENCY_KEY_TTL_HOURS", 24)) * time.Hour,
+			Lease: time.Duration(getEnvInt("IDEMPOTENCY_KEY_LEASE_SECONDS", 120)) * time.Second,
+		},
+		OpenAPI: OpenAPIConfig{
+			Validate: getEnvBool("OPENAPI_VALIDATION", env != "production"),
+		},
+		Outbox: OutboxConfig{
+			PollInterval: time.Duration(getEnvInt("OUTBOX_POLL_INTERVAL_SECONDS", 5)) * time.Second,
+			BatchSize:    getEnvInt("OUTBOX_BATCH_SIZE", 20),
+			MaxAttempts:  getEnvInt("OUTBOX_MAX_ATTEMPTS", 8),
+			BaseBackoff:  time.Duration(getEnvInt("OUTBOX_BACKOFF_SECONDS", 30)) * time.Second,
+			MaxBackoff:   time.Duration(getEnvInt("OUTBOX_MAX_BACKOFF_MINUTES", 60)) * time.Minute,
+		},
+		Notification: NotificationConfig{
+			Routes:          getEnv("NOTIFY_ROUTES", "*=email"),
+			SlackWebhookURL: getEnv("SLACK_WEBHOOK_URL", ""),
+			TeamsWebhookURL: getEnv("TEAMS_WEBHOOK_URL", ""),
+			WebhookURL:      getEnv("NOTIFY_WEBHOOK_URL", ""),
+			WebhookSecret:   getEnv("NOTIFY_WEBHOOK_SECRET", ""),
+			DefaultLocale:   getEnv("NOTIFY_DEFAULT_LOCALE", "en"),
+			DigestTime:      getEnv("NOTIFY_DIGEST_TIME", "08:00"),
+			DigestDays:      getEnvInt("NOTIFY_DIGEST_DAYS", 7),
+		},
+		Calendar: CalendarConfig{
+			Holidays:    getEnv("COMPANY_HOLIDAYS", ""),
+			HistoryDays: getEnvInt("CALENDAR_FEED_HISTORY_DAYS", 365),
+		},
 	}, nil
 }
 
+// IsProduction reports whether the server runs in production, where internal
+// error details are hidden from clients
+func (c *Config) IsProduction() bool {
+	return c.Env == "production"
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -93,3 +264,16 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
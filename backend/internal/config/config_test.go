@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLoad(t *testing.T) {
@@ -10,9 +11,11 @@ func TestLoad(t *testing.T) {
 	originalEnv := make(map[string]string)
 	envVars := []string{
 		"PORT", "ENV", "DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD",
-		"DB_NAME", "DB_SSLMODE", "JWT_SECRET", "NEXTAUTH_URL",
+		"DB_NAME", "DB_SSLMODE", "DB_QUERY_TIMEOUT_SECONDS", "JWT_SECRET", "NEXTAUTH_URL",
 		"SMTP_HOST", "SMTP_PORT", "SMTP_USER", "SMTP_PASSWORD", "SMTP_FROM",
-		"KEYCLOAK_ISSUER", "KEYCLOAK_CLIENT_ID",
+		"KEYCLOAK_ISSUER", "KEYCLOAK_CLIENT_ID", "OPENAPI_VALIDATION",
+		"OUTBOX_MAX_ATTEMPTS", "OUTBOX_BACKOFF_SECONDS", "PUBLIC_URL", "EMAIL_ACTION_SECRET",
+		"NOTIFY_DIGEST_TIME", "NOTIFY_DIGEST_DAYS",
 	}
 
 	for _, key := range envVars {
@@ -49,12 +52,67 @@ func TestLoad(t *testing.T) {
 		if cfg.Database.Host != "localhost" {
 			t.Errorf("expected default DB host localhost, got %s", cfg.Database.Host)
 		}
+
+		if cfg.Database.QueryTimeout != 5*time.Second {
+			t.Errorf("expected default query timeout 5s, got %s", cfg.Database.QueryTimeout)
+		}
+
+		if cfg.Idempotency.TTL != 24*time.Hour || cfg.Idempotency.Lease != 2*time.Minute {
+			t.Errorf("expected default idempotency key TTL 24h and lease 2m, got %+v", cfg.Idempotency)
+		}
+
+		if cfg.Outbox.MaxAttempts != 8 || cfg.Outbox.BaseBackoff != 30*time.Second || cfg.Outbox.MaxBackoff != time.Hour {
+			t.Errorf("unexpected default outbox retry policy: %+v", cfg.Outbox)
+		}
+
+		if cfg.Notification.Routes != "*=email" {
+			t.Errorf("expected every event routed to email by default, got %q", cfg.Notification.Routes)
+		}
+		if cfg.Notification.DefaultLocale != "en" {
+			t.Errorf("expected English notifications by default, got %q", cfg.Notification.DefaultLocale)
+		}
+
+		if cfg.Notification.DigestTime != "08:00" || cfg.Notification.DigestDays != 7 {
+			t.Errorf("expected digests at 08:00 covering 7 days by default, got %q and %d", cfg.Notification.DigestTime, cfg.Notification.DigestDays)
+		}
+
+		if !cfg.OpenAPI.Validate {
+			t.Error("expected OpenAPI validation on outside production")
+		}
+
+		if cfg.Email.ActionSecret != "" || cfg.Email.ActionTTL != 72*time.Hour {
+			t.Errorf("expected email actions off with a 72h TTL by default, got %+v", cfg.Email)
+		}
+
+		if cfg.PublicURL != "http://localhost:8081" {
+			t.Errorf("expected the local server as public URL, got %q", cfg.PublicURL)
+		}
+	})
+
+	t.Run("OpenAPI validation is off in production unless enabled", func(t *testing.T) {
+		os.Setenv("ENV", "production")
+		defer os.Unsetenv("ENV")
+
+		cfg, _ := Load()
+		if cfg.OpenAPI.Validate {
+			t.Error("expected OpenAPI validation off in production")
+		}
+
+		os.Setenv("OPENAPI_VALIDATION", "true")
+		defer os.Unsetenv("OPENAPI_VALIDATION")
+
+		cfg, _ = Load()
+		if !cfg.OpenAPI.Validate {
+			t.Error("expected OPENAPI_VALIDATION=true to enable validation")
+		}
 	})
 
 	t.Run("load with custom values", func(t *testing.T) {
 		os.Setenv("PORT", "9000")
 		os.Setenv("DB_HOST", "custom-host")
 		os.Setenv("SMTP_PORT", "465")
+		os.Setenv("DB_QUERY_TIMEOUT_SECONDS", "0")
+		os.Setenv("PUBLIC_URL", "https://leave.example.com/")
 
 		cfg, err := Load()
 		if err != nil {
@@ -74,10 +132,20 @@ func TestLoad(t *testing.T) {
 			t.Errorf("expected SMTP port 465, got %d", cfg.Email.Port)
 		}
 
+		if cfg.Database.QueryTimeout != 0 {
+			t.Errorf("expected query timeout disabled, got %s", cfg.Database.QueryTimeout)
+		}
+
+		if cfg.PublicURL != "https://leave.example.com" {
+			t.Errorf("expected the public URL without a trailing slash, got %q", cfg.PublicURL)
+		}
+
 		// Cleanup
 		os.Unsetenv("PORT")
 		os.Unsetenv("DB_HOST")
 		os.Unsetenv("SMTP_PORT")
+		os.Unsetenv("DB_QUERY_TIMEOUT_SECONDS")
+		os.Unsetenv("PUBLIC_URL")
 	})
 }
 
@@ -96,3 +164,11 @@ func TestGetEnv(t *testing.T) {
 	}
 }
 
+
+func TestConfig_IsProduction(t *testing.T) {
+	for env, want := range map[string]bool{"production": true, "development": false, "staging": false} {
+		if got := (&Config{Env: env}).IsProduction(); got != want {
+			t.Errorf("IsProduction() with ENV=%s = %t, want %t", env, got, want)
+		}
+	}
+}
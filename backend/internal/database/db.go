@@ -11,9 +11,9 @@ import (
 // DB is the database connection pool
 var DB *sql.DB
 
-// Connect initializes the database connection
-func Connect(cfg *config.Config) error {
-	dsn := fmt.Sprintf(
+// DSN returns the connection string of the configured database
+func DSN(cfg *config.Config) string {
+	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Database.Host,
 		cfg.Database.Port,
@@ -22,9 +22,12 @@ func Connect(cfg *config.Config) error {
 		cfg.Database.Name,
 		cfg.Database.SSLMode,
 	)
+}
 
+// Connect initializes the database connection
+func Connect(cfg *config.Config) error {
 	var err error
-	DB, err = sql.Open("postgres", dsn)
+	DB, err = sql.Open("postgres", DSN(cfg))
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
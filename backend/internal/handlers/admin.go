@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/middleware"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/services"
+	"leave-management-system/internal/utils"
+)
+
+// AdminHandler handles administrative endpoints
+type AdminHandler struct {
+	adminService *services.AdminService
+	leaveService *services.LeaveService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(adminService *services.AdminService, leaveService *services.LeaveService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+		leaveService: leaveService,
+	}
+}
+
+// SearchLeaveRequests handles GET /api/v1/admin/leave
+func (h *AdminHandler) SearchLeaveRequests(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	filter, err := parseLeaveFilter(c)
+	if err != nil {
+		log.Warnf("admin_search_failed reason=invalid_query error=%v", err)
+		return apperror.InvalidQuery(err.Error())
+	}
+
+	page, err := h.adminService.SearchLeaveRequests(c.Request().Context(), filter)
+	if err != nil {
+		return adminError(c, "admin_search_failed", uuid.Nil, err)
+	}
+
+	log.Infof("admin_search_success count=%d total=%d", len(page.Items), page.Total)
+	return writeLeavePage(c, page)
+}
+
+// GetLeaveRequest handles GET /api/v1/admin/leave/:id
+func (h *AdminHandler) GetLeaveRequest(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("admin_get_leave_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	leave, err := h.leaveService.GetLeaveRequestByID(c.Request().Context(), id)
+	if err != nil {
+		return adminError(c, "admin_get_leave_failed", id, err)
+	}
+
+	log.Infof("admin_get_leave_success leave_id=%s", id)
+	return writeLeave(c, http.StatusOK, leave)
+}
+
+// ForceStatus handles PUT /api/v1/admin/leave/:id/status
+func (h *AdminHandler) ForceStatus(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	actor, id, err := adminTarget(c, "admin_force_status_failed")
+	if err != nil {
+		return err
+	}
+
+	var req models.ForceStatusRequest
+	if err := c.Bind(&req); err != nil {
+		log.Warnf("admin_force_status_failed reason=invalid_request leave_id=%s error=%v", id, err)
+		return apperror.InvalidBody(err)
+	}
+
+	if err := validateRequest(c, "admin_force_status_failed", &req); err != nil {
+		return err
+	}
+
+	leave, err := h.adminService.ForceStatus(c.Request().Context(), id, req.Status, req.Reason, actor)
+	if err != nil {
+		return adminError(c, "admin_force_status_failed", id, err)
+	}
+
+	log.Infof("admin_force_status_success leave_id=%s status=%s actor_id=%s", id, leave.Status, actor.ID)
+	return writeLeave(c, http.StatusOK, leave)
+}
+
+// ReassignApprover handles PUT /api/v1/admin/leave/:id/approver
+func (h *AdminHandler) ReassignApprover(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	actor, id, err := adminTarget(c, "admin_reassign_approver_failed")
+	if err != nil {
+		return err
+	}
+
+	var req models.ReassignApproverRequest
+	if err := c.Bind(&req); err != nil {
+		log.Warnf("admin_reassign_approver_failed reason=invalid_request leave_id=%s error=%v", id, err)
+		return apperror.InvalidBody(err)
+	}
+
+	if err := validateRequest(c, "admin_reassign_approver_failed", &req); err != nil {
+		return err
+	}
+
+	leave, err := h.adminService.ReassignApprover(c.Request().Context(), id, &req, actor)
+	if err != nil {
+		return adminError(c, "admin_reassign_approver_failed", id, err)
+	}
+
+	log.Infof("admin_reassign_approver_success leave_id=%s approver_id=%s actor_id=%s", id, leave.ApproverID, actor.ID)
+	return writeLeave(c, http.StatusOK, leave)
+}
+
+// CorrectDays handles PUT /api/v1/admin/leave/:id/days
+func (h *AdminHandler) CorrectDays(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	actor, id, err := adminTarget(c, "admin_correct_days_failed")
+	if err != nil {
+		return err
+	}
+
+	var req models.CorrectDaysRequest
+	if err := c.Bind(&req); err != nil {
+		log.Warnf("admin_correct_days_failed reason=invalid_request leave_id=%s error=%v", id, err)
+		return apperror.InvalidBody(err)
+	}
+
+	if err := validateRequest(c, "admin_correct_days_failed", &req); err != nil {
+		return err
+	}
+
+	leave, err := h.adminService.CorrectDays(c.Request().Context(), id, req.Days, req.Reason, actor)
+	if err != nil {
+		return adminError(c, "admin_correct_days_failed", id, err)
+	}
+
+	log.Infof("admin_correct_days_success leave_id=%s days=%d actor_id=%s", id, leave.Days, actor.ID)
+	return writeLeave(c, http.StatusOK, leave)
+}
+
+// GetHistory handles GET /api/v1/admin/leave/:id/history
+func (h *AdminHandler) GetHistory(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("admin_get_history_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	entries, err := h.adminService.GetHistory(c.Request().Context(), id)
+	if err != nil {
+		return adminError(c, "admin_get_history_failed", id, err)
+	}
+
+	log.Infof("admin_get_history_success leave_id=%s count=%d", id, len(entries))
+	return c.JSON(http.StatusOK, entries)
+}
+
+// GetAuditTrail handles GET /api/v1/admin/audit
+func (h *AdminHandler) GetAuditTrail(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	filter := models.AuditFilter{
+		ActorID: c.QueryParam("actorId"),
+		Action:  models.HistoryAction(c.QueryParam("action")),
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			log.Warnf("admin_get_audit_failed reason=invalid_query limit=%s", limit)
+			return apperror.InvalidQuery("Invalid limit")
+		}
+		filter.Limit = n
+	}
+
+	entries, err := h.adminService.GetAuditTrail(c.Request().Context(), filter)
+	if err != nil {
+		return adminError(c, "admin_get_audit_failed", uuid.Nil, err)
+	}
+
+	log.Infof("admin_get_audit_success count=%d", len(entries))
+	return c.JSON(http.StatusOK, entries)
+}
+
+// adminTarget reads the acting admin and the leave request ID of a modifying request
+func adminTarget(c echo.Context, event string) (*models.Employee, uuid.UUID, error) {
+	log := middleware.GetLogger(c)
+
+	actor, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("%s reason=unauthorized error=%v", event, err)
+		return nil, uuid.Nil, apperror.Unauthorized(err)
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("%s reason=invalid_id id=%s error=%v", event, c.Param("id"), err)
+		return nil, uuid.Nil, apperror.InvalidID("Invalid leave request ID")
+	}
+
+	return actor, id, nil
+}
+
+// adminError logs and maps admin service errors to HTTP errors
+func adminError(c echo.Context, event string, leaveID uuid.UUID, err error) error {
+	log := middleware.GetLogger(c)
+
+	var validationErr *utils.ValidationError
+	switch {
+	case errors.Is(err, services.ErrLeaveNotFound):
+		log.Warnf("%s reason=not_found leave_id=%s", event, leaveID)
+		return apperror.New(http.StatusNotFound, apperror.CodeLeaveNotFound, "Leave request not found")
+	case errors.Is(err, services.ErrInvalidStatus):
+		log.Warnf("%s reason=invalid_status leave_id=%s error=%v", event, leaveID, err)
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidStatusTransition, err.Error())
+	case errors.Is(err, services.ErrConflict):
+		log.Warnf("%s reason=conflict leave_id=%s", event, leaveID)
+		return apperror.New(http.StatusConflict, apperror.CodeConflict, err.Error())
+	case errors.As(err, &validationErr):
+		log.Warnf("%s reason=validation leave_id=%s error=%v", event, leaveID, err)
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidationFailed, err.Error())
+	default:
+		log.Errorf("%s leave_id=%s error=%v", event, leaveID, err)
+		return apperror.Internal(err)
+	}
+}
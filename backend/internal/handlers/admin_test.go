@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/services"
+)
+
+func setupTestAdminHandler() (*AdminHandler, *repository.MockLeaveRepository) {
+	repo := repository.NewMockLeaveRepository()
+	leaveService := services.NewLeaveService(repository.NewMockUnitOfWork(repo, nil), services.DefaultLeavePolicy())
+	adminService := services.NewAdminService(leaveService)
+	return NewAdminHandler(adminService, leaveService), repo
+}
+
+func TestAdminHandler_SearchLeaveRequests(t *testing.T) {
+	handler, repo := setupTestAdminHandler()
+
+	repo.Create(context.Background(), &models.LeaveRequest{ID: uuid.New(), EmployeeID: "emp-1", Status: models.LeaveStatusPending, CreatedAt: time.Now()})
+	repo.Create(context.Background(), &models.LeaveRequest{ID: uuid.New(), EmployeeID: "emp-2", Status: models.LeaveStatusApproved, CreatedAt: time.Now()})
+
+	c, rec := setupEchoContext(http.MethodGet, "/api/v1/admin/leave?status=approved", nil)
+	if err := handler.SearchLeaveRequests(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var leaves []models.LeaveRequestJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &leaves); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(leaves) != 1 || leaves[0].EmployeeID != "emp-2" {
+		t.Errorf("expected only emp-2's approved request, got %d results", len(leaves))
+	}
+
+	c, _ = setupEchoContext(http.MethodGet, "/api/v1/admin/leave?from=yesterday", nil)
+	err := handler.SearchLeaveRequests(c)
+	if httpErr, ok := appError(err); !ok || httpErr.Status != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid date, got %v", err)
+	}
+}
+
+func TestAdminHandler_ForceStatus(t *testing.T) {
+	handler, repo := setupTestAdminHandler()
+
+	id := uuid.New()
+	repo.Create(context.Background(), &models.LeaveRequest{ID: id, EmployeeID: "emp-1", Status: models.LeaveStatusRejected, Days: 2, CreatedAt: time.Now()})
+
+	tests := []struct {
+		name           string
+		id             string
+		body           map[string]string
+		wantStatusCode int
+	}{
+		{"missing reason", id.String(), map[string]string{"status": "approved"}, http.StatusBadRequest},
+		{"invalid status", id.String(), map[string]string{"status": "archived", "reason": "Cleanup"}, http.StatusBadRequest},
+		{"not found", uuid.New().String(), map[string]string{"status": "approved", "reason": "Appeal upheld"}, http.StatusNotFound},
+		{"force approve", id.String(), map[string]string{"status": "approved", "reason": "Appeal upheld"}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := setupEchoContext(http.MethodPut, "/api/v1/admin/leave/:id/status", tt.body)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			c.Set("userID", "admin-1")
+
+			err := handler.ForceStatus(c)
+
+			if tt.wantStatusCode >= 400 {
+				httpErr, ok := appError(err)
+				if !ok {
+					t.Fatalf("expected an error, got %v", err)
+				}
+				if httpErr.Status != tt.wantStatusCode {
+					t.Errorf("expected status code %d, got %d", tt.wantStatusCode, httpErr.Status)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status code %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/middleware"
+	"leave-management-system/internal/services"
+)
+
+// AttachmentHandler handles leave attachment endpoints
+type AttachmentHandler struct {
+	attachmentService *services.AttachmentService
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(attachmentService *services.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// multipartOverhead allows for the multipart boundaries and part headers that
+// wrap an uploaded file
+const multipartOverhead = 64 * 1024
+
+// UploadBodyLimit rejects upload requests whose body is larger than an
+// attachment of maxSize bytes, before the multipart form is parsed
+func UploadBodyLimit(maxSize int64) echo.MiddlewareFunc {
+	return echomiddleware.BodyLimit(fmt.Sprintf("%dB", maxSize+multipartOverhead))
+}
+
+// UploadAttachment handles POST /api/v1/leave/:id/attachments
+func (h *AttachmentHandler) UploadAttachment(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		log.Warnf("upload_attachment_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	leaveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("upload_attachment_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if errors.Is(err, echo.ErrStatusRequestEntityTooLarge) {
+		log.Warnf("upload_attachment_failed reason=too_large leave_id=%s", leaveID)
+		return apperror.New(http.StatusRequestEntityTooLarge, apperror.CodePayloadTooLarge, "Attachment exceeds maximum size")
+	}
+	if err != nil {
+		log.Warnf("upload_attachment_failed reason=missing_file leave_id=%s error=%v", leaveID, err)
+		return apperror.InvalidBody(err)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Errorf("upload_attachment_failed reason=open_file leave_id=%s error=%v", leaveID, err)
+		return apperror.InvalidBody(err)
+	}
+	defer file.Close()
+
+	log.Debugf("upload_attachment_start leave_id=%s file_name=%q size=%d", leaveID, fileHeader.Filename, fileHeader.Size)
+
+	attachment, err := h.attachmentService.UploadAttachment(c.Request().Context(), leaveID, userID, middleware.GetUserRoles(c), fileHeader.Filename, fileHeader.Size, file)
+	if err != nil {
+		return attachmentError(c, "upload_attachment_failed", leaveID, err)
+	}
+
+	log.Infof("upload_attachment_success leave_id=%s attachment_id=%s content_type=%s size=%d", leaveID, attachment.ID, attachment.ContentType, attachment.SizeBytes)
+	return c.JSON(http.StatusCreated, attachment)
+}
+
+// GetAttachments handles GET /api/v1/leave/:id/attachments
+func (h *AttachmentHandler) GetAttachments(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		log.Warnf("get_attachments_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	leaveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("get_attachments_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	attachments, err := h.attachmentService.GetAttachments(c.Request().Context(), leaveID, userID, middleware.GetUserRoles(c))
+	if err != nil {
+		return attachmentError(c, "get_attachments_failed", leaveID, err)
+	}
+
+	log.Infof("get_attachments_success leave_id=%s count=%d", leaveID, len(attachments))
+	return c.JSON(http.StatusOK, attachments)
+}
+
+// DownloadAttachment handles GET /api/v1/leave/:id/attachments/:attachmentId
+func (h *AttachmentHandler) DownloadAttachment(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		log.Warnf("download_attachment_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	leaveID, attachmentID, err := parseAttachmentParams(c)
+	if err != nil {
+		log.Warnf("download_attachment_failed reason=invalid_id error=%v", err)
+		return apperror.InvalidID(err.Error())
+	}
+
+	attachment, body, err := h.attachmentService.OpenAttachment(c.Request().Context(), leaveID, attachmentID, userID, middleware.GetUserRoles(c))
+	if err != nil {
+		return attachmentError(c, "download_attachment_failed", leaveID, err)
+	}
+	defer body.Close()
+
+	log.Infof("download_attachment_success leave_id=%s attachment_id=%s", leaveID, attachmentID)
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, contentDisposition(attachment.FileName))
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(attachment.SizeBytes, 10))
+	header.Set("X-Content-Type-Options", "nosniff")
+	return c.Stream(http.StatusOK, attachment.ContentType, body)
+}
+
+// DeleteAttachment handles DELETE /api/v1/leave/:id/attachments/:attachmentId
+func (h *AttachmentHandler) DeleteAttachment(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		log.Warnf("delete_attachment_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	leaveID, attachmentID, err := parseAttachmentParams(c)
+	if err != nil {
+		log.Warnf("delete_attachment_failed reason=invalid_id error=%v", err)
+		return apperror.InvalidID(err.Error())
+	}
+
+	if err := h.attachmentService.DeleteAttachment(c.Request().Context(), leaveID, attachmentID, userID, middleware.GetUserRoles(c)); err != nil {
+		return attachmentError(c, "delete_attachment_failed", leaveID, err)
+	}
+
+	log.Infof("delete_attachment_success leave_id=%s attachment_id=%s", leaveID, attachmentID)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// contentDisposition builds an RFC 6266 attachment header, encoding file names
+// that are not plain ASCII as filename*
+func contentDisposition(fileName string) string {
+	if value := mime.FormatMediaType("attachment", map[string]string{"filename": fileName}); value != "" {
+		return value
+	}
+	return "attachment"
+}
+
+func parseAttachmentParams(c echo.Context) (uuid.UUID, uuid.UUID, error) {
+	leaveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("Invalid leave request ID")
+	}
+	attachmentID, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("Invalid attachment ID")
+	}
+	return leaveID, attachmentID, nil
+}
+
+// attachmentError logs and maps attachment service errors to HTTP errors
+func attachmentError(c echo.Context, event string, leaveID uuid.UUID, err error) error {
+	log := middleware.GetLogger(c)
+
+	switch {
+	case errors.Is(err, services.ErrLeaveNotFound):
+		log.Warnf("%s reason=not_found leave_id=%s", event, leaveID)
+		return apperror.New(http.StatusNotFound, apperror.CodeLeaveNotFound, "Leave request not found")
+	case errors.Is(err, services.ErrAttachmentNotFound):
+		log.Warnf("%s reason=attachment_not_found leave_id=%s", event, leaveID)
+		return apperror.New(http.StatusNotFound, apperror.CodeAttachmentNotFound, "Attachment not found")
+	case errors.Is(err, services.ErrUnauthorizedAction):
+		log.Warnf("%s reason=forbidden leave_id=%s", event, leaveID)
+		return apperror.New(http.StatusForbidden, apperror.CodeForbidden, "Access denied")
+	case errors.Is(err, services.ErrInvalidStatus):
+		log.Warnf("%s reason=invalid_status leave_id=%s error=%v", event, leaveID, err)
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidStatusTransition, err.Error())
+	case errors.Is(err, services.ErrAttachmentTooLarge):
+		log.Warnf("%s reason=too_large leave_id=%s", event, leaveID)
+		return apperror.New(http.StatusRequestEntityTooLarge, apperror.CodePayloadTooLarge, "Attachment exceeds maximum size")
+	case errors.Is(err, services.ErrUnsupportedFileType):
+		log.Warnf("%s reason=unsupported_type leave_id=%s error=%v", event, leaveID, err)
+		return apperror.New(http.StatusUnsupportedMediaType, apperror.CodeUnsupportedMediaType, "Only PDF, JPEG and PNG files are allowed")
+	default:
+		log.Errorf("%s leave_id=%s error=%v", event, leaveID, err)
+		return apperror.Internal(err)
+	}
+}
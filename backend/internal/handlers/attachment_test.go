@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/services"
+	"leave-management-system/internal/storage"
+)
+
+func setupTestAttachmentHandler(t *testing.T) (*AttachmentHandler, uuid.UUID) {
+	t.Helper()
+
+	leaveRepo := repository.NewMockLeaveRepository()
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	leaveID := uuid.New()
+	leaveRepo.Create(context.Background(), &models.LeaveRequest{
+		ID:         leaveID,
+		EmployeeID: "emp-1",
+		Status:     models.LeaveStatusPending,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	})
+
+	service := services.NewAttachmentService(repository.NewMockAttachmentRepository(), services.NewLeaveService(repository.NewMockUnitOfWork(leaveRepo, nil), services.DefaultLeavePolicy()), store, 1024*1024)
+	return NewAttachmentHandler(service), leaveID
+}
+
+func multipartUpload(t *testing.T, fileName string, content []byte) (*bytes.Buffer, string) {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	part.Write(content)
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestAttachmentHandler_UploadAndDownload(t *testing.T) {
+	handler, leaveID := setupTestAttachmentHandler(t)
+	e := echo.New()
+	pdf := []byte("%PDF-1.4 medical certificate")
+
+	tests := []struct {
+		name           string
+		userID         string
+		content        []byte
+		wantStatusCode int
+	}{
+		{"owner uploads pdf", "emp-1", pdf, http.StatusCreated},
+		{"other employee forbidden", "emp-2", pdf, http.StatusForbidden},
+		{"unsupported type", "emp-1", []byte("plain text file"), http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType := multipartUpload(t, "certificate.pdf", tt.content)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/leave/:id/attachments", body)
+			req.Header.Set(echo.HeaderContentType, contentType)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(leaveID.String())
+			c.Set("userID", tt.userID)
+
+			err := handler.UploadAttachment(c)
+
+			if tt.wantStatusCode >= 400 {
+				he, ok := appError(err)
+				if !ok {
+					t.Fatalf("expected an error, got %v", err)
+				}
+				if he.Status != tt.wantStatusCode {
+					t.Errorf("expected status code %d, got %d", tt.wantStatusCode, he.Status)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status code %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+
+	t.Run("manager downloads", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/leave/:id/attachments", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(leaveID.String())
+		c.Set("userID", "mgr-1")
+		c.Set("userRoles", []string{"manager"})
+
+		attachments, err := handler.attachmentService.GetAttachments(context.Background(), leaveID, "mgr-1", []string{"manager"})
+		if err != nil || len(attachments) != 1 {
+			t.Fatalf("expected 1 attachment, got %d (err=%v)", len(attachments), err)
+		}
+
+		c.SetParamNames("id", "attachmentId")
+		c.SetParamValues(leaveID.String(), attachments[0].ID.String())
+
+		if err := handler.DownloadAttachment(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+		if !bytes.Equal(rec.Body.Bytes(), pdf) {
+			t.Errorf("downloaded content does not match upload")
+		}
+		if got := rec.Header().Get(echo.HeaderContentType); got != "application/pdf" {
+			t.Errorf("expected content type application/pdf, got %q", got)
+		}
+	})
+}
+
+func TestAttachmentHandler_DownloadEncodesFileName(t *testing.T) {
+	handler, leaveID := setupTestAttachmentHandler(t)
+	e := echo.New()
+	fileName := "ใบรับรอง \"แพทย์\".pdf"
+
+	body, contentType := multipartUpload(t, fileName, []byte("%PDF-1.4 medical certificate"))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/leave/:id/attachments", body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues(leaveID.String())
+	c.Set("userID", "emp-1")
+	if err := handler.UploadAttachment(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	attachments, err := handler.attachmentService.GetAttachments(context.Background(), leaveID, "emp-1", nil)
+	if err != nil || len(attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %d (err=%v)", len(attachments), err)
+	}
+
+	rec := httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/leave/:id/attachments/:attachmentId", nil), rec)
+	c.SetParamNames("id", "attachmentId")
+	c.SetParamValues(leaveID.String(), attachments[0].ID.String())
+	c.Set("userID", "emp-1")
+	if err := handler.DownloadAttachment(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	disposition, params, err := mime.ParseMediaType(rec.Header().Get(echo.HeaderContentDisposition))
+	if err != nil {
+		t.Fatalf("unparsable Content-Disposition %q: %v", rec.Header().Get(echo.HeaderContentDisposition), err)
+	}
+	if disposition != "attachment" || params["filename"] != fileName {
+		t.Errorf("expected attachment named %q, got %s %q", fileName, disposition, params["filename"])
+	}
+}
+
+func TestUploadBodyLimit(t *testing.T) {
+	handler, leaveID := setupTestAttachmentHandler(t)
+	e := newTestEcho()
+	e.POST("/api/v1/leave/:id/attachments", func(c echo.Context) error {
+		c.Set("userID", "emp-1")
+		return handler.UploadAttachment(c)
+	}, UploadBodyLimit(1024))
+
+	tests := []struct {
+		name           string
+		size           int
+		wantStatusCode int
+	}{
+		{"within limit", 1024, http.StatusCreated},
+		{"body too large", 1024 + multipartOverhead, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := append([]byte("%PDF-1.4 "), bytes.Repeat([]byte("x"), tt.size-9)...)
+			body, contentType := multipartUpload(t, "certificate.pdf", content)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/leave/"+leaveID.String()+"/attachments", body)
+			req.Header.Set(echo.HeaderContentType, contentType)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status code %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/ical"
+	"leave-management-system/internal/middleware"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/services"
+)
+
+// CalendarHandler handles calendar feed subscriptions and the feeds themselves
+type CalendarHandler struct {
+	calendarService *services.CalendarService
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(calendarService *services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// GetFeeds handles GET /api/v1/calendar/feeds
+func (h *CalendarHandler) GetFeeds(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		log.Warnf("get_calendar_feeds_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	feeds, err := h.calendarService.ListFeeds(c.Request().Context(), userID)
+	if err != nil {
+		log.Errorf("get_calendar_feeds_failed user_id=%s error=%v", userID, err)
+		return apperror.Internal(err)
+	}
+
+	log.Infof("get_calendar_feeds_success user_id=%s count=%d", userID, len(feeds))
+	return c.JSON(http.StatusOK, feeds)
+}
+
+// UpdateFeed handles PUT /api/v1/calendar/feeds/:kind
+func (h *CalendarHandler) UpdateFeed(c echo.Context) error {
+	log := middleware.GetLogger(c)
+	kind := models.CalendarFeedKind(c.Param("kind"))
+
+	employee, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("update_calendar_feed_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	var req models.UpdateCalendarFeedRequest
+	if err := c.Bind(&req); err != nil {
+		log.Warnf("update_calendar_feed_failed reason=invalid_request kind=%s error=%v", kind, err)
+		return apperror.InvalidBody(err)
+	}
+
+	if err := validateRequest(c, "update_calendar_feed_failed", &req); err != nil {
+		return err
+	}
+
+	feed, err := h.calendarService.SaveFeed(c.Request().Context(), kind, &req, employee, middleware.GetUserRoles(c))
+	if err != nil {
+		return calendarError(c, "update_calendar_feed_failed", kind, err)
+	}
+
+	log.Infof("update_calendar_feed_success user_id=%s kind=%s privacy=%s", employee.ID, kind, feed.Privacy)
+	return c.JSON(http.StatusOK, feed)
+}
+
+// RegenerateFeed handles POST /api/v1/calendar/feeds/:kind/regenerate
+func (h *CalendarHandler) RegenerateFeed(c echo.Context) error {
+	log := middleware.GetLogger(c)
+	kind := models.CalendarFeedKind(c.Param("kind"))
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		log.Warnf("regenerate_calendar_feed_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	feed, err := h.calendarService.RegenerateFeed(c.Request().Context(), kind, userID)
+	if err != nil {
+		return calendarError(c, "regenerate_calendar_feed_failed", kind, err)
+	}
+
+	log.Infof("regenerate_calendar_feed_success user_id=%s kind=%s", userID, kind)
+	return c.JSON(http.StatusOK, feed)
+}
+
+// DeleteFeed handles DELETE /api/v1/calendar/feeds/:kind
+func (h *CalendarHandler) DeleteFeed(c echo.Context) error {
+	log := middleware.GetLogger(c)
+	kind := models.CalendarFeedKind(c.Param("kind"))
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		log.Warnf("delete_calendar_feed_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	if err := h.calendarService.DeleteFeed(c.Request().Context(), kind, userID); err != nil {
+		return calendarError(c, "delete_calendar_feed_failed", kind, err)
+	}
+
+	log.Infof("delete_calendar_feed_success user_id=%s kind=%s", userID, kind)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetFeed handles GET /ical/:feed, where feed is the feed's token followed by
+// ".ics". The token is the only credential, so it is never logged.
+func (h *CalendarHandler) GetFeed(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	token, ok := strings.CutSuffix(c.Param("feed"), ".ics")
+	if !ok || token == "" {
+		log.Warnf("get_calendar_feed_failed reason=not_found")
+		return apperror.New(http.StatusNotFound, apperror.CodeCalendarFeedNotFound, "Calendar feed not found")
+	}
+
+	feed, body, err := h.calendarService.RenderFeed(c.Request().Context(), token)
+	if err != nil {
+		return calendarError(c, "get_calendar_feed_failed", "", err)
+	}
+
+	log.Infof("get_calendar_feed_success user_id=%s kind=%s privacy=%s", feed.EmployeeID, feed.Kind, feed.Privacy)
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", string(feed.Kind)+".ics"))
+	header.Set("Cache-Control", "private, max-age=300")
+	return c.Blob(http.StatusOK, ical.ContentType+"; charset=utf-8", body)
+}
+
+// calendarError logs and maps calendar service errors to HTTP errors
+func calendarError(c echo.Context, event string, kind models.CalendarFeedKind, err error) error {
+	log := middleware.GetLogger(c)
+
+	switch {
+	case errors.Is(err, services.ErrCalendarFeedNotFound):
+		log.Warnf("%s reason=not_found kind=%s", event, kind)
+		return apperror.New(http.StatusNotFound, apperror.CodeCalendarFeedNotFound, "Calendar feed not found")
+	case errors.Is(err, services.ErrTeamFeedDetails):
+		log.Warnf("%s reason=forbidden kind=%s", event, kind)
+		return apperror.New(http.StatusForbidden, apperror.CodeForbidden, "Only approvers and HR may see the type of team leave")
+	}
+	log.Errorf("%s kind=%s error=%v", event, kind, err)
+	return apperror.Internal(err)
+}
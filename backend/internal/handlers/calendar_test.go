@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"leave-management-system/internal/config"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/services"
+)
+
+func TestCalendarHandler(t *testing.T) {
+	service, err := services.NewCalendarService(repository.NewMockCalendarFeedRepository(),
+		services.NewLeaveService(repository.NewMockUnitOfWork(nil, nil), services.DefaultLeavePolicy()),
+		"https://leave.example.com", config.CalendarConfig{Holidays: "2024-01-01=New Year's Day"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	handler := NewCalendarHandler(service)
+
+	update := func(kind string, body interface{}) (*models.CalendarFeedInfo, int, error) {
+		c, rec := setupEchoContext(http.MethodPut, "/api/v1/calendar/feeds/"+kind, body)
+		c.Set("userID", "emp-1")
+		c.SetParamNames("kind")
+		c.SetParamValues(kind)
+		err := handler.UpdateFeed(c)
+		var feed models.CalendarFeedInfo
+		json.Unmarshal(rec.Body.Bytes(), &feed)
+		return &feed, rec.Code, err
+	}
+	get := func(feed string) (string, string, error) {
+		c, rec := setupEchoContext(http.MethodGet, "/ical/"+feed, nil)
+		c.SetParamNames("feed")
+		c.SetParamValues(feed)
+		err := handler.GetFeed(c)
+		return rec.Body.String(), rec.Header().Get("Content-Type"), err
+	}
+
+	feed, code, err := update("holidays", nil)
+	if err != nil || code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%v)", code, err)
+	}
+	body, contentType, err := get(feed.URL[strings.LastIndex(feed.URL, "/")+1:])
+	if err != nil || contentType != "text/calendar; charset=utf-8" || !strings.Contains(body, "SUMMARY:New Year's Day") {
+		t.Fatalf("expected the holiday feed, got %q %q (%v)", contentType, body, err)
+	}
+
+	c, rec := setupEchoContext(http.MethodGet, "/api/v1/calendar/feeds", nil)
+	c.Set("userID", "emp-1")
+	if err := handler.GetFeeds(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var feeds []models.CalendarFeedInfo
+	if json.Unmarshal(rec.Body.Bytes(), &feeds); len(feeds) != 1 || feeds[0].Kind != models.CalendarFeedHolidays || feeds[0].URL != "" {
+		t.Errorf("expected the holiday feed without its URL, got %+v", feeds)
+	}
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantCode int
+	}{
+		{"unknown kind", func() error { _, _, err := update("everyone", nil); return err }, http.StatusNotFound},
+		{"invalid privacy", func() error { _, _, err := update("team", map[string]string{"privacy": "secret"}); return err }, http.StatusBadRequest},
+		{"team details for an employee", func() error { _, _, err := update("team", map[string]string{"privacy": "details"}); return err }, http.StatusForbidden},
+		{"unknown token", func() error { _, _, err := get("not-a-token.ics"); return err }, http.StatusNotFound},
+		{"missing extension", func() error {
+			_, _, err := get(strings.TrimSuffix(feed.URL[strings.LastIndex(feed.URL, "/")+1:], ".ics"))
+			return err
+		}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr, ok := appError(tt.call())
+			if !ok || appErr.Status != tt.wantCode {
+				t.Errorf("expected %d, got %+v", tt.wantCode, appErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/middleware"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/services"
+	"leave-management-system/internal/utils"
+)
+
+// CommentHandler handles the discussion thread endpoints of a leave request
+type CommentHandler struct {
+	commentService *services.CommentService
+}
+
+// NewCommentHandler creates a new comment handler
+func NewCommentHandler(commentService *services.CommentService) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+// AddComment handles POST /api/v1/leave/:id/comments
+func (h *CommentHandler) AddComment(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	author, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("add_comment_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	leaveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("add_comment_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	var req models.CommentRequest
+	if err := c.Bind(&req); err != nil {
+		log.Warnf("add_comment_failed reason=invalid_request leave_id=%s error=%v", leaveID, err)
+		return apperror.InvalidBody(err)
+	}
+
+	if err := validateRequest(c, "add_comment_failed", &req); err != nil {
+		return err
+	}
+
+	log.Debugf("add_comment_start leave_id=%s", leaveID)
+
+	comment, recipients, err := h.commentService.AddComment(c.Request().Context(), leaveID, author, middleware.GetUserRoles(c), req.Body)
+	if err != nil {
+		return commentError(c, "add_comment_failed", leaveID, err)
+	}
+
+	log.Infof("add_comment_success leave_id=%s comment_id=%s recipients=%d", leaveID, comment.ID, len(recipients))
+	return c.JSON(http.StatusCreated, comment)
+}
+
+// GetComments handles GET /api/v1/leave/:id/comments
+func (h *CommentHandler) GetComments(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		log.Warnf("get_comments_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	leaveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("get_comments_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	comments, err := h.commentService.GetComments(c.Request().Context(), leaveID, userID, middleware.GetUserRoles(c))
+	if err != nil {
+		return commentError(c, "get_comments_failed", leaveID, err)
+	}
+
+	log.Infof("get_comments_success leave_id=%s count=%d", leaveID, len(comments))
+	return c.JSON(http.StatusOK, comments)
+}
+
+// UpdateComment handles PUT /api/v1/leave/:id/comments/:commentId
+func (h *CommentHandler) UpdateComment(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		log.Warnf("update_comment_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	leaveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("update_comment_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	commentID, err := uuid.Parse(c.Param("commentId"))
+	if err != nil {
+		log.Warnf("update_comment_failed reason=invalid_id comment_id=%s error=%v", c.Param("commentId"), err)
+		return apperror.InvalidID("Invalid comment ID")
+	}
+
+	var req models.CommentRequest
+	if err := c.Bind(&req); err != nil {
+		log.Warnf("update_comment_failed reason=invalid_request leave_id=%s error=%v", leaveID, err)
+		return apperror.InvalidBody(err)
+	}
+
+	if err := validateRequest(c, "update_comment_failed", &req); err != nil {
+		return err
+	}
+
+	comment, err := h.commentService.UpdateComment(c.Request().Context(), leaveID, commentID, userID, req.Body)
+	if err != nil {
+		return commentError(c, "update_comment_failed", leaveID, err)
+	}
+
+	log.Infof("update_comment_success leave_id=%s comment_id=%s", leaveID, commentID)
+	return c.JSON(http.StatusOK, comment)
+}
+
+// commentError logs and maps comment service errors to HTTP errors
+func commentError(c echo.Context, event string, leaveID uuid.UUID, err error) error {
+	log := middleware.GetLogger(c)
+
+	var validationErr *utils.ValidationError
+	switch {
+	case errors.Is(err, services.ErrLeaveNotFound):
+		log.Warnf("%s reason=not_found leave_id=%s", event, leaveID)
+		return apperror.New(http.StatusNotFound, apperror.CodeLeaveNotFound, "Leave request not found")
+	case errors.Is(err, services.ErrCommentNotFound):
+		log.Warnf("%s reason=comment_not_found leave_id=%s", event, leaveID)
+		return apperror.New(http.StatusNotFound, apperror.CodeCommentNotFound, "Comment not found")
+	case errors.Is(err, services.ErrUnauthorizedAction):
+		log.Warnf("%s reason=forbidden leave_id=%s", event, leaveID)
+		return apperror.New(http.StatusForbidden, apperror.CodeForbidden, "Access denied")
+	case errors.Is(err, services.ErrEditWindowExpired):
+		log.Warnf("%s reason=edit_window_expired leave_id=%s", event, leaveID)
+		return apperror.New(http.StatusForbidden, apperror.CodeEditWindowExpired, "Comment can no longer be edited")
+	case errors.As(err, &validationErr):
+		log.Warnf("%s reason=invalid_comment leave_id=%s error=%v", event, leaveID, err)
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidationFailed, err.Error())
+	default:
+		log.Errorf("%s leave_id=%s error=%v", event, leaveID, err)
+		return apperror.Internal(err)
+	}
+}
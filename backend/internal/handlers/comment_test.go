@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/config"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/notify"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/services"
+	"leave-management-system/internal/templates"
+)
+
+func setupTestCommentHandler() (*CommentHandler, uuid.UUID) {
+	leaveRepo := repository.NewMockLeaveRepository()
+	leaveID := uuid.New()
+	leaveRepo.Create(context.Background(), &models.LeaveRequest{
+		ID:         leaveID,
+		EmployeeID: "emp-1",
+		Status:     models.LeaveStatusPending,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	})
+
+	uow := repository.NewMockUnitOfWork(leaveRepo, nil)
+	leaveService := services.NewLeaveService(uow, services.DefaultLeavePolicy())
+	renderer, _ := templates.NewRenderer(nil, "en")
+	notificationService := services.NewNotificationService(notify.NewSMTPNotifier(config.EmailConfig{}), renderer, "", "", nil)
+	commentService := services.NewCommentService(uow, leaveService, notificationService, 15*time.Minute)
+	return NewCommentHandler(commentService), leaveID
+}
+
+func TestCommentHandler_AddAndGetComments(t *testing.T) {
+	handler, leaveID := setupTestCommentHandler()
+
+	tests := []struct {
+		name           string
+		userID         string
+		roles          []string
+		body           string
+		wantStatusCode int
+	}{
+		{"employee comments", "emp-1", nil, "When will this be reviewed?", http.StatusCreated},
+		{"manager replies", "mgr-1", []string{"manager"}, "Later today.", http.StatusCreated},
+		{"other employee", "emp-2", nil, "Hello", http.StatusForbidden},
+		{"empty comment", "emp-1", nil, "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := setupEchoContext(http.MethodPost, "/api/v1/leave/:id/comments", map[string]string{"body": tt.body})
+			c.SetParamNames("id")
+			c.SetParamValues(leaveID.String())
+			c.Set("userID", tt.userID)
+			c.Set("userRoles", tt.roles)
+
+			err := handler.AddComment(c)
+
+			if tt.wantStatusCode >= 400 {
+				httpErr, ok := appError(err)
+				if !ok {
+					t.Fatalf("expected an error, got %v", err)
+				}
+				if httpErr.Status != tt.wantStatusCode {
+					t.Errorf("expected status code %d, got %d", tt.wantStatusCode, httpErr.Status)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status code %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+
+	c, rec := setupEchoContext(http.MethodGet, "/api/v1/leave/:id/comments", nil)
+	c.SetParamNames("id")
+	c.SetParamValues(leaveID.String())
+	c.Set("userID", "emp-1")
+
+	if err := handler.GetComments(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var comments []models.LeaveComment
+	if err := json.Unmarshal(rec.Body.Bytes(), &comments); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("expected 2 comments, got %d", len(comments))
+	}
+	if comments[0].AuthorID != "emp-1" || comments[1].AuthorID != "mgr-1" {
+		t.Errorf("expected comments in posting order, got %s then %s", comments[0].AuthorID, comments[1].AuthorID)
+	}
+}
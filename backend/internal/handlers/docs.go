@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+// docsPage renders the OpenAPI document with Redoc
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>Leave Management System API</title>
+</head>
+<body>
+  <redoc spec-url="/api/v1/openapi.json"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`
+
+// DocsHandler serves the OpenAPI document and a page to browse it
+type DocsHandler struct {
+	spec []byte
+}
+
+// NewDocsHandler creates a new docs handler for doc
+func NewDocsHandler(doc *openapi3.T) (*DocsHandler, error) {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+	return &DocsHandler{spec: spec}, nil
+}
+
+// GetSpec handles GET /api/v1/openapi.json
+func (h *DocsHandler) GetSpec(c echo.Context) error {
+	return c.JSONBlob(http.StatusOK, h.spec)
+}
+
+// GetDocs handles GET /api/v1/docs
+func (h *DocsHandler) GetDocs(c echo.Context) error {
+	return c.HTML(http.StatusOK, docsPage)
+}
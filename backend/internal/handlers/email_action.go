@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/middleware"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/services"
+	"leave-management-system/internal/utils"
+)
+
+// emailActionPage asks an approver to confirm a decision taken from an email,
+// then shows its outcome
+var emailActionPage = template.Must(template.New("email_action").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <meta name="robots" content="noindex">
+  <title>{{.Title}} - Leave Management System</title>
+</head>
+<body style="margin:0;padding:24px;background:#f4f5f7;font-family:Arial,Helvetica,sans-serif;color:#1f2933;line-height:1.5;">
+<div style="max-width:600px;margin:0 auto;background:#ffffff;border-radius:6px;padding:24px;">
+  <h1 style="font-size:20px;margin-top:0;">{{.Title}}</h1>
+  {{with .Message}}<p>{{.}}</p>{{end}}
+  {{with .Leave}}<table style="border-collapse:collapse;margin:16px 0;">
+    <tr><td style="padding:4px 16px 4px 0;color:#616e7c;">Employee</td><td>{{.EmployeeName}}</td></tr>
+    <tr><td style="padding:4px 16px 4px 0;color:#616e7c;">Type</td><td>{{.LeaveType}}</td></tr>
+    <tr><td style="padding:4px 16px 4px 0;color:#616e7c;">Dates</td><td>{{.StartDate}} to {{.EndDate}} ({{.Days}} days)</td></tr>
+    <tr><td style="padding:4px 16px 4px 0;color:#616e7c;">Reason</td><td>{{.Reason}}</td></tr>
+    <tr><td style="padding:4px 16px 4px 0;color:#616e7c;">Status</td><td>{{.Status}}</td></tr>
+  </table>{{end}}
+  {{if .Confirm}}<form method="post">
+    {{with .Error}}<p style="color:#c53030;">{{.}}</p>{{end}}
+    <p><label for="comment">Comment{{if .Reject}} (required, at least 10 characters){{else}} (optional){{end}}</label><br>
+    <textarea id="comment" name="comment" rows="4" maxlength="500" style="width:100%;"{{if .Reject}} required minlength="10"{{end}}>{{.Comment}}</textarea></p>
+    <button type="submit" style="padding:10px 20px;border:0;border-radius:4px;color:#ffffff;background:{{if .Reject}}#c53030{{else}}#2f855a{{end}};">{{if .Reject}}Reject{{else}}Approve{{end}}</button>
+  </form>{{end}}
+</div>
+</body>
+</html>
+`))
+
+// emailActionView is the data of emailActionPage
+type emailActionView struct {
+	Title   string
+	Message string
+	Leave   *models.LeaveRequest
+	// Confirm shows the form that takes the decision
+	Confirm bool
+	Reject  bool
+	Comment string
+	Error   string
+}
+
+// EmailActionHandler handles the approve and reject links of approver emails.
+// Opening a link only shows a confirmation page, so mail scanners that follow
+// links cannot decide anything; the decision is taken when the page's form is
+// posted.
+type EmailActionHandler struct {
+	actionService *services.EmailActionService
+}
+
+// NewEmailActionHandler creates a new email action handler
+func NewEmailActionHandler(actionService *services.EmailActionService) *EmailActionHandler {
+	return &EmailActionHandler{
+		actionService: actionService,
+	}
+}
+
+// GetAction handles GET /email-actions/:token
+func (h *EmailActionHandler) GetAction(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	claims, leave, err := h.actionService.Preview(c.Request().Context(), c.Param("token"))
+	if err != nil {
+		return emailActionError(c, "email_action_preview_failed", claims, leave, err)
+	}
+
+	log.Infof("email_action_preview_success leave_id=%s action=%s approver=%s", claims.LeaveID, claims.Action, claims.Approver)
+	return renderEmailAction(c, http.StatusOK, confirmView(claims, leave, "", ""))
+}
+
+// PerformAction handles POST /email-actions/:token
+func (h *EmailActionHandler) PerformAction(c echo.Context) error {
+	log := middleware.GetLogger(c)
+	comment := c.FormValue("comment")
+
+	claims, leave, err := h.actionService.Perform(c.Request().Context(), c.Param("token"), comment)
+	if err != nil {
+		var validationErr *utils.ValidationError
+		if errors.As(err, &validationErr) {
+			log.Warnf("%s_leave_failed reason=validation source=email leave_id=%s approver=%s", claims.Action, claims.LeaveID, claims.Approver)
+			return renderEmailAction(c, http.StatusBadRequest, confirmView(claims, leave, comment, "A comment of at least 10 characters is required to reject."))
+		}
+		return emailActionError(c, string(claims.Action)+"_leave_failed", claims, leave, err)
+	}
+
+	log.Infof("%s_leave_success leave_id=%s employee_id=%s source=email approver=%s", claims.Action, leave.ID, leave.EmployeeID, claims.Approver)
+
+	outcome := "approved"
+	if claims.Action == models.EmailActionReject {
+		outcome = "rejected"
+	}
+	return renderEmailAction(c, http.StatusOK, &emailActionView{
+		Title:   "Leave request " + outcome,
+		Message: leave.EmployeeName + " will be notified.",
+		Leave:   leave,
+	})
+}
+
+// confirmView asks to confirm the decision claims are for
+func confirmView(claims *models.EmailActionClaims, leave *models.LeaveRequest, comment, problem string) *emailActionView {
+	reject := claims.Action == models.EmailActionReject
+	title := "Approve this leave request?"
+	if reject {
+		title = "Reject this leave request?"
+	}
+	return &emailActionView{
+		Title:   title,
+		Leave:   leave,
+		Confirm: true,
+		Reject:  reject,
+		Comment: strings.TrimSpace(comment),
+		Error:   problem,
+	}
+}
+
+// emailActionError logs err and renders a page explaining why the decision
+// cannot be taken. claims is nil when the token itself is invalid, and leave
+// is set when the request could be read.
+func emailActionError(c echo.Context, event string, claims *models.EmailActionClaims, leave *models.LeaveRequest, err error) error {
+	log := middleware.GetLogger(c)
+
+	if claims == nil {
+		if errors.Is(err, services.ErrActionTokenExpired) {
+			log.Warnf("%s reason=expired source=email", event)
+			return renderEmailAction(c, http.StatusGone, &emailActionView{
+				Title:   "This link has expired",
+				Message: "Open the leave request in the Leave Management System to decide it.",
+			})
+		}
+		log.Warnf("%s reason=invalid_token source=email error=%v", event, err)
+		return renderEmailAction(c, http.StatusBadRequest, &emailActionView{
+			Title:   "This link is not valid",
+			Message: "Check that the whole link was copied from the email.",
+		})
+	}
+
+	switch {
+	case errors.Is(err, services.ErrSelfReview):
+		log.Warnf("%s reason=self_review source=email leave_id=%s", event, claims.LeaveID)
+		return renderEmailAction(c, http.StatusForbidden, &emailActionView{
+			Title:   "You cannot review your own leave request",
+			Message: "Ask another approver to decide it.",
+			Leave:   leave,
+		})
+	case errors.Is(err, services.ErrNotAssignedApprover):
+		log.Warnf("%s reason=not_assigned_approver source=email leave_id=%s approver=%s", event, claims.LeaveID, claims.Approver)
+		return renderEmailAction(c, http.StatusForbidden, &emailActionView{
+			Title:   "This leave request was assigned to another approver",
+			Message: "Only its assigned approver can decide it now.",
+			Leave:   leave,
+		})
+	case errors.Is(err, services.ErrLeaveNotFound):
+		log.Warnf("%s reason=not_found source=email leave_id=%s", event, claims.LeaveID)
+		return renderEmailAction(c, http.StatusNotFound, &emailActionView{
+			Title: "Leave request not found",
+		})
+	case errors.Is(err, services.ErrInvalidStatus):
+		log.Warnf("%s reason=invalid_status source=email leave_id=%s status=%s", event, claims.LeaveID, leave.Status)
+		return renderEmailAction(c, http.StatusConflict, &emailActionView{
+			Title:   "This leave request has already been decided",
+			Message: "It is " + string(leave.Status) + ", so this link no longer works.",
+			Leave:   leave,
+		})
+	case errors.Is(err, services.ErrPreconditionFailed), errors.Is(err, services.ErrConflict):
+		log.Warnf("%s reason=stale_version source=email leave_id=%s version=%d", event, claims.LeaveID, claims.Version)
+		return renderEmailAction(c, http.StatusConflict, &emailActionView{
+			Title:   "This leave request has changed",
+			Message: "It was changed after this email was sent. Review the latest version in the Leave Management System.",
+			Leave:   leave,
+		})
+	}
+
+	log.Errorf("%s source=email leave_id=%s error=%v", event, claims.LeaveID, err)
+	return renderEmailAction(c, http.StatusInternalServerError, &emailActionView{
+		Title:   "Something went wrong",
+		Message: "The leave request was not changed. Please try again later.",
+	})
+}
+
+// renderEmailAction writes the page for view
+func renderEmailAction(c echo.Context, status int, view *emailActionView) error {
+	var page strings.Builder
+	if err := emailActionPage.Execute(&page, view); err != nil {
+		return err
+	}
+	c.Response().Header().Set("Cache-Control", "no-store")
+	c.Response().Header().Set("Referrer-Policy", "no-referrer")
+	return c.HTML(status, page.String())
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/config"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/services"
+)
+
+func TestEmailActionHandler(t *testing.T) {
+	repo := repository.NewMockLeaveRepository()
+	leaveService := services.NewLeaveService(repository.NewMockUnitOfWork(repo, nil), services.DefaultLeavePolicy())
+	actions := services.NewEmailActionService(leaveService, "https://leave.example.com", config.EmailConfig{ActionSecret: "test-secret", ActionTTL: time.Hour})
+	handler := NewEmailActionHandler(actions)
+
+	leave := &models.LeaveRequest{ID: uuid.New(), EmployeeID: "emp-1", EmployeeName: "John Doe", Status: models.LeaveStatusPending, CreatedAt: time.Now()}
+	repo.Create(context.Background(), leave)
+	_, rejectURL := actions.Links(leave, "manager@example.com")
+	token := strings.TrimPrefix(rejectURL, "https://leave.example.com/email-actions/")
+
+	call := func(method, token, comment string) (int, string) {
+		form := url.Values{"comment": {comment}}
+		req := httptest.NewRequest(method, "/email-actions/"+token, strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := newTestEcho().NewContext(req, rec)
+		c.SetParamNames("token")
+		c.SetParamValues(token)
+
+		var err error
+		if method == http.MethodGet {
+			err = handler.GetAction(c)
+		} else {
+			err = handler.PerformAction(c)
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return rec.Code, rec.Body.String()
+	}
+
+	// Opening the link only asks for confirmation
+	code, body := call(http.MethodGet, token, "")
+	if code != http.StatusOK || !strings.Contains(body, "Reject this leave request?") || !strings.Contains(body, `<form method="post">`) {
+		t.Fatalf("expected the confirmation page, got %d %q", code, body)
+	}
+	if current, _ := leaveService.GetLeaveRequestByID(context.Background(), leave.ID); current.Status != models.LeaveStatusPending {
+		t.Fatalf("expected opening the link to change nothing, got %s", current.Status)
+	}
+
+	if code, body := call(http.MethodPost, token, "No"); code != http.StatusBadRequest || !strings.Contains(body, "at least 10 characters") {
+		t.Errorf("expected the form again for a short comment, got %d %q", code, body)
+	}
+	if code, body := call(http.MethodPost, token, "Team is short-staffed"); code != http.StatusOK || !strings.Contains(body, "Leave request rejected") {
+		t.Errorf("expected the request rejected, got %d %q", code, body)
+	}
+	if code, _ := call(http.MethodPost, token, "Team is short-staffed"); code != http.StatusConflict {
+		t.Errorf("expected 409 reusing the link, got %d", code)
+	}
+	if code, _ := call(http.MethodGet, token+"x", ""); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a tampered link, got %d", code)
+	}
+}
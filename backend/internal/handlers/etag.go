@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/services"
+)
+
+// A leave request's entity tag is its row version, e.g. "3"
+const (
+	HeaderETag    = "ETag"
+	HeaderIfMatch = "If-Match"
+)
+
+// leaveETag formats the version of leave as a strong entity tag
+func leaveETag(leave *models.LeaveRequest) string {
+	return strconv.Quote(strconv.Itoa(leave.Version))
+}
+
+// writeLeave writes leave as JSON with its ETag
+func writeLeave(c echo.Context, code int, leave *models.LeaveRequest) error {
+	c.Response().Header().Set(HeaderETag, leaveETag(leave))
+	return c.JSON(code, leave)
+}
+
+// ifMatchVersion returns the leave version named by the If-Match header, which
+// writes to a leave request must carry. "*" matches any version.
+func ifMatchVersion(c echo.Context) (int, error) {
+	value := strings.TrimSpace(c.Request().Header.Get(HeaderIfMatch))
+	switch {
+	case value == "":
+		return 0, apperror.New(http.StatusPreconditionRequired, apperror.CodePreconditionRequired, "If-Match header is required")
+	case value == "*":
+		return services.AnyVersion, nil
+	case strings.HasPrefix(value, "W/"):
+		// If-Match uses strong comparison, so a weak tag never matches
+		return 0, apperror.New(http.StatusPreconditionFailed, apperror.CodePreconditionFailed, services.ErrPreconditionFailed.Error())
+	}
+
+	tag, err := strconv.Unquote(value)
+	if err != nil || !strings.HasPrefix(value, `"`) {
+		return 0, apperror.New(http.StatusBadRequest, apperror.CodeBadRequest, "If-Match must be a single quoted entity tag")
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return 0, apperror.New(http.StatusPreconditionFailed, apperror.CodePreconditionFailed, services.ErrPreconditionFailed.Error())
+	}
+	return version, nil
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/services"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		want     int
+		wantCode int
+	}{
+		{name: "strong tag", header: `"3"`, want: 3},
+		{name: "any version", header: "*", want: services.AnyVersion},
+		{name: "missing", header: "", wantCode: http.StatusPreconditionRequired},
+		{name: "weak tag", header: `W/"3"`, wantCode: http.StatusPreconditionFailed},
+		{name: "unquoted", header: "3", wantCode: http.StatusBadRequest},
+		{name: "not a version", header: `"abc"`, wantCode: http.StatusPreconditionFailed},
+		{name: "zero", header: `"0"`, wantCode: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := setupEchoContext(http.MethodPut, "/api/v1/leave/:id", nil)
+			if tt.header != "" {
+				c.Request().Header.Set(HeaderIfMatch, tt.header)
+			}
+
+			version, err := ifMatchVersion(c)
+			if tt.wantCode != 0 {
+				he, ok := appError(err)
+				if !ok || he.Status != tt.wantCode {
+					t.Fatalf("expected status code %d, got %v", tt.wantCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if version != tt.want {
+				t.Errorf("expected version %d, got %d", tt.want, version)
+			}
+		})
+	}
+}
+
+func TestLeaveHandler_ETagPreconditions(t *testing.T) {
+	handler, repo := setupTestHandler()
+
+	leave := &models.LeaveRequest{
+		ID:         uuid.New(),
+		EmployeeID: "emp-1",
+		LeaveType:  models.LeaveTypeAnnual,
+		Reason:     "Vacation",
+		Status:     models.LeaveStatusPending,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	repo.Create(context.Background(), leave)
+
+	get := func() string {
+		c, rec := setupEchoContext(http.MethodGet, "/api/v1/leave/:id", nil)
+		c.SetParamNames("id")
+		c.SetParamValues(leave.ID.String())
+		c.Set("userID", "emp-1")
+		if err := handler.GetLeaveRequest(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return rec.Header().Get(HeaderETag)
+	}
+	update := func(ifMatch string) (string, error) {
+		c, rec := setupEchoContext(http.MethodPut, "/api/v1/leave/:id", map[string]interface{}{"reason": "Family vacation"})
+		c.SetParamNames("id")
+		c.SetParamValues(leave.ID.String())
+		c.Set("userID", "emp-1")
+		if ifMatch != "" {
+			c.Request().Header.Set(HeaderIfMatch, ifMatch)
+		}
+		err := handler.UpdateLeaveRequest(c)
+		return rec.Header().Get(HeaderETag), err
+	}
+
+	etag := get()
+	if etag != `"1"` {
+		t.Fatalf("expected ETag %q, got %q", `"1"`, etag)
+	}
+
+	if _, err := update(""); !hasStatus(err, http.StatusPreconditionRequired) {
+		t.Fatalf("expected 428 without If-Match, got %v", err)
+	}
+
+	updated, err := update(etag)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated != `"2"` || get() != updated {
+		t.Fatalf("expected the update to return and store ETag %q, got %q", `"2"`, updated)
+	}
+
+	// Replaying the original tag means the client has not seen the last edit
+	if _, err := update(etag); !hasStatus(err, http.StatusPreconditionFailed) {
+		t.Fatalf("expected 412 for a stale If-Match, got %v", err)
+	}
+}
+
+func hasStatus(err error, code int) bool {
+	he, ok := appError(err)
+	return ok && he.Status == code
+}
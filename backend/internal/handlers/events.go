@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/middleware"
+	"leave-management-system/internal/services"
+)
+
+// eventStreamKeepAlive is how often an idle event stream sends a comment, so
+// proxies do not close it
+const eventStreamKeepAlive = 25 * time.Second
+
+// eventStreamRetry is how long clients wait before reconnecting, in milliseconds
+const eventStreamRetry = 3000
+
+// EventHandler streams real-time leave events
+type EventHandler struct {
+	broker *services.LeaveEventBroker
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(broker *services.LeaveEventBroker) *EventHandler {
+	return &EventHandler{
+		broker: broker,
+	}
+}
+
+// StreamEvents handles GET /api/v1/events. It streams the changes to the
+// leave requests the caller can see as Server-Sent Events until the client
+// disconnects or the subscription ends.
+func (h *EventHandler) StreamEvents(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		log.Warnf("stream_events_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	sub := h.broker.Subscribe(userID, middleware.GetUserRoles(c))
+	defer h.broker.Unsubscribe(sub)
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	fmt.Fprintf(res, "retry: %d\n\n", eventStreamRetry)
+	res.Flush()
+
+	log.Infof("stream_events_start user_id=%s", userID)
+
+	ticker := time.NewTicker(eventStreamKeepAlive)
+	defer ticker.Stop()
+
+	events := 0
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			log.Infof("stream_events_end reason=client_closed user_id=%s events=%d", userID, events)
+			return nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				log.Infof("stream_events_end reason=subscription_closed user_id=%s events=%d", userID, events)
+				return nil
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Errorf("stream_events_failed user_id=%s error=%v", userID, err)
+				return nil
+			}
+			fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data)
+			events++
+		case <-ticker.C:
+			fmt.Fprint(res, ": keep-alive\n\n")
+		}
+		res.Flush()
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/services"
+)
+
+func TestEventHandler_StreamEvents(t *testing.T) {
+	broker := services.NewLeaveEventBroker()
+	handler := NewEventHandler(broker)
+
+	e := newTestEcho()
+	e.GET("/api/v1/events", handler.StreamEvents, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("userID", "emp-1")
+			return next(c)
+		}
+	})
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/api/v1/events")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK || res.Header.Get(echo.HeaderContentType) != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %d %s", res.StatusCode, res.Header.Get(echo.HeaderContentType))
+	}
+
+	// Read the event after the retry advice, which is sent once subscribed
+	body := bufio.NewReader(res.Body)
+	readEvent := func() string {
+		t.Helper()
+		var lines []string
+		for {
+			line, err := body.ReadString('\n')
+			if err != nil {
+				t.Fatalf("stream ended: %v", err)
+			}
+			if line == "\n" {
+				return strings.Join(lines, "")
+			}
+			lines = append(lines, line)
+		}
+	}
+	if retry := readEvent(); !strings.HasPrefix(retry, "retry: ") {
+		t.Fatalf("expected the retry advice first, got %q", retry)
+	}
+
+	id := uuid.New()
+	broker.Publish(&models.LeaveChange{Operation: "UPDATE", ID: uuid.New(), EmployeeID: "emp-2", PreviousStatus: models.LeaveStatusPending, Status: models.LeaveStatusApproved})
+	broker.Publish(&models.LeaveChange{Operation: "UPDATE", ID: id, EmployeeID: "emp-1", PreviousStatus: models.LeaveStatusPending, Status: models.LeaveStatusApproved, Version: 2})
+
+	event := readEvent()
+	if !strings.HasPrefix(event, "event: leave.approved\ndata: {") || !strings.Contains(event, id.String()) || !strings.Contains(event, `"version":2`) {
+		t.Errorf("expected the approval of the caller's request, got %q", event)
+	}
+
+	// Closing the subscriptions ends the stream
+	broker.Close()
+	if _, err := body.ReadString('\n'); err == nil {
+		t.Error("expected the stream to end")
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/middleware"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/services"
+	"leave-management-system/internal/utils"
+)
+
+// HRHandler handles endpoints for HR acting on behalf of employees
+type HRHandler struct {
+	hrService *services.HRLeaveService
+}
+
+// NewHRHandler creates a new HR handler
+func NewHRHandler(hrService *services.HRLeaveService) *HRHandler {
+	return &HRHandler{
+		hrService: hrService,
+	}
+}
+
+// CreateLeaveRequest handles POST /api/v1/hr/leave
+func (h *HRHandler) CreateLeaveRequest(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	actor, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("hr_create_leave_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	var req models.OnBehalfCreateLeaveRequest
+	if err := c.Bind(&req); err != nil {
+		log.Warnf("hr_create_leave_failed reason=invalid_request error=%v", err)
+		return apperror.InvalidBody(err)
+	}
+
+	if err := validateRequest(c, "hr_create_leave_failed", &req); err != nil {
+		return err
+	}
+
+	log.Debugf("hr_create_leave_start employee_id=%s leave_type=%s start_date=%s end_date=%s approved=%t allow_past=%t",
+		req.EmployeeID, req.LeaveType, req.StartDate, req.EndDate, req.Approved, req.AllowPastDates)
+
+	leave, err := h.hrService.CreateLeaveRequest(c.Request().Context(), &req, actor)
+	if err != nil {
+		return hrError(c, "hr_create_leave_failed", uuid.Nil, err)
+	}
+
+	log.Infof("hr_create_leave_success leave_id=%s employee_id=%s actor_id=%s status=%s", leave.ID, leave.EmployeeID, actor.ID, leave.Status)
+	return writeLeave(c, http.StatusCreated, leave)
+}
+
+// UpdateLeaveRequest handles PUT /api/v1/hr/leave/:id
+func (h *HRHandler) UpdateLeaveRequest(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	actor, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("hr_update_leave_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("hr_update_leave_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	var req models.OnBehalfUpdateLeaveRequest
+	if err := c.Bind(&req); err != nil {
+		log.Warnf("hr_update_leave_failed reason=invalid_request leave_id=%s error=%v", id, err)
+		return apperror.InvalidBody(err)
+	}
+
+	if err := validateRequest(c, "hr_update_leave_failed", &req); err != nil {
+		return err
+	}
+
+	leave, err := h.hrService.UpdateLeaveRequest(c.Request().Context(), id, &req, actor)
+	if err != nil {
+		return hrError(c, "hr_update_leave_failed", id, err)
+	}
+
+	log.Infof("hr_update_leave_success leave_id=%s employee_id=%s actor_id=%s", id, leave.EmployeeID, actor.ID)
+	return writeLeave(c, http.StatusOK, leave)
+}
+
+// CancelLeaveRequest handles DELETE /api/v1/hr/leave/:id
+func (h *HRHandler) CancelLeaveRequest(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	actor, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("hr_cancel_leave_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("hr_cancel_leave_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	// The body is optional
+	var req models.OnBehalfCancelLeaveRequest
+	if c.Request().ContentLength > 0 {
+		if err := c.Bind(&req); err != nil {
+			log.Warnf("hr_cancel_leave_failed reason=invalid_request leave_id=%s error=%v", id, err)
+			return apperror.InvalidBody(err)
+		}
+	}
+
+	if err := h.hrService.CancelLeaveRequest(c.Request().Context(), id, req.Reason, actor); err != nil {
+		return hrError(c, "hr_cancel_leave_failed", id, err)
+	}
+
+	log.Infof("hr_cancel_leave_success leave_id=%s actor_id=%s", id, actor.ID)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetHistory handles GET /api/v1/hr/leave/:id/history
+func (h *HRHandler) GetHistory(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("get_history_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	entries, err := h.hrService.GetHistory(c.Request().Context(), id)
+	if err != nil {
+		return hrError(c, "get_history_failed", id, err)
+	}
+
+	log.Infof("get_history_success leave_id=%s count=%d", id, len(entries))
+	return c.JSON(http.StatusOK, entries)
+}
+
+// hrError logs and maps HR service errors to HTTP errors
+func hrError(c echo.Context, event string, leaveID uuid.UUID, err error) error {
+	log := middleware.GetLogger(c)
+
+	var validationErr *utils.ValidationError
+	switch {
+	case errors.Is(err, services.ErrLeaveNotFound):
+		log.Warnf("%s reason=not_found leave_id=%s", event, leaveID)
+		return apperror.New(http.StatusNotFound, apperror.CodeLeaveNotFound, "Leave request not found")
+	case errors.Is(err, services.ErrInvalidStatus):
+		log.Warnf("%s reason=invalid_status leave_id=%s error=%v", event, leaveID, err)
+		return apperror.New(http.StatusBadRequest, apperror.CodeInvalidStatusTransition, err.Error())
+	case errors.Is(err, services.ErrConflict):
+		log.Warnf("%s reason=conflict leave_id=%s", event, leaveID)
+		return apperror.New(http.StatusConflict, apperror.CodeConflict, err.Error())
+	case errors.As(err, &validationErr):
+		log.Warnf("%s reason=validation leave_id=%s error=%v", event, leaveID, err)
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidationFailed, err.Error())
+	default:
+		log.Errorf("%s leave_id=%s error=%v", event, leaveID, err)
+		return apperror.Internal(err)
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/services"
+)
+
+func TestHRHandler_CreateLeaveRequest(t *testing.T) {
+	leaveService := services.NewLeaveService(repository.NewMockUnitOfWork(nil, nil), services.DefaultLeavePolicy())
+	handler := NewHRHandler(services.NewHRLeaveService(leaveService))
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		wantStatusCode int
+	}{
+		{
+			name: "back-dated sick leave",
+			body: map[string]interface{}{
+				"employeeId":     "emp-1",
+				"employeeName":   "John Doe",
+				"employeeEmail":  "john@example.com",
+				"leaveType":      "sick",
+				"startDate":      "2024-01-08",
+				"endDate":        "2024-01-09",
+				"approved":       true,
+				"allowPastDates": true,
+			},
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name: "past dates without allowPastDates",
+			body: map[string]interface{}{
+				"employeeId":    "emp-1",
+				"employeeName":  "John Doe",
+				"employeeEmail": "john@example.com",
+				"leaveType":     "sick",
+				"startDate":     "2024-01-08",
+				"endDate":       "2024-01-09",
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "missing employee",
+			body: map[string]interface{}{
+				"leaveType": "sick",
+				"startDate": futureDate(7),
+				"endDate":   futureDate(8),
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := setupEchoContext(http.MethodPost, "/api/v1/hr/leave", tt.body)
+			c.Set("userID", "hr-1")
+			c.Set("userName", "Helen HR")
+
+			err := handler.CreateLeaveRequest(c)
+
+			if tt.wantStatusCode >= 400 {
+				httpErr, ok := appError(err)
+				if !ok {
+					t.Fatalf("expected an error, got %v", err)
+				}
+				if httpErr.Status != tt.wantStatusCode {
+					t.Errorf("expected status code %d, got %d", tt.wantStatusCode, httpErr.Status)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status code %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/middleware"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/services"
+	"leave-management-system/internal/utils"
+)
+
+// InboxHandler handles each user's in-app notifications
+type InboxHandler struct {
+	inboxService *services.InboxService
+}
+
+// NewInboxHandler creates a new inbox handler
+func NewInboxHandler(inboxService *services.InboxService) *InboxHandler {
+	return &InboxHandler{
+		inboxService: inboxService,
+	}
+}
+
+// GetNotifications handles GET /api/v1/notifications
+func (h *InboxHandler) GetNotifications(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	employee, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("get_notifications_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	filter := models.NotificationFilter{Cursor: c.QueryParam("cursor")}
+	if unread := c.QueryParam("unread"); unread != "" {
+		if filter.Unread, err = strconv.ParseBool(unread); err != nil {
+			log.Warnf("get_notifications_failed reason=invalid_query unread=%s", unread)
+			return apperror.InvalidQuery("Invalid unread: expected true or false")
+		}
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 1 {
+			log.Warnf("get_notifications_failed reason=invalid_query limit=%s", limit)
+			return apperror.InvalidQuery("Invalid limit")
+		}
+	}
+
+	page, err := h.inboxService.ListNotifications(c.Request().Context(), employee, filter)
+	if err != nil {
+		var validationErr *utils.ValidationError
+		if errors.As(err, &validationErr) {
+			log.Warnf("get_notifications_failed reason=validation user_id=%s error=%v", employee.ID, err)
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidationFailed, err.Error())
+		}
+		log.Errorf("get_notifications_failed user_id=%s error=%v", employee.ID, err)
+		return apperror.Internal(err)
+	}
+
+	log.Infof("get_notifications_success user_id=%s count=%d total=%d", employee.ID, len(page.Items), page.Total)
+	return writePage(c, page.Items, page.Total, page.NextCursor)
+}
+
+// GetUnreadCount handles GET /api/v1/notifications/unread-count
+func (h *InboxHandler) GetUnreadCount(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	employee, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("get_unread_count_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	count, err := h.inboxService.UnreadCount(c.Request().Context(), employee)
+	if err != nil {
+		log.Errorf("get_unread_count_failed user_id=%s error=%v", employee.ID, err)
+		return apperror.Internal(err)
+	}
+
+	log.Infof("get_unread_count_success user_id=%s unread=%d", employee.ID, count.Unread)
+	return c.JSON(http.StatusOK, count)
+}
+
+// MarkRead handles POST /api/v1/notifications/:id/read
+func (h *InboxHandler) MarkRead(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	employee, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("mark_notification_read_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("mark_notification_read_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
+		return apperror.InvalidID("Invalid notification ID")
+	}
+
+	notification, err := h.inboxService.MarkRead(c.Request().Context(), employee, id)
+	if err != nil {
+		if errors.Is(err, services.ErrNotificationNotFound) {
+			log.Warnf("mark_notification_read_failed reason=not_found user_id=%s notification_id=%s", employee.ID, id)
+			return apperror.New(http.StatusNotFound, apperror.CodeNotificationNotFound, "Notification not found")
+		}
+		log.Errorf("mark_notification_read_failed user_id=%s notification_id=%s error=%v", employee.ID, id, err)
+		return apperror.Internal(err)
+	}
+
+	log.Infof("mark_notification_read_success user_id=%s notification_id=%s", employee.ID, id)
+	return c.JSON(http.StatusOK, notification)
+}
+
+// MarkAllRead handles POST /api/v1/notifications/read-all
+func (h *InboxHandler) MarkAllRead(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	employee, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("mark_all_notifications_read_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	marked, err := h.inboxService.MarkAllRead(c.Request().Context(), employee)
+	if err != nil {
+		log.Errorf("mark_all_notifications_read_failed user_id=%s error=%v", employee.ID, err)
+		return apperror.Internal(err)
+	}
+
+	log.Infof("mark_all_notifications_read_success user_id=%s updated=%d", employee.ID, marked.Updated)
+	return c.JSON(http.StatusOK, marked)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/config"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/notify"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/services"
+)
+
+func TestInboxHandler(t *testing.T) {
+	repo := repository.NewMockNotificationRepository()
+	notifier := services.NewInboxNotifier(notify.NewSMTPNotifier(config.EmailConfig{}), repo)
+	for _, subject := range []string{"First", "Second", "Third"} {
+		notifier.Notify(context.Background(), &notify.Notification{Event: notify.EventCommentAdded, To: []string{"john@example.com"}, Subject: subject})
+	}
+	handler := NewInboxHandler(services.NewInboxService(repo))
+
+	call := func(method, path string, fn func(*InboxHandler) func(echo.Context) error, params ...string) (int, *httptest.ResponseRecorder) {
+		c, rec := setupEchoContext(method, path, nil)
+		c.Set("userID", "emp-1")
+		c.Set("userEmail", "john@example.com")
+		if len(params) > 0 {
+			c.SetParamNames("id")
+			c.SetParamValues(params[0])
+		}
+		err := fn(handler)(c)
+		if appErr, ok := appError(err); ok {
+			return appErr.Status, rec
+		}
+		return rec.Code, rec
+	}
+	list := func(h *InboxHandler) func(echo.Context) error { return h.GetNotifications }
+
+	code, rec := call(http.MethodGet, "/api/v1/notifications?limit=2", list)
+	var page []models.Notification
+	json.Unmarshal(rec.Body.Bytes(), &page)
+	if code != http.StatusOK || len(page) != 2 || rec.Header().Get(HeaderTotalCount) != "3" || rec.Header().Get(HeaderNextCursor) == "" {
+		t.Fatalf("expected a page of 2 of 3 notifications, got %d %s", code, rec.Body.String())
+	}
+
+	for _, query := range []string{"?unread=maybe", "?limit=0", "?cursor=bogus"} {
+		if code, _ := call(http.MethodGet, "/api/v1/notifications"+query, list); code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, code)
+		}
+	}
+
+	markRead := func(h *InboxHandler) func(echo.Context) error { return h.MarkRead }
+	if code, _ := call(http.MethodPost, "/api/v1/notifications/x/read", markRead, page[0].ID.String()); code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", code)
+	}
+	if code, _ := call(http.MethodPost, "/api/v1/notifications/x/read", markRead, uuid.NewString()); code != http.StatusNotFound {
+		t.Errorf("expected status 404 for an unknown notification, got %d", code)
+	}
+	if code, _ := call(http.MethodPost, "/api/v1/notifications/x/read", markRead, "not-a-uuid"); code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid ID, got %d", code)
+	}
+
+	_, rec = call(http.MethodGet, "/api/v1/notifications/unread-count", func(h *InboxHandler) func(echo.Context) error { return h.GetUnreadCount })
+	var count models.UnreadCount
+	json.Unmarshal(rec.Body.Bytes(), &count)
+	if count.Unread != 2 {
+		t.Errorf("expected 2 unread notifications, got %d", count.Unread)
+	}
+
+	_, rec = call(http.MethodPost, "/api/v1/notifications/read-all", func(h *InboxHandler) func(echo.Context) error { return h.MarkAllRead })
+	var marked models.MarkedRead
+	json.Unmarshal(rec.Body.Bytes(), &marked)
+	if marked.Updated != 2 {
+		t.Errorf("expected 2 notifications marked read, got %d", marked.Updated)
+	}
+}
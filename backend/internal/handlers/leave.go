@@ -3,13 +3,14 @@ package handlers
 import (
 	"errors"
 	"net/http"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
 	"leave-management-system/internal/middleware"
 	"leave-management-system/internal/models"
 	"leave-management-system/internal/services"
+	"leave-management-system/internal/utils"
 )
 
 // LeaveHandler handles leave request endpoints
@@ -28,44 +29,38 @@ func NewLeaveHandler(leaveService *services.LeaveService) *LeaveHandler {
 func (h *LeaveHandler) CreateLeaveRequest(c echo.Context) error {
 	log := middleware.GetLogger(c)
 
-	userID, err := middleware.GetUserID(c)
+	employee, err := middleware.GetEmployee(c)
 	if err != nil {
 		log.Warnf("create_leave_failed reason=unauthorized error=%v", err)
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return apperror.Unauthorized(err)
 	}
 
-	userName, _ := middleware.GetUserName(c)
-	userEmail, _ := middleware.GetUserEmail(c)
-
 	var req models.CreateLeaveRequest
 	if err := c.Bind(&req); err != nil {
 		log.Warnf("create_leave_failed reason=invalid_request error=%v", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
-	}
-
-	// Validate dates
-	if req.StartDate.After(req.EndDate) {
-		log.Warnf("create_leave_failed reason=invalid_dates start=%v end=%v", req.StartDate, req.EndDate)
-		return echo.NewHTTPError(http.StatusBadRequest, "Start date must be before end date")
+		return apperror.InvalidBody(err)
 	}
 
-	// Allow same-day leaves but not past dates
-	today := time.Now().Truncate(24 * time.Hour)
-	if req.StartDate.Before(today) {
-		log.Warnf("create_leave_failed reason=past_date start=%v", req.StartDate)
-		return echo.NewHTTPError(http.StatusBadRequest, "Start date cannot be in the past")
+	if err := validateRequest(c, "create_leave_failed", &req); err != nil {
+		return err
 	}
 
-	log.Debugf("create_leave_start leave_type=%s start_date=%v end_date=%v", req.LeaveType, req.StartDate, req.EndDate)
+	log.Debugf("create_leave_start leave_type=%s start_date=%s end_date=%s draft=%t", req.LeaveType, req.StartDate, req.EndDate, req.Draft)
 
-	leave, err := h.leaveService.CreateLeaveRequest(&req, userID, userName, userEmail)
+	// Date rules (range, past dates, notice) are evaluated in the employee's time zone by the service
+	leave, err := h.leaveService.CreateLeaveRequest(c.Request().Context(), &req, employee)
 	if err != nil {
+		var validationErr *utils.ValidationError
+		if errors.As(err, &validationErr) {
+			log.Warnf("create_leave_failed reason=invalid_dates start=%s end=%s error=%v", req.StartDate, req.EndDate, err)
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidationFailed, err.Error())
+		}
 		log.Errorf("create_leave_failed error=%v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return apperror.Internal(err)
 	}
 
-	log.Infof("create_leave_success leave_id=%s days=%d", leave.ID, leave.Days)
-	return c.JSON(http.StatusCreated, leave)
+	log.Infof("create_leave_success leave_id=%s days=%d status=%s", leave.ID, leave.Days, leave.Status)
+	return writeLeave(c, http.StatusCreated, leave)
 }
 
 // GetLeaveRequests handles GET /api/v1/leave
@@ -75,19 +70,30 @@ func (h *LeaveHandler) GetLeaveRequests(c echo.Context) error {
 	userID, err := middleware.GetUserID(c)
 	if err != nil {
 		log.Warnf("get_leaves_failed reason=unauthorized error=%v", err)
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return apperror.Unauthorized(err)
+	}
+
+	filter, err := parseLeaveFilter(c)
+	if err != nil {
+		log.Warnf("get_leaves_failed reason=invalid_query error=%v", err)
+		return apperror.InvalidQuery(err.Error())
 	}
 
 	log.Debug("get_leaves_start")
 
-	leaves, err := h.leaveService.GetLeaveRequestsByEmployeeID(userID)
+	page, err := h.leaveService.GetLeaveRequestsByEmployeeID(c.Request().Context(), userID, filter)
 	if err != nil {
+		var validationErr *utils.ValidationError
+		if errors.As(err, &validationErr) {
+			log.Warnf("get_leaves_failed reason=invalid_query error=%v", err)
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidationFailed, err.Error())
+		}
 		log.Errorf("get_leaves_failed error=%v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return apperror.Internal(err)
 	}
 
-	log.Infof("get_leaves_success count=%d", len(leaves))
-	return c.JSON(http.StatusOK, leaves)
+	log.Infof("get_leaves_success count=%d total=%d", len(page.Items), page.Total)
+	return writeLeavePage(c, page)
 }
 
 // GetLeaveRequest handles GET /api/v1/leave/:id
@@ -97,35 +103,35 @@ func (h *LeaveHandler) GetLeaveRequest(c echo.Context) error {
 	userID, err := middleware.GetUserID(c)
 	if err != nil {
 		log.Warnf("get_leave_failed reason=unauthorized error=%v", err)
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return apperror.Unauthorized(err)
 	}
 
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		log.Warnf("get_leave_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid leave request ID")
+		return apperror.InvalidID("Invalid leave request ID")
 	}
 
 	log.Debugf("get_leave_start leave_id=%s", id)
 
-	leave, err := h.leaveService.GetLeaveRequestByID(id)
+	leave, err := h.leaveService.GetLeaveRequestByID(c.Request().Context(), id)
 	if err != nil {
 		if errors.Is(err, services.ErrLeaveNotFound) {
 			log.Warnf("get_leave_failed reason=not_found leave_id=%s", id)
-			return echo.NewHTTPError(http.StatusNotFound, "Leave request not found")
+			return apperror.New(http.StatusNotFound, apperror.CodeLeaveNotFound, "Leave request not found")
 		}
 		log.Errorf("get_leave_failed leave_id=%s error=%v", id, err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return apperror.Internal(err)
 	}
 
 	// Verify ownership
 	if leave.EmployeeID != userID {
 		log.Warnf("get_leave_failed reason=forbidden leave_id=%s owner=%s requester=%s", id, leave.EmployeeID, userID)
-		return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+		return apperror.New(http.StatusForbidden, apperror.CodeForbidden, "Access denied")
 	}
 
 	log.Infof("get_leave_success leave_id=%s", id)
-	return c.JSON(http.StatusOK, leave)
+	return writeLeave(c, http.StatusOK, leave)
 }
 
 // UpdateLeaveRequest handles PUT /api/v1/leave/:id
@@ -135,58 +141,115 @@ func (h *LeaveHandler) UpdateLeaveRequest(c echo.Context) error {
 	userID, err := middleware.GetUserID(c)
 	if err != nil {
 		log.Warnf("update_leave_failed reason=unauthorized error=%v", err)
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return apperror.Unauthorized(err)
 	}
 
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		log.Warnf("update_leave_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid leave request ID")
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		log.Warnf("update_leave_failed reason=if_match leave_id=%s error=%v", id, err)
+		return err
 	}
 
 	var req models.UpdateLeaveRequest
 	if err := c.Bind(&req); err != nil {
 		log.Warnf("update_leave_failed reason=invalid_request leave_id=%s error=%v", id, err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+		return apperror.InvalidBody(err)
 	}
 
-	// Validate dates if provided
-	if req.StartDate != nil && req.EndDate != nil {
-		if req.StartDate.After(*req.EndDate) {
-			log.Warnf("update_leave_failed reason=invalid_dates leave_id=%s start=%v end=%v", id, req.StartDate, req.EndDate)
-			return echo.NewHTTPError(http.StatusBadRequest, "Start date must be before end date")
-		}
-	} else if req.StartDate != nil {
-		// If only start date is provided, check it's not in the past
-		today := time.Now().Truncate(24 * time.Hour)
-		if req.StartDate.Before(today) {
-			log.Warnf("update_leave_failed reason=past_date leave_id=%s start=%v", id, req.StartDate)
-			return echo.NewHTTPError(http.StatusBadRequest, "Start date cannot be in the past")
-		}
+	if err := validateRequest(c, "update_leave_failed", &req); err != nil {
+		return err
 	}
 
 	log.Debugf("update_leave_start leave_id=%s", id)
 
-	leave, err := h.leaveService.UpdateLeaveRequest(id, userID, &req)
+	leave, err := h.leaveService.UpdateLeaveRequest(c.Request().Context(), id, version, userID, &req)
 	if err != nil {
 		if errors.Is(err, services.ErrLeaveNotFound) {
 			log.Warnf("update_leave_failed reason=not_found leave_id=%s", id)
-			return echo.NewHTTPError(http.StatusNotFound, "Leave request not found")
+			return apperror.New(http.StatusNotFound, apperror.CodeLeaveNotFound, "Leave request not found")
 		}
 		if errors.Is(err, services.ErrUnauthorizedAction) {
 			log.Warnf("update_leave_failed reason=forbidden leave_id=%s user_id=%s", id, userID)
-			return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+			return apperror.New(http.StatusForbidden, apperror.CodeForbidden, "Access denied")
 		}
 		if errors.Is(err, services.ErrInvalidStatus) {
 			log.Warnf("update_leave_failed reason=invalid_status leave_id=%s error=%v", id, err)
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidStatusTransition, err.Error())
+		}
+		if errors.Is(err, services.ErrPreconditionFailed) {
+			log.Warnf("update_leave_failed reason=stale_version leave_id=%s version=%d", id, version)
+			return apperror.New(http.StatusPreconditionFailed, apperror.CodePreconditionFailed, err.Error())
+		}
+		if errors.Is(err, services.ErrConflict) {
+			log.Warnf("update_leave_failed reason=conflict leave_id=%s", id)
+			return apperror.New(http.StatusConflict, apperror.CodeConflict, err.Error())
+		}
+		var validationErr *utils.ValidationError
+		if errors.As(err, &validationErr) {
+			log.Warnf("update_leave_failed reason=invalid_dates leave_id=%s error=%v", id, err)
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidationFailed, err.Error())
 		}
 		log.Errorf("update_leave_failed leave_id=%s error=%v", id, err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return apperror.Internal(err)
 	}
 
 	log.Infof("update_leave_success leave_id=%s", id)
-	return c.JSON(http.StatusOK, leave)
+	return writeLeave(c, http.StatusOK, leave)
+}
+
+// SubmitLeaveRequest handles POST /api/v1/leave/:id/submit
+func (h *LeaveHandler) SubmitLeaveRequest(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	employee, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("submit_leave_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("submit_leave_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	log.Debugf("submit_leave_start leave_id=%s", id)
+
+	leave, err := h.leaveService.SubmitLeaveRequest(c.Request().Context(), id, employee)
+	if err != nil {
+		if errors.Is(err, services.ErrLeaveNotFound) {
+			log.Warnf("submit_leave_failed reason=not_found leave_id=%s", id)
+			return apperror.New(http.StatusNotFound, apperror.CodeLeaveNotFound, "Leave request not found")
+		}
+		if errors.Is(err, services.ErrUnauthorizedAction) {
+			log.Warnf("submit_leave_failed reason=forbidden leave_id=%s user_id=%s", id, employee.ID)
+			return apperror.New(http.StatusForbidden, apperror.CodeForbidden, "Access denied")
+		}
+		if errors.Is(err, services.ErrInvalidStatus) {
+			log.Warnf("submit_leave_failed reason=invalid_status leave_id=%s error=%v", id, err)
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidStatusTransition, err.Error())
+		}
+		if errors.Is(err, services.ErrConflict) {
+			log.Warnf("submit_leave_failed reason=conflict leave_id=%s", id)
+			return apperror.New(http.StatusConflict, apperror.CodeConflict, err.Error())
+		}
+		var validationErr *utils.ValidationError
+		if errors.As(err, &validationErr) {
+			log.Warnf("submit_leave_failed reason=invalid_dates leave_id=%s error=%v", id, err)
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidationFailed, err.Error())
+		}
+		log.Errorf("submit_leave_failed leave_id=%s error=%v", id, err)
+		return apperror.Internal(err)
+	}
+
+	log.Infof("submit_leave_success leave_id=%s days=%d", id, leave.Days)
+	return writeLeave(c, http.StatusOK, leave)
 }
 
 // CancelLeaveRequest handles DELETE /api/v1/leave/:id
@@ -196,35 +259,49 @@ func (h *LeaveHandler) CancelLeaveRequest(c echo.Context) error {
 	userID, err := middleware.GetUserID(c)
 	if err != nil {
 		log.Warnf("cancel_leave_failed reason=unauthorized error=%v", err)
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return apperror.Unauthorized(err)
 	}
 
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		log.Warnf("cancel_leave_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid leave request ID")
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		log.Warnf("cancel_leave_failed reason=if_match leave_id=%s error=%v", id, err)
+		return err
 	}
 
 	log.Debugf("cancel_leave_start leave_id=%s", id)
 
-	err = h.leaveService.CancelLeaveRequest(id, userID)
+	err = h.leaveService.CancelLeaveRequest(c.Request().Context(), id, version, userID)
 	if err != nil {
 		if errors.Is(err, services.ErrLeaveNotFound) {
 			log.Warnf("cancel_leave_failed reason=not_found leave_id=%s", id)
-			return echo.NewHTTPError(http.StatusNotFound, "Leave request not found")
+			return apperror.New(http.StatusNotFound, apperror.CodeLeaveNotFound, "Leave request not found")
 		}
 		if errors.Is(err, services.ErrUnauthorizedAction) {
 			log.Warnf("cancel_leave_failed reason=forbidden leave_id=%s user_id=%s", id, userID)
-			return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+			return apperror.New(http.StatusForbidden, apperror.CodeForbidden, "Access denied")
 		}
 		if errors.Is(err, services.ErrInvalidStatus) {
 			log.Warnf("cancel_leave_failed reason=invalid_status leave_id=%s error=%v", id, err)
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidStatusTransition, err.Error())
+		}
+		if errors.Is(err, services.ErrPreconditionFailed) {
+			log.Warnf("cancel_leave_failed reason=stale_version leave_id=%s version=%d", id, version)
+			return apperror.New(http.StatusPreconditionFailed, apperror.CodePreconditionFailed, err.Error())
+		}
+		if errors.Is(err, services.ErrConflict) {
+			log.Warnf("cancel_leave_failed reason=conflict leave_id=%s", id)
+			return apperror.New(http.StatusConflict, apperror.CodeConflict, err.Error())
 		}
 		log.Errorf("cancel_leave_failed leave_id=%s error=%v", id, err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return apperror.Internal(err)
 	}
 
 	log.Infof("cancel_leave_success leave_id=%s", id)
-	return c.String(http.StatusNoContent, "")
+	return c.NoContent(http.StatusNoContent)
 }
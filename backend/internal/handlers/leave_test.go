@@ -2,28 +2,48 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/middleware"
 	"leave-management-system/internal/models"
 	"leave-management-system/internal/repository"
 	"leave-management-system/internal/services"
+	"leave-management-system/internal/utils"
 )
 
 func setupTestHandler() (*LeaveHandler, *repository.MockLeaveRepository) {
 	repo := repository.NewMockLeaveRepository()
-	service := services.NewLeaveService(repo)
+	service := services.NewLeaveService(repository.NewMockUnitOfWork(repo, nil), services.DefaultLeavePolicy())
 	handler := NewLeaveHandler(service)
 	return handler, repo
 }
 
-func setupEchoContext(method, path string, body interface{}) (echo.Context, *httptest.ResponseRecorder) {
+// appError returns the error a handler reports to the client, if it failed
+func appError(err error) (*apperror.Error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	return apperror.From(err), true
+}
+
+// newTestEcho creates an Echo instance with the request validator registered
+func newTestEcho() *echo.Echo {
 	e := echo.New()
+	e.Validator = utils.NewRequestValidator(services.DefaultLeavePolicy().Location)
+	return e
+}
+
+func setupEchoContext(method, path string, body interface{}) (echo.Context, *httptest.ResponseRecorder) {
+	e := newTestEcho()
 	var reqBody []byte
 	if body != nil {
 		reqBody, _ = json.Marshal(body)
@@ -50,8 +70,8 @@ func TestLeaveHandler_CreateLeaveRequest(t *testing.T) {
 			body: map[string]interface{}{
 				"leaveType": "annual",
 				"reason":    "Vacation time",
-				"startDate": "2024-01-01T00:00:00Z",
-				"endDate":   "2024-01-05T00:00:00Z",
+				"startDate": futureDate(7),
+				"endDate":   futureDate(11),
 			},
 			setupContext: func(c echo.Context) {
 				c.Set("userID", "emp-1")
@@ -104,13 +124,13 @@ func TestLeaveHandler_CreateLeaveRequest(t *testing.T) {
 					t.Errorf("expected error but got none")
 					return
 				}
-				he, ok := err.(*echo.HTTPError)
+				he, ok := appError(err)
 				if !ok {
-					t.Errorf("expected HTTPError, got %T", err)
+					t.Errorf("expected an error, got %v", err)
 					return
 				}
-				if he.Code != tt.wantStatusCode {
-					t.Errorf("expected status code %d, got %d", tt.wantStatusCode, he.Code)
+				if he.Status != tt.wantStatusCode {
+					t.Errorf("expected status code %d, got %d", tt.wantStatusCode, he.Status)
 				}
 				return
 			}
@@ -139,14 +159,14 @@ func TestLeaveHandler_GetLeaveRequests(t *testing.T) {
 		EmployeeEmail: "john@example.com",
 		LeaveType:     models.LeaveTypeAnnual,
 		Reason:        "Vacation",
-		StartDate:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-		EndDate:       time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		StartDate:     models.NewDate(2024, 1, 1),
+		EndDate:       models.NewDate(2024, 1, 5),
 		Days:          5,
 		Status:        models.LeaveStatusPending,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
-	repo.Create(leave)
+	repo.Create(context.Background(), leave)
 
 	tests := []struct {
 		name           string
@@ -218,14 +238,14 @@ func TestLeaveHandler_GetLeaveRequest(t *testing.T) {
 		EmployeeEmail: "john@example.com",
 		LeaveType:     models.LeaveTypeAnnual,
 		Reason:        "Vacation",
-		StartDate:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-		EndDate:       time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		StartDate:     models.NewDate(2024, 1, 1),
+		EndDate:       models.NewDate(2024, 1, 5),
 		Days:          5,
 		Status:        models.LeaveStatusPending,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
-	repo.Create(leave)
+	repo.Create(context.Background(), leave)
 
 	tests := []struct {
 		name           string
@@ -273,13 +293,13 @@ func TestLeaveHandler_GetLeaveRequest(t *testing.T) {
 					t.Errorf("expected error but got none")
 					return
 				}
-				he, ok := err.(*echo.HTTPError)
+				he, ok := appError(err)
 				if !ok {
-					t.Errorf("expected HTTPError, got %T", err)
+					t.Errorf("expected an error, got %v", err)
 					return
 				}
-				if he.Code != tt.wantStatusCode {
-					t.Errorf("expected status code %d, got %d", tt.wantStatusCode, he.Code)
+				if he.Status != tt.wantStatusCode {
+					t.Errorf("expected status code %d, got %d", tt.wantStatusCode, he.Status)
 				}
 				return
 			}
@@ -307,7 +327,7 @@ func TestLeaveHandler_UpdateLeaveRequest(t *testing.T) {
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
-	repo.Create(leave)
+	repo.Create(context.Background(), leave)
 
 	tests := []struct {
 		name           string
@@ -344,11 +364,12 @@ func TestLeaveHandler_UpdateLeaveRequest(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Reset status to pending
 			leave.Status = models.LeaveStatusPending
-			repo.Update(leave)
+			repo.Create(context.Background(), leave)
 
 			c, rec := setupEchoContext(http.MethodPut, "/api/v1/leave/:id", tt.body)
 			c.SetParamNames("id")
 			c.SetParamValues(tt.id)
+			c.Request().Header.Set(HeaderIfMatch, leaveETag(leave))
 			tt.setupContext(c)
 
 			err := handler.UpdateLeaveRequest(c)
@@ -384,7 +405,7 @@ func TestLeaveHandler_CancelLeaveRequest(t *testing.T) {
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
-	repo.Create(leave)
+	repo.Create(context.Background(), leave)
 
 	tests := []struct {
 		name           string
@@ -414,11 +435,12 @@ func TestLeaveHandler_CancelLeaveRequest(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Reset status to pending
 			leave.Status = models.LeaveStatusPending
-			repo.Update(leave)
+			repo.Create(context.Background(), leave)
 
 			c, rec := setupEchoContext(http.MethodDelete, "/api/v1/leave/:id", nil)
 			c.SetParamNames("id")
 			c.SetParamValues(tt.id)
+			c.Request().Header.Set(HeaderIfMatch, leaveETag(leave))
 			tt.setupContext(c)
 
 			err := handler.CancelLeaveRequest(c)
@@ -443,3 +465,184 @@ func TestLeaveHandler_CancelLeaveRequest(t *testing.T) {
 	}
 }
 
+func TestLeaveHandler_SubmitLeaveRequest(t *testing.T) {
+	handler, repo := setupTestHandler()
+
+	monday := models.Today(time.UTC).AddDays(7)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDays(1)
+	}
+
+	draftID := uuid.New()
+	repo.Create(context.Background(), &models.LeaveRequest{
+		ID:         draftID,
+		EmployeeID: "emp-1",
+		LeaveType:  models.LeaveTypeAnnual,
+		StartDate:  monday,
+		EndDate:    monday.AddDays(1),
+		Status:     models.LeaveStatusDraft,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	})
+
+	incompleteID := uuid.New()
+	repo.Create(context.Background(), &models.LeaveRequest{
+		ID:         incompleteID,
+		EmployeeID: "emp-1",
+		LeaveType:  models.LeaveTypeAnnual,
+		Status:     models.LeaveStatusDraft,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	})
+
+	tests := []struct {
+		name           string
+		id             string
+		userID         string
+		wantStatusCode int
+	}{
+		{"submit another user's draft", draftID.String(), "emp-2", http.StatusForbidden},
+		{"submit draft without dates", incompleteID.String(), "emp-1", http.StatusBadRequest},
+		{"submit draft", draftID.String(), "emp-1", http.StatusOK},
+		{"submit already submitted request", draftID.String(), "emp-1", http.StatusBadRequest},
+		{"submit unknown request", uuid.New().String(), "emp-1", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := setupEchoContext(http.MethodPost, "/api/v1/leave/:id/submit", nil)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			c.Set("userID", tt.userID)
+
+			err := handler.SubmitLeaveRequest(c)
+
+			if tt.wantStatusCode >= 400 {
+				httpErr, ok := appError(err)
+				if !ok {
+					t.Fatalf("expected an error, got %v", err)
+				}
+				if httpErr.Status != tt.wantStatusCode {
+					t.Errorf("expected status code %d, got %d", tt.wantStatusCode, httpErr.Status)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status code %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+
+			var leave models.LeaveRequestJSON
+			if err := json.Unmarshal(rec.Body.Bytes(), &leave); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if leave.Status != models.LeaveStatusPending {
+				t.Errorf("expected status pending, got %s", leave.Status)
+			}
+		})
+	}
+}
+
+func TestLeaveHandler_GetLeaveRequests_Pagination(t *testing.T) {
+	handler, repo := setupTestHandler()
+
+	for i := 0; i < 3; i++ {
+		repo.Create(context.Background(), &models.LeaveRequest{
+			ID:         uuid.New(),
+			EmployeeID: "emp-1",
+			Status:     models.LeaveStatusPending,
+			CreatedAt:  time.Now().Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	c, rec := setupEchoContext(http.MethodGet, "/api/v1/leave?limit=2&status=pending", nil)
+	c.Set("userID", "emp-1")
+
+	if err := handler.GetLeaveRequests(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var leaves []models.LeaveRequestJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &leaves); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(leaves) != 2 {
+		t.Errorf("expected 2 requests, got %d", len(leaves))
+	}
+	if got := rec.Header().Get(HeaderTotalCount); got != "3" {
+		t.Errorf("expected total count 3, got %q", got)
+	}
+	cursor := rec.Header().Get(HeaderNextCursor)
+	if cursor == "" {
+		t.Fatalf("expected a next cursor")
+	}
+	if link := rec.Header().Get("Link"); !strings.Contains(link, "cursor="+cursor) || !strings.Contains(link, `rel="next"`) {
+		t.Errorf("unexpected Link header %q", link)
+	}
+
+	c, rec = setupEchoContext(http.MethodGet, "/api/v1/leave?limit=2&status=pending&cursor="+cursor, nil)
+	c.Set("userID", "emp-1")
+
+	if err := handler.GetLeaveRequests(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &leaves); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(leaves) != 1 || rec.Header().Get(HeaderNextCursor) != "" {
+		t.Errorf("expected a last page with 1 request, got %d", len(leaves))
+	}
+
+	for _, query := range []string{"order=sideways", "limit=0", "sort=reason", "from=tomorrow"} {
+		c, _ = setupEchoContext(http.MethodGet, "/api/v1/leave?"+query, nil)
+		c.Set("userID", "emp-1")
+		err := handler.GetLeaveRequests(c)
+		if httpErr, ok := appError(err); !ok || httpErr.Status != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %v", query, err)
+		}
+	}
+}
+
+func TestLeaveHandler_CreateLeaveRequest_FieldErrors(t *testing.T) {
+	handler, _ := setupTestHandler()
+
+	c, rec := setupEchoContext(http.MethodPost, "/api/v1/leave", map[string]interface{}{
+		"leaveType": "sabbatical",
+		"reason":    "Rest",
+		"startDate": futureDate(5),
+		"endDate":   futureDate(3),
+	})
+	c.Set("userID", "emp-1")
+
+	err := handler.CreateLeaveRequest(c)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	middleware.ErrorHandler(false)(err, c)
+
+	if rec.Code != http.StatusBadRequest || rec.Header().Get(echo.HeaderContentType) != apperror.MIMEProblemJSON {
+		t.Fatalf("expected a 400 problem, got %d %s", rec.Code, rec.Header().Get(echo.HeaderContentType))
+	}
+	var problem apperror.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("failed to unmarshal problem: %v", err)
+	}
+	if problem.Code != apperror.CodeValidationFailed {
+		t.Errorf("expected code %s, got %s", apperror.CodeValidationFailed, problem.Code)
+	}
+	fields := make(map[string]string)
+	for _, fieldErr := range problem.Errors {
+		fields[fieldErr.Field] = fieldErr.Rule
+	}
+	if fields["leaveType"] != "leavetype" || fields["reason"] != "min" || fields["endDate"] != "onorafter" {
+		t.Errorf("unexpected field errors: %s", rec.Body.String())
+	}
+}
+
+// futureDate returns an RFC 3339 date the given number of days from today
+func futureDate(days int) string {
+	return time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, days).Format(time.RFC3339)
+}
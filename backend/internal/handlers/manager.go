@@ -7,22 +7,23 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
 	"leave-management-system/internal/middleware"
 	"leave-management-system/internal/models"
 	"leave-management-system/internal/services"
+	"leave-management-system/internal/utils"
 )
 
 // ManagerHandler handles manager leave endpoints
 type ManagerHandler struct {
 	leaveService *services.LeaveService
-	emailService *services.EmailService
 }
 
-// NewManagerHandler creates a new manager handler
-func NewManagerHandler(leaveService *services.LeaveService, emailService *services.EmailService) *ManagerHandler {
+// NewManagerHandler creates a new manager handler. Decision emails are queued
+// by the leave service and sent by the outbox dispatcher.
+func NewManagerHandler(leaveService *services.LeaveService) *ManagerHandler {
 	return &ManagerHandler{
 		leaveService: leaveService,
-		emailService: emailService,
 	}
 }
 
@@ -30,117 +31,206 @@ func NewManagerHandler(leaveService *services.LeaveService, emailService *servic
 func (h *ManagerHandler) GetPendingLeaveRequests(c echo.Context) error {
 	log := middleware.GetLogger(c)
 
+	filter, err := parseLeaveFilter(c)
+	if err != nil {
+		log.Warnf("get_pending_leaves_failed reason=invalid_query error=%v", err)
+		return apperror.InvalidQuery(err.Error())
+	}
+
 	log.Debug("get_pending_leaves_start")
 
-	leaves, err := h.leaveService.GetPendingLeaveRequests()
+	page, err := h.leaveService.GetPendingLeaveRequests(c.Request().Context(), filter)
 	if err != nil {
+		var validationErr *utils.ValidationError
+		if errors.As(err, &validationErr) {
+			log.Warnf("get_pending_leaves_failed reason=invalid_query error=%v", err)
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidationFailed, err.Error())
+		}
 		log.Errorf("get_pending_leaves_failed error=%v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return apperror.Internal(err)
 	}
 
-	log.Infof("get_pending_leaves_success count=%d", len(leaves))
-	return c.JSON(http.StatusOK, leaves)
+	log.Infof("get_pending_leaves_success count=%d total=%d", len(page.Items), page.Total)
+	return writeLeavePage(c, page)
 }
 
 // ApproveLeaveRequest handles PUT /api/v1/manager/leave/:id/approve
 func (h *ManagerHandler) ApproveLeaveRequest(c echo.Context) error {
 	log := middleware.GetLogger(c)
 
+	reviewer, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("approve_leave_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		log.Warnf("approve_leave_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid leave request ID")
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		log.Warnf("approve_leave_failed reason=if_match leave_id=%s error=%v", id, err)
+		return err
 	}
 
 	var req models.ApproveLeaveRequest
 	if err := c.Bind(&req); err != nil {
 		log.Warnf("approve_leave_failed reason=invalid_request leave_id=%s error=%v", id, err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+		return apperror.InvalidBody(err)
 	}
 
 	log.Debugf("approve_leave_start leave_id=%s", id)
 
-	leave, err := h.leaveService.ApproveLeaveRequest(id, strings.TrimSpace(req.Comment))
+	leave, err := h.leaveService.ApproveLeaveRequest(c.Request().Context(), id, version, reviewer, strings.TrimSpace(req.Comment))
 	if err != nil {
 		if errors.Is(err, services.ErrLeaveNotFound) {
 			log.Warnf("approve_leave_failed reason=not_found leave_id=%s", id)
-			return echo.NewHTTPError(http.StatusNotFound, "Leave request not found")
+			return apperror.New(http.StatusNotFound, apperror.CodeLeaveNotFound, "Leave request not found")
+		}
+		if errors.Is(err, services.ErrSelfReview) {
+			log.Warnf("approve_leave_failed reason=self_review leave_id=%s", id)
+			return apperror.New(http.StatusForbidden, apperror.CodeForbidden, err.Error())
+		}
+		if errors.Is(err, services.ErrNotAssignedApprover) {
+			log.Warnf("approve_leave_failed reason=not_assigned_approver leave_id=%s approver_id=%s", id, reviewer.ID)
+			return apperror.New(http.StatusForbidden, apperror.CodeForbidden, err.Error())
 		}
 		if errors.Is(err, services.ErrInvalidStatus) {
 			log.Warnf("approve_leave_failed reason=invalid_status leave_id=%s error=%v", id, err)
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidStatusTransition, err.Error())
+		}
+		if errors.Is(err, services.ErrPreconditionFailed) {
+			log.Warnf("approve_leave_failed reason=stale_version leave_id=%s version=%d", id, version)
+			return apperror.New(http.StatusPreconditionFailed, apperror.CodePreconditionFailed, err.Error())
+		}
+		if errors.Is(err, services.ErrConflict) {
+			log.Warnf("approve_leave_failed reason=conflict leave_id=%s", id)
+			return apperror.New(http.StatusConflict, apperror.CodeConflict, err.Error())
 		}
 		log.Errorf("approve_leave_failed leave_id=%s error=%v", id, err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return apperror.Internal(err)
 	}
 
 	log.Infof("approve_leave_success leave_id=%s employee_id=%s", id, leave.EmployeeID)
 
-	// Send email notification (non-blocking)
-	go func() {
-		if err := h.emailService.SendLeaveApprovalEmail(leave); err != nil {
-			log.Errorf("email_send_failed type=approval leave_id=%s employee_email=%s error=%v", id, leave.EmployeeEmail, err)
-		} else {
-			log.Debugf("email_sent type=approval leave_id=%s employee_email=%s", id, leave.EmployeeEmail)
-		}
-	}()
-
-	return c.JSON(http.StatusOK, leave)
+	return writeLeave(c, http.StatusOK, leave)
 }
 
 // RejectLeaveRequest handles PUT /api/v1/manager/leave/:id/reject
 func (h *ManagerHandler) RejectLeaveRequest(c echo.Context) error {
 	log := middleware.GetLogger(c)
 
+	reviewer, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("reject_leave_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		log.Warnf("reject_leave_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid leave request ID")
+		return apperror.InvalidID("Invalid leave request ID")
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		log.Warnf("reject_leave_failed reason=if_match leave_id=%s error=%v", id, err)
+		return err
 	}
 
 	var req models.RejectLeaveRequest
 	if err := c.Bind(&req); err != nil {
 		log.Warnf("reject_leave_failed reason=invalid_request leave_id=%s error=%v", id, err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
-	}
-
-	comment := strings.TrimSpace(req.Comment)
-	if comment == "" {
-		log.Warnf("reject_leave_failed reason=missing_comment leave_id=%s", id)
-		return echo.NewHTTPError(http.StatusBadRequest, "Comment is required for rejection")
+		return apperror.InvalidBody(err)
 	}
 
-	if len(comment) < 10 {
-		log.Warnf("reject_leave_failed reason=comment_too_short leave_id=%s comment_len=%d", id, len(comment))
-		return echo.NewHTTPError(http.StatusBadRequest, "Comment must be at least 10 characters")
+	req.Comment = strings.TrimSpace(req.Comment)
+	if err := validateRequest(c, "reject_leave_failed", &req); err != nil {
+		return err
 	}
 
 	log.Debugf("reject_leave_start leave_id=%s", id)
 
-	leave, err := h.leaveService.RejectLeaveRequest(id, comment)
+	leave, err := h.leaveService.RejectLeaveRequest(c.Request().Context(), id, version, reviewer, req.Comment)
 	if err != nil {
 		if errors.Is(err, services.ErrLeaveNotFound) {
 			log.Warnf("reject_leave_failed reason=not_found leave_id=%s", id)
-			return echo.NewHTTPError(http.StatusNotFound, "Leave request not found")
+			return apperror.New(http.StatusNotFound, apperror.CodeLeaveNotFound, "Leave request not found")
+		}
+		if errors.Is(err, services.ErrSelfReview) {
+			log.Warnf("reject_leave_failed reason=self_review leave_id=%s", id)
+			return apperror.New(http.StatusForbidden, apperror.CodeForbidden, err.Error())
+		}
+		if errors.Is(err, services.ErrNotAssignedApprover) {
+			log.Warnf("reject_leave_failed reason=not_assigned_approver leave_id=%s approver_id=%s", id, reviewer.ID)
+			return apperror.New(http.StatusForbidden, apperror.CodeForbidden, err.Error())
 		}
 		if errors.Is(err, services.ErrInvalidStatus) {
 			log.Warnf("reject_leave_failed reason=invalid_status leave_id=%s error=%v", id, err)
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return apperror.New(http.StatusBadRequest, apperror.CodeInvalidStatusTransition, err.Error())
+		}
+		if errors.Is(err, services.ErrPreconditionFailed) {
+			log.Warnf("reject_leave_failed reason=stale_version leave_id=%s version=%d", id, version)
+			return apperror.New(http.StatusPreconditionFailed, apperror.CodePreconditionFailed, err.Error())
+		}
+		if errors.Is(err, services.ErrConflict) {
+			log.Warnf("reject_leave_failed reason=conflict leave_id=%s", id)
+			return apperror.New(http.StatusConflict, apperror.CodeConflict, err.Error())
 		}
 		log.Errorf("reject_leave_failed leave_id=%s error=%v", id, err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return apperror.Internal(err)
 	}
 
 	log.Infof("reject_leave_success leave_id=%s employee_id=%s", id, leave.EmployeeID)
 
-	// Send email notification (non-blocking)
-	go func() {
-		if err := h.emailService.SendLeaveRejectionEmail(leave); err != nil {
-			log.Errorf("email_send_failed type=rejection leave_id=%s employee_email=%s error=%v", id, leave.EmployeeEmail, err)
-		} else {
-			log.Debugf("email_sent type=rejection leave_id=%s employee_email=%s", id, leave.EmployeeEmail)
+	return writeLeave(c, http.StatusOK, leave)
+}
+
+// BulkReviewLeaveRequests handles POST /api/v1/manager/leave/bulk
+func (h *ManagerHandler) BulkReviewLeaveRequests(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	reviewer, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("bulk_review_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	var req models.BulkLeaveRequest
+	if err := c.Bind(&req); err != nil {
+		log.Warnf("bulk_review_failed reason=invalid_request error=%v", err)
+		return apperror.InvalidBody(err)
+	}
+
+	if err := validateRequest(c, "bulk_review_failed", &req); err != nil {
+		return err
+	}
+
+	log.Debugf("bulk_review_start action=%s count=%d", req.Action, len(req.Items))
+
+	results, err := h.leaveService.BulkReviewLeaveRequests(c.Request().Context(), req.Action, req.Items, reviewer)
+	if err != nil {
+		var validationErr *utils.ValidationError
+		if errors.As(err, &validationErr) {
+			log.Warnf("bulk_review_failed reason=invalid_request error=%v", err)
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidationFailed, err.Error())
 		}
-	}()
+		log.Errorf("bulk_review_failed error=%v", err)
+		return apperror.Internal(err)
+	}
+
+	counts := make(map[models.BulkLeaveOutcome]int)
+	for _, result := range results {
+		counts[result.Outcome]++
+	}
+
+	log.Infof("bulk_review_success action=%s count=%d approved=%d rejected=%d already_processed=%d not_found=%d policy_violation=%d error=%d",
+		req.Action, len(results), counts[models.BulkOutcomeApproved], counts[models.BulkOutcomeRejected],
+		counts[models.BulkOutcomeAlreadyProcessed], counts[models.BulkOutcomeNotFound],
+		counts[models.BulkOutcomePolicyViolation], counts[models.BulkOutcomeError])
 
-	return c.JSON(http.StatusOK, leave)
+	return c.JSON(http.StatusOK, results)
 }
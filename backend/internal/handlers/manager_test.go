@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -10,7 +11,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"leave-management-system/internal/config"
+	"leave-management-system/internal/apperror"
 	"leave-management-system/internal/models"
 	"leave-management-system/internal/repository"
 	"leave-management-system/internal/services"
@@ -18,14 +19,13 @@ import (
 
 func setupTestManagerHandler() (*ManagerHandler, *repository.MockLeaveRepository) {
 	repo := repository.NewMockLeaveRepository()
-	leaveService := services.NewLeaveService(repo)
-	emailService := services.NewEmailService(&config.Config{})
-	handler := NewManagerHandler(leaveService, emailService)
+	leaveService := services.NewLeaveService(repository.NewMockUnitOfWork(repo, nil), services.DefaultLeavePolicy())
+	handler := NewManagerHandler(leaveService)
 	return handler, repo
 }
 
 func setupEchoContextForManager(method, path string, body interface{}) (echo.Context, *httptest.ResponseRecorder) {
-	e := echo.New()
+	e := newTestEcho()
 	var reqBody []byte
 	if body != nil {
 		reqBody, _ = json.Marshal(body)
@@ -34,6 +34,8 @@ func setupEchoContextForManager(method, path string, body interface{}) (echo.Con
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
+	c.Set("userID", "mgr-1")
+	c.Set("userRoles", []string{"manager"})
 	return c, rec
 }
 
@@ -63,9 +65,9 @@ func TestManagerHandler_GetPendingLeaveRequests(t *testing.T) {
 		UpdatedAt:     time.Now(),
 	}
 
-	repo.Create(leave1)
-	repo.Create(leave2)
-	repo.Create(leave3)
+	repo.Create(context.Background(), leave1)
+	repo.Create(context.Background(), leave2)
+	repo.Create(context.Background(), leave3)
 
 	c, rec := setupEchoContextForManager(http.MethodGet, "/api/v1/manager/leave", nil)
 
@@ -110,7 +112,7 @@ func TestManagerHandler_ApproveLeaveRequest(t *testing.T) {
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
-	repo.Create(leave)
+	repo.Create(context.Background(), leave)
 
 	tests := []struct {
 		name           string
@@ -152,11 +154,12 @@ func TestManagerHandler_ApproveLeaveRequest(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Reset status to pending
 			leave.Status = models.LeaveStatusPending
-			repo.Update(leave)
+			repo.Create(context.Background(), leave)
 
 			c, rec := setupEchoContextForManager(http.MethodPut, "/api/v1/manager/leave/:id/approve", tt.body)
 			c.SetParamNames("id")
 			c.SetParamValues(tt.id)
+			c.Request().Header.Set(HeaderIfMatch, leaveETag(leave))
 
 			err := handler.ApproveLeaveRequest(c)
 
@@ -165,13 +168,13 @@ func TestManagerHandler_ApproveLeaveRequest(t *testing.T) {
 					t.Errorf("expected error but got none")
 					return
 				}
-				he, ok := err.(*echo.HTTPError)
+				he, ok := appError(err)
 				if !ok {
-					t.Errorf("expected HTTPError, got %T", err)
+					t.Errorf("expected an error, got %v", err)
 					return
 				}
-				if he.Code != tt.wantStatusCode {
-					t.Errorf("expected status code %d, got %d", tt.wantStatusCode, he.Code)
+				if he.Status != tt.wantStatusCode {
+					t.Errorf("expected status code %d, got %d", tt.wantStatusCode, he.Status)
 				}
 				return
 			}
@@ -208,7 +211,7 @@ func TestManagerHandler_RejectLeaveRequest(t *testing.T) {
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
-	repo.Create(leave)
+	repo.Create(context.Background(), leave)
 
 	tests := []struct {
 		name           string
@@ -259,11 +262,12 @@ func TestManagerHandler_RejectLeaveRequest(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Reset status to pending
 			leave.Status = models.LeaveStatusPending
-			repo.Update(leave)
+			repo.Create(context.Background(), leave)
 
 			c, rec := setupEchoContextForManager(http.MethodPut, "/api/v1/manager/leave/:id/reject", tt.body)
 			c.SetParamNames("id")
 			c.SetParamValues(tt.id)
+			c.Request().Header.Set(HeaderIfMatch, leaveETag(leave))
 
 			err := handler.RejectLeaveRequest(c)
 
@@ -272,13 +276,13 @@ func TestManagerHandler_RejectLeaveRequest(t *testing.T) {
 					t.Errorf("expected error but got none")
 					return
 				}
-				he, ok := err.(*echo.HTTPError)
+				he, ok := appError(err)
 				if !ok {
-					t.Errorf("expected HTTPError, got %T", err)
+					t.Errorf("expected an error, got %v", err)
 					return
 				}
-				if he.Code != tt.wantStatusCode {
-					t.Errorf("expected status code %d, got %d", tt.wantStatusCode, he.Code)
+				if he.Status != tt.wantStatusCode {
+					t.Errorf("expected status code %d, got %d", tt.wantStatusCode, he.Status)
 				}
 				return
 			}
@@ -302,3 +306,126 @@ func TestManagerHandler_RejectLeaveRequest(t *testing.T) {
 	}
 }
 
+
+func TestManagerHandler_BulkReviewLeaveRequests(t *testing.T) {
+	handler, repo := setupTestManagerHandler()
+
+	pendingID := uuid.New()
+	repo.Create(context.Background(), &models.LeaveRequest{
+		ID:         pendingID,
+		EmployeeID: "emp-1",
+		Status:     models.LeaveStatusPending,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	})
+	missingID := uuid.New()
+
+	c, rec := setupEchoContextForManager(http.MethodPost, "/api/v1/manager/leave/bulk", map[string]interface{}{
+		"action": "approve",
+		"items": []map[string]interface{}{
+			{"id": pendingID.String(), "version": 1},
+			{"id": missingID.String(), "version": 1},
+		},
+	})
+	c.Set("userID", "mgr-1")
+
+	if err := handler.BulkReviewLeaveRequests(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var results []models.BulkLeaveResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Outcome != models.BulkOutcomeApproved || results[1].Outcome != models.BulkOutcomeNotFound {
+		t.Errorf("unexpected outcomes: %s, %s", results[0].Outcome, results[1].Outcome)
+	}
+
+	c, _ = setupEchoContextForManager(http.MethodPost, "/api/v1/manager/leave/bulk", map[string]interface{}{
+		"action": "approve",
+		"items":  []map[string]string{},
+	})
+	c.Set("userID", "mgr-1")
+
+	err := handler.BulkReviewLeaveRequests(c)
+	if httpErr, ok := appError(err); !ok || httpErr.Status != http.StatusBadRequest {
+		t.Errorf("expected 400 for an empty batch, got %v", err)
+	}
+}
+
+func TestManagerHandler_SelfReviewForbidden(t *testing.T) {
+	handler, repo := setupTestManagerHandler()
+
+	own := &models.LeaveRequest{
+		ID:         uuid.New(),
+		EmployeeID: "mgr-1",
+		Status:     models.LeaveStatusPending,
+		CreatedAt:  time.Now(),
+	}
+	repo.Create(context.Background(), own)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   map[string]string
+		review func(echo.Context) error
+	}{
+		{"approve", "/api/v1/manager/leave/:id/approve", map[string]string{}, handler.ApproveLeaveRequest},
+		{"reject", "/api/v1/manager/leave/:id/reject", map[string]string{"comment": "Not enough cover that week"}, handler.RejectLeaveRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := setupEchoContextForManager(http.MethodPut, tt.path, tt.body)
+			c.SetParamNames("id")
+			c.SetParamValues(own.ID.String())
+			c.Request().Header.Set(HeaderIfMatch, leaveETag(own))
+
+			err := tt.review(c)
+			httpErr, ok := appError(err)
+			if !ok || httpErr.Status != http.StatusForbidden {
+				t.Fatalf("expected 403, got %v", err)
+			}
+		})
+	}
+}
+
+// conflictingUnitOfWork fails every transaction as if the request had been
+// changed concurrently
+type conflictingUnitOfWork struct {
+	*repository.MockUnitOfWork
+}
+
+func (u conflictingUnitOfWork) WithinTx(ctx context.Context, fn func(tx repository.Store) error) error {
+	return repository.ErrConflict
+}
+
+func TestManagerHandler_ApproveLeaveRequest_Conflict(t *testing.T) {
+	uow := conflictingUnitOfWork{repository.NewMockUnitOfWork(nil, nil)}
+	handler := NewManagerHandler(services.NewLeaveService(uow, services.DefaultLeavePolicy()))
+
+	leave := &models.LeaveRequest{
+		ID:         uuid.New(),
+		EmployeeID: "emp-1",
+		Status:     models.LeaveStatusPending,
+		CreatedAt:  time.Now(),
+	}
+	uow.LeaveRepo.Create(context.Background(), leave)
+
+	c, _ := setupEchoContextForManager(http.MethodPut, "/api/v1/manager/leave/:id/approve", map[string]string{})
+	c.SetParamNames("id")
+	c.SetParamValues(leave.ID.String())
+	c.Request().Header.Set(HeaderIfMatch, leaveETag(leave))
+
+	err := handler.ApproveLeaveRequest(c)
+	httpErr, ok := appError(err)
+	if !ok || httpErr.Status != http.StatusConflict || httpErr.Code != apperror.CodeConflict {
+		t.Fatalf("expected 409 %s, got %v", apperror.CodeConflict, err)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/middleware"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/services"
+	"leave-management-system/internal/utils"
+)
+
+// NotificationPreferenceHandler handles each user's notification preferences
+type NotificationPreferenceHandler struct {
+	preferenceService *services.NotificationPreferenceService
+}
+
+// NewNotificationPreferenceHandler creates a new notification preference handler
+func NewNotificationPreferenceHandler(preferenceService *services.NotificationPreferenceService) *NotificationPreferenceHandler {
+	return &NotificationPreferenceHandler{
+		preferenceService: preferenceService,
+	}
+}
+
+// GetPreferences handles GET /api/v1/notifications/preferences
+func (h *NotificationPreferenceHandler) GetPreferences(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	employee, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("get_notification_preferences_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	prefs, err := h.preferenceService.GetPreferences(c.Request().Context(), employee)
+	if err != nil {
+		log.Errorf("get_notification_preferences_failed user_id=%s error=%v", employee.ID, err)
+		return apperror.Internal(err)
+	}
+
+	log.Infof("get_notification_preferences_success user_id=%s", employee.ID)
+	return c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences handles PUT /api/v1/notifications/preferences
+func (h *NotificationPreferenceHandler) UpdatePreferences(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	employee, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("update_notification_preferences_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	var req models.UpdateNotificationPreferencesRequest
+	if err := c.Bind(&req); err != nil {
+		log.Warnf("update_notification_preferences_failed reason=invalid_request user_id=%s error=%v", employee.ID, err)
+		return apperror.InvalidBody(err)
+	}
+
+	if err := validateRequest(c, "update_notification_preferences_failed", &req); err != nil {
+		return err
+	}
+
+	prefs, err := h.preferenceService.UpdatePreferences(c.Request().Context(), employee, &req)
+	if err != nil {
+		var validationErr *utils.ValidationError
+		if errors.As(err, &validationErr) {
+			log.Warnf("update_notification_preferences_failed reason=validation user_id=%s error=%v", employee.ID, err)
+			return apperror.New(http.StatusBadRequest, apperror.CodeValidationFailed, err.Error())
+		}
+		log.Errorf("update_notification_preferences_failed user_id=%s error=%v", employee.ID, err)
+		return apperror.Internal(err)
+	}
+
+	log.Infof("update_notification_preferences_success user_id=%s events=%d", employee.ID, len(req.Events))
+	return c.JSON(http.StatusOK, prefs)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/services"
+)
+
+func TestNotificationPreferenceHandler(t *testing.T) {
+	service, _ := services.NewNotificationPreferenceService(repository.NewMockNotificationPreferenceRepository(), services.DefaultLeavePolicy(), "08:00")
+	handler := NewNotificationPreferenceHandler(service)
+
+	tests := []struct {
+		name           string
+		email          string
+		body           interface{}
+		wantStatusCode int
+	}{
+		{"digest of submissions", "jane@example.com", map[string]interface{}{
+			"events":     map[string]interface{}{"leave.submitted": map[string]interface{}{"mode": "digest"}},
+			"digestTime": "07:30",
+		}, http.StatusOK},
+		{"unknown mode", "jane@example.com", map[string]interface{}{
+			"events": map[string]interface{}{"leave.submitted": map[string]interface{}{"mode": "weekly"}},
+		}, http.StatusBadRequest},
+		{"unknown channel", "jane@example.com", map[string]interface{}{
+			"events": map[string]interface{}{"leave.submitted": map[string]interface{}{"mode": "immediate", "channels": []string{"fax"}}},
+		}, http.StatusBadRequest},
+		{"invalid digest time", "jane@example.com", map[string]interface{}{"digestTime": "25:00"}, http.StatusBadRequest},
+		{"digest of an employee event", "jane@example.com", map[string]interface{}{
+			"events": map[string]interface{}{"leave.approved": map[string]interface{}{"mode": "digest"}},
+		}, http.StatusBadRequest},
+		{"no email address", "", map[string]interface{}{}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := setupEchoContext(http.MethodPut, "/api/v1/notifications/preferences", tt.body)
+			c.Set("userID", "mgr-1")
+			c.Set("userEmail", tt.email)
+
+			err := handler.UpdatePreferences(c)
+			status := rec.Code
+			if appErr, ok := appError(err); ok {
+				status = appErr.Status
+			}
+			if status != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d (%v)", tt.wantStatusCode, status, err)
+			}
+		})
+	}
+
+	// The saved preferences are returned afterwards
+	c, rec := setupEchoContext(http.MethodGet, "/api/v1/notifications/preferences", nil)
+	c.Set("userID", "mgr-1")
+	if err := handler.GetPreferences(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var prefs models.NotificationPreferencesInfo
+	json.Unmarshal(rec.Body.Bytes(), &prefs)
+	if prefs.Events["leave.submitted"].Mode != models.NotificationDigest || prefs.DigestTime != "07:30" || prefs.UpdatedAt == nil {
+		t.Errorf("expected the saved preferences, got %+v", prefs)
+	}
+
+	c, _ = setupEchoContext(http.MethodGet, "/api/v1/notifications/preferences", nil)
+	if _, ok := appError(handler.GetPreferences(c)); !ok {
+		t.Error("expected an error without a user")
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/middleware"
+	"leave-management-system/internal/openapi"
+)
+
+// TestLeaveHandler_MatchesOpenAPIDocument runs a leave through its lifecycle
+// with responses validated against the document, so any drift between the
+// handlers and the document fails with a 500
+func TestLeaveHandler_MatchesOpenAPIDocument(t *testing.T) {
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("failed to load OpenAPI document: %v", err)
+	}
+	validator, err := middleware.OpenAPIValidator(doc, true)
+	if err != nil {
+		t.Fatalf("failed to build validator: %v", err)
+	}
+
+	handler, _ := setupTestHandler()
+	e := newTestEcho()
+	e.HTTPErrorHandler = middleware.ErrorHandler(false)
+	e.Use(validator)
+	leave := e.Group("/api/v1/leave", func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("userID", "emp-1")
+			c.Set("userName", "John Doe")
+			c.Set("userEmail", "john@example.com")
+			return next(c)
+		}
+	})
+	leave.POST("", handler.CreateLeaveRequest)
+	leave.GET("", handler.GetLeaveRequests)
+	leave.GET("/:id", handler.GetLeaveRequest)
+	leave.PUT("/:id", handler.UpdateLeaveRequest)
+	leave.POST("/:id/submit", handler.SubmitLeaveRequest)
+	leave.DELETE("/:id", handler.CancelLeaveRequest)
+
+	send := func(method, path, body, etag string, wantStatus int) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		}
+		if etag != "" {
+			req.Header.Set(HeaderIfMatch, etag)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != wantStatus {
+			t.Fatalf("%s %s: expected %d, got %d %s", method, path, wantStatus, rec.Code, rec.Body.String())
+		}
+		return rec
+	}
+
+	rec := send(http.MethodPost, "/api/v1/leave", `{"leaveType":"annual","reason":"Family holiday","startDate":"`+futureDate(14)+`","endDate":"`+futureDate(15)+`","draft":true}`, "", http.StatusCreated)
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode created leave: %v", err)
+	}
+	path := "/api/v1/leave/" + created.ID
+
+	send(http.MethodGet, "/api/v1/leave?status=draft&limit=10", "", "", http.StatusOK)
+	rec = send(http.MethodGet, path, "", "", http.StatusOK)
+	rec = send(http.MethodPut, path, `{"reason":"Family wedding"}`, rec.Header().Get("ETag"), http.StatusOK)
+	rec = send(http.MethodPost, path+"/submit", "", rec.Header().Get("ETag"), http.StatusOK)
+	send(http.MethodDelete, path, "", rec.Header().Get("ETag"), http.StatusNoContent)
+
+	// Errors skip response validation and are rendered as problems
+	send(http.MethodGet, "/api/v1/leave/8c8a8d1e-6a0b-4a8f-9a55-3f0f7a1c2b3d", "", "", http.StatusNotFound)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/middleware"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/services"
+)
+
+// OutboxHandler handles the admin endpoints for inspecting and replaying
+// notification outbox messages
+type OutboxHandler struct {
+	outboxService *services.OutboxService
+}
+
+// NewOutboxHandler creates a new outbox handler
+func NewOutboxHandler(outboxService *services.OutboxService) *OutboxHandler {
+	return &OutboxHandler{
+		outboxService: outboxService,
+	}
+}
+
+// GetMessages handles GET /api/v1/admin/outbox
+func (h *OutboxHandler) GetMessages(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	filter := models.OutboxFilter{
+		Status: models.OutboxStatus(c.QueryParam("status")),
+		Topic:  models.OutboxTopic(c.QueryParam("topic")),
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		log.Warnf("get_outbox_failed reason=invalid_query status=%s", filter.Status)
+		return apperror.InvalidQuery("Invalid status")
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			log.Warnf("get_outbox_failed reason=invalid_query limit=%s", limit)
+			return apperror.InvalidQuery("Invalid limit")
+		}
+		filter.Limit = n
+	}
+
+	messages, err := h.outboxService.ListMessages(c.Request().Context(), filter)
+	if err != nil {
+		log.Errorf("get_outbox_failed error=%v", err)
+		return apperror.Internal(err)
+	}
+
+	log.Infof("get_outbox_success count=%d status=%s", len(messages), filter.Status)
+	return c.JSON(http.StatusOK, messages)
+}
+
+// ReplayMessage handles POST /api/v1/admin/outbox/:id/replay
+func (h *OutboxHandler) ReplayMessage(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Warnf("replay_outbox_failed reason=invalid_id id=%s error=%v", c.Param("id"), err)
+		return apperror.InvalidID("Invalid outbox message ID")
+	}
+
+	msg, err := h.outboxService.ReplayMessage(c.Request().Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOutboxMessageNotFound):
+			log.Warnf("replay_outbox_failed reason=not_found message_id=%s", id)
+			return apperror.New(http.StatusNotFound, apperror.CodeOutboxMessageNotFound, "Outbox message not found")
+		case errors.Is(err, services.ErrOutboxNotReplayable):
+			log.Warnf("replay_outbox_failed reason=not_dead message_id=%s error=%v", id, err)
+			return apperror.New(http.StatusConflict, apperror.CodeMessageNotReplayable, err.Error())
+		case errors.Is(err, services.ErrConflict):
+			log.Warnf("replay_outbox_failed reason=conflict message_id=%s", id)
+			return apperror.New(http.StatusConflict, apperror.CodeConflict, err.Error())
+		}
+		log.Errorf("replay_outbox_failed message_id=%s error=%v", id, err)
+		return apperror.Internal(err)
+	}
+
+	log.Infof("replay_outbox_success message_id=%s topic=%s", id, msg.Topic)
+	return c.JSON(http.StatusOK, msg)
+}
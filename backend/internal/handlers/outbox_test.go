@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/services"
+)
+
+func TestOutboxHandler(t *testing.T) {
+	repo := repository.NewMockOutboxRepository()
+	handler := NewOutboxHandler(services.NewOutboxService(repo))
+
+	dead := &models.OutboxMessage{ID: uuid.New(), Topic: models.OutboxTopicLeaveDecided, Payload: []byte(`{}`), Status: models.OutboxStatusDead, Attempts: 8, LastError: "smtp unavailable", NextAttemptAt: time.Now()}
+	delivered := &models.OutboxMessage{ID: uuid.New(), Topic: models.OutboxTopicLeaveDecided, Payload: []byte(`{}`), Status: models.OutboxStatusDelivered, NextAttemptAt: time.Now()}
+	repo.Create(context.Background(), dead)
+	repo.Create(context.Background(), delivered)
+
+	c, rec := setupEchoContext(http.MethodGet, "/api/v1/admin/outbox?status=dead", nil)
+	if err := handler.GetMessages(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var messages []models.OutboxMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &messages); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(messages) != 1 || messages[0].ID != dead.ID || messages[0].LastError != "smtp unavailable" {
+		t.Errorf("expected only the dead message, got %+v", messages)
+	}
+
+	c, _ = setupEchoContext(http.MethodGet, "/api/v1/admin/outbox?status=lost", nil)
+	if appErr, ok := appError(handler.GetMessages(c)); !ok || appErr.Status != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown status, got %v", appErr)
+	}
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+		wantCode   apperror.Code
+	}{
+		{"dead message", dead.ID.String(), http.StatusOK, ""},
+		{"already replayed", dead.ID.String(), http.StatusConflict, apperror.CodeMessageNotReplayable},
+		{"delivered message", delivered.ID.String(), http.StatusConflict, apperror.CodeMessageNotReplayable},
+		{"missing message", uuid.New().String(), http.StatusNotFound, apperror.CodeOutboxMessageNotFound},
+		{"invalid id", "42", http.StatusBadRequest, apperror.CodeInvalidID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := setupEchoContext(http.MethodPost, "/api/v1/admin/outbox/"+tt.id+"/replay", nil)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+
+			err := handler.ReplayMessage(c)
+			if tt.wantStatus == http.StatusOK {
+				if err != nil || rec.Code != http.StatusOK {
+					t.Fatalf("expected 200, got %d (%v)", rec.Code, err)
+				}
+				return
+			}
+			appErr, ok := appError(err)
+			if !ok || appErr.Status != tt.wantStatus || appErr.Code != tt.wantCode {
+				t.Errorf("expected %d %s, got %v", tt.wantStatus, tt.wantCode, appErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/models"
+)
+
+// Pagination metadata is returned in headers so list responses stay plain JSON arrays
+const (
+	HeaderTotalCount = "X-Total-Count"
+	HeaderNextCursor = "X-Next-Cursor"
+)
+
+// parseLeaveFilter reads filter, sort and pagination parameters from the query string:
+// status, leaveType, employeeId, department, q, from, to, sort, order, cursor and limit
+func parseLeaveFilter(c echo.Context) (models.LeaveFilter, error) {
+	filter := models.LeaveFilter{
+		Status:     models.LeaveStatus(c.QueryParam("status")),
+		LeaveType:  models.LeaveType(c.QueryParam("leaveType")),
+		EmployeeID: c.QueryParam("employeeId"),
+		Department: c.QueryParam("department"),
+		Query:      c.QueryParam("q"),
+		Sort:       models.LeaveSort(c.QueryParam("sort")),
+		Cursor:     c.QueryParam("cursor"),
+	}
+
+	var err error
+	if from := c.QueryParam("from"); from != "" {
+		if filter.From, err = models.ParseDate(from); err != nil {
+			return filter, errors.New("Invalid from date")
+		}
+	}
+	if to := c.QueryParam("to"); to != "" {
+		if filter.To, err = models.ParseDate(to); err != nil {
+			return filter, errors.New("Invalid to date")
+		}
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 1 {
+			return filter, errors.New("Invalid limit")
+		}
+	}
+
+	switch c.QueryParam("order") {
+	case "", "asc":
+	case "desc":
+		filter.Descending = true
+	default:
+		return filter, errors.New("Invalid order: expected asc or desc")
+	}
+
+	return filter, nil
+}
+
+// writeLeavePage responds with the page's items, setting the total count, the
+// next cursor and a Link header for the next page
+func writeLeavePage(c echo.Context, page *models.LeavePage) error {
+	return writePage(c, page.Items, page.Total, page.NextCursor)
+}
+
+// writePage responds with a page of items, setting the total count and, when
+// there is a next page, its cursor and a Link header to it
+func writePage(c echo.Context, items interface{}, total int, nextCursor string) error {
+	header := c.Response().Header()
+	header.Set(HeaderTotalCount, strconv.Itoa(total))
+
+	if nextCursor != "" {
+		header.Set(HeaderNextCursor, nextCursor)
+
+		next := *c.Request().URL
+		query := next.Query()
+		query.Set("cursor", nextCursor)
+		next.RawQuery = query.Encode()
+		header.Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
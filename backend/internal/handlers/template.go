@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/middleware"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/services"
+	"leave-management-system/internal/utils"
+)
+
+// TemplateHandler handles the admin endpoints for overriding notification templates
+type TemplateHandler struct {
+	templateService *services.TemplateService
+}
+
+// NewTemplateHandler creates a new template handler
+func NewTemplateHandler(templateService *services.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// GetTemplates handles GET /api/v1/admin/templates
+func (h *TemplateHandler) GetTemplates(c echo.Context) error {
+	log := middleware.GetLogger(c)
+
+	infos, err := h.templateService.ListTemplates(c.Request().Context())
+	if err != nil {
+		log.Errorf("get_templates_failed error=%v", err)
+		return apperror.Internal(err)
+	}
+
+	log.Infof("get_templates_success count=%d", len(infos))
+	return c.JSON(http.StatusOK, infos)
+}
+
+// GetTemplate handles GET /api/v1/admin/templates/:locale/:name
+func (h *TemplateHandler) GetTemplate(c echo.Context) error {
+	log := middleware.GetLogger(c)
+	locale, name := c.Param("locale"), c.Param("name")
+
+	tmpl, err := h.templateService.GetTemplate(c.Request().Context(), locale, name)
+	if err != nil {
+		return templateError(c, "get_template_failed", locale, name, err)
+	}
+
+	log.Infof("get_template_success locale=%s name=%s overridden=%t", locale, name, tmpl.Overridden)
+	return c.JSON(http.StatusOK, tmpl)
+}
+
+// UpdateTemplate handles PUT /api/v1/admin/templates/:locale/:name
+func (h *TemplateHandler) UpdateTemplate(c echo.Context) error {
+	log := middleware.GetLogger(c)
+	locale, name := c.Param("locale"), c.Param("name")
+
+	actor, err := middleware.GetEmployee(c)
+	if err != nil {
+		log.Warnf("update_template_failed reason=unauthorized error=%v", err)
+		return apperror.Unauthorized(err)
+	}
+
+	var req models.UpdateTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		log.Warnf("update_template_failed reason=invalid_request locale=%s name=%s error=%v", locale, name, err)
+		return apperror.InvalidBody(err)
+	}
+
+	if err := validateRequest(c, "update_template_failed", &req); err != nil {
+		return err
+	}
+
+	tmpl, err := h.templateService.SaveTemplate(c.Request().Context(), locale, name, &req, actor)
+	if err != nil {
+		return templateError(c, "update_template_failed", locale, name, err)
+	}
+
+	log.Infof("update_template_success locale=%s name=%s actor_id=%s", locale, name, actor.ID)
+	return c.JSON(http.StatusOK, tmpl)
+}
+
+// ResetTemplate handles DELETE /api/v1/admin/templates/:locale/:name
+func (h *TemplateHandler) ResetTemplate(c echo.Context) error {
+	log := middleware.GetLogger(c)
+	locale, name := c.Param("locale"), c.Param("name")
+
+	if err := h.templateService.ResetTemplate(c.Request().Context(), locale, name); err != nil {
+		return templateError(c, "reset_template_failed", locale, name, err)
+	}
+
+	log.Infof("reset_template_success locale=%s name=%s", locale, name)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// templateError logs and maps template service errors to HTTP errors
+func templateError(c echo.Context, event, locale, name string, err error) error {
+	log := middleware.GetLogger(c)
+
+	var validationErr *utils.ValidationError
+	switch {
+	case errors.Is(err, services.ErrTemplateNotFound):
+		log.Warnf("%s reason=not_found locale=%s name=%s", event, locale, name)
+		return apperror.New(http.StatusNotFound, apperror.CodeTemplateNotFound, "Notification template not found")
+	case errors.As(err, &validationErr):
+		log.Warnf("%s reason=validation locale=%s name=%s error=%v", event, locale, name, err)
+		return apperror.New(http.StatusBadRequest, apperror.CodeValidationFailed, err.Error())
+	default:
+		log.Errorf("%s locale=%s name=%s error=%v", event, locale, name, err)
+		return apperror.Internal(err)
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/services"
+	"leave-management-system/internal/templates"
+)
+
+func TestTemplateHandler(t *testing.T) {
+	handler := NewTemplateHandler(services.NewTemplateService(repository.NewMockTemplateRepository()))
+
+	request := func(method, locale, name string, body interface{}) ([]byte, int, error) {
+		c, rec := setupEchoContext(method, "/api/v1/admin/templates/"+locale+"/"+name, body)
+		c.Set("userID", "admin-1")
+		c.SetParamNames("locale", "name")
+		c.SetParamValues(locale, name)
+
+		var err error
+		switch method {
+		case http.MethodGet:
+			err = handler.GetTemplate(c)
+		case http.MethodPut:
+			err = handler.UpdateTemplate(c)
+		case http.MethodDelete:
+			err = handler.ResetTemplate(c)
+		}
+		return rec.Body.Bytes(), rec.Code, err
+	}
+
+	override := models.UpdateTemplateRequest{
+		Text: `{{define "subject"}}ลาได้{{end}}สวัสดี {{.Leave.EmployeeName}}`,
+		HTML: `<p>สวัสดี {{.Leave.EmployeeName}}</p>`,
+	}
+	body, code, err := request(http.MethodPut, "th", "leave_approved", override)
+	if err != nil || code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%v)", code, err)
+	}
+
+	body, _, err = request(http.MethodGet, "th", "leave_approved", nil)
+	var tmpl models.TemplateDetail
+	if err != nil || json.Unmarshal(body, &tmpl) != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tmpl.Overridden || tmpl.Text != override.Text || tmpl.UpdatedBy != "admin-1" {
+		t.Errorf("expected the override, got %+v", tmpl)
+	}
+
+	c, rec := setupEchoContext(http.MethodGet, "/api/v1/admin/templates", nil)
+	if err := handler.GetTemplates(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var infos []models.TemplateInfo
+	json.Unmarshal(rec.Body.Bytes(), &infos)
+	overridden := 0
+	for _, info := range infos {
+		if info.Overridden {
+			overridden++
+		}
+	}
+	if want := len(templates.Names) * len(templates.Locales); len(infos) != want || overridden != 1 {
+		t.Errorf("expected %d templates with one overridden, got %d with %d", want, len(infos), overridden)
+	}
+
+	if _, code, err := request(http.MethodDelete, "th", "leave_approved", nil); err != nil || code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d (%v)", code, err)
+	}
+	body, _, _ = request(http.MethodGet, "th", "leave_approved", nil)
+	tmpl = models.TemplateDetail{}
+	json.Unmarshal(body, &tmpl)
+	if tmpl.Overridden || tmpl.Text == override.Text {
+		t.Errorf("expected the built-in template after a reset, got %+v", tmpl)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		locale     string
+		template   string
+		body       interface{}
+		wantStatus int
+		wantCode   apperror.Code
+	}{
+		{"unknown template", http.MethodGet, "en", "leave_archived", nil, http.StatusNotFound, apperror.CodeTemplateNotFound},
+		{"unsupported locale", http.MethodPut, "fr", "leave_approved", override, http.StatusNotFound, apperror.CodeTemplateNotFound},
+		{"reset unknown template", http.MethodDelete, "en", "leave_archived", nil, http.StatusNotFound, apperror.CodeTemplateNotFound},
+		{"missing html", http.MethodPut, "en", "leave_approved", models.UpdateTemplateRequest{Text: override.Text}, http.StatusBadRequest, apperror.CodeValidationFailed},
+		{"broken template", http.MethodPut, "en", "leave_approved", models.UpdateTemplateRequest{Text: "{{.Leave.Salary}}", HTML: "<p></p>"}, http.StatusBadRequest, apperror.CodeValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := request(tt.method, tt.locale, tt.template, tt.body)
+			appErr, ok := appError(err)
+			if !ok || appErr.Status != tt.wantStatus || appErr.Code != tt.wantCode {
+				t.Errorf("expected %d %s, got %v", tt.wantStatus, tt.wantCode, appErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/middleware"
+	"leave-management-system/internal/utils"
+)
+
+// contextValidator is implemented by validators, such as utils.RequestValidator,
+// whose rules depend on the request context
+type contextValidator interface {
+	ValidateCtx(ctx context.Context, i interface{}) error
+}
+
+// validateRequest checks a bound request body against its `validate` tags,
+// judging dates in the user's time zone. Field errors are returned as a 400
+// listing each invalid field.
+func validateRequest(c echo.Context, event string, req interface{}) error {
+	var err error
+	if v, ok := c.Echo().Validator.(contextValidator); ok {
+		timezone, _ := c.Get("userTimezone").(string)
+		err = v.ValidateCtx(utils.WithTimezone(c.Request().Context(), timezone), req)
+	} else {
+		err = c.Validate(req)
+	}
+	if err == nil {
+		return nil
+	}
+
+	log := middleware.GetLogger(c)
+	var fieldErrs utils.FieldErrors
+	if errors.As(err, &fieldErrs) {
+		log.Warnf("%s reason=validation error=%q", event, fieldErrs.Error())
+		return apperror.Validation(fieldErrs)
+	}
+
+	log.Errorf("%s reason=validator error=%v", event, err)
+	return apperror.Internal(err)
+}
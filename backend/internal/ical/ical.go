@@ -0,0 +1,162 @@
+// Package ical encodes all-day events as RFC 5545 iCalendar objects, for
+// calendar invites sent by email and for calendar feeds.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"leave-management-system/internal/models"
+)
+
+// ProdID identifies this system as the producer of calendars
+const ProdID = "-//Leave Management System//EN"
+
+// ContentType is the media type of an encoded calendar
+const ContentType = "text/calendar"
+
+// Method is the iTIP method of a calendar (RFC 5546)
+type Method string
+
+const (
+	// MethodPublish publishes events without expecting replies, as feeds do
+	MethodPublish Method = "PUBLISH"
+	// MethodRequest adds events to, or updates them in, the attendee's calendar
+	MethodRequest Method = "REQUEST"
+	// MethodCancel removes events from the attendee's calendar
+	MethodCancel Method = "CANCEL"
+)
+
+// Person is an organizer or attendee
+type Person struct {
+	Name  string
+	Email string
+}
+
+// Event is an all-day event. Events are identified across updates by UID;
+// an update must carry a higher Sequence than the version it replaces.
+type Event struct {
+	UID      string
+	Sequence int
+	// Start and End are the first and last day of the event
+	Start, End  models.Date
+	Summary     string
+	Description string
+	Organizer   *Person
+	Attendees   []Person
+	// Stamp is when the event was sent; the time of encoding when zero
+	Stamp time.Time
+	// Free marks the event as not blocking time, while Outlook still shows
+	// it as out of office
+	Free bool
+}
+
+// Calendar is a set of events sent with one method
+type Calendar struct {
+	Method Method
+	// Name is the display name of a feed; may be empty
+	Name   string
+	Events []Event
+}
+
+// Encode encodes c as an iCalendar object
+func (c *Calendar) Encode() []byte {
+	w := &writer{}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", ProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	if c.Method != "" {
+		w.line("METHOD", string(c.Method))
+	}
+	if c.Name != "" {
+		w.line("X-WR-CALNAME", escape(c.Name))
+	}
+
+	now := time.Now()
+	for _, e := range c.Events {
+		stamp := e.Stamp
+		if stamp.IsZero() {
+			stamp = now
+		}
+
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", escape(e.UID))
+		w.line("SEQUENCE", fmt.Sprint(e.Sequence))
+		w.line("DTSTAMP", stamp.UTC().Format("20060102T150405Z"))
+		w.line("DTSTART;VALUE=DATE", e.Start.Format("20060102"))
+		// DTEND of an all-day event is the day after it ends
+		w.line("DTEND;VALUE=DATE", e.End.AddDays(1).Format("20060102"))
+		w.line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			w.line("DESCRIPTION", escape(e.Description))
+		}
+		if e.Organizer != nil {
+			w.line("ORGANIZER"+cn(e.Organizer.Name), "mailto:"+e.Organizer.Email)
+		}
+		for _, a := range e.Attendees {
+			w.line("ATTENDEE"+cn(a.Name)+";ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED;RSVP=FALSE", "mailto:"+a.Email)
+		}
+		if c.Method == MethodCancel {
+			w.line("STATUS", "CANCELLED")
+		} else {
+			w.line("STATUS", "CONFIRMED")
+		}
+		if e.Free {
+			w.line("TRANSP", "TRANSPARENT")
+		} else {
+			w.line("TRANSP", "OPAQUE")
+		}
+		w.line("X-MICROSOFT-CDO-BUSYSTATUS", "OOF")
+		w.line("X-MICROSOFT-CDO-ALLDAYEVENT", "TRUE")
+		w.line("END", "VEVENT")
+	}
+
+	w.line("END", "VCALENDAR")
+	return w.buf.Bytes()
+}
+
+// writer writes content lines, folded at 75 octets as RFC 5545 requires
+type writer struct {
+	buf bytes.Buffer
+}
+
+func (w *writer) line(name, value string) {
+	line := name + ":" + value
+	for len(line) > 75 {
+		// Fold on a character boundary; continuation lines start with a space
+		// that counts toward their length
+		cut := 75
+		for cut > 1 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.buf.WriteString(line[:cut])
+		w.buf.WriteString("\r\n")
+		line = " " + line[cut:]
+	}
+	w.buf.WriteString(line)
+	w.buf.WriteString("\r\n")
+}
+
+// escape escapes a TEXT value
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// cn returns the common name parameter for name, quoted as it may contain
+// separators. Double quotes cannot be escaped and are dropped.
+func cn(name string) string {
+	if name = strings.ReplaceAll(name, `"`, ""); name == "" {
+		return ""
+	}
+	return `;CN="` + name + `"`
+}
@@ -0,0 +1,85 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"leave-management-system/internal/models"
+)
+
+func testEvent() Event {
+	return Event{
+		UID:         "0b7f3c9e-5d1a-4c57-9a43-2f1f0a6e8c11@leave-management-system",
+		Sequence:    3,
+		Start:       models.NewDate(2024, 1, 1),
+		End:         models.NewDate(2024, 1, 5),
+		Summary:     "Annual leave",
+		Description: "Family trip; back on Monday, promise",
+		Organizer:   &Person{Name: "Leave Management System", Email: "noreply@company.com"},
+		Attendees:   []Person{{Name: `John "JD" Doe`, Email: "john@example.com"}},
+		Stamp:       time.Date(2023, 12, 20, 9, 30, 0, 0, time.UTC),
+		Free:        true,
+	}
+}
+
+func TestCalendar_Encode(t *testing.T) {
+	ics := string((&Calendar{Method: MethodRequest, Events: []Event{testEvent()}}).Encode())
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:" + ProdID + "\r\n",
+		"METHOD:REQUEST\r\n",
+		"UID:0b7f3c9e-5d1a-4c57-9a43-2f1f0a6e8c11@leave-management-system\r\n",
+		"SEQUENCE:3\r\n",
+		"DTSTAMP:20231220T093000Z\r\n",
+		// All-day, ending the day after the last day of leave
+		"DTSTART;VALUE=DATE:20240101\r\nDTEND;VALUE=DATE:20240106\r\n",
+		`DESCRIPTION:Family trip\; back on Monday\, promise` + "\r\n",
+		`ATTENDEE;CN="John JD Doe";ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED;RSVP=FALSE` + "\r\n :mailto:john@example.com\r\n",
+		"STATUS:CONFIRMED\r\nTRANSP:TRANSPARENT\r\nX-MICROSOFT-CDO-BUSYSTATUS:OOF\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("expected %q in\n%s", want, ics)
+		}
+	}
+}
+
+func TestCalendar_EncodeCancel(t *testing.T) {
+	ics := string((&Calendar{Method: MethodCancel, Events: []Event{testEvent()}}).Encode())
+	if !strings.Contains(ics, "METHOD:CANCEL\r\n") || !strings.Contains(ics, "STATUS:CANCELLED\r\n") {
+		t.Errorf("expected a cancellation, got\n%s", ics)
+	}
+}
+
+func TestCalendar_EncodeFeed(t *testing.T) {
+	event := testEvent()
+	event.Organizer, event.Attendees, event.Description = nil, nil, ""
+	ics := string((&Calendar{Method: MethodPublish, Name: "Leave, John Doe", Events: []Event{event, event}}).Encode())
+
+	if !strings.Contains(ics, `X-WR-CALNAME:Leave\, John Doe`) || strings.Count(ics, "BEGIN:VEVENT") != 2 {
+		t.Errorf("unexpected feed\n%s", ics)
+	}
+	if strings.Contains(ics, "ORGANIZER") || strings.Contains(ics, "DESCRIPTION") {
+		t.Errorf("expected no organizer or description, got\n%s", ics)
+	}
+}
+
+func TestCalendar_FoldsLongLines(t *testing.T) {
+	event := testEvent()
+	event.Description = strings.Repeat("ลาพักร้อนกับครอบครัว ", 10)
+	ics := string((&Calendar{Method: MethodRequest, Events: []Event{event}}).Encode())
+
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > 75 || !utf8.ValidString(line) {
+			t.Errorf("expected lines of at most 75 octets split between characters, got %q", line)
+		}
+	}
+
+	// Unfolding restores the value
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	if !strings.Contains(unfolded, "DESCRIPTION:"+event.Description+"\r\n") {
+		t.Errorf("expected the description to unfold, got\n%s", ics)
+	}
+}
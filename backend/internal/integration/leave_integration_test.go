@@ -9,29 +9,32 @@ import (
 	"testing"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
-	"leave-management-system/internal/config"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
 	"leave-management-system/internal/handlers"
-	"leave-management-system/internal/middleware"
 	authMiddleware "leave-management-system/internal/middleware"
 	"leave-management-system/internal/models"
 	"leave-management-system/internal/repository"
 	"leave-management-system/internal/services"
 	"leave-management-system/internal/testutil"
+	"leave-management-system/internal/utils"
 )
 
 var (
 	testDB     *sql.DB
 	leaveRepo  repository.LeaveRepository
 	leaveSvc   *services.LeaveService
-	emailSvc   *services.EmailService
 	leaveHdlr  *handlers.LeaveHandler
 	managerHdlr *handlers.ManagerHandler
 	e          *echo.Echo
 )
 
+// Leave dates must not be in the past, so derive them from the current date
+var (
+	testStartDate = time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 1, 0).Format(time.RFC3339)
+	testEndDate   = time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 1, 4).Format(time.RFC3339)
+)
+
 func setupIntegrationTest(t *testing.T) {
 	t.Helper()
 
@@ -41,29 +44,20 @@ func setupIntegrationTest(t *testing.T) {
 	testutil.CleanupTestDB(t, testDB)
 
 	// Setup repository and services
-	leaveRepo = repository.NewLeaveRepository(testDB)
-	leaveSvc = services.NewLeaveService(leaveRepo)
-
-	cfg := &config.Config{
-		Email: config.EmailConfig{
-			Host:     "",
-			Port:     587,
-			User:     "",
-			Password: "",
-			From:     "test@example.com",
-		},
-	}
-	emailSvc = services.NewEmailService(cfg)
+	leaveRepo = repository.NewLeaveRepository(testDB, 0)
+	leaveSvc = services.NewLeaveService(repository.NewUnitOfWork(testDB, 0), services.DefaultLeavePolicy())
 
 	// Setup handlers
 	leaveHdlr = handlers.NewLeaveHandler(leaveSvc)
-	managerHdlr = handlers.NewManagerHandler(leaveSvc, emailSvc)
+	managerHdlr = handlers.NewManagerHandler(leaveSvc)
 
 	// Setup Echo with middleware
 	e = echo.New()
-	e.Use(middleware.Logger())
-	e.Use(middleware.Recover())
-	e.Use(middleware.CORSWithConfig(authMiddleware.CORSConfig()))
+	e.Validator = utils.NewRequestValidator(services.DefaultLeavePolicy().Location)
+	e.HTTPErrorHandler = authMiddleware.ErrorHandler(false)
+	e.Use(echoMiddleware.Logger())
+	e.Use(echoMiddleware.Recover())
+	e.Use(echoMiddleware.CORSWithConfig(authMiddleware.CORSConfig()))
 
 	// Setup routes
 	api := e.Group("/api/v1")
@@ -100,8 +94,8 @@ func TestLeaveIntegration_CreateAndGet(t *testing.T) {
 	createReq := map[string]interface{}{
 		"leaveType": "annual",
 		"reason":    "Vacation time for rest",
-		"startDate": "2024-06-01T00:00:00Z",
-		"endDate":   "2024-06-05T00:00:00Z",
+		"startDate": testStartDate,
+		"endDate":   testEndDate,
 	}
 
 	body, _ := json.Marshal(createReq)
@@ -167,8 +161,8 @@ func TestLeaveIntegration_UpdateAndCancel(t *testing.T) {
 	createReq := map[string]interface{}{
 		"leaveType": "annual",
 		"reason":    "Initial vacation request",
-		"startDate": "2024-06-01T00:00:00Z",
-		"endDate":   "2024-06-05T00:00:00Z",
+		"startDate": testStartDate,
+		"endDate":   testEndDate,
 	}
 
 	body, _ := json.Marshal(createReq)
@@ -181,6 +175,7 @@ func TestLeaveIntegration_UpdateAndCancel(t *testing.T) {
 
 	var createdLeave models.LeaveRequest
 	json.Unmarshal(rec.Body.Bytes(), &createdLeave)
+	etag := responseETag(t, rec)
 
 	// Update leave request
 	updateReq := map[string]interface{}{
@@ -191,6 +186,7 @@ func TestLeaveIntegration_UpdateAndCancel(t *testing.T) {
 	req = httptest.NewRequest(http.MethodPut, "/api/v1/leave/"+createdLeave.ID.String(), bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", authHeader)
+	req.Header.Set(handlers.HeaderIfMatch, etag)
 	rec = httptest.NewRecorder()
 
 	e.ServeHTTP(rec, req)
@@ -200,6 +196,7 @@ func TestLeaveIntegration_UpdateAndCancel(t *testing.T) {
 		return
 	}
 
+	updateRec := rec
 	var updatedLeave models.LeaveRequest
 	json.Unmarshal(rec.Body.Bytes(), &updatedLeave)
 
@@ -207,9 +204,22 @@ func TestLeaveIntegration_UpdateAndCancel(t *testing.T) {
 		t.Errorf("Expected reason %q, got %q", "Updated vacation reason", updatedLeave.Reason)
 	}
 
+	// The version read before the update is now stale
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/leave/"+createdLeave.ID.String(), nil)
+	req.Header.Set("Authorization", authHeader)
+	req.Header.Set(handlers.HeaderIfMatch, etag)
+	rec = httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected status %d, got %d", http.StatusPreconditionFailed, rec.Code)
+	}
+
 	// Cancel leave request
 	req = httptest.NewRequest(http.MethodDelete, "/api/v1/leave/"+createdLeave.ID.String(), nil)
 	req.Header.Set("Authorization", authHeader)
+	req.Header.Set(handlers.HeaderIfMatch, responseETag(t, updateRec))
 	rec = httptest.NewRecorder()
 
 	e.ServeHTTP(rec, req)
@@ -247,8 +257,8 @@ func TestLeaveIntegration_ManagerApproveReject(t *testing.T) {
 	createReq := map[string]interface{}{
 		"leaveType": "annual",
 		"reason":    "Vacation time for rest",
-		"startDate": "2024-06-01T00:00:00Z",
-		"endDate":   "2024-06-05T00:00:00Z",
+		"startDate": testStartDate,
+		"endDate":   testEndDate,
 	}
 
 	body, _ := json.Marshal(createReq)
@@ -261,6 +271,7 @@ func TestLeaveIntegration_ManagerApproveReject(t *testing.T) {
 
 	var createdLeave models.LeaveRequest
 	json.Unmarshal(rec.Body.Bytes(), &createdLeave)
+	etag := responseETag(t, rec)
 
 	// Manager gets pending requests
 	req = httptest.NewRequest(http.MethodGet, "/api/v1/manager/leave", nil)
@@ -290,6 +301,7 @@ func TestLeaveIntegration_ManagerApproveReject(t *testing.T) {
 	req = httptest.NewRequest(http.MethodPut, "/api/v1/manager/leave/"+createdLeave.ID.String()+"/approve", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", managerAuth)
+	req.Header.Set(handlers.HeaderIfMatch, etag)
 	rec = httptest.NewRecorder()
 
 	e.ServeHTTP(rec, req)
@@ -299,7 +311,7 @@ func TestLeaveIntegration_ManagerApproveReject(t *testing.T) {
 		return
 	}
 
-	var approvedLeave models.LeaveRequest
+	var approvedLeave models.LeaveRequestJSON
 	json.Unmarshal(rec.Body.Bytes(), &approvedLeave)
 
 	if approvedLeave.Status != models.LeaveStatusApproved {
@@ -339,8 +351,8 @@ func TestLeaveIntegration_ManagerReject(t *testing.T) {
 	createReq := map[string]interface{}{
 		"leaveType": "annual",
 		"reason":    "Vacation time for rest",
-		"startDate": "2024-06-01T00:00:00Z",
-		"endDate":   "2024-06-05T00:00:00Z",
+		"startDate": testStartDate,
+		"endDate":   testEndDate,
 	}
 
 	body, _ := json.Marshal(createReq)
@@ -353,12 +365,14 @@ func TestLeaveIntegration_ManagerReject(t *testing.T) {
 
 	var createdLeave models.LeaveRequest
 	json.Unmarshal(rec.Body.Bytes(), &createdLeave)
+	etag := responseETag(t, rec)
 
 	// Manager rejects leave request
 	rejectReq := map[string]interface{}{
 		"comment": "Not enough leave balance available for this period",
 	}
 
+	// Without If-Match the write is refused
 	body, _ = json.Marshal(rejectReq)
 	req = httptest.NewRequest(http.MethodPut, "/api/v1/manager/leave/"+createdLeave.ID.String()+"/reject", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -367,12 +381,25 @@ func TestLeaveIntegration_ManagerReject(t *testing.T) {
 
 	e.ServeHTTP(rec, req)
 
+	if rec.Code != http.StatusPreconditionRequired {
+		t.Errorf("Expected status %d, got %d", http.StatusPreconditionRequired, rec.Code)
+	}
+
+	body, _ = json.Marshal(rejectReq)
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/manager/leave/"+createdLeave.ID.String()+"/reject", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", managerAuth)
+	req.Header.Set(handlers.HeaderIfMatch, etag)
+	rec = httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
 	if rec.Code != http.StatusOK {
 		t.Errorf("Expected status %d, got %d. Body: %s", http.StatusOK, rec.Code, rec.Body.String())
 		return
 	}
 
-	var rejectedLeave models.LeaveRequest
+	var rejectedLeave models.LeaveRequestJSON
 	json.Unmarshal(rec.Body.Bytes(), &rejectedLeave)
 
 	if rejectedLeave.Status != models.LeaveStatusRejected {
@@ -398,8 +425,8 @@ func TestLeaveIntegration_UnauthorizedAccess(t *testing.T) {
 	createReq := map[string]interface{}{
 		"leaveType": "annual",
 		"reason":    "Vacation time",
-		"startDate": "2024-06-01T00:00:00Z",
-		"endDate":   "2024-06-05T00:00:00Z",
+		"startDate": testStartDate,
+		"endDate":   testEndDate,
 	}
 
 	body, _ := json.Marshal(createReq)
@@ -470,3 +497,14 @@ func TestLeaveIntegration_InvalidToken(t *testing.T) {
 	}
 }
 
+// responseETag returns the ETag of a leave response, to send as If-Match on
+// the next write
+func responseETag(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	etag := rec.Header().Get(handlers.HeaderETag)
+	if etag == "" {
+		t.Fatalf("Expected an ETag header, got none. Body: %s", rec.Body.String())
+	}
+	return etag
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/models"
 	"leave-management-system/internal/utils"
 )
 
@@ -47,6 +48,9 @@ func AuthMiddleware() echo.MiddlewareFunc {
 			c.Set("userEmail", userInfo.Email)
 			c.Set("userName", userInfo.Name)
 			c.Set("userRoles", userInfo.Roles)
+			c.Set("userTimezone", userInfo.Timezone)
+			c.Set("userDepartment", userInfo.Department)
+			c.Set("userLocale", userInfo.Locale)
 
 			// Update logger with user context
 			if logFromCtx := GetLogger(c); logFromCtx != nil {
@@ -119,3 +123,31 @@ func GetUserName(c echo.Context) (string, error) {
 	return name, nil
 }
 
+// GetUserRoles extracts user roles from context
+func GetUserRoles(c echo.Context) []string {
+	roles, _ := c.Get("userRoles").([]string)
+	return roles
+}
+
+// GetEmployee builds the employee identity of the authenticated user from context
+func GetEmployee(c echo.Context) (*models.Employee, error) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		return nil, err
+	}
+	name, _ := GetUserName(c)
+	email, _ := GetUserEmail(c)
+	timezone, _ := c.Get("userTimezone").(string)
+	department, _ := c.Get("userDepartment").(string)
+	locale, _ := c.Get("userLocale").(string)
+
+	return &models.Employee{
+		ID:         userID,
+		Name:       name,
+		Email:      email,
+		Timezone:   timezone,
+		Department: department,
+		Locale:     locale,
+	}, nil
+}
+
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+)
+
+// ErrorHandler renders errors returned by handlers and middleware as RFC 7807
+// problem details carrying the error code and request ID. When hideInternal is
+// set, as in production, the causes of server errors are not shown to clients.
+func ErrorHandler(hideInternal bool) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		appErr := apperror.From(err)
+		problem := apperror.NewProblem(appErr, !hideInternal)
+		problem.Instance = c.Request().URL.Path
+		problem.RequestID, _ = c.Get("request_id").(string)
+
+		var writeErr error
+		if c.Request().Method == http.MethodHead {
+			writeErr = c.NoContent(appErr.Status)
+		} else {
+			c.Response().Header().Set(echo.HeaderContentType, apperror.MIMEProblemJSON)
+			writeErr = c.JSON(appErr.Status, problem)
+		}
+		if writeErr != nil {
+			GetLogger(c).Errorf("error_response_failed status=%d code=%s error=%v", appErr.Status, appErr.Code, writeErr)
+		}
+	}
+}
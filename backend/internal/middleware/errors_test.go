@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+)
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		hideInternal bool
+		err          error
+		wantStatus   int
+		wantCode     apperror.Code
+		wantDetail   string
+	}{
+		{
+			name:       "application error",
+			err:        apperror.New(http.StatusNotFound, apperror.CodeLeaveNotFound, "Leave request not found"),
+			wantStatus: http.StatusNotFound,
+			wantCode:   apperror.CodeLeaveNotFound,
+			wantDetail: "Leave request not found",
+		},
+		{
+			name:       "echo error",
+			err:        echo.NewHTTPError(http.StatusForbidden, "Insufficient permissions"),
+			wantStatus: http.StatusForbidden,
+			wantCode:   apperror.CodeForbidden,
+			wantDetail: "Insufficient permissions",
+		},
+		{
+			name:       "internal error in development",
+			err:        apperror.Internal(errors.New("pq: connection refused")),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   apperror.CodeInternal,
+			wantDetail: "Internal server error: pq: connection refused",
+		},
+		{
+			name:         "internal error in production",
+			hideInternal: true,
+			err:          errors.New("pq: connection refused"),
+			wantStatus:   http.StatusInternalServerError,
+			wantCode:     apperror.CodeInternal,
+			wantDetail:   "Internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = ErrorHandler(tt.hideInternal)
+			e.GET("/api/v1/leave/:id", func(c echo.Context) error {
+				return tt.err
+			}, RequestLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/leave/42", nil)
+			req.Header.Set("X-Request-ID", "req-1")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if contentType := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(contentType, apperror.MIMEProblemJSON) {
+				t.Errorf("expected %s, got %s", apperror.MIMEProblemJSON, contentType)
+			}
+
+			var problem apperror.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("failed to unmarshal problem: %v", err)
+			}
+			if problem.Code != tt.wantCode || problem.Detail != tt.wantDetail || problem.Status != tt.wantStatus {
+				t.Errorf("unexpected problem: %+v", problem)
+			}
+			if problem.Type != "about:blank" || problem.Title != http.StatusText(tt.wantStatus) {
+				t.Errorf("unexpected type or title: %+v", problem)
+			}
+			if problem.Instance != "/api/v1/leave/42" || problem.RequestID != "req-1" {
+				t.Errorf("expected the path and request ID, got %+v", problem)
+			}
+		})
+	}
+}
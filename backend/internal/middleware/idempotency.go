@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/services"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// replayedHeaders are the response headers stored with an idempotent response
+var replayedHeaders = []string{echo.HeaderContentType, echo.HeaderLocation, "ETag"}
+
+// Idempotency lets clients retry a request without repeating it by sending an
+// Idempotency-Key header. The first successful response for a user's key is
+// stored and returned to retries with the same body; reusing the key for a
+// different body is rejected with 422. Requests without the header are
+// processed as usual. It must run after AuthMiddleware.
+func Idempotency(service *services.IdempotencyService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderIdempotencyKey)
+			if key == "" {
+				return next(c)
+			}
+
+			log := GetLogger(c)
+
+			userID, err := GetUserID(c)
+			if err != nil {
+				log.Warnf("idempotency_failed reason=unauthorized error=%v", err)
+				return apperror.Unauthorized(err)
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				log.Warnf("idempotency_failed reason=read_body error=%v", err)
+				return apperror.InvalidBody(err)
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+			requestHash := hashRequest(c.Request().Method, c.Request().URL.Path, body)
+
+			ctx := c.Request().Context()
+			stored, err := service.Begin(ctx, userID, key, requestHash)
+			switch {
+			case errors.Is(err, services.ErrInvalidIdempotencyKey):
+				log.Warnf("idempotency_failed reason=invalid_key user_id=%s", userID)
+				return apperror.New(http.StatusBadRequest, apperror.CodeInvalidIdempotencyKey, err.Error())
+			case errors.Is(err, services.ErrIdempotencyKeyReused):
+				log.Warnf("idempotency_failed reason=key_reused user_id=%s key=%q", userID, key)
+				return apperror.New(http.StatusUnprocessableEntity, apperror.CodeIdempotencyKeyReused, err.Error())
+			case errors.Is(err, services.ErrIdempotencyKeyInProgress):
+				log.Warnf("idempotency_failed reason=in_progress user_id=%s key=%q", userID, key)
+				return apperror.New(http.StatusConflict, apperror.CodeIdempotencyKeyInUse, err.Error())
+			case err != nil:
+				log.Errorf("idempotency_failed user_id=%s key=%q error=%v", userID, key, err)
+				return apperror.Internal(err)
+			}
+
+			if stored != nil {
+				log.Infof("idempotency_replay user_id=%s key=%q status=%d", userID, key, stored.StatusCode)
+				for name, value := range stored.Headers {
+					c.Response().Header().Set(name, value)
+				}
+				c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+				return c.Blob(stored.StatusCode, stored.Headers[echo.HeaderContentType], stored.Body)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			err = next(c)
+			c.Response().Writer = recorder.ResponseWriter
+
+			// Record the outcome even if the client has gone away, so its retry
+			// is not told the request is still in progress
+			ctx = context.WithoutCancel(ctx)
+			status := c.Response().Status
+			if err != nil || status < 200 || status >= 300 {
+				// Failed requests are not stored so they can be retried with the same key
+				if releaseErr := service.Release(ctx, userID, key); releaseErr != nil {
+					log.Errorf("idempotency_release_failed user_id=%s key=%q error=%v", userID, key, releaseErr)
+				}
+				return err
+			}
+
+			headers := make(map[string]string)
+			for _, name := range replayedHeaders {
+				if value := c.Response().Header().Get(name); value != "" {
+					headers[name] = value
+				}
+			}
+			if err := service.Complete(ctx, userID, key, requestHash, status, headers, recorder.body.Bytes()); err != nil {
+				log.Errorf("idempotency_complete_failed user_id=%s key=%q error=%v", userID, key, err)
+				if releaseErr := service.Release(ctx, userID, key); releaseErr != nil {
+					log.Errorf("idempotency_release_failed user_id=%s key=%q error=%v", userID, key, releaseErr)
+				}
+			}
+			return nil
+		}
+	}
+}
+
+// hashRequest identifies a request by its method, path and body
+func hashRequest(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder copies the response body as it is written
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/services"
+)
+
+func TestIdempotency(t *testing.T) {
+	service := services.NewIdempotencyService(repository.NewMockIdempotencyRepository(), 24*time.Hour, 2*time.Minute)
+
+	created := 0
+	fail := false
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(false)
+	e.POST("/api/v1/leave", func(c echo.Context) error {
+		if fail {
+			return echo.NewHTTPError(http.StatusInternalServerError, "database unavailable")
+		}
+		created++
+		c.Response().Header().Set("ETag", `"1"`)
+		return c.JSON(http.StatusCreated, map[string]int{"created": created})
+	}, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("userID", "emp-1")
+			return next(c)
+		}
+	}, Idempotency(service))
+
+	post := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/leave", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if key != "" {
+			req.Header.Set(HeaderIdempotencyKey, key)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := post("key-1", `{"reason":"Holiday"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", first.Code)
+	}
+
+	retry := post("key-1", `{"reason":"Holiday"}`)
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Fatalf("expected the original response replayed, got %d %s", retry.Code, retry.Body.String())
+	}
+	if retry.Header().Get(HeaderIdempotentReplayed) != "true" || retry.Header().Get("ETag") != `"1"` {
+		t.Errorf("expected replay headers, got %v", retry.Header())
+	}
+	if created != 1 {
+		t.Errorf("expected the handler to run once, ran %d times", created)
+	}
+
+	if rec := post("key-1", `{"reason":"Different"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 reusing a key for another body, got %d", rec.Code)
+	}
+
+	if rec := post("", `{"reason":"Holiday"}`); rec.Code != http.StatusCreated || created != 2 {
+		t.Errorf("expected requests without a key to be processed, got %d", rec.Code)
+	}
+
+	// A failed request releases its key so the retry is processed
+	fail = true
+	if rec := post("key-2", `{"reason":"Holiday"}`); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	fail = false
+	if rec := post("key-2", `{"reason":"Holiday"}`); rec.Code != http.StatusCreated || created != 3 {
+		t.Errorf("expected the retry after a failure to be processed, got %d", rec.Code)
+	}
+
+	if rec := post("bad key", `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid key, got %d", rec.Code)
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"leave-management-system/internal/logger"
@@ -28,7 +30,7 @@ func RequestLogger() echo.MiddlewareFunc {
 			// Log request
 			log.Infof("request_start method=%s path=%s remote_addr=%s",
 				c.Request().Method,
-				c.Request().URL.Path,
+				redactPath(c.Request().URL.Path),
 				c.Request().RemoteAddr,
 			)
 
@@ -37,6 +39,10 @@ func RequestLogger() echo.MiddlewareFunc {
 
 			// Continue request
 			err := next(c)
+			if err != nil {
+				// Render the error now so the logged status is the one sent
+				c.Error(err)
+			}
 
 			// Log response
 			status := c.Response().Status
@@ -51,6 +57,20 @@ func RequestLogger() echo.MiddlewareFunc {
 	}
 }
 
+// secretPathPrefixes start the paths of routes authorized by a secret token
+// in the path, such as calendar feeds and email actions
+var secretPathPrefixes = []string{"/ical/", "/email-actions/"}
+
+// redactPath hides the secret in path, if any, so it does not reach the logs
+func redactPath(path string) string {
+	for _, prefix := range secretPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return prefix + "REDACTED"
+		}
+	}
+	return path
+}
+
 // GetLogger extracts logger from Echo context
 func GetLogger(c echo.Context) *logger.Logger {
 	if log, ok := c.Get("logger").(*logger.Logger); ok {
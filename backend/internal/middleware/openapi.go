@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/utils"
+)
+
+// OpenAPIValidator rejects requests that do not match the OpenAPI document with
+// a 400 listing the mismatches. When validateResponses is set, responses are
+// buffered and one that does not match its documented status, headers or body
+// is replaced by a 500, so drift between handlers and the document shows up in
+// development and tests. Event streams are never buffered. Authentication is
+// left to AuthMiddleware, and routes missing from the document are passed
+// through.
+func OpenAPIValidator(doc *openapi3.T, validateResponses bool) (echo.MiddlewareFunc, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAPI router: %w", err)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			route, pathParams, err := router.FindRoute(req)
+			if err != nil {
+				return next(c)
+			}
+
+			log := GetLogger(c)
+			input := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+					MultiError:         true,
+				},
+			}
+			if err := openapi3filter.ValidateRequest(req.Context(), input); err != nil {
+				log.Warnf("openapi_request_invalid method=%s path=%s error=%q", req.Method, req.URL.Path, err.Error())
+				return requestMismatch(err)
+			}
+
+			if !validateResponses || streams(route) {
+				return next(c)
+			}
+			return validateResponse(c, next, input, route)
+		}
+	}, nil
+}
+
+// streams reports whether route responds with Server-Sent Events, which must
+// reach the client as they are written
+func streams(route *routers.Route) bool {
+	if route.Operation == nil || route.Operation.Responses == nil {
+		return false
+	}
+	for _, response := range route.Operation.Responses.Map() {
+		if response.Value != nil && response.Value.Content.Get("text/event-stream") != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// validateResponse runs next with the response buffered and writes it only if
+// it matches the document
+func validateResponse(c echo.Context, next echo.HandlerFunc, input *openapi3filter.RequestValidationInput, route *routers.Route) error {
+	res := c.Response()
+	original := res.Writer
+	headers := res.Header().Clone()
+	buffer := &bufferedWriter{header: original.Header()}
+	res.Writer = buffer
+
+	err := next(c)
+	res.Writer = original
+	if err != nil || !res.Committed {
+		// Errors are rendered by the error handler once the writer is restored
+		return err
+	}
+
+	contentType := res.Header().Get(echo.HeaderContentType)
+	output := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 res.Status,
+		Header:                 res.Header(),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			// Only JSON bodies are checked; files are streamed as is
+			ExcludeResponseBody: !strings.Contains(contentType, "json"),
+			MultiError:          true,
+		},
+	}
+	output.SetBodyBytes(buffer.body.Bytes())
+	if err := openapi3filter.ValidateResponse(c.Request().Context(), output); err != nil {
+		GetLogger(c).Errorf("openapi_response_invalid method=%s path=%s status=%d error=%q", c.Request().Method, route.Path, res.Status, err.Error())
+
+		// Discard the buffered response so the error can be written instead
+		for name := range res.Header() {
+			res.Header().Del(name)
+		}
+		for name, values := range headers {
+			res.Header()[name] = values
+		}
+		res.Committed = false
+		res.Size = 0
+		return apperror.Internal(fmt.Errorf("response does not match the OpenAPI document: %w", err))
+	}
+
+	original.WriteHeader(res.Status)
+	_, err = original.Write(buffer.body.Bytes())
+	return err
+}
+
+// requestMismatch describes how a request differs from the document. Schema
+// violations are reported per field like validation errors.
+func requestMismatch(err error) error {
+	var fields utils.FieldErrors
+	collectFieldErrors(err, &fields)
+	if len(fields) == 0 {
+		return apperror.New(http.StatusBadRequest, apperror.CodeBadRequest, err.Error())
+	}
+	return apperror.Validation(fields)
+}
+
+func collectFieldErrors(err error, fields *utils.FieldErrors) {
+	if multi, ok := err.(openapi3.MultiError); ok {
+		for _, err := range multi {
+			collectFieldErrors(err, fields)
+		}
+		return
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return
+	}
+	if multi, ok := requestErr.Err.(openapi3.MultiError); ok {
+		for _, err := range multi {
+			collectFieldErrors(&openapi3filter.RequestError{Input: requestErr.Input, Parameter: requestErr.Parameter, RequestBody: requestErr.RequestBody, Err: err}, fields)
+		}
+		return
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(requestErr.Err, &schemaErr) {
+		return
+	}
+	field := jsonPath(schemaErr.JSONPointer())
+	if requestErr.Parameter != nil {
+		field = requestErr.Parameter.Name
+	}
+	message := schemaErr.Reason
+	if field != "" {
+		message = fmt.Sprintf("%s: %s", field, schemaErr.Reason)
+	}
+	*fields = append(*fields, utils.FieldError{Field: field, Rule: schemaErr.SchemaField, Message: message})
+}
+
+// jsonPath formats a JSON pointer the way field errors name fields, e.g. "items[0].id"
+func jsonPath(pointer []string) string {
+	var path strings.Builder
+	for _, segment := range pointer {
+		if _, err := strconv.Atoi(segment); err == nil {
+			fmt.Fprintf(&path, "[%s]", segment)
+			continue
+		}
+		if path.Len() > 0 {
+			path.WriteByte('.')
+		}
+		path.WriteString(segment)
+	}
+	return path.String()
+}
+
+// bufferedWriter holds a response back until it has been validated
+type bufferedWriter struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteHeader(int) {}
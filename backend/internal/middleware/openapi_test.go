@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"leave-management-system/internal/apperror"
+	"leave-management-system/internal/openapi"
+)
+
+const validLeave = `{
+	"id": "8c8a8d1e-6a0b-4a8f-9a55-3f0f7a1c2b3d",
+	"employeeId": "emp-1",
+	"employeeName": "Jane Doe",
+	"employeeEmail": "jane@example.com",
+	"leaveType": "annual",
+	"reason": "Family holiday",
+	"startDate": "2030-01-07",
+	"endDate": "2030-01-08",
+	"timezone": "UTC",
+	"days": 2,
+	"status": "pending",
+	"version": 1,
+	"createdAt": "2029-12-01T09:00:00Z",
+	"updatedAt": "2029-12-01T09:00:00Z"
+}`
+
+func TestOpenAPIValidator(t *testing.T) {
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("failed to load OpenAPI document: %v", err)
+	}
+	validator, err := OpenAPIValidator(doc, true)
+	if err != nil {
+		t.Fatalf("failed to build validator: %v", err)
+	}
+
+	response := validLeave
+	status := http.StatusCreated
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(false)
+	e.Use(validator)
+	e.POST("/api/v1/leave", func(c echo.Context) error {
+		c.Response().Header().Set("ETag", `"1"`)
+		return c.JSONBlob(status, []byte(response))
+	})
+	e.GET("/api/v1/leave/:id", func(c echo.Context) error {
+		return c.JSONBlob(http.StatusOK, []byte(validLeave))
+	})
+	e.GET("/api/v1/events", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+		c.Response().WriteHeader(http.StatusOK)
+		c.Response().Write([]byte("retry: 3000\n\n"))
+		c.Response().Flush()
+		return nil
+	})
+	e.GET("/undocumented", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	send := func(method, path, body string) (*httptest.ResponseRecorder, apperror.Problem) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		var problem apperror.Problem
+		if rec.Code >= 400 {
+			json.Unmarshal(rec.Body.Bytes(), &problem)
+		}
+		return rec, problem
+	}
+
+	valid := `{"leaveType":"annual","reason":"Family holiday","startDate":"2030-01-07","endDate":"2030-01-08"}`
+	if rec, _ := send(http.MethodPost, "/api/v1/leave", valid); rec.Code != http.StatusCreated || rec.Header().Get("ETag") != `"1"` {
+		t.Fatalf("expected a valid request and response to pass, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec, problem := send(http.MethodPost, "/api/v1/leave", `{"leaveType":"sabbatical","startDate":"next week","notes":"x"}`)
+	if rec.Code != http.StatusBadRequest || problem.Code != apperror.CodeValidationFailed {
+		t.Fatalf("expected 400 %s, got %d %s", apperror.CodeValidationFailed, rec.Code, rec.Body.String())
+	}
+	rules := make(map[string]string)
+	for _, fieldErr := range problem.Errors {
+		rules[fieldErr.Field] = fieldErr.Rule
+	}
+	if rules["leaveType"] != "enum" || rules["startDate"] != "pattern" || rules[""] != "properties" {
+		t.Errorf("unexpected field errors: %v", problem.Errors)
+	}
+
+	if rec, problem := send(http.MethodGet, "/api/v1/leave/42", ""); rec.Code != http.StatusBadRequest || problem.Errors[0].Field != "id" {
+		t.Errorf("expected 400 for a malformed path parameter, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	// A response that drifted from the document is replaced by an error
+	response = `{"id":"8c8a8d1e-6a0b-4a8f-9a55-3f0f7a1c2b3d","status":"pending"}`
+	if rec, problem := send(http.MethodPost, "/api/v1/leave", valid); rec.Code != http.StatusInternalServerError || problem.Code != apperror.CodeInternal {
+		t.Errorf("expected 500 for an invalid response body, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec, _ := send(http.MethodPost, "/api/v1/leave", valid); rec.Header().Get("ETag") != "" {
+		t.Errorf("expected the invalid response's headers to be discarded, got %v", rec.Header())
+	}
+
+	response, status = validLeave, http.StatusAccepted
+	if rec, _ := send(http.MethodPost, "/api/v1/leave", valid); rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 for an undocumented status, got %d", rec.Code)
+	}
+
+	// Event streams are flushed as they are written, not buffered
+	if rec, _ := send(http.MethodGet, "/api/v1/events", ""); rec.Code != http.StatusOK || !rec.Flushed || rec.Body.String() != "retry: 3000\n\n" {
+		t.Errorf("expected the event stream passed through, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	if rec, _ := send(http.MethodGet, "/undocumented", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected routes missing from the document to pass, got %d", rec.Code)
+	}
+}
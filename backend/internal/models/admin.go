@@ -0,0 +1,28 @@
+package models
+
+// ForceStatusRequest represents the payload for an admin overriding a request's status
+type ForceStatusRequest struct {
+	Status LeaveStatus `json:"status" validate:"required,oneof=pending approved rejected cancelled"`
+	Reason string      `json:"reason" validate:"required"`
+}
+
+// ReassignApproverRequest represents the payload for assigning a different approver
+type ReassignApproverRequest struct {
+	ApproverID    string `json:"approverId" validate:"required"`
+	ApproverName  string `json:"approverName"`
+	ApproverEmail string `json:"approverEmail" validate:"omitempty,email"`
+	Reason        string `json:"reason"`
+}
+
+// CorrectDaysRequest represents the payload for correcting a request's day count
+type CorrectDaysRequest struct {
+	Days   int    `json:"days" validate:"required,min=1"`
+	Reason string `json:"reason" validate:"required"`
+}
+
+// AuditFilter narrows a listing of the audit trail. Zero values match everything.
+type AuditFilter struct {
+	ActorID string
+	Action  HistoryAction
+	Limit   int
+}
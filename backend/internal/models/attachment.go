@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment represents a file attached to a leave request (e.g. a medical certificate)
+type Attachment struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	LeaveRequestID uuid.UUID `json:"leaveRequestId" db:"leave_request_id"`
+	FileName       string    `json:"fileName" db:"file_name"`
+	ContentType    string    `json:"contentType" db:"content_type"`
+	SizeBytes      int64     `json:"sizeBytes" db:"size_bytes"`
+	StorageKey     string    `json:"-" db:"storage_key"`
+	UploadedBy     string    `json:"uploadedBy" db:"uploaded_by"`
+	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
+}
+
+// AllowedAttachmentContentTypes lists the content types accepted for attachments
+var AllowedAttachmentContentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+}
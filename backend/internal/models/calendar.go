@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// CalendarFeedKind is what a calendar feed lists
+type CalendarFeedKind string
+
+const (
+	// CalendarFeedPersonal lists the subscriber's own approved leave
+	CalendarFeedPersonal CalendarFeedKind = "personal"
+	// CalendarFeedTeam lists the approved leave of the subscriber's department
+	CalendarFeedTeam CalendarFeedKind = "team"
+	// CalendarFeedHolidays lists the company holidays
+	CalendarFeedHolidays CalendarFeedKind = "holidays"
+)
+
+// IsValid reports whether k is a supported feed kind
+func (k CalendarFeedKind) IsValid() bool {
+	switch k {
+	case CalendarFeedPersonal, CalendarFeedTeam, CalendarFeedHolidays:
+		return true
+	}
+	return false
+}
+
+// CalendarPrivacy is how much a feed tells about each leave
+type CalendarPrivacy string
+
+const (
+	// CalendarPrivacyDetails shows the type of leave, e.g. "Sick leave"
+	CalendarPrivacyDetails CalendarPrivacy = "details"
+	// CalendarPrivacyBusy shows every leave as "Out of office"
+	CalendarPrivacyBusy CalendarPrivacy = "busy"
+)
+
+// CalendarFeed is an employee's subscription to a calendar of leave. Anyone
+// with the token in its URL can read the feed, so the token is only shown to
+// its owner, once, and only its hash is stored.
+type CalendarFeed struct {
+	EmployeeID string           `json:"employeeId" db:"employee_id"`
+	Kind       CalendarFeedKind `json:"kind" db:"kind"`
+	// TokenHash is the hex SHA-256 of the token in the feed's URL
+	TokenHash    string `json:"-" db:"token_hash"`
+	EmployeeName string `json:"employeeName" db:"employee_name"`
+	// Department is the team of a team feed, as of the last time its owner saved it
+	Department string `json:"department" db:"department"`
+	// Privacy of a team feed is busy unless its owner was an approver or HR
+	// the last time they saved it
+	Privacy   CalendarPrivacy `json:"privacy" db:"privacy"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time       `json:"updatedAt" db:"updated_at"`
+}
+
+// CalendarFeedInfo is a calendar feed as shown to its owner
+type CalendarFeedInfo struct {
+	Kind    CalendarFeedKind `json:"kind"`
+	Privacy CalendarPrivacy  `json:"privacy"`
+	// URL to subscribe to, only set when the feed is created or given a new
+	// URL, as its token is not stored
+	URL       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// UpdateCalendarFeedRequest is the payload for creating or changing a calendar feed
+type UpdateCalendarFeedRequest struct {
+	// Privacy defaults to details for a new personal feed and busy for a new
+	// team feed, and is kept otherwise
+	Privacy CalendarPrivacy `json:"privacy" validate:"omitempty,oneof=details busy"`
+}
+
+// Holiday is a day the whole company is off
+type Holiday struct {
+	Date Date   `json:"date"`
+	Name string `json:"name"`
+}
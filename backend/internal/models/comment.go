@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxCommentLength is the maximum number of characters in a comment
+const MaxCommentLength = 2000
+
+// LeaveComment is a message in the discussion thread of a leave request
+type LeaveComment struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	LeaveRequestID uuid.UUID `json:"leaveRequestId" db:"leave_request_id"`
+	AuthorID       string    `json:"authorId" db:"author_id"`
+	AuthorName     string    `json:"authorName" db:"author_name"`
+	AuthorEmail    string    `json:"authorEmail" db:"author_email"`
+	Body           string    `json:"body" db:"body"`
+	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt      time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// Recipient is someone to notify, with the locale to write to them in;
+// an empty locale means the default
+type Recipient struct {
+	Email  string `json:"email"`
+	Locale string `json:"locale,omitempty"`
+}
+
+// CommentRequest represents the payload for creating or editing a comment
+type CommentRequest struct {
+	Body string `json:"body" validate:"required,max=2000"`
+}
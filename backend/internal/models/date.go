@@ -0,0 +1,168 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// dateLayout is the ISO 8601 calendar date format used for JSON and SQL
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date with no time of day or time zone, matching a
+// PostgreSQL DATE column. Leave is taken in whole days, so comparing leave
+// dates must not depend on the server's clock zone.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDate returns the date for the given year, month and day, normalizing
+// out-of-range values the way time.Date does
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns the calendar date of t in t's location
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// Today returns the current calendar date in loc
+func Today(loc *time.Location) Date {
+	return DateOf(time.Now().In(loc))
+}
+
+// ParseDate parses a date in YYYY-MM-DD format. For compatibility with
+// clients that send timestamps, an RFC 3339 value is also accepted and its
+// date is taken as written, ignoring the time and offset.
+func ParseDate(s string) (Date, error) {
+	if t, err := time.Parse(dateLayout, s); err == nil {
+		return DateOf(t), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return DateOf(t), nil
+	}
+	return Date{}, fmt.Errorf("invalid date %q: expected YYYY-MM-DD", s)
+}
+
+// String returns the date in YYYY-MM-DD format, or "" for the zero Date
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.In(time.UTC).Format(dateLayout)
+}
+
+// Format formats the date with a time layout string
+func (d Date) Format(layout string) string {
+	return d.In(time.UTC).Format(layout)
+}
+
+// In returns midnight at the start of the date in loc
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// IsZero reports whether the date is unset
+func (d Date) IsZero() bool {
+	return d.Year == 0 && d.Month == 0 && d.Day == 0
+}
+
+// Before reports whether d is before other
+func (d Date) Before(other Date) bool {
+	return d.compare(other) < 0
+}
+
+// After reports whether d is after other
+func (d Date) After(other Date) bool {
+	return d.compare(other) > 0
+}
+
+// AddDays returns the date n days after d
+func (d Date) AddDays(n int) Date {
+	return DateOf(d.In(time.UTC).AddDate(0, 0, n))
+}
+
+// DaysSince returns the number of days from other to d
+func (d Date) DaysSince(other Date) int {
+	return int(d.In(time.UTC).Sub(other.In(time.UTC)).Hours() / 24)
+}
+
+// Weekday returns the day of the week of d
+func (d Date) Weekday() time.Weekday {
+	return d.In(time.UTC).Weekday()
+}
+
+func (d Date) compare(other Date) int {
+	switch {
+	case d.Year != other.Year:
+		return d.Year - other.Year
+	case d.Month != other.Month:
+		return int(d.Month) - int(other.Month)
+	default:
+		return d.Day - other.Day
+	}
+}
+
+// MarshalJSON encodes the date as a "YYYY-MM-DD" string, or null when unset
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a "YYYY-MM-DD" (or RFC 3339) string
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid date: %w", err)
+	}
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value implements driver.Valuer for DATE columns
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+// Scan implements sql.Scanner for DATE columns
+func (d *Date) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case time.Time:
+		// The driver returns DATE values as midnight UTC
+		*d = DateOf(v)
+		return nil
+	case string:
+		parsed, err := ParseDate(v)
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	case []byte:
+		return d.Scan(string(v))
+	case nil:
+		*d = Date{}
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Date", src)
+	}
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Date
+		wantErr bool
+	}{
+		{"date only", "2024-03-15", NewDate(2024, 3, 15), false},
+		{"RFC 3339 UTC", "2024-03-15T00:00:00Z", NewDate(2024, 3, 15), false},
+		// The date is taken as written, not converted to UTC
+		{"RFC 3339 with offset", "2024-03-15T06:30:00+07:00", NewDate(2024, 3, 15), false},
+		{"invalid", "15/03/2024", Date{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDate(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDate(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDate_JSON(t *testing.T) {
+	d := NewDate(2024, 2, 29)
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `"2024-02-29"` {
+		t.Errorf("expected \"2024-02-29\", got %s", data)
+	}
+
+	var decoded Date
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded != d {
+		t.Errorf("expected %v, got %v", d, decoded)
+	}
+
+	data, _ = json.Marshal(Date{})
+	if string(data) != "null" {
+		t.Errorf("expected zero date to marshal as null, got %s", data)
+	}
+}
+
+func TestDate_Scan(t *testing.T) {
+	var d Date
+	if err := d.Scan(time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d != NewDate(2024, 6, 3) {
+		t.Errorf("expected 2024-06-03, got %v", d)
+	}
+
+	if err := d.Scan([]byte("2024-12-31")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d != NewDate(2024, 12, 31) {
+		t.Errorf("expected 2024-12-31, got %v", d)
+	}
+}
+
+func TestDate_Arithmetic(t *testing.T) {
+	d := NewDate(2024, 12, 30)
+
+	if got := d.AddDays(3); got != NewDate(2025, 1, 2) {
+		t.Errorf("AddDays across year end = %v", got)
+	}
+	if got := NewDate(2025, 1, 2).DaysSince(d); got != 3 {
+		t.Errorf("DaysSince = %d, want 3", got)
+	}
+	if !d.Before(d.AddDays(1)) || d.After(d.AddDays(1)) {
+		t.Errorf("Before/After ordering is wrong")
+	}
+}
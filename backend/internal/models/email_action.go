@@ -0,0 +1,24 @@
+package models
+
+import "github.com/google/uuid"
+
+// EmailAction is a decision an approver can take from a notification email
+type EmailAction string
+
+const (
+	EmailActionApprove EmailAction = "approve"
+	EmailActionReject  EmailAction = "reject"
+)
+
+// EmailActionClaims are what an email action link is signed for: one decision
+// on one version of a leave request, by the approvers it was emailed to
+type EmailActionClaims struct {
+	LeaveID uuid.UUID   `json:"l"`
+	Action  EmailAction `json:"a"`
+	// Version is the version of the request the email described
+	Version int `json:"v"`
+	// Approver is the address the link was sent to
+	Approver string `json:"r"`
+	// ExpiresAt is a Unix time
+	ExpiresAt int64 `json:"e"`
+}
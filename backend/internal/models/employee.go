@@ -0,0 +1,13 @@
+package models
+
+// Employee describes the person a leave request belongs to, as identified by their token
+type Employee struct {
+	ID       string
+	Name     string
+	Email    string
+	Timezone string // IANA time zone name, e.g. "Asia/Bangkok"; empty for the office default
+	// Department is the organisational unit used to filter team leave; may be empty
+	Department string
+	// Locale is the language notifications are written in, e.g. "th"; empty for the default
+	Locale string
+}
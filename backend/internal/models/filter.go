@@ -0,0 +1,53 @@
+package models
+
+// LeaveSort is a field leave listings can be ordered by
+type LeaveSort string
+
+const (
+	SortCreatedAt    LeaveSort = "createdAt"
+	SortStartDate    LeaveSort = "startDate"
+	SortEndDate      LeaveSort = "endDate"
+	SortDays         LeaveSort = "days"
+	SortEmployeeName LeaveSort = "employeeName"
+)
+
+// IsValid reports whether s is one of the supported sort fields
+func (s LeaveSort) IsValid() bool {
+	switch s {
+	case SortCreatedAt, SortStartDate, SortEndDate, SortDays, SortEmployeeName:
+		return true
+	}
+	return false
+}
+
+// LeaveFilter narrows and orders a leave listing. Zero values match everything.
+type LeaveFilter struct {
+	Status     LeaveStatus
+	LeaveType  LeaveType
+	EmployeeID string
+	Department string
+	// Query matches the employee's name or email, case-insensitively
+	Query string
+	// From and To select requests overlapping the date range
+	From Date
+	To   Date
+	// Approvers limits requests to those addressed to one of these approver
+	// emails, case-insensitively; "" matches requests without an approver
+	// of their own
+	Approvers []string
+
+	Sort       LeaveSort
+	Descending bool
+	// Cursor continues a previous listing from its NextCursor
+	Cursor string
+	Limit  int
+}
+
+// LeavePage is one page of a leave listing
+type LeavePage struct {
+	Items []*LeaveRequest
+	// NextCursor fetches the following page; empty on the last page
+	NextCursor string
+	// Total is the number of requests matching the filter across all pages
+	Total int
+}
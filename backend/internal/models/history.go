@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HistoryAction describes what happened to a leave request in a history entry
+type HistoryAction string
+
+const (
+	HistoryActionCreated   HistoryAction = "created"
+	HistoryActionUpdated   HistoryAction = "updated"
+	HistoryActionCancelled HistoryAction = "cancelled"
+
+	// Administrative corrections
+	HistoryActionStatusForced       HistoryAction = "status_forced"
+	HistoryActionApproverReassigned HistoryAction = "approver_reassigned"
+	HistoryActionDaysCorrected      HistoryAction = "days_corrected"
+)
+
+// LeaveHistoryEntry records a change to a leave request and who made it.
+// ActorID differs from EmployeeID when someone acted on the employee's behalf.
+type LeaveHistoryEntry struct {
+	ID             uuid.UUID     `json:"id" db:"id"`
+	LeaveRequestID uuid.UUID     `json:"leaveRequestId" db:"leave_request_id"`
+	EmployeeID     string        `json:"employeeId" db:"employee_id"`
+	ActorID        string        `json:"actorId" db:"actor_id"`
+	ActorName      string        `json:"actorName" db:"actor_name"`
+	Action         HistoryAction `json:"action" db:"action"`
+	Status         LeaveStatus   `json:"status" db:"status"`
+	Note           string        `json:"note,omitempty" db:"note"`
+	CreatedAt      time.Time     `json:"createdAt" db:"created_at"`
+}
+
+// OnBehalf reports whether the change was made by someone other than the employee
+func (e *LeaveHistoryEntry) OnBehalf() bool {
+	return e.ActorID != e.EmployeeID
+}
+
+// OnBehalfCreateLeaveRequest represents the payload for HR recording leave for an employee
+type OnBehalfCreateLeaveRequest struct {
+	EmployeeID       string `json:"employeeId" validate:"required"`
+	EmployeeName     string `json:"employeeName" validate:"required"`
+	EmployeeEmail    string `json:"employeeEmail" validate:"required,email"`
+	EmployeeTimezone string `json:"employeeTimezone"`
+	Department       string `json:"department"`
+	LeaveType        string `json:"leaveType" validate:"required,leavetype"`
+	Reason           string `json:"reason"`
+	StartDate        Date   `json:"startDate" validate:"required"`
+	EndDate          Date   `json:"endDate" validate:"required,onorafter=StartDate"`
+	// Approved records the request as already approved, e.g. phoned-in sick leave
+	Approved bool   `json:"approved"`
+	Comment  string `json:"comment"`
+	// AllowPastDates permits back-dated leave
+	AllowPastDates bool `json:"allowPastDates"`
+}
+
+// OnBehalfUpdateLeaveRequest represents the payload for HR editing an employee's leave
+type OnBehalfUpdateLeaveRequest struct {
+	UpdateLeaveRequest
+	AllowPastDates bool `json:"allowPastDates"`
+}
+
+// OnBehalfCancelLeaveRequest represents the payload for HR cancelling an employee's leave
+type OnBehalfCancelLeaveRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// MaxIdempotencyKeyLength is the maximum length of an Idempotency-Key header
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyKey records a request made with an Idempotency-Key header and,
+// once it has completed, the response to replay when the request is retried
+type IdempotencyKey struct {
+	UserID      string            `db:"user_id"`
+	Key         string            `db:"idempotency_key"`
+	RequestHash string            `db:"request_hash"` // SHA-256 of the method, path and body
+	StatusCode  int               `db:"status_code"`  // 0 while the original request is in progress
+	Headers     map[string]string `db:"response_headers"`
+	Body        []byte            `db:"response_body"`
+	CreatedAt   time.Time         `db:"created_at"`
+}
+
+// Completed reports whether the original request has finished and its response was stored
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
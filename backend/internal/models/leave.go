@@ -18,29 +18,57 @@ const (
 	LeaveTypeOther    LeaveType = "other"
 )
 
+// IsValid reports whether t is one of the known leave types
+func (t LeaveType) IsValid() bool {
+	switch t {
+	case LeaveTypeAnnual, LeaveTypeSick, LeaveTypePersonal, LeaveTypeOther:
+		return true
+	}
+	return false
+}
+
 // LeaveStatus represents the status of a leave request
 type LeaveStatus string
 
 const (
+	// LeaveStatusDraft is a request still being prepared by its owner. Drafts
+	// are not visible to approvers and are not validated until submitted.
+	LeaveStatusDraft     LeaveStatus = "draft"
 	LeaveStatusPending   LeaveStatus = "pending"
 	LeaveStatusApproved  LeaveStatus = "approved"
 	LeaveStatusRejected  LeaveStatus = "rejected"
 	LeaveStatusCancelled LeaveStatus = "cancelled"
 )
 
+// IsValid reports whether s is one of the known statuses
+func (s LeaveStatus) IsValid() bool {
+	switch s {
+	case LeaveStatusDraft, LeaveStatusPending, LeaveStatusApproved, LeaveStatusRejected, LeaveStatusCancelled:
+		return true
+	}
+	return false
+}
+
 // LeaveRequest represents a leave request in the database
 type LeaveRequest struct {
 	ID             uuid.UUID      `json:"id" db:"id"`
 	EmployeeID     string          `json:"employeeId" db:"employee_id"`
 	EmployeeName   string          `json:"employeeName" db:"employee_name"`
 	EmployeeEmail  string          `json:"employeeEmail" db:"employee_email"`
+	Department     string          `json:"department" db:"employee_department"`
 	LeaveType      LeaveType       `json:"leaveType" db:"leave_type"`
 	Reason         string          `json:"reason" db:"reason"`
-	StartDate      time.Time       `json:"startDate" db:"start_date"`
-	EndDate        time.Time       `json:"endDate" db:"end_date"`
+	StartDate      Date            `json:"startDate" db:"start_date"`
+	EndDate        Date            `json:"endDate" db:"end_date"`
+	Timezone       string          `json:"timezone" db:"timezone"`
+	Locale         string          `json:"locale" db:"locale"` // Language of the employee's notifications
 	Days           int             `json:"days" db:"days"`
 	Status         LeaveStatus     `json:"status" db:"status"`
 	ManagerComment sql.NullString  `json:"-" db:"manager_comment"` // Use custom MarshalJSON
+	ApproverID     string          `json:"approverId" db:"approver_id"`
+	ApproverName   string          `json:"approverName" db:"approver_name"`
+	ApproverEmail  string          `json:"approverEmail" db:"approver_email"`
+	Version        int             `json:"version" db:"version"` // Incremented on every change
 	CreatedAt      time.Time       `json:"createdAt" db:"created_at"`
 	UpdatedAt      time.Time       `json:"updatedAt" db:"updated_at"`
 }
@@ -51,13 +79,20 @@ type LeaveRequestJSON struct {
 	EmployeeID     string      `json:"employeeId"`
 	EmployeeName   string      `json:"employeeName"`
 	EmployeeEmail  string      `json:"employeeEmail"`
+	Department     string      `json:"department,omitempty"`
 	LeaveType      LeaveType   `json:"leaveType"`
 	Reason         string      `json:"reason"`
-	StartDate      time.Time   `json:"startDate"`
-	EndDate        time.Time   `json:"endDate"`
+	StartDate      Date        `json:"startDate"`
+	EndDate        Date        `json:"endDate"`
+	Timezone       string      `json:"timezone"`
+	Locale         string      `json:"locale,omitempty"`
 	Days           int         `json:"days"`
 	Status         LeaveStatus `json:"status"`
 	ManagerComment string      `json:"managerComment,omitempty"`
+	ApproverID     string      `json:"approverId,omitempty"`
+	ApproverName   string      `json:"approverName,omitempty"`
+	ApproverEmail  string      `json:"approverEmail,omitempty"`
+	Version        int         `json:"version"`
 	CreatedAt      time.Time   `json:"createdAt"`
 	UpdatedAt      time.Time   `json:"updatedAt"`
 }
@@ -69,12 +104,19 @@ func (l *LeaveRequest) MarshalJSON() ([]byte, error) {
 		EmployeeID:    l.EmployeeID,
 		EmployeeName:  l.EmployeeName,
 		EmployeeEmail: l.EmployeeEmail,
+		Department:    l.Department,
 		LeaveType:     l.LeaveType,
 		Reason:        l.Reason,
 		StartDate:     l.StartDate,
 		EndDate:       l.EndDate,
+		Timezone:      l.Timezone,
+		Locale:        l.Locale,
 		Days:          l.Days,
 		Status:        l.Status,
+		ApproverID:    l.ApproverID,
+		ApproverName:  l.ApproverName,
+		ApproverEmail: l.ApproverEmail,
+		Version:       l.Version,
 		CreatedAt:     l.CreatedAt,
 		UpdatedAt:     l.UpdatedAt,
 	}
@@ -88,6 +130,38 @@ func (l *LeaveRequest) MarshalJSON() ([]byte, error) {
 	return json.Marshal((*Alias)(&jsonData))
 }
 
+// UnmarshalJSON reverses MarshalJSON, e.g. for leave requests stored in notification payloads
+func (l *LeaveRequest) UnmarshalJSON(data []byte) error {
+	var jsonData LeaveRequestJSON
+	if err := json.Unmarshal(data, &jsonData); err != nil {
+		return err
+	}
+
+	*l = LeaveRequest{
+		ID:             jsonData.ID,
+		EmployeeID:     jsonData.EmployeeID,
+		EmployeeName:   jsonData.EmployeeName,
+		EmployeeEmail:  jsonData.EmployeeEmail,
+		Department:     jsonData.Department,
+		LeaveType:      jsonData.LeaveType,
+		Reason:         jsonData.Reason,
+		StartDate:      jsonData.StartDate,
+		EndDate:        jsonData.EndDate,
+		Timezone:       jsonData.Timezone,
+		Locale:         jsonData.Locale,
+		Days:           jsonData.Days,
+		Status:         jsonData.Status,
+		ManagerComment: sql.NullString{String: jsonData.ManagerComment, Valid: jsonData.ManagerComment != ""},
+		ApproverID:     jsonData.ApproverID,
+		ApproverName:   jsonData.ApproverName,
+		ApproverEmail:  jsonData.ApproverEmail,
+		Version:        jsonData.Version,
+		CreatedAt:      jsonData.CreatedAt,
+		UpdatedAt:      jsonData.UpdatedAt,
+	}
+	return nil
+}
+
 // GetManagerComment returns the manager comment as a string, or empty string if NULL
 func (l *LeaveRequest) GetManagerComment() string {
 	if l.ManagerComment.Valid {
@@ -96,20 +170,22 @@ func (l *LeaveRequest) GetManagerComment() string {
 	return ""
 }
 
-// CreateLeaveRequest represents the payload for creating a leave request
+// CreateLeaveRequest represents the payload for creating a leave request.
+// When Draft is set the request is saved as a draft and dates may be left empty.
 type CreateLeaveRequest struct {
-	LeaveType string    `json:"leaveType" validate:"required,oneof=annual sick personal other"`
-	Reason    string    `json:"reason" validate:"omitempty,min=10"`
-	StartDate time.Time `json:"startDate" validate:"required"`
-	EndDate   time.Time `json:"endDate" validate:"required,gtfield=StartDate"`
+	LeaveType string `json:"leaveType" validate:"required,leavetype"`
+	Reason    string `json:"reason" validate:"omitempty,min=10"`
+	StartDate Date   `json:"startDate" validate:"required_unless=Draft true,notpast_unless=Draft"`
+	EndDate   Date   `json:"endDate" validate:"required_unless=Draft true,onorafter=StartDate"`
+	Draft     bool   `json:"draft"`
 }
 
 // UpdateLeaveRequest represents the payload for updating a leave request
 type UpdateLeaveRequest struct {
-	LeaveType string     `json:"leaveType" validate:"omitempty,oneof=annual sick personal other"`
-	Reason    string     `json:"reason" validate:"omitempty,min=10"`
-	StartDate *time.Time `json:"startDate" validate:"omitempty"`
-	EndDate   *time.Time `json:"endDate" validate:"omitempty,gtfield=StartDate"`
+	LeaveType string `json:"leaveType" validate:"omitempty,leavetype"`
+	Reason    string `json:"reason" validate:"omitempty,min=10"`
+	StartDate *Date  `json:"startDate" validate:"omitempty"`
+	EndDate   *Date  `json:"endDate" validate:"omitempty,onorafter=StartDate"`
 }
 
 // ApproveLeaveRequest represents the payload for approving a leave request
@@ -122,9 +198,56 @@ type RejectLeaveRequest struct {
 	Comment string `json:"comment" validate:"required,min=10"`
 }
 
+// BulkLeaveAction is the decision applied by a bulk review
+type BulkLeaveAction string
+
+const (
+	BulkActionApprove BulkLeaveAction = "approve"
+	BulkActionReject  BulkLeaveAction = "reject"
+)
+
+// BulkLeaveOutcome is the result of a bulk review for a single request
+type BulkLeaveOutcome string
+
+const (
+	BulkOutcomeApproved         BulkLeaveOutcome = "approved"
+	BulkOutcomeRejected         BulkLeaveOutcome = "rejected"
+	BulkOutcomeAlreadyProcessed BulkLeaveOutcome = "already_processed"
+	BulkOutcomeNotFound         BulkLeaveOutcome = "not_found"
+	BulkOutcomePolicyViolation  BulkLeaveOutcome = "policy_violation"
+	BulkOutcomeError            BulkLeaveOutcome = "error"
+)
+
+// BulkLeaveItem identifies one request in a bulk review, at the version the
+// reviewer saw, with an optional comment
+type BulkLeaveItem struct {
+	ID      uuid.UUID `json:"id" validate:"required"`
+	Version int       `json:"version" validate:"required,min=1"`
+	Comment string    `json:"comment"`
+}
+
+// BulkLeaveRequest represents the payload for approving or rejecting many requests at once
+type BulkLeaveRequest struct {
+	Action BulkLeaveAction `json:"action" validate:"required,oneof=approve reject"`
+	Items  []BulkLeaveItem `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+// BulkLeaveResult reports what happened to one request in a bulk review
+type BulkLeaveResult struct {
+	ID      uuid.UUID        `json:"id"`
+	Outcome BulkLeaveOutcome `json:"outcome"`
+	Message string           `json:"message,omitempty"`
+	Leave   *LeaveRequest    `json:"leave,omitempty"`
+}
+
 // CalculateDays calculates the number of leave days between start and end date
 // Excludes weekends (Saturday and Sunday)
-func CalculateDays(startDate, endDate time.Time) int {
+func CalculateDays(startDate, endDate Date) int {
+	// Unset dates (drafts) cover no days
+	if startDate.IsZero() || endDate.IsZero() {
+		return 0
+	}
+
 	// Include both start and end dates
 	days := 0
 	currentDate := startDate
@@ -135,7 +258,7 @@ func CalculateDays(startDate, endDate time.Time) int {
 		if weekday != time.Saturday && weekday != time.Sunday {
 			days++
 		}
-		currentDate = currentDate.AddDate(0, 0, 1)
+		currentDate = currentDate.AddDays(1)
 	}
 
 	return days
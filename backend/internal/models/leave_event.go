@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LeaveEventType is the kind of change a leave event reports
+type LeaveEventType string
+
+const (
+	LeaveEventCreated   LeaveEventType = "leave.created"
+	LeaveEventUpdated   LeaveEventType = "leave.updated"
+	LeaveEventApproved  LeaveEventType = "leave.approved"
+	LeaveEventRejected  LeaveEventType = "leave.rejected"
+	LeaveEventCancelled LeaveEventType = "leave.cancelled"
+)
+
+// LeaveChange is a committed write to a leave request, as published by the
+// database on the leave_changes channel
+type LeaveChange struct {
+	// Operation is INSERT or UPDATE
+	Operation  string      `json:"op"`
+	ID         uuid.UUID   `json:"id"`
+	EmployeeID string      `json:"employeeId"`
+	Status     LeaveStatus `json:"status"`
+	// PreviousStatus is empty for a new request
+	PreviousStatus LeaveStatus `json:"previousStatus"`
+	Version        int         `json:"version"`
+	At             time.Time   `json:"at"`
+}
+
+// Event describes the change to real-time subscribers. A write that moves a
+// request to approved, rejected or cancelled is reported as that decision;
+// any other write to an existing request, including its submission, is an
+// update.
+func (c *LeaveChange) Event() *LeaveEvent {
+	eventType := LeaveEventUpdated
+	switch {
+	case c.Operation == "INSERT":
+		eventType = LeaveEventCreated
+	case c.Status == c.PreviousStatus:
+	case c.Status == LeaveStatusApproved:
+		eventType = LeaveEventApproved
+	case c.Status == LeaveStatusRejected:
+		eventType = LeaveEventRejected
+	case c.Status == LeaveStatusCancelled:
+		eventType = LeaveEventCancelled
+	}
+	return &LeaveEvent{
+		Type:           eventType,
+		LeaveRequestID: c.ID,
+		EmployeeID:     c.EmployeeID,
+		Status:         c.Status,
+		Version:        c.Version,
+		At:             c.At,
+	}
+}
+
+// LeaveEvent tells a real-time subscriber that a leave request they can see
+// changed, so they can reload it
+type LeaveEvent struct {
+	Type           LeaveEventType `json:"type"`
+	LeaveRequestID uuid.UUID      `json:"leaveRequestId"`
+	EmployeeID     string         `json:"employeeId"`
+	Status         LeaveStatus    `json:"status"`
+	// Version is the request's version after the change, as in its ETag
+	Version int       `json:"version"`
+	At      time.Time `json:"at"`
+}
@@ -1,57 +1,62 @@
 package models
 
 import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 func TestCalculateDays(t *testing.T) {
 	tests := []struct {
 		name      string
-		startDate time.Time
-		endDate   time.Time
+		startDate Date
+		endDate   Date
 		want      int
 	}{
 		{
 			name:      "single weekday",
-			startDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), // Monday
-			endDate:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			startDate: NewDate(2024, 1, 1), // Monday
+			endDate:   NewDate(2024, 1, 1),
 			want:      1,
 		},
 		{
 			name:      "week spanning Monday to Friday",
-			startDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), // Monday
-			endDate:   time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), // Friday
+			startDate: NewDate(2024, 1, 1), // Monday
+			endDate:   NewDate(2024, 1, 5), // Friday
 			want:      5,
 		},
 		{
 			name:      "week spanning Monday to Sunday (excludes weekend)",
-			startDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), // Monday
-			endDate:   time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC), // Sunday
+			startDate: NewDate(2024, 1, 1), // Monday
+			endDate:   NewDate(2024, 1, 7), // Sunday
 			want:      5, // Mon-Fri only
 		},
 		{
 			name:      "two full weeks",
-			startDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), // Monday
-			endDate:   time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC), // Sunday (2 weeks later)
+			startDate: NewDate(2024, 1, 1), // Monday
+			endDate:   NewDate(2024, 1, 14), // Sunday (2 weeks later)
 			want:      10, // 2 weeks * 5 weekdays
 		},
 		{
 			name:      "weekend only (should return 0)",
-			startDate: time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), // Saturday
-			endDate:   time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC), // Sunday
+			startDate: NewDate(2024, 1, 6), // Saturday
+			endDate:   NewDate(2024, 1, 7), // Sunday
 			want:      0,
 		},
 		{
 			name:      "Friday to Monday (excludes weekend)",
-			startDate: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), // Friday
-			endDate:   time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), // Monday
+			startDate: NewDate(2024, 1, 5), // Friday
+			endDate:   NewDate(2024, 1, 8), // Monday
 			want:      2, // Friday and Monday only
 		},
 		{
 			name:      "long period with multiple weekends",
-			startDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), // Monday
-			endDate:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), // Wednesday (end of month)
+			startDate: NewDate(2024, 1, 1), // Monday
+			endDate:   NewDate(2024, 1, 31), // Wednesday (end of month)
 			want:      23, // Approximate: 31 days - 8-9 weekends = ~23 weekdays
 		},
 	}
@@ -106,3 +111,34 @@ func TestLeaveStatus_String(t *testing.T) {
 	}
 }
 
+
+func TestLeaveRequest_JSONRoundTrip(t *testing.T) {
+	leave := &LeaveRequest{
+		ID:             uuid.New(),
+		EmployeeID:     "emp-1",
+		EmployeeName:   "John Doe",
+		EmployeeEmail:  "john@example.com",
+		LeaveType:      LeaveTypeAnnual,
+		StartDate:      NewDate(2030, time.January, 7),
+		EndDate:        NewDate(2030, time.January, 8),
+		Timezone:       "UTC",
+		Days:           2,
+		Status:         LeaveStatusApproved,
+		ManagerComment: sql.NullString{String: "Enjoy", Valid: true},
+		Version:        3,
+		CreatedAt:      time.Date(2029, time.December, 1, 9, 0, 0, 0, time.UTC),
+		UpdatedAt:      time.Date(2029, time.December, 2, 9, 0, 0, 0, time.UTC),
+	}
+
+	data, err := json.Marshal(leave)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	var decoded LeaveRequest
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, leave) {
+		t.Errorf("round trip changed the leave request:\n got %+v\nwant %+v", decoded, *leave)
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification is an entry in a user's in-app inbox. One is kept for each
+// recipient of every notification sent, whichever channels delivered it.
+type Notification struct {
+	ID uuid.UUID `json:"id"`
+	// Recipient is the lowercased email address the notification was sent to
+	Recipient string `json:"-"`
+	Event     string `json:"event"`
+	Subject   string `json:"subject"`
+	Summary   string `json:"summary"`
+	// LeaveRequestID is the request the notification is about, if it is
+	// about a single one
+	LeaveRequestID *uuid.UUID `json:"leaveRequestId,omitempty"`
+	ReadAt         *time.Time `json:"readAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	// Key identifies the content, so a redelivered notification is kept once
+	Key string `json:"-"`
+}
+
+// NotificationFilter selects a page of a recipient's inbox, newest first
+type NotificationFilter struct {
+	Recipient string
+	// Unread leaves out notifications already read
+	Unread bool
+	// Cursor continues a previous listing from its NextCursor
+	Cursor string
+	Limit  int
+}
+
+// NotificationPage is one page of an inbox listing
+type NotificationPage struct {
+	Items []*Notification
+	// NextCursor fetches the following page; empty on the last page
+	NextCursor string
+	// Total is the number of notifications matching the filter across all pages
+	Total int
+}
+
+// UnreadCount is how many notifications in an inbox are unread
+type UnreadCount struct {
+	Unread int `json:"unread"`
+}
+
+// MarkedRead is how many notifications a request marked as read
+type MarkedRead struct {
+	Updated int `json:"updated"`
+}
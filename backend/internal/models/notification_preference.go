@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// NotificationMode is how someone wants to be told about one kind of event
+type NotificationMode string
+
+const (
+	// NotificationImmediate delivers each notification as it happens
+	NotificationImmediate NotificationMode = "immediate"
+	// NotificationDigest leaves the event to the daily digest instead
+	NotificationDigest NotificationMode = "digest"
+	// NotificationOff delivers nothing
+	NotificationOff NotificationMode = "off"
+)
+
+// EventPreference is how someone wants to receive one event
+type EventPreference struct {
+	Mode NotificationMode `json:"mode" validate:"required,oneof=immediate digest off"`
+	// Channels limits immediate notifications to these of the channels the
+	// event is routed to; empty for all of them
+	Channels []string `json:"channels,omitempty" validate:"omitempty,dive,oneof=email slack teams webhook"`
+}
+
+// NotificationPreferences are one person's notification settings. People
+// without any keep the defaults: every event immediately, on every channel.
+type NotificationPreferences struct {
+	EmployeeID string `json:"employeeId" db:"employee_id"`
+	// Email is the address notifications to the employee are sent to; it is
+	// how their preferences are found when notifying them
+	Email        string `json:"email" db:"email"`
+	EmployeeName string `json:"employeeName" db:"employee_name"`
+	// Department, Timezone and Locale are as of the last time the employee
+	// saved their preferences, for the digest
+	Department string `json:"department" db:"department"`
+	Timezone   string `json:"timezone" db:"timezone"`
+	Locale     string `json:"locale" db:"locale"`
+	// Events maps a notification event, e.g. "leave.submitted", to how the
+	// employee wants it; events not listed are immediate on every channel
+	Events map[string]EventPreference `json:"events" db:"events"`
+	// DigestTime is the local time of day the digest is sent, as "15:04";
+	// empty for the configured default
+	DigestTime string `json:"digestTime" db:"digest_time"`
+	// LastDigestOn is the employee's local date the last digest was queued
+	// for; zero if none was
+	LastDigestOn Date      `json:"-" db:"last_digest_on"`
+	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt    time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// WantsDigest reports whether any event is left to the daily digest
+func (p *NotificationPreferences) WantsDigest() bool {
+	for _, pref := range p.Events {
+		if pref.Mode == NotificationDigest {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationPreferencesInfo is what an employee sees of their preferences:
+// every event that can be configured, with its current setting
+type NotificationPreferencesInfo struct {
+	Events map[string]EventPreference `json:"events"`
+	// DigestTime is the local time the digest is sent, as "15:04"
+	DigestTime string `json:"digestTime"`
+	// Timezone is the zone DigestTime is in
+	Timezone string `json:"timezone"`
+	// UpdatedAt is when the preferences were last changed; nil if never
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// UpdateNotificationPreferencesRequest is the payload for changing
+// notification preferences. Only the events listed change; an empty
+// DigestTime keeps the current one.
+type UpdateNotificationPreferencesRequest struct {
+	Events     map[string]EventPreference `json:"events" validate:"omitempty,dive"`
+	DigestTime string                     `json:"digestTime" validate:"omitempty,datetime=15:04"`
+}
+
+// DigestRequest is the payload of OutboxTopicDigest messages: the digest of
+// one employee for one of their local dates
+type DigestRequest struct {
+	EmployeeID string `json:"employeeId"`
+	Date       Date   `json:"date"`
+}
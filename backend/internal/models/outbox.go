@@ -0,0 +1,119 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxTopic names the kind of notification an outbox message carries
+type OutboxTopic string
+
+const (
+	// OutboxTopicLeaveDecided tells an employee their request was approved
+	// or rejected; the payload is the decided LeaveRequest
+	OutboxTopicLeaveDecided OutboxTopic = "leave.decided"
+	// OutboxTopicLeaveActivity tells approvers an employee submitted, edited
+	// or cancelled a request; the payload is a LeaveActivity
+	OutboxTopicLeaveActivity OutboxTopic = "leave.activity"
+	// OutboxTopicLeaveCalendar tells an employee their approved leave was
+	// withdrawn or moved, updating their calendar; the payload is a
+	// CalendarChange
+	OutboxTopicLeaveCalendar OutboxTopic = "leave.calendar"
+	// OutboxTopicLeaveComment tells the other party of a thread about a new
+	// comment; the payload is a CommentNotice
+	OutboxTopicLeaveComment OutboxTopic = "leave.comment"
+	// OutboxTopicDigest sends an approver their daily digest; the payload is
+	// a DigestRequest
+	OutboxTopicDigest OutboxTopic = "notification.digest"
+)
+
+// LeaveActivityAction is what an employee did to their leave request
+type LeaveActivityAction string
+
+const (
+	LeaveActivitySubmitted LeaveActivityAction = "submitted"
+	LeaveActivityUpdated   LeaveActivityAction = "updated"
+	LeaveActivityCancelled LeaveActivityAction = "cancelled"
+)
+
+// LeaveActivity is the payload of OutboxTopicLeaveActivity messages
+type LeaveActivity struct {
+	Action LeaveActivityAction `json:"action"`
+	Leave  *LeaveRequest       `json:"leave"`
+	// TeamAbsences are the approved leaves of others in the employee's
+	// department overlapping Leave when the change was made
+	TeamAbsences []*LeaveRequest `json:"teamAbsences,omitempty"`
+}
+
+// CalendarChangeAction is what happened to approved leave after it was approved
+type CalendarChangeAction string
+
+const (
+	// CalendarChangeWithdrawn means the leave is no longer approved
+	CalendarChangeWithdrawn CalendarChangeAction = "withdrawn"
+	// CalendarChangeRescheduled means the dates of approved leave changed
+	CalendarChangeRescheduled CalendarChangeAction = "rescheduled"
+)
+
+// CalendarChange is the payload of OutboxTopicLeaveCalendar messages
+type CalendarChange struct {
+	Action CalendarChangeAction `json:"action"`
+	Leave  *LeaveRequest        `json:"leave"`
+}
+
+// CommentNotice is the payload of OutboxTopicLeaveComment messages
+type CommentNotice struct {
+	Comment    *LeaveComment `json:"comment"`
+	Recipients []Recipient   `json:"recipients"`
+}
+
+// OutboxStatus is the delivery state of an outbox message
+type OutboxStatus string
+
+const (
+	OutboxStatusPending    OutboxStatus = "pending"
+	OutboxStatusProcessing OutboxStatus = "processing"
+	OutboxStatusDelivered  OutboxStatus = "delivered"
+	// OutboxStatusDead marks a message that ran out of attempts; it stays
+	// until an admin replays it
+	OutboxStatusDead OutboxStatus = "dead"
+)
+
+// IsValid reports whether s is a known outbox status
+func (s OutboxStatus) IsValid() bool {
+	switch s {
+	case OutboxStatusPending, OutboxStatusProcessing, OutboxStatusDelivered, OutboxStatusDead:
+		return true
+	}
+	return false
+}
+
+// OutboxMessage is a notification recorded in the same transaction as the
+// change it announces and delivered afterwards by the dispatcher. Messages
+// sharing a GroupKey are delivered together, e.g. one email for several
+// decisions made in a bulk review.
+type OutboxMessage struct {
+	ID       uuid.UUID       `json:"id" db:"id"`
+	Topic    OutboxTopic     `json:"topic" db:"topic"`
+	GroupKey string          `json:"groupKey,omitempty" db:"group_key"`
+	Payload  json.RawMessage `json:"payload" db:"payload"`
+	Status   OutboxStatus    `json:"status" db:"status"`
+	Attempts int             `json:"attempts" db:"attempts"`
+	// LastError is why the latest delivery attempt failed
+	LastError string `json:"lastError,omitempty" db:"last_error"`
+	// NextAttemptAt is when a pending message is due, or when the lease of
+	// one being processed expires
+	NextAttemptAt time.Time  `json:"nextAttemptAt" db:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updatedAt" db:"updated_at"`
+	DeliveredAt   *time.Time `json:"deliveredAt,omitempty" db:"delivered_at"`
+}
+
+// OutboxFilter narrows a listing of outbox messages. Zero values match everything.
+type OutboxFilter struct {
+	Status OutboxStatus
+	Topic  OutboxTopic
+	Limit  int
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// NotificationTemplate is an admin's replacement for one of the built-in
+// notification templates in one locale
+type NotificationTemplate struct {
+	Locale string `json:"locale" db:"locale"`
+	Name   string `json:"name" db:"name"`
+	// Text is a text/template defining the "subject" and "summary" templates
+	// and, at the top level, the plain-text body
+	Text string `json:"text" db:"text_body"`
+	// HTML is an html/template for the HTML body, rendered inside the layout
+	HTML      string    `json:"html" db:"html_body"`
+	UpdatedBy string    `json:"updatedBy" db:"updated_by"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// TemplateInfo describes one notification template and whether an admin has overridden it
+type TemplateInfo struct {
+	Locale     string     `json:"locale"`
+	Name       string     `json:"name"`
+	Overridden bool       `json:"overridden"`
+	UpdatedBy  string     `json:"updatedBy,omitempty"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+}
+
+// UpdateTemplateRequest is the payload for overriding a notification template
+type UpdateTemplateRequest struct {
+	Text string `json:"text" validate:"required,max=20000"`
+	HTML string `json:"html" validate:"required,max=50000"`
+}
+
+// TemplateDetail is a notification template as it is rendered: the override
+// if there is one, otherwise the built-in template
+type TemplateDetail struct {
+	TemplateInfo
+	Text string `json:"text"`
+	HTML string `json:"html"`
+}
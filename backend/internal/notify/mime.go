@@ -0,0 +1,177 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// buildMessage builds an RFC 5322 email of n to one recipient. Header values
+// are RFC 2047 encoded so non-ASCII subjects and names survive transport.
+// With an HTML body the text is multipart/alternative, plain text first so
+// clients that cannot display HTML fall back to it; with attachments the
+// whole is multipart/mixed.
+func buildMessage(from, to string, n *Notification, date time.Time) ([]byte, error) {
+	var msg bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&msg, "%s: %s\r\n", name, value)
+	}
+
+	header("From", formatAddress(from))
+	header("To", formatAddress(to))
+	header("Subject", mime.BEncoding.Encode("utf-8", n.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("Message-ID", messageID(from))
+	header("MIME-Version", "1.0")
+
+	bodyHeader, body, err := bodyEntity(n.Text, n.HTML)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(n.Attachments) == 0 {
+		writeHeader(&msg, bodyHeader)
+		msg.WriteString("\r\n")
+		msg.Write(body)
+		return msg.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&msg)
+	header("Content-Type", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": parts.Boundary()}))
+	msg.WriteString("\r\n")
+
+	w, err := parts.CreatePart(bodyHeader)
+	if err != nil {
+		return nil, err
+	}
+	w.Write(body)
+
+	for _, a := range n.Attachments {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		writeBase64(w, a.Data)
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+// bodyEntity returns the headers and encoded content of the message text:
+// text/plain, or multipart/alternative when there is an HTML version
+func bodyEntity(text, html string) (textproto.MIMEHeader, []byte, error) {
+	var body bytes.Buffer
+	if html == "" {
+		if err := writeQuotedPrintable(&body, text); err != nil {
+			return nil, nil, err
+		}
+		return textPart("text/plain"), body.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ mediaType, body string }{
+		{"text/plain", text},
+		{"text/html", html},
+	} {
+		w, err := parts.CreatePart(textPart(part.mediaType))
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	header := textproto.MIMEHeader{
+		"Content-Type": {mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": parts.Boundary()})},
+	}
+	return header, body.Bytes(), nil
+}
+
+// textPart returns the headers of a quoted-printable UTF-8 text part
+func textPart(mediaType string) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		"Content-Type":              {mediaType + `; charset="utf-8"`},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	}
+}
+
+// writeHeader writes the fields of h in a stable order
+func writeHeader(w io.Writer, h textproto.MIMEHeader) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range h[k] {
+			fmt.Fprintf(w, "%s: %s\r\n", k, v)
+		}
+	}
+}
+
+// writeQuotedPrintable writes body with CRLF line endings, quoted-printable encoded
+func writeQuotedPrintable(w io.Writer, body string) error {
+	body = strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n")
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// writeBase64 writes data base64 encoded in lines of 76 characters
+func writeBase64(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		io.WriteString(w, encoded[:76]+"\r\n")
+		encoded = encoded[76:]
+	}
+	io.WriteString(w, encoded+"\r\n")
+}
+
+// formatAddress encodes the display name of an address such as
+// "Leave System <noreply@company.com>". Bare addresses are returned as is.
+func formatAddress(address string) string {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return address
+	}
+	if parsed.Name == "" {
+		return parsed.Address
+	}
+	return parsed.String()
+}
+
+// messageID returns a unique Message-ID in the domain of the sender
+func messageID(from string) string {
+	domain := "localhost"
+	if parsed, err := mail.ParseAddress(from); err == nil {
+		if _, d, ok := strings.Cut(parsed.Address, "@"); ok {
+			domain = d
+		}
+	}
+	id := make([]byte, 16)
+	rand.Read(id)
+	return "<" + hex.EncodeToString(id) + "@" + domain + ">"
+}
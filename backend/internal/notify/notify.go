@@ -0,0 +1,164 @@
+// Package notify delivers notifications over pluggable channels: email, Slack
+// and Microsoft Teams incoming webhooks and a generic HTTP webhook. Which
+// channels receive an event is decided by per-event routes in configuration.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"leave-management-system/internal/config"
+)
+
+// Event names what a notification is about. Routes are keyed by event.
+type Event string
+
+const (
+	EventLeaveApproved Event = "leave.approved"
+	EventLeaveRejected Event = "leave.rejected"
+	// EventLeaveReviewed summarises several decisions made for one employee in a bulk review
+	EventLeaveReviewed Event = "leave.reviewed"
+	EventCommentAdded  Event = "comment.added"
+
+	// Events for approvers when an employee changes a request
+	EventLeaveSubmitted Event = "leave.submitted"
+	EventLeaveUpdated   Event = "leave.updated"
+	EventLeaveCancelled Event = "leave.cancelled"
+	// EventLeaveReceived confirms to the employee that their request was submitted
+	EventLeaveReceived Event = "leave.received"
+
+	// Events for the employee when approved leave changes, with a calendar update
+	EventLeaveWithdrawn   Event = "leave.withdrawn"
+	EventLeaveRescheduled Event = "leave.rescheduled"
+
+	// EventDailyDigest is an approver's daily summary of pending approvals
+	// and upcoming team absences
+	EventDailyDigest Event = "digest.daily"
+)
+
+// Notification is a message about an event, rendered for every channel
+type Notification struct {
+	Event Event
+	// To lists the email addresses of the people concerned. Chat channels
+	// post to the channel their webhook belongs to instead.
+	To      []string
+	Subject string
+	// Text is the full plain-text message, as sent by email
+	Text string
+	// HTML is an optional HTML version of Text, sent alongside it by email
+	HTML string
+	// Attachments are sent by email only, e.g. a calendar invite
+	Attachments []Attachment
+	// Summary is a one-line version for chat channels; Text is used when empty
+	Summary string
+	// Data is what the event is about, e.g. the leave request, and is
+	// included in generic webhook payloads
+	Data interface{}
+	// Channels, when not nil, limits delivery to these of the channels
+	// routed to Event, e.g. as the recipients asked
+	Channels []string
+}
+
+// Attachment is a file attached to an email
+type Attachment struct {
+	Filename string
+	// ContentType is the media type with its parameters, e.g.
+	// "text/calendar; method=REQUEST"
+	ContentType string
+	Data        []byte
+}
+
+// Key identifies the content of n, which is the same each time a
+// notification is delivered again
+func (n *Notification) Key() (string, error) {
+	data, err := json.Marshal(n.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode notification data: %w", err)
+	}
+	h := sha256.New()
+	for _, part := range [][]byte{[]byte(n.Event), []byte(n.Subject), []byte(n.Summary), data} {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// summary returns the text chat channels post
+func (n *Notification) summary() string {
+	if n.Summary != "" {
+		return n.Summary
+	}
+	return strings.TrimSpace(n.Text)
+}
+
+// Notifier delivers notifications over one channel, or several in the case of Router
+type Notifier interface {
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// New creates the notifier selected by configuration: a Router over every
+// configured channel using the routes in cfg.Notification.Routes, recording
+// deliveries in deliveries. Email is always available, and does nothing while
+// SMTP is not configured.
+func New(cfg *config.Config, deliveries DeliveryLog) (*Router, error) {
+	routes, err := ParseRoutes(cfg.Notification.Routes)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := map[string]Notifier{
+		ChannelEmail: NewSMTPNotifier(cfg.Email),
+	}
+	if url := cfg.Notification.SlackWebhookURL; url != "" {
+		channels[ChannelSlack] = NewSlackNotifier(url)
+	}
+	if url := cfg.Notification.TeamsWebhookURL; url != "" {
+		channels[ChannelTeams] = NewTeamsNotifier(url)
+	}
+	if url := cfg.Notification.WebhookURL; url != "" {
+		channels[ChannelWebhook] = NewWebhookNotifier(url, cfg.Notification.WebhookSecret)
+	}
+
+	return NewRouter(channels, routes, deliveries)
+}
+
+// httpClient is shared by the webhook channels. Deliveries that time out are
+// retried by the caller.
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// postJSON posts payload to url and fails on any non-2xx response
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}, header http.Header) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected response %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Channel names used in routes
+const (
+	ChannelEmail   = "email"
+	ChannelSlack   = "slack"
+	ChannelTeams   = "teams"
+	ChannelWebhook = "webhook"
+)
+
+// DefaultRoute is the route key matching events without a route of their own
+const DefaultRoute = "*"
+
+// Routes maps an event, or DefaultRoute, to the channels it is delivered on
+type Routes map[string][]string
+
+// ParseRoutes parses routes written as "event=channel,channel;event=channel",
+// e.g. "leave.approved=email,slack;*=email". An event with no channels, as in
+// "comment.added=", is not delivered anywhere.
+func ParseRoutes(s string) (Routes, error) {
+	routes := make(Routes)
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		event, list, ok := strings.Cut(entry, "=")
+		event = strings.TrimSpace(event)
+		if !ok || event == "" {
+			return nil, fmt.Errorf("invalid notification route %q: expected event=channel,...", entry)
+		}
+		if _, exists := routes[event]; exists {
+			return nil, fmt.Errorf("notification route for %s is defined twice", event)
+		}
+
+		channels := []string{}
+		for _, channel := range strings.Split(list, ",") {
+			if channel = strings.TrimSpace(channel); channel != "" {
+				channels = append(channels, channel)
+			}
+		}
+		routes[event] = channels
+	}
+	return routes, nil
+}
+
+// DeliveryLog records which channels and recipients a notification reached,
+// by the notification's Key
+type DeliveryLog interface {
+	// Delivered reports whether the notification with key was delivered on
+	// channel to recipient
+	Delivered(ctx context.Context, key, channel, recipient string) (bool, error)
+	// RecordDelivery records that it was
+	RecordDelivery(ctx context.Context, key, channel, recipient string) error
+}
+
+// Router is a Notifier that delivers each notification on the channels routed
+// to its event
+type Router struct {
+	channels map[string]Notifier
+	routes   Routes
+	// deliveries lets a redelivered notification skip the channels and
+	// recipients it already reached; may be nil
+	deliveries DeliveryLog
+}
+
+// NewRouter creates a router over the named channels. Every channel used in
+// routes must be among them. Deliveries are recorded in deliveries when it is
+// not nil.
+func NewRouter(channels map[string]Notifier, routes Routes, deliveries DeliveryLog) (*Router, error) {
+	for event, names := range routes {
+		for _, name := range names {
+			if _, ok := channels[name]; !ok {
+				return nil, fmt.Errorf("notification route for %s uses channel %q, which is not configured", event, name)
+			}
+		}
+	}
+	return &Router{channels: channels, routes: routes, deliveries: deliveries}, nil
+}
+
+// Channels returns the channels n's event is delivered on
+func (r *Router) Channels(event Event) []string {
+	if names, ok := r.routes[string(event)]; ok {
+		return names
+	}
+	return r.routes[DefaultRoute]
+}
+
+// Notify delivers n on every channel routed to its event, or those of them
+// in n.Channels when set. A failing channel does not stop the others; their
+// errors are returned together. With a delivery log, delivering n again, as
+// the outbox does after a failure, only retries what failed.
+func (r *Router) Notify(ctx context.Context, n *Notification) error {
+	var key string
+	if r.deliveries != nil {
+		var err error
+		if key, err = n.Key(); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for _, name := range r.Channels(n.Event) {
+		if n.Channels != nil && !contains(n.Channels, name) {
+			continue
+		}
+		if err := r.deliver(ctx, key, name, n); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver sends n on channel name. When deliveries are recorded, email is
+// sent and recorded one recipient at a time; the other channels post once.
+func (r *Router) deliver(ctx context.Context, key, name string, n *Notification) error {
+	if r.deliveries == nil {
+		return r.channels[name].Notify(ctx, n)
+	}
+	if name != ChannelEmail {
+		return r.deliverOnce(ctx, key, name, "", n)
+	}
+
+	var errs []error
+	for _, to := range n.To {
+		single := *n
+		single.To = []string{to}
+		if err := r.deliverOnce(ctx, key, name, strings.ToLower(strings.TrimSpace(to)), &single); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliverOnce sends n on channel name unless it already reached recipient
+// there, and records the delivery. When the log cannot be read n is sent
+// anyway, as a duplicate is better than a lost notification.
+func (r *Router) deliverOnce(ctx context.Context, key, name, recipient string, n *Notification) error {
+	if done, err := r.deliveries.Delivered(ctx, key, name, recipient); err == nil && done {
+		return nil
+	}
+	if err := r.channels[name].Notify(ctx, n); err != nil {
+		return err
+	}
+	if err := r.deliveries.RecordDelivery(ctx, key, name, recipient); err != nil {
+		return fmt.Errorf("delivered but not recorded: %w", err)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes the routes, e.g. for a startup log line
+func (r *Router) String() string {
+	events := make([]string, 0, len(r.routes))
+	for event := range r.routes {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+
+	parts := make([]string, len(events))
+	for i, event := range events {
+		parts[i] = event + "=" + strings.Join(r.routes[event], ",")
+	}
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,162 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"leave-management-system/internal/config"
+)
+
+// recorder is a channel that records the events it is sent
+type recorder struct {
+	events []Event
+	err    error
+}
+
+func (r *recorder) Notify(ctx context.Context, n *Notification) error {
+	r.events = append(r.events, n.Event)
+	return r.err
+}
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes(" leave.approved = email, slack ; comment.added= ;*=email")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Routes{
+		"leave.approved": {"email", "slack"},
+		"comment.added":  {},
+		"*":              {"email"},
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("expected %v, got %v", want, routes)
+	}
+
+	for _, invalid := range []string{"email", "=email", "*=email;*=slack"} {
+		if _, err := ParseRoutes(invalid); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestRouter_Notify(t *testing.T) {
+	email, slack := &recorder{}, &recorder{}
+	routes, _ := ParseRoutes("leave.approved=email,slack;comment.added=;*=email")
+	router, err := NewRouter(map[string]Notifier{ChannelEmail: email, ChannelSlack: slack}, routes, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, event := range []Event{EventLeaveApproved, EventLeaveRejected, EventCommentAdded} {
+		if err := router.Notify(context.Background(), &Notification{Event: event}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if want := []Event{EventLeaveApproved, EventLeaveRejected}; !reflect.DeepEqual(email.events, want) {
+		t.Errorf("expected email to get %v, got %v", want, email.events)
+	}
+	if want := []Event{EventLeaveApproved}; !reflect.DeepEqual(slack.events, want) {
+		t.Errorf("expected slack to get %v, got %v", want, slack.events)
+	}
+
+	// A notification can narrow its routed channels, but not add to them
+	email.events, slack.events = nil, nil
+	if err := router.Notify(context.Background(), &Notification{Event: EventLeaveApproved, Channels: []string{ChannelSlack}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := router.Notify(context.Background(), &Notification{Event: EventLeaveRejected, Channels: []string{ChannelSlack}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(email.events) != 0 || !reflect.DeepEqual(slack.events, []Event{EventLeaveApproved}) {
+		t.Errorf("expected only slack to get the approval, got email %v and slack %v", email.events, slack.events)
+	}
+}
+
+func TestRouter_FailingChannelDoesNotStopOthers(t *testing.T) {
+	slackErr := errors.New("unexpected response 500")
+	email, slack := &recorder{}, &recorder{err: slackErr}
+	router, _ := NewRouter(map[string]Notifier{ChannelEmail: email, ChannelSlack: slack}, Routes{"*": {"slack", "email"}}, nil)
+
+	err := router.Notify(context.Background(), &Notification{Event: EventLeaveApproved})
+	if !errors.Is(err, slackErr) || err.Error() != "slack: unexpected response 500" {
+		t.Errorf("expected the slack error, got %v", err)
+	}
+	if len(email.events) != 1 {
+		t.Errorf("expected email to be delivered despite slack failing")
+	}
+}
+
+// deliveryLog is an in-memory DeliveryLog
+type deliveryLog map[[3]string]bool
+
+func (l deliveryLog) Delivered(ctx context.Context, key, channel, recipient string) (bool, error) {
+	return l[[3]string{key, channel, recipient}], nil
+}
+
+func (l deliveryLog) RecordDelivery(ctx context.Context, key, channel, recipient string) error {
+	l[[3]string{key, channel, recipient}] = true
+	return nil
+}
+
+// mailbox is an email channel that records who it sent to and fails for
+// the addresses in bounce
+type mailbox struct {
+	sent   []string
+	bounce map[string]bool
+}
+
+func (m *mailbox) Notify(ctx context.Context, n *Notification) error {
+	for _, to := range n.To {
+		if m.bounce[to] {
+			return errors.New(to + ": mailbox unavailable")
+		}
+		m.sent = append(m.sent, to)
+	}
+	return nil
+}
+
+func TestRouter_RedeliverySkipsDelivered(t *testing.T) {
+	email := &mailbox{bounce: map[string]bool{"jane@example.com": true}}
+	slack := &recorder{}
+	router, _ := NewRouter(map[string]Notifier{ChannelEmail: email, ChannelSlack: slack}, Routes{"*": {"email", "slack"}}, deliveryLog{})
+
+	n := &Notification{Event: EventLeaveApproved, To: []string{"john@example.com", "jane@example.com"}, Subject: "Leave Request Approved"}
+	if err := router.Notify(context.Background(), n); err == nil {
+		t.Fatal("expected the bounce to fail the notification")
+	}
+
+	// The retry only goes to the recipient who did not get it
+	delete(email.bounce, "jane@example.com")
+	if err := router.Notify(context.Background(), n); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"john@example.com", "jane@example.com"}; !reflect.DeepEqual(email.sent, want) {
+		t.Errorf("expected email to %v, got %v", want, email.sent)
+	}
+	if len(slack.events) != 1 {
+		t.Errorf("expected slack to be posted to once, got %d", len(slack.events))
+	}
+}
+
+func TestNew(t *testing.T) {
+	cfg := &config.Config{Notification: config.NotificationConfig{
+		Routes:          "leave.approved=email,slack;*=email",
+		SlackWebhookURL: "https://hooks.slack.test/services/T000/B000/XXX",
+	}}
+	router, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := router.String(); got != "*=email;leave.approved=email,slack" {
+		t.Errorf("unexpected routes %s", got)
+	}
+
+	// A route to a channel without a webhook URL is a configuration mistake
+	cfg.Notification.Routes = "*=teams"
+	if _, err := New(cfg, nil); err == nil {
+		t.Error("expected an error for a route to an unconfigured channel")
+	}
+}
@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+)
+
+// SlackNotifier posts notifications to a Slack incoming webhook, i.e. to the
+// channel the webhook was created for
+type SlackNotifier struct {
+	url string
+}
+
+// NewSlackNotifier creates a notifier for the Slack incoming webhook at url
+func NewSlackNotifier(url string) *SlackNotifier {
+	return &SlackNotifier{url: url}
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// Notify posts the subject in bold followed by the summary
+func (s *SlackNotifier) Notify(ctx context.Context, n *Notification) error {
+	msg := slackMessage{Text: fmt.Sprintf("*%s*\n%s", n.Subject, n.summary())}
+	return postJSON(ctx, httpClient, s.url, msg, nil)
+}
@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"leave-management-system/internal/config"
+)
+
+// smtpTimeout bounds sending one email when the caller sets no earlier deadline
+const smtpTimeout = 30 * time.Second
+
+// SMTPNotifier emails a notification to each of its recipients
+type SMTPNotifier struct {
+	cfg config.EmailConfig
+}
+
+// NewSMTPNotifier creates an SMTP notifier. While cfg.Host is empty email is
+// not configured and notifications are dropped.
+func NewSMTPNotifier(cfg config.EmailConfig) *SMTPNotifier {
+	return &SMTPNotifier{cfg: cfg}
+}
+
+// Notify sends one email per recipient so addresses are not disclosed to each
+// other. Notifications with HTML are sent as multipart/alternative, and with
+// attachments as multipart/mixed.
+func (s *SMTPNotifier) Notify(ctx context.Context, n *Notification) error {
+	if s.cfg.Host == "" {
+		// Email not configured, skip sending
+		return nil
+	}
+
+	var errs []error
+	for _, to := range n.To {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.send(ctx, to, n); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", to, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// send emails n to one recipient. It works like smtp.SendMail, but the
+// connection is dialled with ctx and every read and write on it must finish
+// by the deadline of ctx, so a hung server cannot block the caller.
+func (s *SMTPNotifier) send(ctx context.Context, to string, n *Notification) error {
+	from := envelopeAddress(s.cfg.From)
+	if strings.ContainsAny(from+to, "\r\n") {
+		return errors.New("address contains a line break")
+	}
+
+	msg, err := buildMessage(s.cfg.From, to, n, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to build message: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	addr := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if s.cfg.User != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(smtp.PlainAuth("", s.cfg.User, s.cfg.Password, s.cfg.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// envelopeAddress returns the bare address of from, which may include a display name
+func envelopeAddress(from string) string {
+	if parsed, err := mail.ParseAddress(from); err == nil {
+		return parsed.Address
+	}
+	return from
+}
@@ -0,0 +1,231 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"leave-management-system/internal/config"
+)
+
+// fakeSMTP accepts mail on a local port and sends each message's recipient
+// and data on the returned channel
+func fakeSMTP(t *testing.T) (config.EmailConfig, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, messages)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return config.EmailConfig{Host: "127.0.0.1", Port: addr.Port, From: "noreply@company.com"}, messages
+}
+
+func serveSMTP(conn net.Conn, messages chan<- string) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP")
+
+	var rcpt string
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 localhost")
+		case "RCPT":
+			rcpt = strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>")
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 Go ahead")
+			data, err := bufio.NewReader(text.DotReader()).ReadString(0)
+			if err != nil && data == "" {
+				return
+			}
+			messages <- rcpt + "\n" + data
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("250 OK")
+		}
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	cfg, messages := fakeSMTP(t)
+
+	n := testNotification()
+	n.To = []string{"john@example.com", "jane@example.com"}
+	if err := NewSMTPNotifier(cfg).Notify(context.Background(), n); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// One message per recipient
+	for _, want := range n.To {
+		msg := <-messages
+		if !strings.HasPrefix(msg, want+"\n") {
+			t.Errorf("expected a message to %s, got %q", want, msg)
+		}
+		if !strings.Contains(msg, "Subject: Leave Request Approved") || !strings.Contains(msg, "Your leave request has been approved.") {
+			t.Errorf("unexpected message %q", msg)
+		}
+	}
+}
+
+func TestSMTPNotifier_NotConfigured(t *testing.T) {
+	if err := NewSMTPNotifier(config.EmailConfig{}).Notify(context.Background(), testNotification()); err != nil {
+		t.Errorf("expected notifications to be dropped without SMTP, got %v", err)
+	}
+}
+
+func TestSMTPNotifier_Unreachable(t *testing.T) {
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	err := NewSMTPNotifier(config.EmailConfig{Host: "127.0.0.1", Port: port}).Notify(context.Background(), testNotification())
+	if err == nil || !strings.Contains(err.Error(), "john@example.com") || !strings.Contains(err.Error(), strconv.Itoa(port)) {
+		t.Errorf("expected a connection error naming the recipient, got %v", err)
+	}
+}
+
+func TestSMTPNotifier_HungServer(t *testing.T) {
+	// The server accepts connections but never greets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	cfg := config.EmailConfig{Host: "127.0.0.1", Port: listener.Addr().(*net.TCPAddr).Port, From: "noreply@company.com"}
+
+	start := time.Now()
+	err = NewSMTPNotifier(cfg).Notify(ctx, testNotification())
+	if err == nil {
+		t.Fatal("expected a timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the deadline of ctx to stop the send, took %s", elapsed)
+	}
+}
+
+func TestSMTPNotifier_HTML(t *testing.T) {
+	cfg, messages := fakeSMTP(t)
+	cfg.From = "ระบบลา <noreply@company.com>"
+
+	n := testNotification()
+	n.Subject = "คำขอลาได้รับการอนุมัติ"
+	n.HTML = "<p>คำขอลาของคุณได้รับการอนุมัติแล้ว</p>"
+	if err := NewSMTPNotifier(cfg).Notify(context.Background(), n); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, data, _ := strings.Cut(<-messages, "\n")
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+
+	decoder := new(mime.WordDecoder)
+	if subject, _ := decoder.DecodeHeader(msg.Header.Get("Subject")); subject != n.Subject {
+		t.Errorf("expected the subject to be encoded, got %q", msg.Header.Get("Subject"))
+	}
+	if from, err := msg.Header.AddressList("From"); err != nil || from[0].Name != "ระบบลา" || from[0].Address != "noreply@company.com" {
+		t.Errorf("expected the sender name to be encoded, got %q", msg.Header.Get("From"))
+	}
+	if msg.Header.Get("Message-ID") == "" || msg.Header.Get("Date") == "" {
+		t.Errorf("expected Message-ID and Date headers, got %v", msg.Header)
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("expected multipart/alternative, got %q", mediaType)
+	}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for _, want := range []struct{ mediaType, body string }{{"text/plain", n.Text}, {"text/html", n.HTML}} {
+		part, err := parts.NextPart()
+		if err != nil {
+			t.Fatalf("expected a %s part: %v", want.mediaType, err)
+		}
+		// multipart.Reader decodes quoted-printable parts
+		body, _ := io.ReadAll(part)
+		if !strings.HasPrefix(part.Header.Get("Content-Type"), want.mediaType) || strings.ReplaceAll(string(body), "\r\n", "\n") != want.body {
+			t.Errorf("unexpected %s part %q: %q", want.mediaType, part.Header.Get("Content-Type"), body)
+		}
+	}
+}
+
+func TestSMTPNotifier_Attachments(t *testing.T) {
+	cfg, messages := fakeSMTP(t)
+
+	n := testNotification()
+	n.HTML = "<p>Your leave request has been approved.</p>"
+	invite := "BEGIN:VCALENDAR\r\nMETHOD:REQUEST\r\nEND:VCALENDAR\r\n"
+	n.Attachments = []Attachment{{Filename: "invite.ics", ContentType: "text/calendar; charset=utf-8; method=REQUEST", Data: []byte(invite)}}
+	if err := NewSMTPNotifier(cfg).Notify(context.Background(), n); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, data, _ := strings.Cut(<-messages, "\n")
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mediaType != "multipart/mixed" {
+		t.Fatalf("expected multipart/mixed, got %q", mediaType)
+	}
+
+	// The text and HTML versions, then the attachment
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	body, err := parts.NextPart()
+	if err != nil || !strings.HasPrefix(body.Header.Get("Content-Type"), "multipart/alternative") {
+		t.Fatalf("expected the alternative versions first, got %v (%v)", body.Header, err)
+	}
+	attachment, err := parts.NextPart()
+	if err != nil {
+		t.Fatalf("expected an attachment: %v", err)
+	}
+	encoded, _ := io.ReadAll(attachment)
+	decoded, _ := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	if attachment.FileName() != "invite.ics" || attachment.Header.Get("Content-Type") != n.Attachments[0].ContentType || string(decoded) != invite {
+		t.Errorf("unexpected attachment %v: %q", attachment.Header, decoded)
+	}
+}
@@ -0,0 +1,35 @@
+package notify
+
+import "context"
+
+// TeamsNotifier posts notifications to a Microsoft Teams incoming webhook
+type TeamsNotifier struct {
+	url string
+}
+
+// NewTeamsNotifier creates a notifier for the Teams incoming webhook at url
+func NewTeamsNotifier(url string) *TeamsNotifier {
+	return &TeamsNotifier{url: url}
+}
+
+// teamsMessage is a legacy actionable message card, the format Teams incoming
+// webhooks accept
+type teamsMessage struct {
+	Type    string `json:"@type"`
+	Context string `json:"@context"`
+	Summary string `json:"summary"`
+	Title   string `json:"title"`
+	Text    string `json:"text"`
+}
+
+// Notify posts a message card titled with the subject
+func (t *TeamsNotifier) Notify(ctx context.Context, n *Notification) error {
+	msg := teamsMessage{
+		Type:    "MessageCard",
+		Context: "https://schema.org/extensions",
+		Summary: n.Subject,
+		Title:   n.Subject,
+		Text:    n.summary(),
+	}
+	return postJSON(ctx, httpClient, t.url, msg, nil)
+}
@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook payload, as
+// "sha256=<hex>", when a secret is configured
+const SignatureHeader = "X-Signature-256"
+
+// WebhookNotifier posts notifications as JSON to an arbitrary HTTP endpoint
+type WebhookNotifier struct {
+	url    string
+	secret string
+}
+
+// NewWebhookNotifier creates a notifier posting to url. When secret is set,
+// payloads are signed with it so the receiver can verify their origin.
+func NewWebhookNotifier(url, secret string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, secret: secret}
+}
+
+// WebhookPayload is the body posted by WebhookNotifier
+type WebhookPayload struct {
+	Event   Event       `json:"event"`
+	Subject string      `json:"subject"`
+	Summary string      `json:"summary"`
+	Text    string      `json:"text"`
+	HTML    string      `json:"html,omitempty"`
+	To      []string    `json:"to"`
+	Data    interface{} `json:"data,omitempty"`
+	SentAt  time.Time   `json:"sentAt"`
+}
+
+// Notify posts n as a WebhookPayload
+func (w *WebhookNotifier) Notify(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(WebhookPayload{
+		Event:   n.Event,
+		Subject: n.Subject,
+		Summary: n.summary(),
+		Text:    n.Text,
+		HTML:    n.HTML,
+		To:      n.To,
+		Data:    n.Data,
+		SentAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	if w.secret != "" {
+		header.Set(SignatureHeader, Sign(w.secret, body))
+	}
+	return postJSON(ctx, httpClient, w.url, json.RawMessage(body), header)
+}
+
+// Sign returns the signature header value for body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// capturedRequest is a request received by a webhook stand-in
+type capturedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookServer stands in for a webhook endpoint, recording every request
+// and answering with status
+func webhookServer(t *testing.T, status int) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	requests := make(chan capturedRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "expected a JSON POST", http.StatusBadRequest)
+			return
+		}
+		requests <- capturedRequest{header: r.Header, body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func testNotification() *Notification {
+	return &Notification{
+		Event:   EventLeaveApproved,
+		To:      []string{"john@example.com"},
+		Subject: "Leave Request Approved",
+		Text:    "\nHello John Doe,\n\nYour leave request has been approved.\n",
+		Summary: "John Doe's annual leave was approved",
+		Data:    map[string]string{"status": "approved"},
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	server, requests := webhookServer(t, http.StatusOK)
+
+	if err := NewSlackNotifier(server.URL).Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var msg slackMessage
+	if err := json.Unmarshal((<-requests).body, &msg); err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+	if msg.Text != "*Leave Request Approved*\nJohn Doe's annual leave was approved" {
+		t.Errorf("unexpected Slack message %q", msg.Text)
+	}
+}
+
+func TestTeamsNotifier(t *testing.T) {
+	server, requests := webhookServer(t, http.StatusOK)
+
+	n := testNotification()
+	n.Summary = ""
+	if err := NewTeamsNotifier(server.URL).Notify(context.Background(), n); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var card teamsMessage
+	if err := json.Unmarshal((<-requests).body, &card); err != nil {
+		t.Fatalf("failed to decode card: %v", err)
+	}
+	if card.Type != "MessageCard" || card.Title != "Leave Request Approved" {
+		t.Errorf("unexpected card %+v", card)
+	}
+	// Without a summary the full text is posted
+	if !strings.HasPrefix(card.Text, "Hello John Doe,") {
+		t.Errorf("expected the trimmed text, got %q", card.Text)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	server, requests := webhookServer(t, http.StatusAccepted)
+
+	if err := NewWebhookNotifier(server.URL, "s3cret").Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := <-requests
+	if got, want := req.header.Get(SignatureHeader), Sign("s3cret", req.body); got != want {
+		t.Errorf("expected signature %s, got %s", want, got)
+	}
+
+	var payload struct {
+		WebhookPayload
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if payload.Event != EventLeaveApproved || payload.Subject != "Leave Request Approved" ||
+		len(payload.To) != 1 || payload.Data["status"] != "approved" || payload.SentAt.IsZero() {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+}
+
+func TestWebhookNotifier_Unsigned(t *testing.T) {
+	server, requests := webhookServer(t, http.StatusOK)
+
+	if err := NewWebhookNotifier(server.URL, "").Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sig := (<-requests).header.Get(SignatureHeader); sig != "" {
+		t.Errorf("expected no signature without a secret, got %s", sig)
+	}
+}
+
+func TestWebhookNotifier_ErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := NewSlackNotifier(server.URL).Notify(context.Background(), testNotification())
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("expected the error response to be reported, got %v", err)
+	}
+}
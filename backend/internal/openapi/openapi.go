@@ -0,0 +1,32 @@
+// Package openapi holds the OpenAPI 3 document describing the HTTP API. The
+// document is the contract for clients; the server serves it and, outside
+// production, checks requests and responses against it.
+package openapi
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+//go:embed openapi.yaml
+var document []byte
+
+func init() {
+	// kin-openapi only checks the date formats by default; IDs are UUIDs
+	openapi3.DefineStringFormatValidator("uuid", openapi3.NewRegexpFormatValidator(openapi3.FormatOfStringForUUIDOfRFC4122))
+}
+
+// Load parses and validates the OpenAPI document
+func Load() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,27 @@
+package openapi
+
+import (
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	doc, err := Load()
+	if err != nil {
+		t.Fatalf("expected a valid document, got %v", err)
+	}
+
+	// Every operation needs an ID for generated clients
+	seen := make(map[string]string)
+	for path, item := range doc.Paths.Map() {
+		for method, op := range item.Operations() {
+			if op.OperationID == "" {
+				t.Errorf("%s %s has no operationId", method, path)
+				continue
+			}
+			if other, ok := seen[op.OperationID]; ok {
+				t.Errorf("operationId %s used by both %s and %s %s", op.OperationID, other, method, path)
+			}
+			seen[op.OperationID] = method + " " + path
+		}
+	}
+}
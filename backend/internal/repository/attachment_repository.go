@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/logger"
+	"leave-management-system/internal/models"
+)
+
+// AttachmentRepository defines the interface for leave attachment data access
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *models.Attachment) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Attachment, error)
+	FindByLeaveRequestID(ctx context.Context, leaveRequestID uuid.UUID) ([]*models.Attachment, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// attachmentRepository implements AttachmentRepository
+type attachmentRepository struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+	logger       *logger.Logger
+}
+
+// NewAttachmentRepository creates a new attachment repository
+func NewAttachmentRepository(db *sql.DB, queryTimeout time.Duration) AttachmentRepository {
+	return &attachmentRepository{
+		db:           db,
+		queryTimeout: queryTimeout,
+		logger:       logger.New().With("component", "repository"),
+	}
+}
+
+// Create inserts a new attachment record
+func (r *attachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO leave_attachments (
+			id, leave_request_id, file_name, content_type, size_bytes, storage_key, uploaded_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		attachment.ID,
+		attachment.LeaveRequestID,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.SizeBytes,
+		attachment.StorageKey,
+		attachment.UploadedBy,
+		attachment.CreatedAt,
+	)
+	if err != nil {
+		r.logger.Errorf("db_create_failed operation=create_attachment attachment_id=%s leave_id=%s error=%v", attachment.ID, attachment.LeaveRequestID, err)
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds an attachment by ID
+func (r *attachmentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Attachment, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT id, leave_request_id, file_name, content_type, size_bytes, storage_key, uploaded_by, created_at
+		FROM leave_attachments
+		WHERE id = $1
+	`
+
+	var attachment models.Attachment
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&attachment.ID,
+		&attachment.LeaveRequestID,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.SizeBytes,
+		&attachment.StorageKey,
+		&attachment.UploadedBy,
+		&attachment.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, "attachment")
+	}
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_attachment_by_id attachment_id=%s error=%v", id, err)
+		return nil, fmt.Errorf("failed to find attachment by ID: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// FindByLeaveRequestID finds all attachments for a leave request
+func (r *attachmentRepository) FindByLeaveRequestID(ctx context.Context, leaveRequestID uuid.UUID) ([]*models.Attachment, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT id, leave_request_id, file_name, content_type, size_bytes, storage_key, uploaded_by, created_at
+		FROM leave_attachments
+		WHERE leave_request_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, leaveRequestID)
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_attachments_by_leave_id leave_id=%s error=%v", leaveRequestID, err)
+		return nil, fmt.Errorf("failed to query attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []*models.Attachment
+	for rows.Next() {
+		var attachment models.Attachment
+		err := rows.Scan(
+			&attachment.ID,
+			&attachment.LeaveRequestID,
+			&attachment.FileName,
+			&attachment.ContentType,
+			&attachment.SizeBytes,
+			&attachment.StorageKey,
+			&attachment.UploadedBy,
+			&attachment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, &attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+// Delete removes an attachment record
+func (r *attachmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM leave_attachments WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Errorf("db_delete_failed operation=delete_attachment attachment_id=%s error=%v", id, err)
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, "attachment")
+	}
+
+	return nil
+}
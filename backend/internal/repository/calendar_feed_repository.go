@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"leave-management-system/internal/logger"
+	"leave-management-system/internal/models"
+)
+
+// CalendarFeedRepository defines the interface for calendar feed data access
+type CalendarFeedRepository interface {
+	Find(ctx context.Context, employeeID string, kind models.CalendarFeedKind) (*models.CalendarFeed, error)
+	// FindByTokenHash finds the feed whose token has hash
+	FindByTokenHash(ctx context.Context, hash string) (*models.CalendarFeed, error)
+	FindByEmployeeID(ctx context.Context, employeeID string) ([]*models.CalendarFeed, error)
+	// Save creates or replaces the employee's feed of its kind
+	Save(ctx context.Context, feed *models.CalendarFeed) error
+	Delete(ctx context.Context, employeeID string, kind models.CalendarFeedKind) error
+}
+
+// calendarFeedRepository implements CalendarFeedRepository
+type calendarFeedRepository struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+	logger       *logger.Logger
+}
+
+// NewCalendarFeedRepository creates a new calendar feed repository
+func NewCalendarFeedRepository(db *sql.DB, queryTimeout time.Duration) CalendarFeedRepository {
+	return &calendarFeedRepository{
+		db:           db,
+		queryTimeout: queryTimeout,
+		logger:       logger.New().With("component", "repository"),
+	}
+}
+
+const calendarFeedColumns = `employee_id, kind, token_hash, employee_name, department, privacy, created_at, updated_at`
+
+func scanCalendarFeed(row interface{ Scan(...interface{}) error }) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := row.Scan(
+		&feed.EmployeeID,
+		&feed.Kind,
+		&feed.TokenHash,
+		&feed.EmployeeName,
+		&feed.Department,
+		&feed.Privacy,
+		&feed.CreatedAt,
+		&feed.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// Find finds an employee's feed of one kind
+func (r *calendarFeedRepository) Find(ctx context.Context, employeeID string, kind models.CalendarFeedKind) (*models.CalendarFeed, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + calendarFeedColumns + ` FROM calendar_feeds WHERE employee_id = $1 AND kind = $2`
+
+	feed, err := scanCalendarFeed(r.db.QueryRowContext(ctx, query, employeeID, kind))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, "calendar feed")
+	}
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_calendar_feed employee_id=%s kind=%s error=%v", employeeID, kind, err)
+		return nil, fmt.Errorf("failed to find calendar feed: %w", err)
+	}
+	return feed, nil
+}
+
+// FindByTokenHash finds the feed whose token has hash
+func (r *calendarFeedRepository) FindByTokenHash(ctx context.Context, hash string) (*models.CalendarFeed, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + calendarFeedColumns + ` FROM calendar_feeds WHERE token_hash = $1`
+
+	feed, err := scanCalendarFeed(r.db.QueryRowContext(ctx, query, hash))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, "calendar feed")
+	}
+	if err != nil {
+		// Not even the hash of a token is logged
+		r.logger.Errorf("db_query_failed operation=find_calendar_feed_by_token error=%v", err)
+		return nil, fmt.Errorf("failed to find calendar feed: %w", err)
+	}
+	return feed, nil
+}
+
+// FindByEmployeeID lists an employee's feeds by kind
+func (r *calendarFeedRepository) FindByEmployeeID(ctx context.Context, employeeID string) ([]*models.CalendarFeed, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + calendarFeedColumns + ` FROM calendar_feeds WHERE employee_id = $1 ORDER BY kind`
+
+	rows, err := r.db.QueryContext(ctx, query, employeeID)
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_calendar_feeds employee_id=%s error=%v", employeeID, err)
+		return nil, fmt.Errorf("failed to query calendar feeds: %w", err)
+	}
+	defer rows.Close()
+
+	var feeds []*models.CalendarFeed
+	for rows.Next() {
+		feed, err := scanCalendarFeed(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan calendar feed: %w", err)
+		}
+		feeds = append(feeds, feed)
+	}
+	return feeds, rows.Err()
+}
+
+// Save upserts a feed
+func (r *calendarFeedRepository) Save(ctx context.Context, feed *models.CalendarFeed) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO calendar_feeds (` + calendarFeedColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (employee_id, kind) DO UPDATE
+		SET token_hash = EXCLUDED.token_hash,
+		    employee_name = EXCLUDED.employee_name,
+		    department = EXCLUDED.department,
+		    privacy = EXCLUDED.privacy,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		feed.EmployeeID,
+		feed.Kind,
+		feed.TokenHash,
+		feed.EmployeeName,
+		feed.Department,
+		feed.Privacy,
+		feed.CreatedAt,
+		feed.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Errorf("db_update_failed operation=save_calendar_feed employee_id=%s kind=%s error=%v", feed.EmployeeID, feed.Kind, err)
+		return fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a feed; its URL stops working
+func (r *calendarFeedRepository) Delete(ctx context.Context, employeeID string, kind models.CalendarFeedKind) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM calendar_feeds WHERE employee_id = $1 AND kind = $2`, employeeID, kind)
+	if err != nil {
+		r.logger.Errorf("db_delete_failed operation=delete_calendar_feed employee_id=%s kind=%s error=%v", employeeID, kind, err)
+		return fmt.Errorf("failed to delete calendar feed: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, "calendar feed")
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/logger"
+	"leave-management-system/internal/models"
+)
+
+// CommentRepository defines the interface for leave comment data access
+type CommentRepository interface {
+	Create(ctx context.Context, comment *models.LeaveComment) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.LeaveComment, error)
+	FindByLeaveRequestID(ctx context.Context, leaveRequestID uuid.UUID) ([]*models.LeaveComment, error)
+	Update(ctx context.Context, comment *models.LeaveComment) error
+}
+
+// commentColumns lists the leave_comments columns in the order scanComment reads them
+const commentColumns = `id, leave_request_id, author_id, author_name, author_email, body, created_at, updated_at`
+
+// scanComment scans a row selected with commentColumns into comment
+func scanComment(row rowScanner, comment *models.LeaveComment) error {
+	return row.Scan(
+		&comment.ID,
+		&comment.LeaveRequestID,
+		&comment.AuthorID,
+		&comment.AuthorName,
+		&comment.AuthorEmail,
+		&comment.Body,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+}
+
+// commentRepository implements CommentRepository
+type commentRepository struct {
+	db           dbtx
+	queryTimeout time.Duration
+	logger       *logger.Logger
+}
+
+// NewCommentRepository creates a new comment repository
+func NewCommentRepository(db *sql.DB, queryTimeout time.Duration) CommentRepository {
+	return &commentRepository{
+		db:           db,
+		queryTimeout: queryTimeout,
+		logger:       logger.New().With("component", "repository"),
+	}
+}
+
+// Create inserts a new comment
+func (r *commentRepository) Create(ctx context.Context, comment *models.LeaveComment) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO leave_comments (` + commentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		comment.ID,
+		comment.LeaveRequestID,
+		comment.AuthorID,
+		comment.AuthorName,
+		comment.AuthorEmail,
+		comment.Body,
+		comment.CreatedAt,
+		comment.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Errorf("db_create_failed operation=create_comment comment_id=%s leave_id=%s error=%v", comment.ID, comment.LeaveRequestID, err)
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds a comment by ID
+func (r *commentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.LeaveComment, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + commentColumns + `
+		FROM leave_comments
+		WHERE id = $1
+	`
+
+	var comment models.LeaveComment
+	err := scanComment(r.db.QueryRowContext(ctx, query, id), &comment)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, "comment")
+	}
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_comment_by_id comment_id=%s error=%v", id, err)
+		return nil, fmt.Errorf("failed to find comment by ID: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// FindByLeaveRequestID finds all comments on a leave request, oldest first
+func (r *commentRepository) FindByLeaveRequestID(ctx context.Context, leaveRequestID uuid.UUID) ([]*models.LeaveComment, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + commentColumns + `
+		FROM leave_comments
+		WHERE leave_request_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, leaveRequestID)
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_comments leave_id=%s error=%v", leaveRequestID, err)
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*models.LeaveComment
+	for rows.Next() {
+		var comment models.LeaveComment
+		if err := scanComment(rows, &comment); err != nil {
+			return nil, err
+		}
+		comments = append(comments, &comment)
+	}
+
+	return comments, rows.Err()
+}
+
+// Update saves the body of an edited comment
+func (r *commentRepository) Update(ctx context.Context, comment *models.LeaveComment) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE leave_comments
+		SET body = $1, updated_at = $2
+		WHERE id = $3
+	`
+
+	result, err := r.db.ExecContext(ctx, query, comment.Body, comment.UpdatedAt, comment.ID)
+	if err != nil {
+		r.logger.Errorf("db_update_failed operation=update_comment comment_id=%s error=%v", comment.ID, err)
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, "comment")
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded or
+// was issued for a different sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// sortColumn describes how a models.LeaveSort maps onto leave_requests
+type sortColumn struct {
+	expr string // SQL expression to order by
+	cast string // Postgres type the cursor value is cast to
+}
+
+// sortColumns lists the supported sort orders. Dates are NULL for drafts, so
+// they are coalesced to keep the keyset comparison total.
+var sortColumns = map[models.LeaveSort]sortColumn{
+	models.SortCreatedAt:    {expr: "created_at", cast: "timestamp"},
+	models.SortStartDate:    {expr: "COALESCE(start_date, DATE '0001-01-01')", cast: "date"},
+	models.SortEndDate:      {expr: "COALESCE(end_date, DATE '0001-01-01')", cast: "date"},
+	models.SortDays:         {expr: "days", cast: "integer"},
+	models.SortEmployeeName: {expr: "employee_name", cast: "text"},
+}
+
+// cursorTimeLayout formats created_at with the microsecond precision Postgres stores
+const cursorTimeLayout = "2006-01-02 15:04:05.000000"
+
+// pageCursor is the position after the last row of a page
+type pageCursor struct {
+	Sort       models.LeaveSort `json:"s"`
+	Descending bool             `json:"d"`
+	Value      string           `json:"v"`
+	ID         uuid.UUID        `json:"id"`
+}
+
+// encodeCursor returns the opaque cursor continuing after leave
+func encodeCursor(filter models.LeaveFilter, leave *models.LeaveRequest) string {
+	data, _ := json.Marshal(pageCursor{
+		Sort:       filter.Sort,
+		Descending: filter.Descending,
+		Value:      sortValue(filter.Sort, leave),
+		ID:         leave.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor and checks it matches the filter's sort order
+func decodeCursor(filter models.LeaveFilter) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Sort != filter.Sort || cursor.Descending != filter.Descending {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// sortValue returns the value of leave's sort field as stored in a cursor
+func sortValue(sort models.LeaveSort, leave *models.LeaveRequest) string {
+	switch sort {
+	case models.SortStartDate:
+		return dateSortValue(leave.StartDate)
+	case models.SortEndDate:
+		return dateSortValue(leave.EndDate)
+	case models.SortDays:
+		return strconv.Itoa(leave.Days)
+	case models.SortEmployeeName:
+		return leave.EmployeeName
+	default:
+		return leave.CreatedAt.Format(cursorTimeLayout)
+	}
+}
+
+func dateSortValue(d models.Date) string {
+	if d.IsZero() {
+		return "0001-01-01"
+	}
+	return d.String()
+}
+
+// compareSortValues orders two cursor values of the same sort field
+func compareSortValues(sort models.LeaveSort, a, b string) int {
+	if sort == models.SortDays {
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
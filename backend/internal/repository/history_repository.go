@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/logger"
+	"leave-management-system/internal/models"
+)
+
+// HistoryRepository defines the interface for leave history data access
+type HistoryRepository interface {
+	Create(ctx context.Context, entry *models.LeaveHistoryEntry) error
+	FindByLeaveRequestID(ctx context.Context, leaveRequestID uuid.UUID) ([]*models.LeaveHistoryEntry, error)
+	FindAll(ctx context.Context, filter models.AuditFilter) ([]*models.LeaveHistoryEntry, error)
+}
+
+// historyColumns lists the leave_request_history columns in the order scanHistory reads them
+const historyColumns = `id, leave_request_id, employee_id, actor_id, actor_name, action, status, note, created_at`
+
+// scanHistory scans a row selected with historyColumns into entry
+func scanHistory(row rowScanner, entry *models.LeaveHistoryEntry) error {
+	return row.Scan(
+		&entry.ID,
+		&entry.LeaveRequestID,
+		&entry.EmployeeID,
+		&entry.ActorID,
+		&entry.ActorName,
+		&entry.Action,
+		&entry.Status,
+		&entry.Note,
+		&entry.CreatedAt,
+	)
+}
+
+// historyRepository implements HistoryRepository
+type historyRepository struct {
+	db           dbtx
+	queryTimeout time.Duration
+	logger       *logger.Logger
+}
+
+// NewHistoryRepository creates a new history repository
+func NewHistoryRepository(db *sql.DB, queryTimeout time.Duration) HistoryRepository {
+	return &historyRepository{
+		db:           db,
+		queryTimeout: queryTimeout,
+		logger:       logger.New().With("component", "repository"),
+	}
+}
+
+// Create inserts a history entry
+func (r *historyRepository) Create(ctx context.Context, entry *models.LeaveHistoryEntry) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO leave_request_history (` + historyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		entry.ID,
+		entry.LeaveRequestID,
+		entry.EmployeeID,
+		entry.ActorID,
+		entry.ActorName,
+		entry.Action,
+		entry.Status,
+		entry.Note,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		r.logger.Errorf("db_create_failed operation=create_history leave_id=%s actor_id=%s error=%v", entry.LeaveRequestID, entry.ActorID, err)
+		return fmt.Errorf("failed to create history entry: %w", err)
+	}
+
+	return nil
+}
+
+// FindByLeaveRequestID finds the history of a leave request, oldest first
+func (r *historyRepository) FindByLeaveRequestID(ctx context.Context, leaveRequestID uuid.UUID) ([]*models.LeaveHistoryEntry, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + historyColumns + `
+		FROM leave_request_history
+		WHERE leave_request_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, leaveRequestID)
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_history leave_id=%s error=%v", leaveRequestID, err)
+		return nil, fmt.Errorf("failed to query leave history: %w", err)
+	}
+	defer rows.Close()
+
+	return scanHistoryRows(rows)
+}
+
+// FindAll finds history entries across all leave requests matching filter, newest first
+func (r *historyRepository) FindAll(ctx context.Context, filter models.AuditFilter) ([]*models.LeaveHistoryEntry, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + historyColumns + `
+		FROM leave_request_history
+		WHERE ($1 = '' OR actor_id = $1) AND ($2 = '' OR action = $2)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.ActorID, filter.Action, filter.Limit)
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_audit actor_id=%s action=%s error=%v", filter.ActorID, filter.Action, err)
+		return nil, fmt.Errorf("failed to query audit trail: %w", err)
+	}
+	defer rows.Close()
+
+	return scanHistoryRows(rows)
+}
+
+// scanHistoryRows reads all history entries from rows
+func scanHistoryRows(rows *sql.Rows) ([]*models.LeaveHistoryEntry, error) {
+	var entries []*models.LeaveHistoryEntry
+	for rows.Next() {
+		var entry models.LeaveHistoryEntry
+		if err := scanHistory(rows, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
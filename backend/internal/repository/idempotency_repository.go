@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"leave-management-system/internal/logger"
+	"leave-management-system/internal/models"
+)
+
+// IdempotencyRepository defines the interface for idempotency key data access
+type IdempotencyRepository interface {
+	// Reserve stores key unless the user already holds the same key, either
+	// completed after expiredBefore or still in progress and claimed after
+	// abandonedBefore. It reports whether key was stored.
+	Reserve(ctx context.Context, key *models.IdempotencyKey, expiredBefore, abandonedBefore time.Time) (bool, error)
+	Find(ctx context.Context, userID, key string) (*models.IdempotencyKey, error)
+	Complete(ctx context.Context, key *models.IdempotencyKey) error
+	Delete(ctx context.Context, userID, key string) error
+	DeleteExpired(ctx context.Context, expiredBefore time.Time) (int64, error)
+}
+
+// idempotencyRepository implements IdempotencyRepository
+type idempotencyRepository struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+	logger       *logger.Logger
+}
+
+// NewIdempotencyRepository creates a new idempotency key repository
+func NewIdempotencyRepository(db *sql.DB, queryTimeout time.Duration) IdempotencyRepository {
+	return &idempotencyRepository{
+		db:           db,
+		queryTimeout: queryTimeout,
+		logger:       logger.New().With("component", "repository"),
+	}
+}
+
+// Reserve inserts key, replacing an expired or abandoned key of the same name
+func (r *idempotencyRepository) Reserve(ctx context.Context, key *models.IdempotencyKey, expiredBefore, abandonedBefore time.Time) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO idempotency_keys (user_id, idempotency_key, request_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, idempotency_key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+		    status_code = 0,
+		    response_headers = '{}',
+		    response_body = NULL,
+		    created_at = EXCLUDED.created_at
+		WHERE idempotency_keys.created_at < $5
+		   OR (idempotency_keys.status_code = 0 AND idempotency_keys.created_at < $6)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, key.UserID, key.Key, key.RequestHash, key.CreatedAt, expiredBefore, abandonedBefore)
+	if err != nil {
+		r.logger.Errorf("db_create_failed operation=reserve_idempotency_key user_id=%s error=%v", key.UserID, err)
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows == 1, nil
+}
+
+// Find finds a user's idempotency key
+func (r *idempotencyRepository) Find(ctx context.Context, userID, key string) (*models.IdempotencyKey, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT user_id, idempotency_key, request_hash, status_code, response_headers, response_body, created_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2
+	`
+
+	var record models.IdempotencyKey
+	var headers []byte
+	err := r.db.QueryRowContext(ctx, query, userID, key).Scan(
+		&record.UserID,
+		&record.Key,
+		&record.RequestHash,
+		&record.StatusCode,
+		&headers,
+		&record.Body,
+		&record.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, "idempotency key")
+	}
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_idempotency_key user_id=%s error=%v", userID, err)
+		return nil, fmt.Errorf("failed to find idempotency key: %w", err)
+	}
+
+	if err := json.Unmarshal(headers, &record.Headers); err != nil {
+		return nil, fmt.Errorf("failed to decode response headers: %w", err)
+	}
+
+	return &record, nil
+}
+
+// Complete stores the response to the request that reserved key
+func (r *idempotencyRepository) Complete(ctx context.Context, key *models.IdempotencyKey) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	headers, err := json.Marshal(key.Headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode response headers: %w", err)
+	}
+
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $1, response_headers = $2, response_body = $3
+		WHERE user_id = $4 AND idempotency_key = $5 AND request_hash = $6
+	`
+
+	result, err := r.db.ExecContext(ctx, query, key.StatusCode, headers, key.Body, key.UserID, key.Key, key.RequestHash)
+	if err != nil {
+		r.logger.Errorf("db_update_failed operation=complete_idempotency_key user_id=%s error=%v", key.UserID, err)
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, "idempotency key")
+	}
+
+	return nil
+}
+
+// Delete removes a user's idempotency key so it can be used again
+func (r *idempotencyRepository) Delete(ctx context.Context, userID, key string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `DELETE FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, key); err != nil {
+		r.logger.Errorf("db_delete_failed operation=delete_idempotency_key user_id=%s error=%v", userID, err)
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes keys created before expiredBefore and returns how many were removed
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `DELETE FROM idempotency_keys WHERE created_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, expiredBefore)
+	if err != nil {
+		r.logger.Errorf("db_delete_failed operation=delete_expired_idempotency_keys error=%v", err)
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	return result.RowsAffected()
+}
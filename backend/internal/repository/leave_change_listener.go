@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"leave-management-system/internal/logger"
+	"leave-management-system/internal/models"
+)
+
+// LeaveChangeChannel is the Postgres notification channel the leave_requests
+// trigger publishes committed changes on
+const LeaveChangeChannel = "leave_changes"
+
+// listenerPingInterval is how often an idle listener checks its connection
+const listenerPingInterval = time.Minute
+
+// LeaveChangeListener receives the changes to leave requests committed by
+// any server sharing the database, on a connection of its own
+type LeaveChangeListener struct {
+	listener *pq.Listener
+	logger   *logger.Logger
+}
+
+// NewLeaveChangeListener connects to the database at dsn and listens on
+// LeaveChangeChannel. The connection is re-established whenever it is lost.
+func NewLeaveChangeListener(dsn string) (*LeaveChangeListener, error) {
+	log := logger.New().With("component", "repository")
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			log.Warnf("leave_change_listener_disconnected error=%v", err)
+		case pq.ListenerEventReconnected:
+			log.Info("leave_change_listener_reconnected")
+		case pq.ListenerEventConnectionAttemptFailed:
+			log.Errorf("leave_change_listener_connect_failed error=%v", err)
+		}
+	})
+	if err := listener.Listen(LeaveChangeChannel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen for leave changes: %w", err)
+	}
+	return &LeaveChangeListener{listener: listener, logger: log}, nil
+}
+
+// Run calls onChange with each change received until ctx is cancelled.
+// Changes committed while the connection was down are lost, so onReconnect
+// is called once it is back.
+func (l *LeaveChangeListener) Run(ctx context.Context, onChange func(*models.LeaveChange), onReconnect func()) {
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-l.listener.Notify:
+			if n == nil {
+				onReconnect()
+				continue
+			}
+			var change models.LeaveChange
+			if err := json.Unmarshal([]byte(n.Extra), &change); err != nil {
+				l.logger.Errorf("leave_change_decode_failed payload=%q error=%v", n.Extra, err)
+				continue
+			}
+			onChange(&change)
+		case <-ticker.C:
+			// A ping notices a dead connection the listener would otherwise
+			// wait on until the next change
+			go func() {
+				if err := l.listener.Ping(); err != nil {
+					l.logger.Warnf("leave_change_listener_ping_failed error=%v", err)
+				}
+			}()
+		}
+	}
+}
+
+// Close stops listening and closes the connection
+func (l *LeaveChangeListener) Close() error {
+	return l.listener.Close()
+}
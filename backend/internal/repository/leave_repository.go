@@ -1,78 +1,120 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"leave-management-system/internal/logger"
 	"leave-management-system/internal/models"
 )
 
 // LeaveRepository defines the interface for leave data access
 type LeaveRepository interface {
-	Create(leave *models.LeaveRequest) error
-	FindByID(id uuid.UUID) (*models.LeaveRequest, error)
-	FindByEmployeeID(employeeID string) ([]*models.LeaveRequest, error)
-	FindPending() ([]*models.LeaveRequest, error)
-	Update(leave *models.LeaveRequest) error
-	UpdateStatus(id uuid.UUID, status models.LeaveStatus, comment string) error
+	Create(ctx context.Context, leave *models.LeaveRequest) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.LeaveRequest, error)
+	// Update saves leave only while its stored version still equals
+	// leave.Version, incrementing it, and returns ErrConflict otherwise
+	Update(ctx context.Context, leave *models.LeaveRequest) error
+	// TransitionStatus moves a request at version in one of the from statuses
+	// to status, setting the manager comment, and returns ErrConflict if it
+	// has since changed
+	TransitionStatus(ctx context.Context, id uuid.UUID, version int, from []models.LeaveStatus, status models.LeaveStatus, comment string) (*models.LeaveRequest, error)
+	Search(ctx context.Context, filter models.LeaveFilter) (*models.LeavePage, error)
+}
+
+// leaveColumns lists the leave_requests columns in the order scanLeave reads them
+const leaveColumns = `id, employee_id, employee_name, employee_email, employee_department, leave_type, reason,
+			start_date, end_date, timezone, locale, days, status, manager_comment,
+			approver_id, approver_name, approver_email, version, created_at, updated_at`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanLeave scans a row selected with leaveColumns into leave
+func scanLeave(row rowScanner, leave *models.LeaveRequest) error {
+	return row.Scan(
+		&leave.ID,
+		&leave.EmployeeID,
+		&leave.EmployeeName,
+		&leave.EmployeeEmail,
+		&leave.Department,
+		&leave.LeaveType,
+		&leave.Reason,
+		&leave.StartDate,
+		&leave.EndDate,
+		&leave.Timezone,
+		&leave.Locale,
+		&leave.Days,
+		&leave.Status,
+		&leave.ManagerComment,
+		&leave.ApproverID,
+		&leave.ApproverName,
+		&leave.ApproverEmail,
+		&leave.Version,
+		&leave.CreatedAt,
+		&leave.UpdatedAt,
+	)
 }
 
 // leaveRepository implements LeaveRepository
 type leaveRepository struct {
-	db     *sql.DB
-	logger *logger.Logger
+	db           dbtx
+	queryTimeout time.Duration
+	logger       *logger.Logger
 }
 
 // NewLeaveRepository creates a new leave repository
-func NewLeaveRepository(db *sql.DB) LeaveRepository {
+func NewLeaveRepository(db *sql.DB, queryTimeout time.Duration) LeaveRepository {
 	return &leaveRepository{
-		db:     db,
-		logger: logger.New().With("component", "repository"),
+		db:           db,
+		queryTimeout: queryTimeout,
+		logger:       logger.New().With("component", "repository"),
 	}
 }
 
 // Create inserts a new leave request
-func (r *leaveRepository) Create(leave *models.LeaveRequest) error {
+func (r *leaveRepository) Create(ctx context.Context, leave *models.LeaveRequest) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
 		INSERT INTO leave_requests (
-			id, employee_id, employee_name, employee_email, leave_type, reason,
-			start_date, end_date, days, status, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		RETURNING id, employee_id, employee_name, employee_email, leave_type, reason,
-			start_date, end_date, days, status, manager_comment, created_at, updated_at
-	`
+			id, employee_id, employee_name, employee_email, employee_department, leave_type, reason,
+			start_date, end_date, timezone, locale, days, status, manager_comment,
+			approver_id, approver_name, approver_email, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		RETURNING ` + leaveColumns
 
-	err := r.db.QueryRow(
+	err := scanLeave(r.db.QueryRowContext(
+		ctx,
 		query,
 		leave.ID,
 		leave.EmployeeID,
 		leave.EmployeeName,
 		leave.EmployeeEmail,
+		leave.Department,
 		leave.LeaveType,
 		leave.Reason,
 		leave.StartDate,
 		leave.EndDate,
+		leave.Timezone,
+		leave.Locale,
 		leave.Days,
 		leave.Status,
+		leave.ManagerComment,
+		leave.ApproverID,
+		leave.ApproverName,
+		leave.ApproverEmail,
 		leave.CreatedAt,
 		leave.UpdatedAt,
-	).Scan(
-		&leave.ID,
-		&leave.EmployeeID,
-		&leave.EmployeeName,
-		&leave.EmployeeEmail,
-		&leave.LeaveType,
-		&leave.Reason,
-		&leave.StartDate,
-		&leave.EndDate,
-		&leave.Days,
-		&leave.Status,
-		&leave.ManagerComment,
-		&leave.CreatedAt,
-		&leave.UpdatedAt,
-	)
+	), leave)
 
 	if err != nil {
 		r.logger.Errorf("db_create_failed operation=create_leave leave_id=%s employee_id=%s error=%v", leave.ID, leave.EmployeeID, err)
@@ -82,30 +124,18 @@ func (r *leaveRepository) Create(leave *models.LeaveRequest) error {
 }
 
 // FindByID finds a leave request by ID
-func (r *leaveRepository) FindByID(id uuid.UUID) (*models.LeaveRequest, error) {
+func (r *leaveRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.LeaveRequest, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
-		SELECT id, employee_id, employee_name, employee_email, leave_type, reason,
-			start_date, end_date, days, status, manager_comment, created_at, updated_at
+		SELECT ` + leaveColumns + `
 		FROM leave_requests
 		WHERE id = $1
 	`
 
 	var leave models.LeaveRequest
-	err := r.db.QueryRow(query, id).Scan(
-		&leave.ID,
-		&leave.EmployeeID,
-		&leave.EmployeeName,
-		&leave.EmployeeEmail,
-		&leave.LeaveType,
-		&leave.Reason,
-		&leave.StartDate,
-		&leave.EndDate,
-		&leave.Days,
-		&leave.Status,
-		&leave.ManagerComment,
-		&leave.CreatedAt,
-		&leave.UpdatedAt,
-	)
+	err := scanLeave(r.db.QueryRowContext(ctx, query, id), &leave)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("%w: %s", ErrNotFound, "leave request")
@@ -118,133 +148,44 @@ func (r *leaveRepository) FindByID(id uuid.UUID) (*models.LeaveRequest, error) {
 	return &leave, nil
 }
 
-// FindByEmployeeID finds all leave requests for an employee
-func (r *leaveRepository) FindByEmployeeID(employeeID string) ([]*models.LeaveRequest, error) {
-	query := `
-		SELECT id, employee_id, employee_name, employee_email, leave_type, reason,
-			start_date, end_date, days, status, manager_comment, created_at, updated_at
-		FROM leave_requests
-		WHERE employee_id = $1
-		ORDER BY created_at DESC
-	`
-
-	rows, err := r.db.Query(query, employeeID)
-	if err != nil {
-		r.logger.Errorf("db_query_failed operation=find_by_employee_id employee_id=%s error=%v", employeeID, err)
-		return nil, fmt.Errorf("failed to query leave requests by employee ID: %w", err)
-	}
-	defer rows.Close()
-
-	var leaves []*models.LeaveRequest
-	for rows.Next() {
-		var leave models.LeaveRequest
-		err := rows.Scan(
-			&leave.ID,
-			&leave.EmployeeID,
-			&leave.EmployeeName,
-			&leave.EmployeeEmail,
-			&leave.LeaveType,
-			&leave.Reason,
-			&leave.StartDate,
-			&leave.EndDate,
-			&leave.Days,
-			&leave.Status,
-			&leave.ManagerComment,
-			&leave.CreatedAt,
-			&leave.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		leaves = append(leaves, &leave)
-	}
-
-	return leaves, rows.Err()
-}
+// Update updates the editable fields, status, approver and manager comment of
+// a leave request provided nobody has changed it since leave was read
+func (r *leaveRepository) Update(ctx context.Context, leave *models.LeaveRequest) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
 
-// FindPending finds all pending leave requests
-func (r *leaveRepository) FindPending() ([]*models.LeaveRequest, error) {
-	query := `
-		SELECT id, employee_id, employee_name, employee_email, leave_type, reason,
-			start_date, end_date, days, status, manager_comment, created_at, updated_at
-		FROM leave_requests
-		WHERE status = $1
-		ORDER BY created_at ASC
-	`
-
-	rows, err := r.db.Query(query, models.LeaveStatusPending)
-	if err != nil {
-		r.logger.Errorf("db_query_failed operation=find_pending error=%v", err)
-		return nil, fmt.Errorf("failed to query pending leave requests: %w", err)
-	}
-	defer rows.Close()
-
-	var leaves []*models.LeaveRequest
-	for rows.Next() {
-		var leave models.LeaveRequest
-		err := rows.Scan(
-			&leave.ID,
-			&leave.EmployeeID,
-			&leave.EmployeeName,
-			&leave.EmployeeEmail,
-			&leave.LeaveType,
-			&leave.Reason,
-			&leave.StartDate,
-			&leave.EndDate,
-			&leave.Days,
-			&leave.Status,
-			&leave.ManagerComment,
-			&leave.CreatedAt,
-			&leave.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		leaves = append(leaves, &leave)
-	}
-
-	return leaves, rows.Err()
-}
-
-// Update updates a leave request
-func (r *leaveRepository) Update(leave *models.LeaveRequest) error {
 	query := `
 		UPDATE leave_requests
-		SET leave_type = $1, reason = $2, start_date = $3, end_date = $4, 
-			days = $5, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $6
-		RETURNING id, employee_id, employee_name, employee_email, leave_type, reason,
-			start_date, end_date, days, status, manager_comment, created_at, updated_at
-	`
+		SET leave_type = $1, reason = $2, start_date = $3, end_date = $4,
+			timezone = $5, locale = $6, days = $7, status = $8,
+			approver_id = $9, approver_name = $10, approver_email = $11,
+			manager_comment = $12, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $13 AND version = $14
+		RETURNING ` + leaveColumns
 
-	err := r.db.QueryRow(
+	err := scanLeave(r.db.QueryRowContext(
+		ctx,
 		query,
 		leave.LeaveType,
 		leave.Reason,
 		leave.StartDate,
 		leave.EndDate,
+		leave.Timezone,
+		leave.Locale,
 		leave.Days,
+		leave.Status,
+		leave.ApproverID,
+		leave.ApproverName,
+		leave.ApproverEmail,
+		leave.ManagerComment,
 		leave.ID,
-	).Scan(
-		&leave.ID,
-		&leave.EmployeeID,
-		&leave.EmployeeName,
-		&leave.EmployeeEmail,
-		&leave.LeaveType,
-		&leave.Reason,
-		&leave.StartDate,
-		&leave.EndDate,
-		&leave.Days,
-		&leave.Status,
-		&leave.ManagerComment,
-		&leave.CreatedAt,
-		&leave.UpdatedAt,
-	)
+		leave.Version,
+	), leave)
 
+	if err == sql.ErrNoRows {
+		return r.missingOrConflict(ctx, leave.ID)
+	}
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("%w: %s", ErrNotFound, "leave request")
-		}
 		r.logger.Errorf("db_update_failed operation=update_leave leave_id=%s error=%v", leave.ID, err)
 		return fmt.Errorf("failed to update leave request: %w", err)
 	}
@@ -252,27 +193,183 @@ func (r *leaveRepository) Update(leave *models.LeaveRequest) error {
 	return nil
 }
 
-// UpdateStatus updates the status and comment of a leave request
-func (r *leaveRepository) UpdateStatus(id uuid.UUID, status models.LeaveStatus, comment string) error {
+// TransitionStatus updates the status and comment of a leave request in a
+// single conditional statement, so concurrent transitions cannot both succeed
+func (r *leaveRepository) TransitionStatus(ctx context.Context, id uuid.UUID, version int, from []models.LeaveStatus, status models.LeaveStatus, comment string) (*models.LeaveRequest, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
 		UPDATE leave_requests
-		SET status = $1, manager_comment = $2, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $3
-	`
+		SET status = $1, manager_comment = $2, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND version = $4 AND status = ANY($5)
+		RETURNING ` + leaveColumns
 
 	// Handle empty comment as NULL
 	var commentValue interface{}
-	if comment == "" {
-		commentValue = nil
-	} else {
+	if comment != "" {
 		commentValue = comment
 	}
 
-	_, err := r.db.Exec(query, status, commentValue, id)
+	allowed := make([]string, len(from))
+	for i, s := range from {
+		allowed[i] = string(s)
+	}
+
+	var leave models.LeaveRequest
+	err := scanLeave(r.db.QueryRowContext(ctx, query, status, commentValue, id, version, pq.Array(allowed)), &leave)
+	if err == sql.ErrNoRows {
+		return nil, r.missingOrConflict(ctx, id)
+	}
 	if err != nil {
-		r.logger.Errorf("db_update_failed operation=update_status leave_id=%s status=%s error=%v", id, status, err)
-		return fmt.Errorf("failed to update leave request status: %w", err)
+		r.logger.Errorf("db_update_failed operation=transition_status leave_id=%s status=%s error=%v", id, status, err)
+		return nil, fmt.Errorf("failed to update leave request status: %w", err)
 	}
-	return nil
+	return &leave, nil
+}
+
+// missingOrConflict explains why a conditional update of id matched no row
+func (r *leaveRepository) missingOrConflict(ctx context.Context, id uuid.UUID) error {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM leave_requests WHERE id = $1)`, id).Scan(&exists); err != nil {
+		r.logger.Errorf("db_query_failed operation=leave_exists leave_id=%s error=%v", id, err)
+		return fmt.Errorf("failed to check leave request: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrNotFound, "leave request")
+	}
+	return fmt.Errorf("%w: %s", ErrConflict, "leave request")
 }
 
+// Search returns one page of leave requests matching filter. Pages are keyset
+// paginated on the sort field and ID, so they stay stable while rows are added.
+func (r *leaveRepository) Search(ctx context.Context, filter models.LeaveFilter) (*models.LeavePage, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	column, ok := sortColumns[filter.Sort]
+	if !ok {
+		filter.Sort = models.SortCreatedAt
+		column = sortColumns[filter.Sort]
+	}
+
+	var conditions []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+arg(filter.Status))
+	}
+	if filter.LeaveType != "" {
+		conditions = append(conditions, "leave_type = "+arg(filter.LeaveType))
+	}
+	if filter.EmployeeID != "" {
+		conditions = append(conditions, "employee_id = "+arg(filter.EmployeeID))
+	}
+	if filter.Department != "" {
+		conditions = append(conditions, "employee_department = "+arg(filter.Department))
+	}
+	if filter.Query != "" {
+		pattern := arg("%" + escapeLike(filter.Query) + "%")
+		conditions = append(conditions, "(employee_name ILIKE "+pattern+" OR employee_email ILIKE "+pattern+")")
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "end_date >= "+arg(filter.From))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "start_date <= "+arg(filter.To))
+	}
+	if len(filter.Approvers) > 0 {
+		lower := make([]string, len(filter.Approvers))
+		for i, email := range filter.Approvers {
+			lower[i] = strings.ToLower(email)
+		}
+		conditions = append(conditions, "lower(approver_email) = ANY("+arg(pq.Array(lower))+")")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Count before the cursor condition so the total covers every page
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM leave_requests`+where, args...).Scan(&total); err != nil {
+		r.logger.Errorf("db_query_failed operation=count_leaves error=%v", err)
+		return nil, fmt.Errorf("failed to count leave requests: %w", err)
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := decodeCursor(filter)
+		if err != nil {
+			return nil, err
+		}
+		keyset := fmt.Sprintf("(%s, id) %s (%s::%s, %s::uuid)", column.expr, comparison, arg(cursor.Value), column.cast, arg(cursor.ID))
+		if where == "" {
+			where = " WHERE " + keyset
+		} else {
+			where += " AND " + keyset
+		}
+	}
+
+	query := `SELECT ` + leaveColumns + ` FROM leave_requests` + where +
+		fmt.Sprintf(" ORDER BY %s %s, id %s", column.expr, direction, direction)
+	if filter.Limit > 0 {
+		// Fetch one extra row to learn whether there is a next page
+		query += " LIMIT " + arg(filter.Limit+1)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=search_leaves error=%v", err)
+		return nil, fmt.Errorf("failed to search leave requests: %w", err)
+	}
+	defer rows.Close()
+
+	leaves, err := scanLeaves(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return newLeavePage(filter, leaves, total), nil
+}
+
+// newLeavePage trims the extra row fetched past the limit and sets the next cursor
+func newLeavePage(filter models.LeaveFilter, leaves []*models.LeaveRequest, total int) *models.LeavePage {
+	page := &models.LeavePage{Items: leaves, Total: total}
+	if filter.Limit > 0 && len(leaves) > filter.Limit {
+		page.Items = leaves[:filter.Limit]
+		page.NextCursor = encodeCursor(filter, page.Items[len(page.Items)-1])
+	}
+	if page.Items == nil {
+		page.Items = []*models.LeaveRequest{}
+	}
+	return page
+}
+
+// escapeLike escapes the LIKE wildcards in s
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// scanLeaves reads all leave requests from rows
+func scanLeaves(rows *sql.Rows) ([]*models.LeaveRequest, error) {
+	var leaves []*models.LeaveRequest
+	for rows.Next() {
+		var leave models.LeaveRequest
+		if err := scanLeave(rows, &leave); err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, &leave)
+	}
+
+	return leaves, rows.Err()
+}
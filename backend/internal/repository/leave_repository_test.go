@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
@@ -20,8 +22,8 @@ func TestMockLeaveRepository(t *testing.T) {
 		EmployeeEmail: "john@example.com",
 		LeaveType:     models.LeaveTypeAnnual,
 		Reason:        "Vacation",
-		StartDate:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-		EndDate:       time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		StartDate:     models.NewDate(2024, 1, 1),
+		EndDate:       models.NewDate(2024, 1, 5),
 		Days:          5,
 		Status:        models.LeaveStatusPending,
 		CreatedAt:     time.Now(),
@@ -29,14 +31,14 @@ func TestMockLeaveRepository(t *testing.T) {
 	}
 
 	t.Run("Create", func(t *testing.T) {
-		err := repo.Create(leave)
+		err := repo.Create(context.Background(), leave)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
 	})
 
 	t.Run("FindByID", func(t *testing.T) {
-		found, err := repo.FindByID(leaveID)
+		found, err := repo.FindByID(context.Background(), leaveID)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -45,78 +47,101 @@ func TestMockLeaveRepository(t *testing.T) {
 		}
 
 		// Test non-existent ID
-		_, err = repo.FindByID(uuid.New())
+		_, err = repo.FindByID(context.Background(), uuid.New())
 		if err != sql.ErrNoRows {
 			t.Errorf("expected sql.ErrNoRows, got %v", err)
 		}
 	})
 
-	t.Run("FindByEmployeeID", func(t *testing.T) {
-		leaves, err := repo.FindByEmployeeID("emp-1")
+	t.Run("Search", func(t *testing.T) {
+		page, err := repo.Search(context.Background(), models.LeaveFilter{EmployeeID: "emp-1", Status: models.LeaveStatusPending})
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
-		if len(leaves) != 1 {
-			t.Errorf("expected 1 leave, got %d", len(leaves))
+		if page.Total != 1 {
+			t.Errorf("expected 1 pending leave, got %d", page.Total)
 		}
 
-		// Test non-existent employee
-		leaves, err = repo.FindByEmployeeID("emp-999")
-		if err != nil {
-			t.Errorf("unexpected error: %v", err)
-		}
-		if len(leaves) != 0 {
-			t.Errorf("expected 0 leaves, got %d", len(leaves))
+		// Update status to approved
+		approved := *leave
+		approved.Status = models.LeaveStatusApproved
+		if err := repo.Update(context.Background(), &approved); err != nil {
+			t.Fatalf("unexpected error: %v", err)
 		}
-	})
 
-	t.Run("FindPending", func(t *testing.T) {
-		leaves, err := repo.FindPending()
+		page, err = repo.Search(context.Background(), models.LeaveFilter{EmployeeID: "emp-1", Status: models.LeaveStatusPending})
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
-		if len(leaves) != 1 {
-			t.Errorf("expected 1 pending leave, got %d", len(leaves))
+		if page.Total != 0 {
+			t.Errorf("expected 0 pending leaves, got %d", page.Total)
 		}
 
-		// Update status to approved
-		leave.Status = models.LeaveStatusApproved
-		repo.Update(leave)
-
-		leaves, err = repo.FindPending()
+		// Test non-existent employee
+		page, err = repo.Search(context.Background(), models.LeaveFilter{EmployeeID: "emp-999"})
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
-		if len(leaves) != 0 {
-			t.Errorf("expected 0 pending leaves, got %d", len(leaves))
+		if page.Total != 0 {
+			t.Errorf("expected 0 leaves, got %d", page.Total)
 		}
 	})
 
 	t.Run("Update", func(t *testing.T) {
-		leave.Reason = "Updated reason"
-		err := repo.Update(leave)
+		current, _ := repo.FindByID(context.Background(), leaveID)
+		edited := *current
+		edited.Reason = "Updated reason"
+		err := repo.Update(context.Background(), &edited)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
 
-		updated, _ := repo.FindByID(leaveID)
+		updated, _ := repo.FindByID(context.Background(), leaveID)
 		if updated.Reason != "Updated reason" {
 			t.Errorf("expected reason %q, got %q", "Updated reason", updated.Reason)
 		}
+		if updated.Version != current.Version+1 {
+			t.Errorf("expected version %d, got %d", current.Version+1, updated.Version)
+		}
 	})
 
-	t.Run("UpdateStatus", func(t *testing.T) {
-		err := repo.UpdateStatus(leaveID, models.LeaveStatusApproved, "Approved")
+	t.Run("TransitionStatus", func(t *testing.T) {
+		current, _ := repo.FindByID(context.Background(), leaveID)
+		_, err := repo.TransitionStatus(context.Background(), leaveID, current.Version, []models.LeaveStatus{models.LeaveStatusPending}, models.LeaveStatusRejected, "Too late")
+		if !errors.Is(err, ErrConflict) {
+			t.Errorf("expected ErrConflict for an approved request, got %v", err)
+		}
+
+		_, err = repo.TransitionStatus(context.Background(), leaveID, current.Version-1, []models.LeaveStatus{models.LeaveStatusApproved}, models.LeaveStatusCancelled, "Cancelled")
+		if !errors.Is(err, ErrConflict) {
+			t.Errorf("expected ErrConflict for a stale version, got %v", err)
+		}
+
+		updated, err := repo.TransitionStatus(context.Background(), leaveID, current.Version, []models.LeaveStatus{models.LeaveStatusPending, models.LeaveStatusApproved}, models.LeaveStatusCancelled, "Cancelled")
 		if err != nil {
-			t.Errorf("unexpected error: %v", err)
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if updated.Status != models.LeaveStatusCancelled {
+			t.Errorf("expected status %q, got %q", models.LeaveStatusCancelled, updated.Status)
+		}
+		if updated.GetManagerComment() != "Cancelled" {
+			t.Errorf("expected comment %q, got %q", "Cancelled", updated.GetManagerComment())
+		}
+		if updated.Version != current.Version+1 {
+			t.Errorf("expected version %d, got %d", current.Version+1, updated.Version)
 		}
 
-		updated, _ := repo.FindByID(leaveID)
-		if updated.Status != models.LeaveStatusApproved {
-			t.Errorf("expected status %q, got %q", models.LeaveStatusApproved, updated.Status)
+		_, err = repo.TransitionStatus(context.Background(), uuid.New(), 1, []models.LeaveStatus{models.LeaveStatusPending}, models.LeaveStatusApproved, "")
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
 		}
-		if updated.GetManagerComment() != "Approved" {
-			t.Errorf("expected comment %q, got %q", "Approved", updated.GetManagerComment())
+	})
+
+	t.Run("Update with stale version", func(t *testing.T) {
+		stale := *leave
+		stale.Reason = "Edited after cancellation"
+		if err := repo.Update(context.Background(), &stale); !errors.Is(err, ErrConflict) {
+			t.Errorf("expected ErrConflict, got %v", err)
 		}
 	})
 }
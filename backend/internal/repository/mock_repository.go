@@ -1,7 +1,12 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,6 +15,7 @@ import (
 
 // MockLeaveRepository is a mock implementation of LeaveRepository for testing
 type MockLeaveRepository struct {
+	mu     sync.Mutex
 	leaves map[uuid.UUID]*models.LeaveRequest
 }
 
@@ -21,13 +27,20 @@ func NewMockLeaveRepository() *MockLeaveRepository {
 }
 
 // Create inserts a new leave request
-func (m *MockLeaveRepository) Create(leave *models.LeaveRequest) error {
+func (m *MockLeaveRepository) Create(ctx context.Context, leave *models.LeaveRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if leave.Version == 0 {
+		leave.Version = 1
+	}
 	m.leaves[leave.ID] = leave
 	return nil
 }
 
 // FindByID finds a leave request by ID
-func (m *MockLeaveRepository) FindByID(id uuid.UUID) (*models.LeaveRequest, error) {
+func (m *MockLeaveRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.LeaveRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	leave, exists := m.leaves[id]
 	if !exists {
 		return nil, sql.ErrNoRows
@@ -35,56 +48,933 @@ func (m *MockLeaveRepository) FindByID(id uuid.UUID) (*models.LeaveRequest, erro
 	return leave, nil
 }
 
-// FindByEmployeeID finds all leave requests for an employee
-func (m *MockLeaveRepository) FindByEmployeeID(employeeID string) ([]*models.LeaveRequest, error) {
-	var result []*models.LeaveRequest
-	for _, leave := range m.leaves {
-		if leave.EmployeeID == employeeID {
-			result = append(result, leave)
-		}
-	}
-	return result, nil
-}
-
-// FindPending finds all pending leave requests
-func (m *MockLeaveRepository) FindPending() ([]*models.LeaveRequest, error) {
-	var result []*models.LeaveRequest
-	for _, leave := range m.leaves {
-		if leave.Status == models.LeaveStatusPending {
-			result = append(result, leave)
-		}
-	}
-	return result, nil
-}
-
-// Update updates a leave request
-func (m *MockLeaveRepository) Update(leave *models.LeaveRequest) error {
-	if _, exists := m.leaves[leave.ID]; !exists {
+// Update updates a leave request if its version has not changed
+func (m *MockLeaveRepository) Update(ctx context.Context, leave *models.LeaveRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, exists := m.leaves[leave.ID]
+	if !exists {
 		return sql.ErrNoRows
 	}
+	if stored.Version != leave.Version {
+		return ErrConflict
+	}
+	leave.Version++
 	leave.UpdatedAt = time.Now()
 	m.leaves[leave.ID] = leave
 	return nil
 }
 
-// UpdateStatus updates the status and comment of a leave request
-func (m *MockLeaveRepository) UpdateStatus(id uuid.UUID, status models.LeaveStatus, comment string) error {
-	leave, exists := m.leaves[id]
+// TransitionStatus updates the status and comment of a leave request if it is
+// still at version and in one of the from statuses. The stored request is replaced rather than
+// modified so callers holding the previous value are unaffected.
+func (m *MockLeaveRepository) TransitionStatus(ctx context.Context, id uuid.UUID, version int, from []models.LeaveStatus, status models.LeaveStatus, comment string) (*models.LeaveRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, exists := m.leaves[id]
 	if !exists {
-		return sql.ErrNoRows
+		return nil, ErrNotFound
 	}
+
+	if stored.Version != version || !containsStatus(from, stored.Status) {
+		return nil, ErrConflict
+	}
+
+	leave := *stored
 	leave.Status = status
+	leave.Version++
 	if comment == "" {
 		leave.ManagerComment = sql.NullString{Valid: false}
 	} else {
 		leave.ManagerComment = sql.NullString{String: comment, Valid: true}
 	}
 	leave.UpdatedAt = time.Now()
-	return nil
+	m.leaves[id] = &leave
+	return &leave, nil
+}
+
+// containsStatus reports whether status is in statuses
+func containsStatus(statuses []models.LeaveStatus, status models.LeaveStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Search returns one page of leave requests matching filter
+func (m *MockLeaveRepository) Search(ctx context.Context, filter models.LeaveFilter) (*models.LeavePage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := sortColumns[filter.Sort]; !ok {
+		filter.Sort = models.SortCreatedAt
+	}
+
+	var matched []*models.LeaveRequest
+	for _, leave := range m.leaves {
+		if filter.Status != "" && leave.Status != filter.Status {
+			continue
+		}
+		if filter.LeaveType != "" && leave.LeaveType != filter.LeaveType {
+			continue
+		}
+		if filter.EmployeeID != "" && leave.EmployeeID != filter.EmployeeID {
+			continue
+		}
+		if filter.Department != "" && leave.Department != filter.Department {
+			continue
+		}
+		if filter.Query != "" {
+			q := strings.ToLower(filter.Query)
+			if !strings.Contains(strings.ToLower(leave.EmployeeName), q) && !strings.Contains(strings.ToLower(leave.EmployeeEmail), q) {
+				continue
+			}
+		}
+		if !filter.From.IsZero() && leave.EndDate.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && leave.StartDate.After(filter.To) {
+			continue
+		}
+		if len(filter.Approvers) > 0 && !slices.ContainsFunc(filter.Approvers, func(email string) bool {
+			return strings.EqualFold(email, leave.ApproverEmail)
+		}) {
+			continue
+		}
+		matched = append(matched, leave)
+	}
+
+	// less orders by the sort field then ID, in the requested direction
+	less := func(a, b *models.LeaveRequest) bool {
+		c := compareSortValues(filter.Sort, sortValue(filter.Sort, a), sortValue(filter.Sort, b))
+		if c == 0 {
+			c = strings.Compare(a.ID.String(), b.ID.String())
+		}
+		if filter.Descending {
+			return c > 0
+		}
+		return c < 0
+	}
+	sort.Slice(matched, func(i, j int) bool { return less(matched[i], matched[j]) })
+
+	total := len(matched)
+	if filter.Cursor != "" {
+		cursor, err := decodeCursor(filter)
+		if err != nil {
+			return nil, err
+		}
+		var rest []*models.LeaveRequest
+		for _, leave := range matched {
+			c := compareSortValues(filter.Sort, sortValue(filter.Sort, leave), cursor.Value)
+			if c == 0 {
+				c = strings.Compare(leave.ID.String(), cursor.ID.String())
+			}
+			if (filter.Descending && c < 0) || (!filter.Descending && c > 0) {
+				rest = append(rest, leave)
+			}
+		}
+		matched = rest
+	}
+
+	if filter.Limit > 0 && len(matched) > filter.Limit+1 {
+		matched = matched[:filter.Limit+1]
+	}
+	return newLeavePage(filter, matched, total), nil
 }
 
 // Clear clears all mock data
 func (m *MockLeaveRepository) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.leaves = make(map[uuid.UUID]*models.LeaveRequest)
 }
 
+// MockAttachmentRepository is a mock implementation of AttachmentRepository for testing
+type MockAttachmentRepository struct {
+	attachments map[uuid.UUID]*models.Attachment
+}
+
+// NewMockAttachmentRepository creates a new mock attachment repository
+func NewMockAttachmentRepository() *MockAttachmentRepository {
+	return &MockAttachmentRepository{
+		attachments: make(map[uuid.UUID]*models.Attachment),
+	}
+}
+
+// Create inserts a new attachment record
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	m.attachments[attachment.ID] = attachment
+	return nil
+}
+
+// FindByID finds an attachment by ID
+func (m *MockAttachmentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Attachment, error) {
+	attachment, exists := m.attachments[id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return attachment, nil
+}
+
+// FindByLeaveRequestID finds all attachments for a leave request
+func (m *MockAttachmentRepository) FindByLeaveRequestID(ctx context.Context, leaveRequestID uuid.UUID) ([]*models.Attachment, error) {
+	var result []*models.Attachment
+	for _, attachment := range m.attachments {
+		if attachment.LeaveRequestID == leaveRequestID {
+			result = append(result, attachment)
+		}
+	}
+	return result, nil
+}
+
+// Delete removes an attachment record
+func (m *MockAttachmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, exists := m.attachments[id]; !exists {
+		return ErrNotFound
+	}
+	delete(m.attachments, id)
+	return nil
+}
+
+// MockCommentRepository is a mock implementation of CommentRepository for testing
+type MockCommentRepository struct {
+	comments []*models.LeaveComment
+}
+
+// NewMockCommentRepository creates a new mock comment repository
+func NewMockCommentRepository() *MockCommentRepository {
+	return &MockCommentRepository{}
+}
+
+// Create inserts a new comment
+func (m *MockCommentRepository) Create(ctx context.Context, comment *models.LeaveComment) error {
+	m.comments = append(m.comments, comment)
+	return nil
+}
+
+// FindByID finds a comment by ID
+func (m *MockCommentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.LeaveComment, error) {
+	for _, comment := range m.comments {
+		if comment.ID == id {
+			return comment, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// FindByLeaveRequestID finds all comments on a leave request in insertion order
+func (m *MockCommentRepository) FindByLeaveRequestID(ctx context.Context, leaveRequestID uuid.UUID) ([]*models.LeaveComment, error) {
+	var result []*models.LeaveComment
+	for _, comment := range m.comments {
+		if comment.LeaveRequestID == leaveRequestID {
+			result = append(result, comment)
+		}
+	}
+	return result, nil
+}
+
+// Update saves an edited comment
+func (m *MockCommentRepository) Update(ctx context.Context, comment *models.LeaveComment) error {
+	for i, existing := range m.comments {
+		if existing.ID == comment.ID {
+			m.comments[i] = comment
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// MockHistoryRepository is a mock implementation of HistoryRepository for testing
+type MockHistoryRepository struct {
+	entries []*models.LeaveHistoryEntry
+}
+
+// NewMockHistoryRepository creates a new mock history repository
+func NewMockHistoryRepository() *MockHistoryRepository {
+	return &MockHistoryRepository{}
+}
+
+// Create inserts a history entry
+func (m *MockHistoryRepository) Create(ctx context.Context, entry *models.LeaveHistoryEntry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+// FindAll finds history entries matching filter, newest first
+func (m *MockHistoryRepository) FindAll(ctx context.Context, filter models.AuditFilter) ([]*models.LeaveHistoryEntry, error) {
+	var result []*models.LeaveHistoryEntry
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		entry := m.entries[i]
+		if filter.ActorID != "" && entry.ActorID != filter.ActorID {
+			continue
+		}
+		if filter.Action != "" && entry.Action != filter.Action {
+			continue
+		}
+		result = append(result, entry)
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// FindByLeaveRequestID finds the history of a leave request in insertion order
+func (m *MockHistoryRepository) FindByLeaveRequestID(ctx context.Context, leaveRequestID uuid.UUID) ([]*models.LeaveHistoryEntry, error) {
+	var result []*models.LeaveHistoryEntry
+	for _, entry := range m.entries {
+		if entry.LeaveRequestID == leaveRequestID {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+// MockUnitOfWork is a mock implementation of UnitOfWork for testing. It runs
+// fn directly against the mock repositories, so nothing is rolled back when
+// fn fails.
+type MockUnitOfWork struct {
+	LeaveRepo   *MockLeaveRepository
+	HistoryRepo *MockHistoryRepository
+	CommentRepo *MockCommentRepository
+	OutboxRepo  *MockOutboxRepository
+	PrefRepo    *MockNotificationPreferenceRepository
+}
+
+// NewMockUnitOfWork creates a mock unit of work over the given repositories,
+// creating any that are nil and fresh comments, outbox and preferences
+func NewMockUnitOfWork(leaves *MockLeaveRepository, history *MockHistoryRepository) *MockUnitOfWork {
+	if leaves == nil {
+		leaves = NewMockLeaveRepository()
+	}
+	if history == nil {
+		history = NewMockHistoryRepository()
+	}
+	return &MockUnitOfWork{
+		LeaveRepo:   leaves,
+		HistoryRepo: history,
+		CommentRepo: NewMockCommentRepository(),
+		OutboxRepo:  NewMockOutboxRepository(),
+		PrefRepo:    NewMockNotificationPreferenceRepository(),
+	}
+}
+
+func (u *MockUnitOfWork) Leaves() LeaveRepository                       { return u.LeaveRepo }
+func (u *MockUnitOfWork) History() HistoryRepository                    { return u.HistoryRepo }
+func (u *MockUnitOfWork) Comments() CommentRepository                   { return u.CommentRepo }
+func (u *MockUnitOfWork) Outbox() OutboxRepository                      { return u.OutboxRepo }
+func (u *MockUnitOfWork) Preferences() NotificationPreferenceRepository { return u.PrefRepo }
+
+// WithinTx calls fn with the mock repositories
+func (u *MockUnitOfWork) WithinTx(ctx context.Context, fn func(tx Store) error) error {
+	return fn(u)
+}
+
+// MockIdempotencyRepository is a mock implementation of IdempotencyRepository for testing
+type MockIdempotencyRepository struct {
+	mu   sync.Mutex
+	keys map[string]*models.IdempotencyKey
+}
+
+// NewMockIdempotencyRepository creates a new mock idempotency key repository
+func NewMockIdempotencyRepository() *MockIdempotencyRepository {
+	return &MockIdempotencyRepository{
+		keys: make(map[string]*models.IdempotencyKey),
+	}
+}
+
+// mockIdempotencyID identifies a key within the user who sent it
+func mockIdempotencyID(userID, key string) string {
+	return userID + "\x00" + key
+}
+
+// Reserve stores key unless an unexpired key of the same name exists
+func (m *MockIdempotencyRepository) Reserve(ctx context.Context, key *models.IdempotencyKey, expiredBefore, abandonedBefore time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := mockIdempotencyID(key.UserID, key.Key)
+	if existing, exists := m.keys[id]; exists {
+		cutoff := expiredBefore
+		if !existing.Completed() {
+			cutoff = abandonedBefore
+		}
+		if !existing.CreatedAt.Before(cutoff) {
+			return false, nil
+		}
+	}
+	stored := *key
+	m.keys[id] = &stored
+	return true, nil
+}
+
+// Find finds a user's idempotency key
+func (m *MockIdempotencyRepository) Find(ctx context.Context, userID, key string) (*models.IdempotencyKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, exists := m.keys[mockIdempotencyID(userID, key)]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	record := *stored
+	return &record, nil
+}
+
+// Complete stores the response to the request that reserved key
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, key *models.IdempotencyKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := mockIdempotencyID(key.UserID, key.Key)
+	stored, exists := m.keys[id]
+	if !exists || stored.RequestHash != key.RequestHash {
+		return ErrNotFound
+	}
+	record := *key
+	record.CreatedAt = stored.CreatedAt
+	m.keys[id] = &record
+	return nil
+}
+
+// Delete removes a user's idempotency key
+func (m *MockIdempotencyRepository) Delete(ctx context.Context, userID, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.keys, mockIdempotencyID(userID, key))
+	return nil
+}
+
+// DeleteExpired removes keys created before expiredBefore
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var removed int64
+	for id, key := range m.keys {
+		if key.CreatedAt.Before(expiredBefore) {
+			delete(m.keys, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// MockOutboxRepository is a mock implementation of OutboxRepository for testing
+type MockOutboxRepository struct {
+	mu       sync.Mutex
+	messages []*models.OutboxMessage
+}
+
+// NewMockOutboxRepository creates a new mock outbox repository
+func NewMockOutboxRepository() *MockOutboxRepository {
+	return &MockOutboxRepository{}
+}
+
+// Create inserts an outbox message
+func (m *MockOutboxRepository) Create(ctx context.Context, msg *models.OutboxMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *msg
+	m.messages = append(m.messages, &stored)
+	return nil
+}
+
+// ClaimDue claims due messages and the pending members of their groups
+func (m *MockOutboxRepository) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*models.OutboxMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []*models.OutboxMessage
+	for _, msg := range m.messages {
+		if (msg.Status == models.OutboxStatusPending || msg.Status == models.OutboxStatusProcessing) && !msg.NextAttemptAt.After(now) {
+			due = append(due, msg)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make(map[uuid.UUID]bool)
+	groups := make(map[string]bool)
+	for _, msg := range due {
+		claimed[msg.ID] = true
+		if msg.GroupKey != "" {
+			groups[msg.GroupKey] = true
+		}
+	}
+
+	var result []*models.OutboxMessage
+	for _, msg := range m.messages {
+		if !claimed[msg.ID] && !(msg.Status == models.OutboxStatusPending && groups[msg.GroupKey]) {
+			continue
+		}
+		msg.Status = models.OutboxStatusProcessing
+		msg.Attempts++
+		msg.NextAttemptAt = now.Add(lease)
+		msg.UpdatedAt = now
+		copied := *msg
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+// MarkDelivered records that a message was delivered
+func (m *MockOutboxRepository) MarkDelivered(ctx context.Context, id uuid.UUID, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, msg := range m.messages {
+		if msg.ID == id {
+			msg.Status = models.OutboxStatusDelivered
+			msg.LastError = ""
+			msg.DeliveredAt = &at
+			msg.UpdatedAt = at
+		}
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, failed *models.OutboxMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, msg := range m.messages {
+		if msg.ID == failed.ID {
+			msg.Status = failed.Status
+			msg.LastError = failed.LastError
+			msg.NextAttemptAt = failed.NextAttemptAt
+			msg.UpdatedAt = failed.UpdatedAt
+		}
+	}
+	return nil
+}
+
+// FindByID finds an outbox message by ID
+func (m *MockOutboxRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.OutboxMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, msg := range m.messages {
+		if msg.ID == id {
+			copied := *msg
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// FindAll finds outbox messages matching filter, newest first
+func (m *MockOutboxRepository) FindAll(ctx context.Context, filter models.OutboxFilter) ([]*models.OutboxMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.OutboxMessage
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		msg := m.messages[i]
+		if filter.Status != "" && msg.Status != filter.Status {
+			continue
+		}
+		if filter.Topic != "" && msg.Topic != filter.Topic {
+			continue
+		}
+		copied := *msg
+		result = append(result, &copied)
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// Replay makes a dead message due now
+func (m *MockOutboxRepository) Replay(ctx context.Context, id uuid.UUID, now time.Time) (*models.OutboxMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, msg := range m.messages {
+		if msg.ID == id && msg.Status == models.OutboxStatusDead {
+			msg.Status = models.OutboxStatusPending
+			msg.Attempts = 0
+			msg.NextAttemptAt = now
+			msg.UpdatedAt = now
+			copied := *msg
+			return &copied, nil
+		}
+	}
+	return nil, ErrConflict
+}
+
+// MockTemplateRepository is a mock implementation of TemplateRepository for testing
+type MockTemplateRepository struct {
+	mu        sync.Mutex
+	templates map[string]*models.NotificationTemplate
+}
+
+// NewMockTemplateRepository creates a new mock notification template repository
+func NewMockTemplateRepository() *MockTemplateRepository {
+	return &MockTemplateRepository{
+		templates: make(map[string]*models.NotificationTemplate),
+	}
+}
+
+// Find finds the override of a template
+func (m *MockTemplateRepository) Find(ctx context.Context, locale, name string) (*models.NotificationTemplate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, exists := m.templates[locale+"\x00"+name]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	tmpl := *stored
+	return &tmpl, nil
+}
+
+// FindAll lists every override by name then locale
+func (m *MockTemplateRepository) FindAll(ctx context.Context) ([]*models.NotificationTemplate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var templates []*models.NotificationTemplate
+	for _, stored := range m.templates {
+		tmpl := *stored
+		templates = append(templates, &tmpl)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Name != templates[j].Name {
+			return templates[i].Name < templates[j].Name
+		}
+		return templates[i].Locale < templates[j].Locale
+	})
+	return templates, nil
+}
+
+// Save creates or replaces the override of a template
+func (m *MockTemplateRepository) Save(ctx context.Context, tmpl *models.NotificationTemplate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *tmpl
+	m.templates[tmpl.Locale+"\x00"+tmpl.Name] = &stored
+	return nil
+}
+
+// Delete removes an override
+func (m *MockTemplateRepository) Delete(ctx context.Context, locale, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := locale + "\x00" + name
+	if _, exists := m.templates[key]; !exists {
+		return ErrNotFound
+	}
+	delete(m.templates, key)
+	return nil
+}
+
+// MockCalendarFeedRepository is a mock implementation of CalendarFeedRepository for testing
+type MockCalendarFeedRepository struct {
+	mu    sync.Mutex
+	feeds map[string]*models.CalendarFeed
+}
+
+// NewMockCalendarFeedRepository creates a new mock calendar feed repository
+func NewMockCalendarFeedRepository() *MockCalendarFeedRepository {
+	return &MockCalendarFeedRepository{
+		feeds: make(map[string]*models.CalendarFeed),
+	}
+}
+
+// Find finds an employee's feed of one kind
+func (m *MockCalendarFeedRepository) Find(ctx context.Context, employeeID string, kind models.CalendarFeedKind) (*models.CalendarFeed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, exists := m.feeds[employeeID+"\x00"+string(kind)]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	feed := *stored
+	return &feed, nil
+}
+
+// FindByTokenHash finds the feed whose token has hash
+func (m *MockCalendarFeedRepository) FindByTokenHash(ctx context.Context, hash string) (*models.CalendarFeed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.feeds {
+		if stored.TokenHash == hash {
+			feed := *stored
+			return &feed, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// FindByEmployeeID lists an employee's feeds by kind
+func (m *MockCalendarFeedRepository) FindByEmployeeID(ctx context.Context, employeeID string) ([]*models.CalendarFeed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var feeds []*models.CalendarFeed
+	for _, stored := range m.feeds {
+		if stored.EmployeeID == employeeID {
+			feed := *stored
+			feeds = append(feeds, &feed)
+		}
+	}
+	sort.Slice(feeds, func(i, j int) bool { return feeds[i].Kind < feeds[j].Kind })
+	return feeds, nil
+}
+
+// Save creates or replaces the employee's feed of its kind
+func (m *MockCalendarFeedRepository) Save(ctx context.Context, feed *models.CalendarFeed) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := feed.EmployeeID + "\x00" + string(feed.Kind)
+	stored := *feed
+	if existing, exists := m.feeds[key]; exists {
+		stored.CreatedAt = existing.CreatedAt
+	}
+	m.feeds[key] = &stored
+	return nil
+}
+
+// Delete removes a feed
+func (m *MockCalendarFeedRepository) Delete(ctx context.Context, employeeID string, kind models.CalendarFeedKind) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := employeeID + "\x00" + string(kind)
+	if _, exists := m.feeds[key]; !exists {
+		return ErrNotFound
+	}
+	delete(m.feeds, key)
+	return nil
+}
+
+// MockNotificationPreferenceRepository is a mock implementation of NotificationPreferenceRepository for testing
+type MockNotificationPreferenceRepository struct {
+	mu    sync.Mutex
+	prefs map[string]*models.NotificationPreferences
+}
+
+// NewMockNotificationPreferenceRepository creates a new mock notification preference repository
+func NewMockNotificationPreferenceRepository() *MockNotificationPreferenceRepository {
+	return &MockNotificationPreferenceRepository{
+		prefs: make(map[string]*models.NotificationPreferences),
+	}
+}
+
+// Find finds an employee's preferences
+func (m *MockNotificationPreferenceRepository) Find(ctx context.Context, employeeID string) (*models.NotificationPreferences, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, exists := m.prefs[employeeID]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return copyPreferences(stored), nil
+}
+
+// FindByEmails finds the preferences of the people with these addresses
+func (m *MockNotificationPreferenceRepository) FindByEmails(ctx context.Context, emails []string) ([]*models.NotificationPreferences, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.NotificationPreferences
+	for _, stored := range m.prefs {
+		for _, email := range emails {
+			if strings.EqualFold(stored.Email, email) {
+				result = append(result, copyPreferences(stored))
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// FindDigestSubscribers lists everyone who leaves an event to the digest
+func (m *MockNotificationPreferenceRepository) FindDigestSubscribers(ctx context.Context) ([]*models.NotificationPreferences, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*models.NotificationPreferences
+	for _, stored := range m.prefs {
+		if stored.WantsDigest() {
+			result = append(result, copyPreferences(stored))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].EmployeeID < result[j].EmployeeID })
+	return result, nil
+}
+
+// Save creates or replaces an employee's preferences
+func (m *MockNotificationPreferenceRepository) Save(ctx context.Context, prefs *models.NotificationPreferences) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := copyPreferences(prefs)
+	if existing, exists := m.prefs[prefs.EmployeeID]; exists {
+		stored.CreatedAt = existing.CreatedAt
+		stored.LastDigestOn = existing.LastDigestOn
+	}
+	m.prefs[prefs.EmployeeID] = stored
+	return nil
+}
+
+// ClaimDigest records that the employee's digest for date is queued
+func (m *MockNotificationPreferenceRepository) ClaimDigest(ctx context.Context, employeeID string, date models.Date) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, exists := m.prefs[employeeID]
+	if !exists || (!stored.LastDigestOn.IsZero() && !stored.LastDigestOn.Before(date)) {
+		return false, nil
+	}
+	stored.LastDigestOn = date
+	return true, nil
+}
+
+func copyPreferences(prefs *models.NotificationPreferences) *models.NotificationPreferences {
+	c := *prefs
+	c.Events = make(map[string]models.EventPreference, len(prefs.Events))
+	for event, pref := range prefs.Events {
+		c.Events[event] = pref
+	}
+	return &c
+}
+
+// MockNotificationRepository is a mock implementation of NotificationRepository for testing
+type MockNotificationRepository struct {
+	mu            sync.Mutex
+	notifications []*models.Notification
+	deliveries    map[[3]string]time.Time
+}
+
+// NewMockNotificationRepository creates a new mock notification repository
+func NewMockNotificationRepository() *MockNotificationRepository {
+	return &MockNotificationRepository{deliveries: make(map[[3]string]time.Time)}
+}
+
+// Create adds a notification unless its recipient already has its key
+func (m *MockNotificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.notifications {
+		if existing.Recipient == notification.Recipient && existing.Key == notification.Key {
+			return nil
+		}
+	}
+	// Postgres keeps microseconds, as do cursors
+	stored := *notification
+	stored.CreatedAt = stored.CreatedAt.Truncate(time.Microsecond)
+	m.notifications = append(m.notifications, &stored)
+	return nil
+}
+
+// List returns a page of the recipient's inbox, newest first
+func (m *MockNotificationRepository) List(ctx context.Context, filter models.NotificationFilter) (*models.NotificationPage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var matched []*models.Notification
+	for _, notification := range m.notifications {
+		if notification.Recipient != filter.Recipient || (filter.Unread && notification.ReadAt != nil) {
+			continue
+		}
+		matched = append(matched, notification)
+	}
+	newer := func(a, b *models.Notification) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID.String() > b.ID.String()
+	}
+	sort.Slice(matched, func(i, j int) bool { return newer(matched[i], matched[j]) })
+	total := len(matched)
+
+	if filter.Cursor != "" {
+		cursor, err := decodeNotificationCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		createdAt, _ := time.Parse(cursorTimeLayout, cursor.CreatedAt)
+		after := &models.Notification{ID: cursor.ID, CreatedAt: createdAt}
+		start := 0
+		for start < len(matched) && !newer(after, matched[start]) {
+			start++
+		}
+		matched = matched[start:]
+	}
+	if filter.Limit > 0 && len(matched) > filter.Limit+1 {
+		matched = matched[:filter.Limit+1]
+	}
+
+	result := make([]*models.Notification, len(matched))
+	for i, notification := range matched {
+		copied := *notification
+		result[i] = &copied
+	}
+	return newNotificationPage(filter, result, total), nil
+}
+
+// CountUnread counts the recipient's unread notifications
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, recipient string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for _, notification := range m.notifications {
+		if notification.Recipient == recipient && notification.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MarkRead sets the read time of one of the recipient's notifications
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, recipient string, id uuid.UUID, at time.Time) (*models.Notification, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, notification := range m.notifications {
+		if notification.ID == id && notification.Recipient == recipient {
+			if notification.ReadAt == nil {
+				notification.ReadAt = &at
+			}
+			copied := *notification
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// MarkAllRead sets the read time of the recipient's unread notifications
+func (m *MockNotificationRepository) MarkAllRead(ctx context.Context, recipient string, at time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for _, notification := range m.notifications {
+		if notification.Recipient == recipient && notification.ReadAt == nil {
+			notification.ReadAt = &at
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Delivered looks up a delivery recorded by RecordDelivery
+func (m *MockNotificationRepository) Delivered(ctx context.Context, key, channel, recipient string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, delivered := m.deliveries[[3]string{key, channel, recipient}]
+	return delivered, nil
+}
+
+// RecordDelivery records a delivery
+func (m *MockNotificationRepository) RecordDelivery(ctx context.Context, key, channel, recipient string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := [3]string{key, channel, recipient}
+	if _, exists := m.deliveries[id]; !exists {
+		m.deliveries[id] = time.Now()
+	}
+	return nil
+}
+
+// PruneDeliveries removes deliveries recorded before olderThan
+func (m *MockNotificationRepository) PruneDeliveries(ctx context.Context, olderThan time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var removed int64
+	for id, at := range m.deliveries {
+		if at.Before(olderThan) {
+			delete(m.deliveries, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"leave-management-system/internal/logger"
+	"leave-management-system/internal/models"
+)
+
+// NotificationPreferenceRepository defines the interface for notification preference data access
+type NotificationPreferenceRepository interface {
+	Find(ctx context.Context, employeeID string) (*models.NotificationPreferences, error)
+	// FindByEmails returns the preferences of the people with these email
+	// addresses, matched case-insensitively; people without any are left out
+	FindByEmails(ctx context.Context, emails []string) ([]*models.NotificationPreferences, error)
+	// FindDigestSubscribers lists everyone who leaves an event to the digest
+	FindDigestSubscribers(ctx context.Context) ([]*models.NotificationPreferences, error)
+	// Save creates or replaces an employee's preferences, keeping the date
+	// of their last digest
+	Save(ctx context.Context, prefs *models.NotificationPreferences) error
+	// ClaimDigest records that the employee's digest for date is queued. It
+	// reports false if it already was, e.g. by another server.
+	ClaimDigest(ctx context.Context, employeeID string, date models.Date) (bool, error)
+}
+
+// notificationPreferenceRepository implements NotificationPreferenceRepository
+type notificationPreferenceRepository struct {
+	db           dbtx
+	queryTimeout time.Duration
+	logger       *logger.Logger
+}
+
+// NewNotificationPreferenceRepository creates a new notification preference repository
+func NewNotificationPreferenceRepository(db *sql.DB, queryTimeout time.Duration) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{
+		db:           db,
+		queryTimeout: queryTimeout,
+		logger:       logger.New().With("component", "repository"),
+	}
+}
+
+const notificationPreferenceColumns = `employee_id, email, employee_name, department, timezone, locale, events, digest_time, last_digest_on, created_at, updated_at`
+
+func scanNotificationPreferences(row interface{ Scan(...interface{}) error }) (*models.NotificationPreferences, error) {
+	var prefs models.NotificationPreferences
+	var events []byte
+	err := row.Scan(
+		&prefs.EmployeeID,
+		&prefs.Email,
+		&prefs.EmployeeName,
+		&prefs.Department,
+		&prefs.Timezone,
+		&prefs.Locale,
+		&events,
+		&prefs.DigestTime,
+		&prefs.LastDigestOn,
+		&prefs.CreatedAt,
+		&prefs.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(events, &prefs.Events); err != nil {
+		return nil, fmt.Errorf("failed to decode notification preferences of %s: %w", prefs.EmployeeID, err)
+	}
+	return &prefs, nil
+}
+
+// Find finds an employee's preferences
+func (r *notificationPreferenceRepository) Find(ctx context.Context, employeeID string) (*models.NotificationPreferences, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + notificationPreferenceColumns + ` FROM notification_preferences WHERE employee_id = $1`
+
+	prefs, err := scanNotificationPreferences(r.db.QueryRowContext(ctx, query, employeeID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, "notification preferences")
+	}
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_notification_preferences employee_id=%s error=%v", employeeID, err)
+		return nil, fmt.Errorf("failed to find notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// FindByEmails finds the preferences of the people with these addresses
+func (r *notificationPreferenceRepository) FindByEmails(ctx context.Context, emails []string) ([]*models.NotificationPreferences, error) {
+	lower := make([]string, len(emails))
+	for i, email := range emails {
+		lower[i] = strings.ToLower(email)
+	}
+
+	query := `SELECT ` + notificationPreferenceColumns + ` FROM notification_preferences WHERE LOWER(email) = ANY($1)`
+	return r.query(ctx, "find_notification_preferences_by_email", query, pq.Array(lower))
+}
+
+// FindDigestSubscribers lists everyone who leaves an event to the digest
+func (r *notificationPreferenceRepository) FindDigestSubscribers(ctx context.Context) ([]*models.NotificationPreferences, error) {
+	query := `SELECT ` + notificationPreferenceColumns + ` FROM notification_preferences WHERE digest ORDER BY employee_id`
+	return r.query(ctx, "find_digest_subscribers", query)
+}
+
+func (r *notificationPreferenceRepository) query(ctx context.Context, operation, query string, args ...interface{}) ([]*models.NotificationPreferences, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=%s error=%v", operation, err)
+		return nil, fmt.Errorf("failed to query notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*models.NotificationPreferences
+	for rows.Next() {
+		prefs, err := scanNotificationPreferences(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification preferences: %w", err)
+		}
+		result = append(result, prefs)
+	}
+	return result, rows.Err()
+}
+
+// Save upserts an employee's preferences
+func (r *notificationPreferenceRepository) Save(ctx context.Context, prefs *models.NotificationPreferences) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	events, err := json.Marshal(prefs.Events)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification preferences: %w", err)
+	}
+
+	query := `
+		INSERT INTO notification_preferences (employee_id, email, employee_name, department, timezone, locale, events, digest_time, digest, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (employee_id) DO UPDATE
+		SET email = EXCLUDED.email,
+		    employee_name = EXCLUDED.employee_name,
+		    department = EXCLUDED.department,
+		    timezone = EXCLUDED.timezone,
+		    locale = EXCLUDED.locale,
+		    events = EXCLUDED.events,
+		    digest_time = EXCLUDED.digest_time,
+		    digest = EXCLUDED.digest,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		prefs.EmployeeID,
+		prefs.Email,
+		prefs.EmployeeName,
+		prefs.Department,
+		prefs.Timezone,
+		prefs.Locale,
+		events,
+		prefs.DigestTime,
+		prefs.WantsDigest(),
+		prefs.CreatedAt,
+		prefs.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Errorf("db_update_failed operation=save_notification_preferences employee_id=%s error=%v", prefs.EmployeeID, err)
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return nil
+}
+
+// ClaimDigest moves the employee's last digest date to date unless it is
+// there already
+func (r *notificationPreferenceRepository) ClaimDigest(ctx context.Context, employeeID string, date models.Date) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE notification_preferences
+		SET last_digest_on = $2
+		WHERE employee_id = $1 AND (last_digest_on IS NULL OR last_digest_on < $2)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, employeeID, date)
+	if err != nil {
+		r.logger.Errorf("db_update_failed operation=claim_digest employee_id=%s date=%s error=%v", employeeID, date, err)
+		return false, fmt.Errorf("failed to claim digest: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim digest: %w", err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/logger"
+	"leave-management-system/internal/models"
+)
+
+// NotificationRepository defines the interface for in-app notification data access
+type NotificationRepository interface {
+	// Create adds a notification to its recipient's inbox. It does nothing if
+	// the inbox already holds one with the same key.
+	Create(ctx context.Context, notification *models.Notification) error
+	// List returns a page of an inbox, newest first
+	List(ctx context.Context, filter models.NotificationFilter) (*models.NotificationPage, error)
+	CountUnread(ctx context.Context, recipient string) (int, error)
+	// MarkRead marks one of the recipient's notifications as read, keeping
+	// when it was first read, and returns it
+	MarkRead(ctx context.Context, recipient string, id uuid.UUID, at time.Time) (*models.Notification, error)
+	// MarkAllRead marks every unread notification of the recipient as read
+	// and returns how many there were
+	MarkAllRead(ctx context.Context, recipient string, at time.Time) (int, error)
+	// Delivered reports whether the notification with key was delivered on
+	// channel to recipient
+	Delivered(ctx context.Context, key, channel, recipient string) (bool, error)
+	// RecordDelivery records that the notification with key was delivered
+	// on channel to recipient
+	RecordDelivery(ctx context.Context, key, channel, recipient string) error
+	// PruneDeliveries removes deliveries recorded before olderThan and
+	// returns how many were removed
+	PruneDeliveries(ctx context.Context, olderThan time.Time) (int64, error)
+}
+
+// notificationColumns lists the notifications columns in the order scanNotification reads them
+const notificationColumns = `id, recipient, event, subject, summary, leave_request_id, dedupe_key, read_at, created_at`
+
+// scanNotification scans a row selected with notificationColumns into notification
+func scanNotification(row rowScanner, notification *models.Notification) error {
+	var leaveID uuid.NullUUID
+	var readAt sql.NullTime
+	err := row.Scan(
+		&notification.ID,
+		&notification.Recipient,
+		&notification.Event,
+		&notification.Subject,
+		&notification.Summary,
+		&leaveID,
+		&notification.Key,
+		&readAt,
+		&notification.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if leaveID.Valid {
+		notification.LeaveRequestID = &leaveID.UUID
+	}
+	if readAt.Valid {
+		notification.ReadAt = &readAt.Time
+	}
+	return nil
+}
+
+// notificationCursor is the position after the last notification of a page
+type notificationCursor struct {
+	CreatedAt string    `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// encodeNotificationCursor returns the opaque cursor continuing after notification
+func encodeNotificationCursor(notification *models.Notification) string {
+	data, _ := json.Marshal(notificationCursor{
+		CreatedAt: notification.CreatedAt.Format(cursorTimeLayout),
+		ID:        notification.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeNotificationCursor parses a cursor made by encodeNotificationCursor
+func decodeNotificationCursor(s string) (*notificationCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor notificationCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.CreatedAt == "" {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := time.Parse(cursorTimeLayout, cursor.CreatedAt); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// newNotificationPage trims the extra row fetched past the limit and sets the next cursor
+func newNotificationPage(filter models.NotificationFilter, notifications []*models.Notification, total int) *models.NotificationPage {
+	page := &models.NotificationPage{Items: notifications, Total: total}
+	if filter.Limit > 0 && len(notifications) > filter.Limit {
+		page.Items = notifications[:filter.Limit]
+		page.NextCursor = encodeNotificationCursor(page.Items[len(page.Items)-1])
+	}
+	if page.Items == nil {
+		page.Items = []*models.Notification{}
+	}
+	return page
+}
+
+// notificationRepository implements NotificationRepository
+type notificationRepository struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+	logger       *logger.Logger
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *sql.DB, queryTimeout time.Duration) NotificationRepository {
+	return &notificationRepository{
+		db:           db,
+		queryTimeout: queryTimeout,
+		logger:       logger.New().With("component", "repository"),
+	}
+}
+
+// Create inserts a notification unless its recipient already has its key
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO notifications (` + notificationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (recipient, dedupe_key) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		notification.ID,
+		notification.Recipient,
+		notification.Event,
+		notification.Subject,
+		notification.Summary,
+		notification.LeaveRequestID,
+		notification.Key,
+		notification.ReadAt,
+		notification.CreatedAt,
+	)
+	if err != nil {
+		r.logger.Errorf("db_create_failed operation=create_notification notification_id=%s event=%s error=%v", notification.ID, notification.Event, err)
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+// List returns a page of the recipient's inbox, newest first
+func (r *notificationRepository) List(ctx context.Context, filter models.NotificationFilter) (*models.NotificationPage, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	args := []interface{}{filter.Recipient}
+	where := " WHERE recipient = $1"
+	if filter.Unread {
+		where += " AND read_at IS NULL"
+	}
+
+	// Count before the cursor condition so the total covers every page
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications`+where, args...).Scan(&total); err != nil {
+		r.logger.Errorf("db_query_failed operation=count_notifications error=%v", err)
+		return nil, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := decodeNotificationCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, cursor.CreatedAt, cursor.ID)
+		where += " AND (created_at, id) < ($2::timestamp, $3::uuid)"
+	}
+
+	query := `SELECT ` + notificationColumns + ` FROM notifications` + where + ` ORDER BY created_at DESC, id DESC`
+	if filter.Limit > 0 {
+		// Fetch one extra row to learn whether there is a next page
+		args = append(args, filter.Limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=list_notifications error=%v", err)
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*models.Notification
+	for rows.Next() {
+		var notification models.Notification
+		if err := scanNotification(rows, &notification); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, &notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	return newNotificationPage(filter, notifications, total), nil
+}
+
+// CountUnread counts the recipient's unread notifications
+func (r *notificationRepository) CountUnread(ctx context.Context, recipient string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var count int
+	query := `SELECT COUNT(*) FROM notifications WHERE recipient = $1 AND read_at IS NULL`
+	if err := r.db.QueryRowContext(ctx, query, recipient).Scan(&count); err != nil {
+		r.logger.Errorf("db_query_failed operation=count_unread_notifications error=%v", err)
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead sets the read time of one of the recipient's notifications
+func (r *notificationRepository) MarkRead(ctx context.Context, recipient string, id uuid.UUID, at time.Time) (*models.Notification, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, $3)
+		WHERE id = $1 AND recipient = $2
+		RETURNING ` + notificationColumns
+
+	var notification models.Notification
+	err := scanNotification(r.db.QueryRowContext(ctx, query, id, recipient, at), &notification)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: notification %s", ErrNotFound, id)
+	}
+	if err != nil {
+		r.logger.Errorf("db_update_failed operation=mark_notification_read notification_id=%s error=%v", id, err)
+		return nil, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	return &notification, nil
+}
+
+// MarkAllRead sets the read time of the recipient's unread notifications
+func (r *notificationRepository) MarkAllRead(ctx context.Context, recipient string, at time.Time) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE notifications SET read_at = $2 WHERE recipient = $1 AND read_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, recipient, at)
+	if err != nil {
+		r.logger.Errorf("db_update_failed operation=mark_all_notifications_read error=%v", err)
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return int(n), nil
+}
+
+// Delivered looks up a delivery recorded by RecordDelivery
+func (r *notificationRepository) Delivered(ctx context.Context, key, channel, recipient string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM notification_deliveries
+			WHERE dedupe_key = $1 AND channel = $2 AND recipient = $3
+		)
+	`
+
+	var delivered bool
+	if err := r.db.QueryRowContext(ctx, query, key, channel, recipient).Scan(&delivered); err != nil {
+		r.logger.Errorf("db_query_failed operation=find_notification_delivery channel=%s error=%v", channel, err)
+		return false, fmt.Errorf("failed to find notification delivery: %w", err)
+	}
+	return delivered, nil
+}
+
+// RecordDelivery inserts a delivery unless it is already recorded
+func (r *notificationRepository) RecordDelivery(ctx context.Context, key, channel, recipient string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO notification_deliveries (dedupe_key, channel, recipient, delivered_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (dedupe_key, channel, recipient) DO NOTHING
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, key, channel, recipient, time.Now()); err != nil {
+		r.logger.Errorf("db_create_failed operation=record_notification_delivery channel=%s error=%v", channel, err)
+		return fmt.Errorf("failed to record notification delivery: %w", err)
+	}
+	return nil
+}
+
+// PruneDeliveries removes deliveries recorded before olderThan
+func (r *notificationRepository) PruneDeliveries(ctx context.Context, olderThan time.Time) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `DELETE FROM notification_deliveries WHERE delivered_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, olderThan)
+	if err != nil {
+		r.logger.Errorf("db_delete_failed operation=prune_notification_deliveries error=%v", err)
+		return 0, fmt.Errorf("failed to prune notification deliveries: %w", err)
+	}
+
+	return result.RowsAffected()
+}
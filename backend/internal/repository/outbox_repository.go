@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/logger"
+	"leave-management-system/internal/models"
+)
+
+// OutboxRepository defines the interface for outbox message data access
+type OutboxRepository interface {
+	Create(ctx context.Context, msg *models.OutboxMessage) error
+	// ClaimDue marks up to limit messages due at now as processing until
+	// now+lease and returns them, together with the pending messages sharing
+	// their group keys. Messages whose lease expired are claimed again.
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*models.OutboxMessage, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID, at time.Time) error
+	// MarkFailed records a failed attempt using msg's Status, LastError and NextAttemptAt
+	MarkFailed(ctx context.Context, msg *models.OutboxMessage) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.OutboxMessage, error)
+	FindAll(ctx context.Context, filter models.OutboxFilter) ([]*models.OutboxMessage, error)
+	// Replay makes a dead message pending again with its attempts reset. It
+	// returns ErrConflict if the message is no longer dead.
+	Replay(ctx context.Context, id uuid.UUID, now time.Time) (*models.OutboxMessage, error)
+}
+
+// outboxColumns lists the outbox_messages columns in the order scanOutbox reads them
+const outboxColumns = `id, topic, group_key, payload, status, attempts, last_error, next_attempt_at, created_at, updated_at, delivered_at`
+
+// scanOutbox scans a row selected with outboxColumns into msg
+func scanOutbox(row rowScanner, msg *models.OutboxMessage) error {
+	var groupKey sql.NullString
+	var deliveredAt sql.NullTime
+	err := row.Scan(
+		&msg.ID,
+		&msg.Topic,
+		&groupKey,
+		&msg.Payload,
+		&msg.Status,
+		&msg.Attempts,
+		&msg.LastError,
+		&msg.NextAttemptAt,
+		&msg.CreatedAt,
+		&msg.UpdatedAt,
+		&deliveredAt,
+	)
+	if err != nil {
+		return err
+	}
+	msg.GroupKey = groupKey.String
+	if deliveredAt.Valid {
+		msg.DeliveredAt = &deliveredAt.Time
+	}
+	return nil
+}
+
+// outboxRepository implements OutboxRepository
+type outboxRepository struct {
+	db           dbtx
+	queryTimeout time.Duration
+	logger       *logger.Logger
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *sql.DB, queryTimeout time.Duration) OutboxRepository {
+	return &outboxRepository{
+		db:           db,
+		queryTimeout: queryTimeout,
+		logger:       logger.New().With("component", "repository"),
+	}
+}
+
+// Create inserts an outbox message
+func (r *outboxRepository) Create(ctx context.Context, msg *models.OutboxMessage) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO outbox_messages (id, topic, group_key, payload, status, attempts, last_error, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		msg.ID,
+		msg.Topic,
+		msg.GroupKey,
+		[]byte(msg.Payload),
+		msg.Status,
+		msg.Attempts,
+		msg.LastError,
+		msg.NextAttemptAt,
+		msg.CreatedAt,
+		msg.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Errorf("db_create_failed operation=create_outbox_message topic=%s error=%v", msg.Topic, err)
+		return fmt.Errorf("failed to create outbox message: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue claims due messages. SKIP LOCKED lets several dispatchers poll
+// without claiming the same message twice.
+func (r *outboxRepository) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*models.OutboxMessage, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		WITH due AS (
+			SELECT id, group_key
+			FROM outbox_messages
+			WHERE status IN ('pending', 'processing') AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox_messages
+		SET status = 'processing', attempts = attempts + 1, next_attempt_at = $3, updated_at = $1
+		WHERE id IN (SELECT id FROM due)
+		   OR (status = 'pending' AND group_key IN (SELECT group_key FROM due WHERE group_key IS NOT NULL))
+		RETURNING ` + outboxColumns
+
+	rows, err := r.db.QueryContext(ctx, query, now, limit, now.Add(lease))
+	if err != nil {
+		r.logger.Errorf("db_update_failed operation=claim_outbox_messages error=%v", err)
+		return nil, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	return scanOutboxRows(rows)
+}
+
+// MarkDelivered records that a message was delivered
+func (r *outboxRepository) MarkDelivered(ctx context.Context, id uuid.UUID, at time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE outbox_messages
+		SET status = 'delivered', last_error = '', delivered_at = $2, updated_at = $2
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, id, at); err != nil {
+		r.logger.Errorf("db_update_failed operation=deliver_outbox_message message_id=%s error=%v", id, err)
+		return fmt.Errorf("failed to mark outbox message delivered: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt
+func (r *outboxRepository) MarkFailed(ctx context.Context, msg *models.OutboxMessage) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE outbox_messages
+		SET status = $2, last_error = $3, next_attempt_at = $4, updated_at = $5
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, msg.ID, msg.Status, msg.LastError, msg.NextAttemptAt, msg.UpdatedAt); err != nil {
+		r.logger.Errorf("db_update_failed operation=fail_outbox_message message_id=%s error=%v", msg.ID, err)
+		return fmt.Errorf("failed to mark outbox message failed: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds an outbox message by ID
+func (r *outboxRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.OutboxMessage, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + outboxColumns + ` FROM outbox_messages WHERE id = $1`
+
+	var msg models.OutboxMessage
+	err := scanOutbox(r.db.QueryRowContext(ctx, query, id), &msg)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, "outbox message")
+	}
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_outbox_message message_id=%s error=%v", id, err)
+		return nil, fmt.Errorf("failed to find outbox message: %w", err)
+	}
+
+	return &msg, nil
+}
+
+// FindAll finds outbox messages matching filter, newest first
+func (r *outboxRepository) FindAll(ctx context.Context, filter models.OutboxFilter) ([]*models.OutboxMessage, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + outboxColumns + `
+		FROM outbox_messages
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR topic = $2)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.Status, filter.Topic, filter.Limit)
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_outbox_messages status=%s topic=%s error=%v", filter.Status, filter.Topic, err)
+		return nil, fmt.Errorf("failed to query outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	return scanOutboxRows(rows)
+}
+
+// Replay makes a dead message due now
+func (r *outboxRepository) Replay(ctx context.Context, id uuid.UUID, now time.Time) (*models.OutboxMessage, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE outbox_messages
+		SET status = 'pending', attempts = 0, next_attempt_at = $2, updated_at = $2
+		WHERE id = $1 AND status = 'dead'
+		RETURNING ` + outboxColumns
+
+	var msg models.OutboxMessage
+	err := scanOutbox(r.db.QueryRowContext(ctx, query, id, now), &msg)
+	if err == sql.ErrNoRows {
+		return nil, ErrConflict
+	}
+	if err != nil {
+		r.logger.Errorf("db_update_failed operation=replay_outbox_message message_id=%s error=%v", id, err)
+		return nil, fmt.Errorf("failed to replay outbox message: %w", err)
+	}
+
+	return &msg, nil
+}
+
+// scanOutboxRows reads all outbox messages from rows
+func scanOutboxRows(rows *sql.Rows) ([]*models.OutboxMessage, error) {
+	var messages []*models.OutboxMessage
+	for rows.Next() {
+		var msg models.OutboxMessage
+		if err := scanOutbox(rows, &msg); err != nil {
+			return nil, err
+		}
+		messages = append(messages, &msg)
+	}
+
+	return messages, rows.Err()
+}
@@ -1,6 +1,10 @@
 package repository
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 // Repository package provides data access layer interfaces and implementations
 // for the Leave Management System.
@@ -10,3 +14,16 @@ import "errors"
 
 // ErrNotFound is returned when a requested resource is not found
 var ErrNotFound = errors.New("resource not found")
+
+// ErrConflict is returned when a conditional write finds that the row was
+// changed by someone else since it was read
+var ErrConflict = errors.New("resource was modified concurrently")
+
+// withQueryTimeout bounds ctx by the repository's per-query timeout. A zero
+// timeout leaves the caller's deadline, if any, in charge.
+func withQueryTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
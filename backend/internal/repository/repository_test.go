@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithQueryTimeout(t *testing.T) {
+	t.Run("applies the timeout", func(t *testing.T) {
+		ctx, cancel := withQueryTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatalf("expected a deadline")
+		}
+		if remaining := time.Until(deadline); remaining <= 0 || remaining > time.Second {
+			t.Errorf("unexpected deadline %s away", remaining)
+		}
+	})
+
+	t.Run("keeps an earlier caller deadline", func(t *testing.T) {
+		parent, cancelParent := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancelParent()
+
+		ctx, cancel := withQueryTimeout(parent, time.Hour)
+		defer cancel()
+
+		parentDeadline, _ := parent.Deadline()
+		if deadline, _ := ctx.Deadline(); !deadline.Equal(parentDeadline) {
+			t.Errorf("expected caller deadline %s, got %s", parentDeadline, deadline)
+		}
+	})
+
+	t.Run("zero timeout leaves no deadline", func(t *testing.T) {
+		ctx, cancel := withQueryTimeout(context.Background(), 0)
+		defer cancel()
+
+		if _, ok := ctx.Deadline(); ok {
+			t.Errorf("expected no deadline")
+		}
+	})
+
+	t.Run("follows caller cancellation", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := withQueryTimeout(parent, time.Hour)
+		defer cancel()
+
+		cancelParent()
+		if ctx.Err() != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", ctx.Err())
+		}
+	})
+}
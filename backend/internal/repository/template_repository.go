@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"leave-management-system/internal/logger"
+	"leave-management-system/internal/models"
+)
+
+// TemplateRepository defines the interface for notification template override data access
+type TemplateRepository interface {
+	Find(ctx context.Context, locale, name string) (*models.NotificationTemplate, error)
+	FindAll(ctx context.Context) ([]*models.NotificationTemplate, error)
+	// Save creates or replaces the override of a template
+	Save(ctx context.Context, tmpl *models.NotificationTemplate) error
+	Delete(ctx context.Context, locale, name string) error
+}
+
+// templateRepository implements TemplateRepository
+type templateRepository struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+	logger       *logger.Logger
+}
+
+// NewTemplateRepository creates a new notification template repository
+func NewTemplateRepository(db *sql.DB, queryTimeout time.Duration) TemplateRepository {
+	return &templateRepository{
+		db:           db,
+		queryTimeout: queryTimeout,
+		logger:       logger.New().With("component", "repository"),
+	}
+}
+
+// Find finds the override of a template
+func (r *templateRepository) Find(ctx context.Context, locale, name string) (*models.NotificationTemplate, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT locale, name, text_body, html_body, updated_by, updated_at
+		FROM notification_templates
+		WHERE locale = $1 AND name = $2
+	`
+
+	var tmpl models.NotificationTemplate
+	err := r.db.QueryRowContext(ctx, query, locale, name).Scan(
+		&tmpl.Locale,
+		&tmpl.Name,
+		&tmpl.Text,
+		&tmpl.HTML,
+		&tmpl.UpdatedBy,
+		&tmpl.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, "notification template")
+	}
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_template locale=%s name=%s error=%v", locale, name, err)
+		return nil, fmt.Errorf("failed to find notification template: %w", err)
+	}
+
+	return &tmpl, nil
+}
+
+// FindAll lists every override
+func (r *templateRepository) FindAll(ctx context.Context) ([]*models.NotificationTemplate, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT locale, name, text_body, html_body, updated_by, updated_at
+		FROM notification_templates
+		ORDER BY name, locale
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Errorf("db_query_failed operation=find_templates error=%v", err)
+		return nil, fmt.Errorf("failed to query notification templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*models.NotificationTemplate
+	for rows.Next() {
+		var tmpl models.NotificationTemplate
+		if err := rows.Scan(&tmpl.Locale, &tmpl.Name, &tmpl.Text, &tmpl.HTML, &tmpl.UpdatedBy, &tmpl.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification template: %w", err)
+		}
+		templates = append(templates, &tmpl)
+	}
+	return templates, rows.Err()
+}
+
+// Save upserts an override
+func (r *templateRepository) Save(ctx context.Context, tmpl *models.NotificationTemplate) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO notification_templates (locale, name, text_body, html_body, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (locale, name) DO UPDATE
+		SET text_body = EXCLUDED.text_body,
+		    html_body = EXCLUDED.html_body,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, tmpl.Locale, tmpl.Name, tmpl.Text, tmpl.HTML, tmpl.UpdatedBy, tmpl.UpdatedAt)
+	if err != nil {
+		r.logger.Errorf("db_update_failed operation=save_template locale=%s name=%s error=%v", tmpl.Locale, tmpl.Name, err)
+		return fmt.Errorf("failed to save notification template: %w", err)
+	}
+	return nil
+}
+
+// Delete removes an override, restoring the built-in template
+func (r *templateRepository) Delete(ctx context.Context, locale, name string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM notification_templates WHERE locale = $1 AND name = $2`, locale, name)
+	if err != nil {
+		r.logger.Errorf("db_delete_failed operation=delete_template locale=%s name=%s error=%v", locale, name, err)
+		return fmt.Errorf("failed to delete notification template: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, "notification template")
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"leave-management-system/internal/logger"
+)
+
+// dbtx is implemented by *sql.DB and *sql.Tx, so a repository can run either
+// directly against the pool or inside a transaction
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Store gives access to the repositories that can take part in a unit of work
+type Store interface {
+	Leaves() LeaveRepository
+	History() HistoryRepository
+	Comments() CommentRepository
+	Outbox() OutboxRepository
+	Preferences() NotificationPreferenceRepository
+}
+
+// UnitOfWork groups repository calls into a single database transaction. Its
+// own Store methods run outside any transaction.
+type UnitOfWork interface {
+	Store
+	// WithinTx calls fn with repositories bound to a new transaction. The
+	// transaction is committed when fn returns nil and rolled back otherwise.
+	WithinTx(ctx context.Context, fn func(tx Store) error) error
+}
+
+// unitOfWork implements UnitOfWork on top of a connection pool
+type unitOfWork struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+	store        *store
+	logger       *logger.Logger
+}
+
+// store implements Store for one dbtx
+type store struct {
+	leaves   *leaveRepository
+	history  *historyRepository
+	comments *commentRepository
+	outbox   *outboxRepository
+	prefs    *notificationPreferenceRepository
+}
+
+func newStore(db dbtx, queryTimeout time.Duration, log *logger.Logger) *store {
+	return &store{
+		leaves:   &leaveRepository{db: db, queryTimeout: queryTimeout, logger: log},
+		history:  &historyRepository{db: db, queryTimeout: queryTimeout, logger: log},
+		comments: &commentRepository{db: db, queryTimeout: queryTimeout, logger: log},
+		outbox:   &outboxRepository{db: db, queryTimeout: queryTimeout, logger: log},
+		prefs:    &notificationPreferenceRepository{db: db, queryTimeout: queryTimeout, logger: log},
+	}
+}
+
+func (s *store) Leaves() LeaveRepository                       { return s.leaves }
+func (s *store) History() HistoryRepository                    { return s.history }
+func (s *store) Comments() CommentRepository                   { return s.comments }
+func (s *store) Outbox() OutboxRepository                      { return s.outbox }
+func (s *store) Preferences() NotificationPreferenceRepository { return s.prefs }
+
+// NewUnitOfWork creates a unit of work over db
+func NewUnitOfWork(db *sql.DB, queryTimeout time.Duration) UnitOfWork {
+	log := logger.New().With("component", "repository")
+	return &unitOfWork{
+		db:           db,
+		queryTimeout: queryTimeout,
+		store:        newStore(db, queryTimeout, log),
+		logger:       log,
+	}
+}
+
+func (u *unitOfWork) Leaves() LeaveRepository                       { return u.store.leaves }
+func (u *unitOfWork) History() HistoryRepository                    { return u.store.history }
+func (u *unitOfWork) Comments() CommentRepository                   { return u.store.comments }
+func (u *unitOfWork) Outbox() OutboxRepository                      { return u.store.outbox }
+func (u *unitOfWork) Preferences() NotificationPreferenceRepository { return u.store.prefs }
+
+// WithinTx runs fn in a transaction
+func (u *unitOfWork) WithinTx(ctx context.Context, fn func(tx Store) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		u.logger.Errorf("db_tx_failed operation=begin error=%v", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(newStore(tx, u.queryTimeout, u.logger)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			u.logger.Errorf("db_tx_failed operation=rollback error=%v", rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		u.logger.Errorf("db_tx_failed operation=commit error=%v", err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
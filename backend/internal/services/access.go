@@ -0,0 +1,75 @@
+package services
+
+import (
+	"strings"
+
+	"leave-management-system/internal/models"
+)
+
+// Role names as issued in the token's roles claim
+const (
+	RoleManager = "manager"
+	RoleAdmin   = "admin"
+	RoleHR      = "hr"
+)
+
+// hasAnyRole reports whether roles contains any of the wanted roles
+func hasAnyRole(roles []string, wanted ...string) bool {
+	for _, w := range wanted {
+		for _, r := range roles {
+			if r == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isApprover reports whether the roles allow approving leave requests
+func isApprover(roles []string) bool {
+	return hasAnyRole(roles, RoleManager, RoleAdmin)
+}
+
+// canViewLeave reports whether a user may see a leave request and its related
+// resources: the owner, approvers and HR. Drafts are visible to the owner only.
+func canViewLeave(leave *models.LeaveRequest, userID string, roles []string) bool {
+	if leave.EmployeeID == userID {
+		return true
+	}
+	if leave.Status == models.LeaveStatusDraft {
+		return false
+	}
+	return isApprover(roles) || hasAnyRole(roles, RoleHR)
+}
+
+// isOwnRequest reports whether reviewer is the employee who requested leave.
+// Reviewers acting from an email link are known only by their address.
+func isOwnRequest(leave *models.LeaveRequest, reviewer *models.Employee) bool {
+	if reviewer.ID != "" && reviewer.ID == leave.EmployeeID {
+		return true
+	}
+	return reviewer.Email != "" && strings.EqualFold(reviewer.Email, leave.EmployeeEmail)
+}
+
+// isAssignedApprover reports whether reviewer may decide leave: anyone who can
+// review when no approver is assigned, otherwise only the assigned approver
+func isAssignedApprover(leave *models.LeaveRequest, reviewer *models.Employee) bool {
+	if leave.ApproverID == "" {
+		return true
+	}
+	if reviewer.ID != "" {
+		return reviewer.ID == leave.ApproverID
+	}
+	return reviewer.Email != "" && strings.EqualFold(reviewer.Email, leave.ApproverEmail)
+}
+
+// checkReviewer returns why reviewer may not decide leave, if they may not
+func checkReviewer(leave *models.LeaveRequest, reviewer *models.Employee) error {
+	if isOwnRequest(leave, reviewer) {
+		return ErrSelfReview
+	}
+	if !isAssignedApprover(leave, reviewer) {
+		return ErrNotAssignedApprover
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/utils"
+)
+
+// errReasonRequired is returned when an administrative change has no reason
+var errReasonRequired = &utils.ValidationError{Message: "reason is required"}
+
+// errSelfApprover is returned when an employee is assigned their own request
+var errSelfApprover = &utils.ValidationError{Message: ErrSelfReview.Error()}
+
+// AdminService provides administrative access to all leave requests. Every
+// change is recorded in the audit trail with the acting admin and a reason.
+type AdminService struct {
+	leaveService *LeaveService
+	history      repository.HistoryRepository
+}
+
+// NewAdminService creates a new admin service sharing the unit of work of leaveService
+func NewAdminService(leaveService *LeaveService) *AdminService {
+	return &AdminService{
+		leaveService: leaveService,
+		history:      leaveService.uow.History(),
+	}
+}
+
+// SearchLeaveRequests searches leave requests across all employees, newest first by default
+func (s *AdminService) SearchLeaveRequests(ctx context.Context, filter models.LeaveFilter) (*models.LeavePage, error) {
+	return s.leaveService.listLeaveRequests(ctx, filter, models.SortCreatedAt, true)
+}
+
+// ForceStatus sets the status of a leave request regardless of the usual
+// transition rules. Drafts belong to their owner and cannot be forced. An
+// admin forcing an approval or rejection decides the request as its
+// reviewer, with reason as the decision's comment, and the employee is
+// notified as for any decision.
+func (s *AdminService) ForceStatus(ctx context.Context, id uuid.UUID, status models.LeaveStatus, reason string, actor *models.Employee) (*models.LeaveRequest, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errReasonRequired
+	}
+
+	switch status {
+	case models.LeaveStatusPending, models.LeaveStatusApproved, models.LeaveStatusRejected, models.LeaveStatusCancelled:
+	default:
+		return nil, &utils.ValidationError{Message: fmt.Sprintf("invalid status %q", status)}
+	}
+
+	existing, err := s.leaveService.GetLeaveRequestByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.Status == models.LeaveStatusDraft {
+		return nil, fmt.Errorf("%w: cannot force status of a draft", ErrInvalidStatus)
+	}
+	if existing.Status == status {
+		return nil, fmt.Errorf("%w: request is already %s", ErrInvalidStatus, status)
+	}
+
+	updated := *existing
+	updated.Status = status
+	if isDecision(status) {
+		updated.ApproverID, updated.ApproverName, updated.ApproverEmail = actor.ID, actor.Name, actor.Email
+		updated.ManagerComment = sql.NullString{String: reason, Valid: true}
+	}
+	note := fmt.Sprintf("%s -> %s: %s", existing.Status, status, reason)
+	return s.save(ctx, existing, &updated, actor, models.HistoryActionStatusForced, note)
+}
+
+// ReassignApprover assigns a different approver to a leave request, who from
+// then on is the only one allowed to decide it
+func (s *AdminService) ReassignApprover(ctx context.Context, id uuid.UUID, req *models.ReassignApproverRequest, actor *models.Employee) (*models.LeaveRequest, error) {
+	approverID := strings.TrimSpace(req.ApproverID)
+	if approverID == "" {
+		return nil, &utils.ValidationError{Message: "approverId is required"}
+	}
+
+	existing, err := s.leaveService.GetLeaveRequestByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if approverID == existing.EmployeeID {
+		return nil, errSelfApprover
+	}
+
+	updated := *existing
+	updated.ApproverID = approverID
+	updated.ApproverName = strings.TrimSpace(req.ApproverName)
+	updated.ApproverEmail = strings.TrimSpace(req.ApproverEmail)
+
+	from := existing.ApproverID
+	if from == "" {
+		from = "unassigned"
+	}
+	note := fmt.Sprintf("%s -> %s", from, approverID)
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		note += ": " + reason
+	}
+	return s.save(ctx, existing, &updated, actor, models.HistoryActionApproverReassigned, note)
+}
+
+// CorrectDays overrides the number of leave days counted for a request
+func (s *AdminService) CorrectDays(ctx context.Context, id uuid.UUID, days int, reason string, actor *models.Employee) (*models.LeaveRequest, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errReasonRequired
+	}
+	if days <= 0 {
+		return nil, &utils.ValidationError{Message: "days must be positive"}
+	}
+
+	existing, err := s.leaveService.GetLeaveRequestByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.Days == days {
+		return existing, nil
+	}
+
+	updated := *existing
+	updated.Days = days
+	note := fmt.Sprintf("%d -> %d: %s", existing.Days, days, reason)
+	return s.save(ctx, existing, &updated, actor, models.HistoryActionDaysCorrected, note)
+}
+
+// GetHistory returns the audit trail of a single leave request, oldest first
+func (s *AdminService) GetHistory(ctx context.Context, id uuid.UUID) ([]*models.LeaveHistoryEntry, error) {
+	if _, err := s.leaveService.GetLeaveRequestByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.history.FindByLeaveRequestID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leave history: %w", err)
+	}
+	return entries, nil
+}
+
+// GetAuditTrail lists recorded changes across all leave requests, newest first
+func (s *AdminService) GetAuditTrail(ctx context.Context, filter models.AuditFilter) ([]*models.LeaveHistoryEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageLimit
+	}
+	if filter.Limit > MaxPageLimit {
+		filter.Limit = MaxPageLimit
+	}
+
+	entries, err := s.history.FindAll(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit trail: %w", err)
+	}
+	return entries, nil
+}
+
+// save writes an administrative change of existing to leave and records it
+// in the audit trail, both in one transaction. The employee is told of a
+// change that decides leave, and their calendar is updated when the change
+// withdraws or moves approved leave.
+func (s *AdminService) save(ctx context.Context, existing, leave *models.LeaveRequest, actor *models.Employee, action models.HistoryAction, note string) (*models.LeaveRequest, error) {
+	err := s.leaveService.uow.WithinTx(ctx, func(tx repository.Store) error {
+		if err := tx.Leaves().Update(ctx, leave); err != nil {
+			return writeError(err, "update")
+		}
+		if err := recordHistory(ctx, tx.History(), leave, actor, action, note); err != nil {
+			return err
+		}
+		if err := queueCalendarChange(ctx, tx, existing, leave); err != nil {
+			return err
+		}
+		return queueDecision(ctx, tx, existing, leave)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return leave, nil
+}
+
+// isDecision reports whether status is the outcome of a review
+func isDecision(status models.LeaveStatus) bool {
+	return status == models.LeaveStatusApproved || status == models.LeaveStatusRejected
+}
+
+// queueDecision queues the approval or rejection notification a review
+// would, with its calendar invite, when a change from before to after
+// decides leave
+func queueDecision(ctx context.Context, tx repository.Store, before, after *models.LeaveRequest) error {
+	if after.Status == before.Status || !isDecision(after.Status) {
+		return nil
+	}
+	return enqueue(ctx, tx.Outbox(), models.OutboxTopicLeaveDecided, "", after, 0)
+}
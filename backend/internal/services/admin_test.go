@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+)
+
+func setupAdminService() (*AdminService, *repository.MockLeaveRepository) {
+	repo := repository.NewMockLeaveRepository()
+	service := NewAdminService(NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy()))
+	return service, repo
+}
+
+func TestAdminService_SearchLeaveRequests(t *testing.T) {
+	service, repo := setupAdminService()
+
+	monday := models.NewDate(2025, 3, 3)
+	repo.Create(context.Background(), &models.LeaveRequest{ID: uuid.New(), EmployeeID: "emp-1", EmployeeName: "John Doe", EmployeeEmail: "john@example.com",
+		LeaveType: models.LeaveTypeAnnual, Status: models.LeaveStatusApproved, StartDate: monday, EndDate: monday.AddDays(4), CreatedAt: time.Now()})
+	repo.Create(context.Background(), &models.LeaveRequest{ID: uuid.New(), EmployeeID: "emp-2", EmployeeName: "Jane Smith", EmployeeEmail: "jane@example.com",
+		LeaveType: models.LeaveTypeSick, Status: models.LeaveStatusPending, StartDate: monday.AddDays(14), EndDate: monday.AddDays(15), CreatedAt: time.Now()})
+
+	tests := []struct {
+		name   string
+		filter models.LeaveFilter
+		want   int
+	}{
+		{"no filter", models.LeaveFilter{}, 2},
+		{"by status", models.LeaveFilter{Status: models.LeaveStatusPending}, 1},
+		{"by type", models.LeaveFilter{LeaveType: models.LeaveTypeAnnual}, 1},
+		{"by name", models.LeaveFilter{Query: "smith"}, 1},
+		{"overlapping dates", models.LeaveFilter{From: monday.AddDays(2), To: monday.AddDays(3)}, 1},
+		{"no overlap", models.LeaveFilter{From: monday.AddDays(7), To: monday.AddDays(8)}, 0},
+		{"limit", models.LeaveFilter{Limit: 1}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := service.SearchLeaveRequests(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(page.Items) != tt.want {
+				t.Errorf("expected %d results, got %d", tt.want, len(page.Items))
+			}
+		})
+	}
+
+	if _, err := service.SearchLeaveRequests(context.Background(), models.LeaveFilter{From: monday.AddDays(1), To: monday}); err == nil {
+		t.Errorf("expected error when from is after to")
+	}
+}
+
+func TestAdminService_Corrections(t *testing.T) {
+	service, repo := setupAdminService()
+	actor := &models.Employee{ID: "admin-1", Name: "Ada Admin"}
+
+	id := uuid.New()
+	repo.Create(context.Background(), &models.LeaveRequest{ID: id, EmployeeID: "emp-1", Status: models.LeaveStatusApproved, Days: 5, CreatedAt: time.Now()})
+
+	if _, err := service.ForceStatus(context.Background(), id, models.LeaveStatusCancelled, "", actor); err == nil {
+		t.Errorf("expected error without a reason")
+	}
+	if _, err := service.ForceStatus(context.Background(), id, models.LeaveStatusApproved, "No change", actor); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("expected ErrInvalidStatus for unchanged status, got %v", err)
+	}
+	leave, err := service.ForceStatus(context.Background(), id, models.LeaveStatusCancelled, "Employee left the company", actor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if leave.Status != models.LeaveStatusCancelled {
+		t.Errorf("expected cancelled, got %s", leave.Status)
+	}
+
+	if _, err := service.ReassignApprover(context.Background(), id, &models.ReassignApproverRequest{ApproverID: "emp-1"}, actor); err == nil {
+		t.Errorf("expected error assigning the employee as their own approver")
+	}
+	leave, err = service.ReassignApprover(context.Background(), id, &models.ReassignApproverRequest{ApproverID: "mgr-2", ApproverEmail: "mgr2@example.com"}, actor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if leave.ApproverID != "mgr-2" {
+		t.Errorf("expected approver mgr-2, got %s", leave.ApproverID)
+	}
+
+	if _, err := service.CorrectDays(context.Background(), id, 0, "Typo", actor); err == nil {
+		t.Errorf("expected error for non-positive days")
+	}
+	leave, err = service.CorrectDays(context.Background(), id, 4, "Public holiday on Friday", actor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if leave.Days != 4 {
+		t.Errorf("expected 4 days, got %d", leave.Days)
+	}
+
+	if _, err := service.CorrectDays(context.Background(), uuid.New(), 4, "Missing", actor); !errors.Is(err, ErrLeaveNotFound) {
+		t.Errorf("expected ErrLeaveNotFound, got %v", err)
+	}
+
+	history, err := service.GetHistory(context.Background(), id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 3 audit entries, got %d", len(history))
+	}
+	if history[2].Action != models.HistoryActionDaysCorrected || history[2].Note != "5 -> 4: Public holiday on Friday" {
+		t.Errorf("unexpected audit entry: %s %q", history[2].Action, history[2].Note)
+	}
+
+	trail, err := service.GetAuditTrail(context.Background(), models.AuditFilter{Action: models.HistoryActionStatusForced})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(trail) != 1 || trail[0].ActorID != "admin-1" {
+		t.Errorf("expected one status change by admin-1, got %d entries", len(trail))
+	}
+}
+
+func TestAdminService_ForcedDecisionNotifies(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMockLeaveRepository()
+	uow := repository.NewMockUnitOfWork(repo, nil)
+	service := NewAdminService(NewLeaveService(uow, DefaultLeavePolicy()))
+	actor := &models.Employee{ID: "admin-1", Name: "Ada Admin", Email: "ada@example.com"}
+
+	id := uuid.New()
+	repo.Create(ctx, &models.LeaveRequest{ID: id, EmployeeID: "emp-1", Status: models.LeaveStatusPending, ApproverID: "mgr-1", CreatedAt: time.Now()})
+
+	leave, err := service.ForceStatus(ctx, id, models.LeaveStatusApproved, "Approver is on sick leave", actor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if leave.ApproverID != "admin-1" || leave.ApproverEmail != "ada@example.com" || leave.GetManagerComment() != "Approver is on sick leave" {
+		t.Errorf("expected the admin recorded as reviewer, got %s %s %q", leave.ApproverID, leave.ApproverEmail, leave.GetManagerComment())
+	}
+
+	messages, _ := uow.OutboxRepo.FindAll(ctx, models.OutboxFilter{})
+	if len(messages) != 1 || messages[0].Topic != models.OutboxTopicLeaveDecided {
+		t.Fatalf("expected the approval queued for the employee, got %d messages", len(messages))
+	}
+
+	// Reopening the request decides nothing
+	if _, err := service.ForceStatus(ctx, id, models.LeaveStatusPending, "Approved by mistake", actor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	messages, _ = uow.OutboxRepo.FindAll(ctx, models.OutboxFilter{Topic: models.OutboxTopicLeaveDecided})
+	if len(messages) != 1 {
+		t.Errorf("expected no decision queued for a reopened request, got %d", len(messages))
+	}
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/storage"
+)
+
+var (
+	ErrAttachmentNotFound  = errors.New("attachment not found")
+	ErrAttachmentTooLarge  = errors.New("attachment exceeds maximum size")
+	ErrUnsupportedFileType = errors.New("unsupported attachment type")
+)
+
+// sniffLength is the number of bytes http.DetectContentType inspects
+const sniffLength = 512
+
+// maxFileNameBytes bounds the stored file name
+const maxFileNameBytes = 255
+
+// AttachmentService handles business logic for leave request attachments
+type AttachmentService struct {
+	repo         repository.AttachmentRepository
+	leaveService *LeaveService
+	store        storage.Storage
+	maxSize      int64
+}
+
+// NewAttachmentService creates a new attachment service
+func NewAttachmentService(repo repository.AttachmentRepository, leaveService *LeaveService, store storage.Storage, maxSize int64) *AttachmentService {
+	return &AttachmentService{
+		repo:         repo,
+		leaveService: leaveService,
+		store:        store,
+		maxSize:      maxSize,
+	}
+}
+
+// UploadAttachment validates and stores a file for a leave request.
+// Only the owner of the request and HR may upload.
+func (s *AttachmentService) UploadAttachment(ctx context.Context, leaveID uuid.UUID, userID string, roles []string, fileName string, size int64, body io.Reader) (*models.Attachment, error) {
+	leave, err := s.leaveService.GetLeaveRequestByID(ctx, leaveID)
+	if err != nil {
+		return nil, err
+	}
+
+	if leave.EmployeeID != userID && !hasAnyRole(roles, RoleHR) {
+		return nil, ErrUnauthorizedAction
+	}
+
+	if leave.Status == models.LeaveStatusCancelled || leave.Status == models.LeaveStatusRejected {
+		return nil, fmt.Errorf("%w: cannot attach files to %s request", ErrInvalidStatus, leave.Status)
+	}
+
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrUnsupportedFileType)
+	}
+	if size > s.maxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	// Detect the content type from the file contents rather than trusting the client
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	if idx := strings.Index(contentType, ";"); idx >= 0 {
+		contentType = contentType[:idx]
+	}
+	if !models.AllowedAttachmentContentTypes[contentType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFileType, contentType)
+	}
+
+	attachment := &models.Attachment{
+		ID:             uuid.New(),
+		LeaveRequestID: leaveID,
+		FileName:       sanitizeFileName(fileName),
+		ContentType:    contentType,
+		SizeBytes:      size,
+		UploadedBy:     userID,
+		CreatedAt:      time.Now(),
+	}
+	attachment.StorageKey = fmt.Sprintf("leave/%s/%s", leaveID, attachment.ID)
+
+	content := io.MultiReader(bytes.NewReader(head), io.LimitReader(body, size-int64(n)))
+	if err := s.store.Put(ctx, attachment.StorageKey, content, size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	if err := s.repo.Create(ctx, attachment); err != nil {
+		// Don't leave orphaned objects behind, even if the request was cancelled
+		_ = s.store.Delete(context.WithoutCancel(ctx), attachment.StorageKey)
+		return nil, fmt.Errorf("failed to save attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// GetAttachments lists the attachments of a leave request
+func (s *AttachmentService) GetAttachments(ctx context.Context, leaveID uuid.UUID, userID string, roles []string) ([]*models.Attachment, error) {
+	leave, err := s.leaveService.GetLeaveRequestByID(ctx, leaveID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canViewLeave(leave, userID, roles) {
+		return nil, ErrUnauthorizedAction
+	}
+
+	attachments, err := s.repo.FindByLeaveRequestID(ctx, leaveID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// OpenAttachment returns an attachment and a reader for its contents.
+// The caller must close the reader.
+func (s *AttachmentService) OpenAttachment(ctx context.Context, leaveID, attachmentID uuid.UUID, userID string, roles []string) (*models.Attachment, io.ReadCloser, error) {
+	attachment, err := s.getAttachment(ctx, leaveID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	leave, err := s.leaveService.GetLeaveRequestByID(ctx, leaveID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !canViewLeave(leave, userID, roles) {
+		return nil, nil, ErrUnauthorizedAction
+	}
+
+	body, err := s.store.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	return attachment, body, nil
+}
+
+// DeleteAttachment removes an attachment. Only the owner of the request and HR may delete.
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, leaveID, attachmentID uuid.UUID, userID string, roles []string) error {
+	attachment, err := s.getAttachment(ctx, leaveID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	leave, err := s.leaveService.GetLeaveRequestByID(ctx, leaveID)
+	if err != nil {
+		return err
+	}
+
+	if leave.EmployeeID != userID && !hasAnyRole(roles, RoleHR) {
+		return ErrUnauthorizedAction
+	}
+
+	// Remove the object first so a failure leaves the record in place for a retry
+	if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+		return fmt.Errorf("failed to delete attachment contents: %w", err)
+	}
+
+	if err := s.repo.Delete(ctx, attachmentID); err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	return nil
+}
+
+// getAttachment loads an attachment and checks that it belongs to the leave request
+func (s *AttachmentService) getAttachment(ctx context.Context, leaveID, attachmentID uuid.UUID) (*models.Attachment, error) {
+	attachment, err := s.repo.FindByID(ctx, attachmentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	if attachment.LeaveRequestID != leaveID {
+		return nil, ErrAttachmentNotFound
+	}
+
+	return attachment, nil
+}
+
+// sanitizeFileName strips any client-supplied path and bounds the length
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		name = "attachment"
+	}
+	if len(name) > maxFileNameBytes {
+		// Keep the end, which holds the extension, cutting on a rune boundary
+		start := len(name) - maxFileNameBytes
+		for start < len(name) && !utf8.RuneStart(name[start]) {
+			start++
+		}
+		name = name[start:]
+	}
+	return name
+}
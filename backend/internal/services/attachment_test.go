@@ -0,0 +1,224 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/storage"
+)
+
+var testPDF = []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n")
+
+func setupAttachmentService(t *testing.T) (*AttachmentService, *repository.MockLeaveRepository, uuid.UUID) {
+	t.Helper()
+
+	leaveRepo := repository.NewMockLeaveRepository()
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	leaveID := uuid.New()
+	leaveRepo.Create(context.Background(), &models.LeaveRequest{
+		ID:         leaveID,
+		EmployeeID: "emp-1",
+		LeaveType:  models.LeaveTypeSick,
+		Status:     models.LeaveStatusPending,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	})
+
+	service := NewAttachmentService(repository.NewMockAttachmentRepository(), NewLeaveService(repository.NewMockUnitOfWork(leaveRepo, nil), DefaultLeavePolicy()), store, 1024)
+	return service, leaveRepo, leaveID
+}
+
+func TestAttachmentService_UploadAttachment(t *testing.T) {
+	service, _, leaveID := setupAttachmentService(t)
+
+	tests := []struct {
+		name    string
+		leaveID uuid.UUID
+		userID  string
+		roles   []string
+		content []byte
+		size    int64
+		errType error
+	}{
+		{
+			name:    "owner uploads pdf",
+			leaveID: leaveID,
+			userID:  "emp-1",
+			content: testPDF,
+		},
+		{
+			name:    "hr uploads on behalf of employee",
+			leaveID: leaveID,
+			userID:  "hr-1",
+			roles:   []string{"hr"},
+			content: testPDF,
+		},
+		{
+			name:    "manager cannot upload",
+			leaveID: leaveID,
+			userID:  "mgr-1",
+			roles:   []string{"manager"},
+			content: testPDF,
+			errType: ErrUnauthorizedAction,
+		},
+		{
+			name:    "unsupported content type",
+			leaveID: leaveID,
+			userID:  "emp-1",
+			content: []byte("#!/bin/sh\necho hello\n"),
+			errType: ErrUnsupportedFileType,
+		},
+		{
+			name:    "too large",
+			leaveID: leaveID,
+			userID:  "emp-1",
+			content: testPDF,
+			size:    2048,
+			errType: ErrAttachmentTooLarge,
+		},
+		{
+			name:    "leave request not found",
+			leaveID: uuid.New(),
+			userID:  "emp-1",
+			content: testPDF,
+			errType: ErrLeaveNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size := tt.size
+			if size == 0 {
+				size = int64(len(tt.content))
+			}
+
+			attachment, err := service.UploadAttachment(context.Background(), tt.leaveID, tt.userID, tt.roles, "../certificate.pdf", size, bytes.NewReader(tt.content))
+
+			if tt.errType != nil {
+				if !errors.Is(err, tt.errType) {
+					t.Errorf("expected error %v, got %v", tt.errType, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if attachment.ContentType != "application/pdf" {
+				t.Errorf("expected content type application/pdf, got %q", attachment.ContentType)
+			}
+			if attachment.FileName != "certificate.pdf" {
+				t.Errorf("expected sanitized file name, got %q", attachment.FileName)
+			}
+			if attachment.UploadedBy != tt.userID {
+				t.Errorf("expected uploadedBy %q, got %q", tt.userID, attachment.UploadedBy)
+			}
+		})
+	}
+}
+
+func TestAttachmentService_DownloadAndDelete(t *testing.T) {
+	service, _, leaveID := setupAttachmentService(t)
+
+	attachment, err := service.UploadAttachment(context.Background(), leaveID, "emp-1", nil, "certificate.pdf", int64(len(testPDF)), bytes.NewReader(testPDF))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("approver can download", func(t *testing.T) {
+		_, body, err := service.OpenAttachment(context.Background(), leaveID, attachment.ID, "mgr-1", []string{"manager"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer body.Close()
+
+		got, _ := io.ReadAll(body)
+		if !bytes.Equal(got, testPDF) {
+			t.Errorf("downloaded content does not match upload")
+		}
+	})
+
+	t.Run("other employee cannot download", func(t *testing.T) {
+		_, _, err := service.OpenAttachment(context.Background(), leaveID, attachment.ID, "emp-2", []string{"employee"})
+		if !errors.Is(err, ErrUnauthorizedAction) {
+			t.Errorf("expected ErrUnauthorizedAction, got %v", err)
+		}
+	})
+
+	t.Run("attachment must belong to leave request", func(t *testing.T) {
+		_, _, err := service.OpenAttachment(context.Background(), uuid.New(), attachment.ID, "emp-1", nil)
+		if !errors.Is(err, ErrAttachmentNotFound) {
+			t.Errorf("expected ErrAttachmentNotFound, got %v", err)
+		}
+	})
+
+	t.Run("approver cannot delete", func(t *testing.T) {
+		err := service.DeleteAttachment(context.Background(), leaveID, attachment.ID, "mgr-1", []string{"manager"})
+		if !errors.Is(err, ErrUnauthorizedAction) {
+			t.Errorf("expected ErrUnauthorizedAction, got %v", err)
+		}
+	})
+
+	t.Run("owner deletes", func(t *testing.T) {
+		if err := service.DeleteAttachment(context.Background(), leaveID, attachment.ID, "emp-1", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		attachments, err := service.GetAttachments(context.Background(), leaveID, "emp-1", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(attachments) != 0 {
+			t.Errorf("expected 0 attachments, got %d", len(attachments))
+		}
+	})
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"strips path", "../../etc/certificate.pdf", "certificate.pdf"},
+		{"strips windows path", `C:\Users\emp\certificate.pdf`, "certificate.pdf"},
+		{"empty falls back", "  ", "attachment"},
+		{"keeps non-ascii", "ใบรับรองแพทย์.pdf", "ใบรับรองแพทย์.pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeFileName(tt.in); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("truncates on a rune boundary", func(t *testing.T) {
+		// Each Thai character is three bytes, so 255 bytes from the end
+		// falls inside one
+		got := sanitizeFileName(strings.Repeat("ก", 100) + ".pdf")
+		if !utf8.ValidString(got) {
+			t.Fatalf("expected valid UTF-8, got %q", got)
+		}
+		if len(got) > maxFileNameBytes {
+			t.Errorf("expected at most %d bytes, got %d", maxFileNameBytes, len(got))
+		}
+		if !strings.HasSuffix(got, ".pdf") {
+			t.Errorf("expected extension to be kept, got %q", got)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/utils"
+)
+
+// MaxBulkItems is the maximum number of requests in one bulk review
+const MaxBulkItems = 100
+
+// minRejectionCommentLength matches the rule for single rejections, in
+// characters
+const minRejectionCommentLength = 10
+
+// bulkNotificationDelay holds back the notifications of a bulk review so the
+// decisions for each employee can be delivered together in one email once
+// every item has been processed
+const bulkNotificationDelay = time.Minute
+
+// BulkReviewLeaveRequests approves or rejects many leave requests. Each item is
+// processed on its own, so one failure does not affect the others; the result
+// for every item is returned in request order.
+func (s *LeaveService) BulkReviewLeaveRequests(ctx context.Context, action models.BulkLeaveAction, items []models.BulkLeaveItem, reviewer *models.Employee) ([]models.BulkLeaveResult, error) {
+	if action != models.BulkActionApprove && action != models.BulkActionReject {
+		return nil, &utils.ValidationError{Message: fmt.Sprintf("invalid action %q", action)}
+	}
+	if len(items) == 0 {
+		return nil, &utils.ValidationError{Message: "at least one item is required"}
+	}
+	if len(items) > MaxBulkItems {
+		return nil, &utils.ValidationError{Message: fmt.Sprintf("at most %d items can be processed at once", MaxBulkItems)}
+	}
+	// Each decision must name the version the reviewer saw, so a request
+	// changed since it was listed is not decided unseen
+	for i, item := range items {
+		if item.Version < 1 {
+			return nil, &utils.ValidationError{Message: fmt.Sprintf("items[%d].version is required", i)}
+		}
+	}
+
+	batchID := uuid.New()
+	results := make([]models.BulkLeaveResult, len(items))
+	for i, item := range items {
+		results[i] = s.reviewOne(ctx, batchID, action, item, reviewer)
+	}
+	return results, nil
+}
+
+// reviewOne applies a bulk action to a single leave request. Its notification
+// is grouped with the batch's other decisions for the same employee.
+func (s *LeaveService) reviewOne(ctx context.Context, batchID uuid.UUID, action models.BulkLeaveAction, item models.BulkLeaveItem, reviewer *models.Employee) models.BulkLeaveResult {
+	result := models.BulkLeaveResult{ID: item.ID}
+	comment := strings.TrimSpace(item.Comment)
+
+	existing, err := s.GetLeaveRequestByID(ctx, item.ID)
+	if err == nil && action == models.BulkActionReject && utf8.RuneCountInString(comment) < minRejectionCommentLength {
+		err = &utils.ValidationError{Message: fmt.Sprintf("comment must be at least %d characters", minRejectionCommentLength)}
+	}
+
+	var leave *models.LeaveRequest
+	if err == nil {
+		notice := decisionNotice{groupKey: batchID.String() + ":" + existing.EmployeeID, delay: bulkNotificationDelay}
+		if action == models.BulkActionApprove {
+			leave, err = s.approve(ctx, item.ID, item.Version, reviewer, comment, notice)
+		} else {
+			leave, err = s.reject(ctx, item.ID, item.Version, reviewer, comment, notice)
+		}
+	}
+
+	var validationErr *utils.ValidationError
+	switch {
+	case err == nil && action == models.BulkActionApprove:
+		result.Outcome = models.BulkOutcomeApproved
+		result.Leave = leave
+	case err == nil:
+		result.Outcome = models.BulkOutcomeRejected
+		result.Leave = leave
+	case errors.Is(err, ErrLeaveNotFound):
+		result.Outcome = models.BulkOutcomeNotFound
+		result.Message = "leave request not found"
+	case errors.Is(err, ErrInvalidStatus), errors.Is(err, ErrConflict), errors.Is(err, ErrPreconditionFailed):
+		result.Outcome = models.BulkOutcomeAlreadyProcessed
+		result.Message = err.Error()
+	case errors.Is(err, ErrSelfReview), errors.Is(err, ErrNotAssignedApprover), errors.As(err, &validationErr):
+		result.Outcome = models.BulkOutcomePolicyViolation
+		result.Message = err.Error()
+	default:
+		result.Outcome = models.BulkOutcomeError
+		result.Message = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+)
+
+func TestLeaveService_BulkReviewLeaveRequests(t *testing.T) {
+	repo := repository.NewMockLeaveRepository()
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
+
+	newLeave := func(employeeID string, status models.LeaveStatus) uuid.UUID {
+		id := uuid.New()
+		repo.Create(context.Background(), &models.LeaveRequest{
+			ID:         id,
+			EmployeeID: employeeID,
+			Status:     status,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		})
+		return id
+	}
+
+	pending1 := newLeave("emp-1", models.LeaveStatusPending)
+	pending2 := newLeave("emp-2", models.LeaveStatusPending)
+	approved := newLeave("emp-1", models.LeaveStatusApproved)
+	own := newLeave("mgr-1", models.LeaveStatusPending)
+	draft := newLeave("emp-3", models.LeaveStatusDraft)
+	missing := uuid.New()
+
+	t.Run("approve", func(t *testing.T) {
+		results, err := service.BulkReviewLeaveRequests(context.Background(), models.BulkActionApprove, []models.BulkLeaveItem{
+			{ID: pending1, Version: 1, Comment: "Enjoy"},
+			{ID: approved, Version: 1},
+			{ID: missing, Version: 1},
+			{ID: own, Version: 1},
+			{ID: draft, Version: 1},
+		}, testReviewer)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []models.BulkLeaveOutcome{
+			models.BulkOutcomeApproved,
+			models.BulkOutcomeAlreadyProcessed,
+			models.BulkOutcomeNotFound,
+			models.BulkOutcomePolicyViolation,
+			models.BulkOutcomeNotFound,
+		}
+		for i, result := range results {
+			if result.Outcome != want[i] {
+				t.Errorf("item %d: expected %s, got %s (%s)", i, want[i], result.Outcome, result.Message)
+			}
+		}
+		if results[0].Leave == nil || results[0].Leave.GetManagerComment() != "Enjoy" {
+			t.Errorf("expected approved leave with per-item comment, got %+v", results[0].Leave)
+		}
+	})
+
+	t.Run("reject requires a comment", func(t *testing.T) {
+		results, err := service.BulkReviewLeaveRequests(context.Background(), models.BulkActionReject, []models.BulkLeaveItem{
+			{ID: pending2, Version: 1, Comment: "No"},
+			// Six characters, though eighteen bytes
+			{ID: pending2, Version: 1, Comment: "ไม่ได้"},
+		}, testReviewer)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, result := range results {
+			if result.Outcome != models.BulkOutcomePolicyViolation {
+				t.Errorf("item %d: expected policy_violation, got %s", i, result.Outcome)
+			}
+		}
+
+		results, _ = service.BulkReviewLeaveRequests(context.Background(), models.BulkActionReject, []models.BulkLeaveItem{
+			{ID: pending2, Version: 1, Comment: "Team is short-staffed that week"},
+		}, testReviewer)
+		if results[0].Outcome != models.BulkOutcomeRejected {
+			t.Errorf("expected rejected, got %s (%s)", results[0].Outcome, results[0].Message)
+		}
+	})
+
+	t.Run("changed since listed", func(t *testing.T) {
+		stale := newLeave("emp-4", models.LeaveStatusPending)
+		// The employee moves the dates after the manager listed version 1
+		leave, _ := repo.FindByID(context.Background(), stale)
+		edited := *leave
+		edited.StartDate = models.NewDate(2024, 6, 3)
+		if err := repo.Update(context.Background(), &edited); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		results, err := service.BulkReviewLeaveRequests(context.Background(), models.BulkActionApprove, []models.BulkLeaveItem{
+			{ID: stale, Version: 1},
+		}, testReviewer)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if results[0].Outcome != models.BulkOutcomeAlreadyProcessed {
+			t.Errorf("expected already_processed, got %s (%s)", results[0].Outcome, results[0].Message)
+		}
+		if stored, _ := repo.FindByID(context.Background(), stale); stored.Status != models.LeaveStatusPending {
+			t.Errorf("expected the edited request still pending, got %s", stored.Status)
+		}
+	})
+
+	t.Run("invalid batch", func(t *testing.T) {
+		if _, err := service.BulkReviewLeaveRequests(context.Background(), models.BulkActionApprove, []models.BulkLeaveItem{{ID: pending1}}, testReviewer); err == nil {
+			t.Errorf("expected error for missing version")
+		}
+		if _, err := service.BulkReviewLeaveRequests(context.Background(), "archive", []models.BulkLeaveItem{{ID: pending1, Version: 1}}, testReviewer); err == nil {
+			t.Errorf("expected error for unknown action")
+		}
+		if _, err := service.BulkReviewLeaveRequests(context.Background(), models.BulkActionApprove, nil, testReviewer); err == nil {
+			t.Errorf("expected error for empty batch")
+		}
+		if _, err := service.BulkReviewLeaveRequests(context.Background(), models.BulkActionApprove, make([]models.BulkLeaveItem, MaxBulkItems+1), testReviewer); err == nil {
+			t.Errorf("expected error for oversized batch")
+		}
+	})
+}
+
+func TestLeaveService_BulkReviewGroupsNotifications(t *testing.T) {
+	repo := repository.NewMockLeaveRepository()
+	uow := repository.NewMockUnitOfWork(repo, nil)
+	service := NewLeaveService(uow, DefaultLeavePolicy())
+
+	var items []models.BulkLeaveItem
+	for _, employeeID := range []string{"emp-1", "emp-2", "emp-1"} {
+		id := uuid.New()
+		repo.Create(context.Background(), &models.LeaveRequest{ID: id, EmployeeID: employeeID, Status: models.LeaveStatusPending, CreatedAt: time.Now()})
+		items = append(items, models.BulkLeaveItem{ID: id, Version: 1})
+	}
+
+	if _, err := service.BulkReviewLeaveRequests(context.Background(), models.BulkActionApprove, items, testReviewer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	messages, _ := uow.OutboxRepo.FindAll(context.Background(), models.OutboxFilter{})
+	if len(messages) != 3 {
+		t.Fatalf("expected a notification per decision, got %d", len(messages))
+	}
+	groups := make(map[string]int)
+	for _, msg := range messages {
+		groups[msg.GroupKey]++
+		if !msg.NextAttemptAt.After(time.Now()) {
+			t.Errorf("expected bulk notifications to be held back, got due at %s", msg.NextAttemptAt)
+		}
+	}
+	if len(groups) != 2 || groups[""] != 0 {
+		t.Errorf("expected the decisions grouped by employee, got %v", groups)
+	}
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/config"
+	"leave-management-system/internal/ical"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+)
+
+// ErrCalendarFeedNotFound is returned for an unknown feed kind, a feed the
+// employee has not created, or a token that gives access to no feed
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+// ErrTeamFeedDetails is returned when someone other than an approver or HR
+// asks for a team feed showing the type of their colleagues' leave
+var ErrTeamFeedDetails = errors.New("only approvers and HR may see the type of team leave")
+
+// CalendarService manages the calendar feeds employees subscribe to and
+// renders them. A feed is read with nothing but the secret token in its URL,
+// so calendar apps can poll it. Only a hash of the token is stored.
+type CalendarService struct {
+	feeds       repository.CalendarFeedRepository
+	leaves      repository.LeaveRepository
+	baseURL     string
+	holidays    []models.Holiday
+	historyDays int
+}
+
+// NewCalendarService creates a new calendar service. Feed URLs start with
+// baseURL, where clients reach the server.
+func NewCalendarService(feeds repository.CalendarFeedRepository, leaveService *LeaveService, baseURL string, cfg config.CalendarConfig) (*CalendarService, error) {
+	holidays, err := ParseHolidays(cfg.Holidays)
+	if err != nil {
+		return nil, err
+	}
+	return &CalendarService{
+		feeds:       feeds,
+		leaves:      leaveService.uow.Leaves(),
+		baseURL:     baseURL,
+		holidays:    holidays,
+		historyDays: cfg.HistoryDays,
+	}, nil
+}
+
+// ParseHolidays parses company holidays written as
+// "2024-01-01=New Year's Day;2024-04-13=Songkran", returning them by date
+func ParseHolidays(s string) ([]models.Holiday, error) {
+	var holidays []models.Holiday
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		day, name, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid holiday %q: expected date=name", entry)
+		}
+		date, err := models.ParseDate(strings.TrimSpace(day))
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday %q: %w", entry, err)
+		}
+		holidays = append(holidays, models.Holiday{Date: date, Name: name})
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date.Before(holidays[j].Date) })
+	return holidays, nil
+}
+
+// ListFeeds lists the employee's calendar feeds by kind
+func (s *CalendarService) ListFeeds(ctx context.Context, employeeID string) ([]*models.CalendarFeedInfo, error) {
+	feeds, err := s.feeds.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query calendar feeds: %w", err)
+	}
+	infos := make([]*models.CalendarFeedInfo, 0, len(feeds))
+	for _, feed := range feeds {
+		infos = append(infos, s.feedInfo(feed, ""))
+	}
+	return infos, nil
+}
+
+// SaveFeed creates the employee's feed of kind, or changes its privacy. The
+// URL of an existing feed is kept, but only returned for a new feed, and a
+// team feed follows the employee's department as of this call. Team feeds
+// only show the type of leave, as canViewLeave allows, to approvers and HR.
+func (s *CalendarService) SaveFeed(ctx context.Context, kind models.CalendarFeedKind, req *models.UpdateCalendarFeedRequest, employee *models.Employee, roles []string) (*models.CalendarFeedInfo, error) {
+	if !kind.IsValid() {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	now := time.Now()
+	var token string
+	feed, err := s.feeds.Find(ctx, employee.ID, kind)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		if token, err = newFeedToken(); err != nil {
+			return nil, err
+		}
+		feed = &models.CalendarFeed{
+			EmployeeID: employee.ID,
+			Kind:       kind,
+			TokenHash:  hashFeedToken(token),
+			Privacy:    models.CalendarPrivacyDetails,
+			CreatedAt:  now,
+		}
+		if kind == models.CalendarFeedTeam {
+			feed.Privacy = models.CalendarPrivacyBusy
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+
+	if req.Privacy != "" {
+		feed.Privacy = req.Privacy
+	}
+	if kind == models.CalendarFeedTeam && feed.Privacy == models.CalendarPrivacyDetails && !isApprover(roles) && !hasAnyRole(roles, RoleHR) {
+		if req.Privacy == models.CalendarPrivacyDetails {
+			return nil, ErrTeamFeedDetails
+		}
+		// The owner is no longer allowed the details they chose
+		feed.Privacy = models.CalendarPrivacyBusy
+	}
+	feed.EmployeeName = employee.Name
+	feed.Department = employee.Department
+	feed.UpdatedAt = now
+
+	if err := s.feeds.Save(ctx, feed); err != nil {
+		return nil, fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+	return s.feedInfo(feed, token), nil
+}
+
+// RegenerateFeed gives the employee's feed of kind a new URL. The old URL
+// stops working, for when it was shared by mistake.
+func (s *CalendarService) RegenerateFeed(ctx context.Context, kind models.CalendarFeedKind, employeeID string) (*models.CalendarFeedInfo, error) {
+	feed, err := s.findFeed(ctx, kind, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, err
+	}
+	feed.TokenHash = hashFeedToken(token)
+	feed.UpdatedAt = time.Now()
+	if err := s.feeds.Save(ctx, feed); err != nil {
+		return nil, fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+	return s.feedInfo(feed, token), nil
+}
+
+// DeleteFeed removes the employee's feed of kind
+func (s *CalendarService) DeleteFeed(ctx context.Context, kind models.CalendarFeedKind, employeeID string) error {
+	if !kind.IsValid() {
+		return ErrCalendarFeedNotFound
+	}
+	err := s.feeds.Delete(ctx, employeeID, kind)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar feed: %w", err)
+	}
+	return nil
+}
+
+// RenderFeed returns the feed token gives access to, encoded as an iCalendar
+// object. Leave feeds list approved leave that ended at most historyDays ago.
+func (s *CalendarService) RenderFeed(ctx context.Context, token string) (*models.CalendarFeed, []byte, error) {
+	feed, err := s.feeds.FindByTokenHash(ctx, hashFeedToken(token))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+
+	cal := &ical.Calendar{Method: ical.MethodPublish}
+	switch feed.Kind {
+	case models.CalendarFeedHolidays:
+		cal.Name = "Company holidays"
+		for _, holiday := range s.holidays {
+			cal.Events = append(cal.Events, ical.Event{
+				UID:     holiday.Date.Format("20060102") + "-holiday@leave-management-system",
+				Start:   holiday.Date,
+				End:     holiday.Date,
+				Summary: holiday.Name,
+				Free:    true,
+			})
+		}
+	case models.CalendarFeedPersonal, models.CalendarFeedTeam:
+		if cal.Events, err = s.leaveEvents(ctx, feed); err != nil {
+			return nil, nil, err
+		}
+		cal.Name = "My leave"
+		if feed.Kind == models.CalendarFeedTeam {
+			cal.Name = "Team leave"
+			if feed.Department != "" {
+				cal.Name += " (" + feed.Department + ")"
+			}
+		}
+	}
+	return feed, cal.Encode(), nil
+}
+
+// leaveEvents returns the approved leave a feed lists, by start date. Reasons
+// are never shown; with busy privacy neither is the type of leave.
+func (s *CalendarService) leaveEvents(ctx context.Context, feed *models.CalendarFeed) ([]ical.Event, error) {
+	filter := models.LeaveFilter{
+		Status: models.LeaveStatusApproved,
+		From:   models.Today(time.UTC).AddDays(-s.historyDays),
+		Sort:   models.SortStartDate,
+	}
+	if feed.Kind == models.CalendarFeedTeam {
+		// Employees without a department have no team
+		if feed.Department == "" {
+			return nil, nil
+		}
+		filter.Department = feed.Department
+	} else {
+		filter.EmployeeID = feed.EmployeeID
+	}
+
+	page, err := s.leaves.Search(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leave for calendar feed: %w", err)
+	}
+
+	events := make([]ical.Event, 0, len(page.Items))
+	for _, leave := range page.Items {
+		event := leaveEvent(leave)
+		event.Description = ""
+		event.Stamp = leave.UpdatedAt
+		if feed.Privacy == models.CalendarPrivacyBusy {
+			event.Summary = "Out of office"
+		}
+		if feed.Kind == models.CalendarFeedTeam {
+			event.Summary = leave.EmployeeName + ": " + event.Summary
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// findFeed returns the employee's feed of kind
+func (s *CalendarService) findFeed(ctx context.Context, kind models.CalendarFeedKind, employeeID string) (*models.CalendarFeed, error) {
+	if !kind.IsValid() {
+		return nil, ErrCalendarFeedNotFound
+	}
+	feed, err := s.feeds.Find(ctx, employeeID, kind)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+	return feed, nil
+}
+
+// feedInfo describes feed to its owner, with its URL when its token was
+// just generated
+func (s *CalendarService) feedInfo(feed *models.CalendarFeed, token string) *models.CalendarFeedInfo {
+	info := &models.CalendarFeedInfo{
+		Kind:      feed.Kind,
+		Privacy:   feed.Privacy,
+		CreatedAt: feed.CreatedAt,
+		UpdatedAt: feed.UpdatedAt,
+	}
+	if token != "" {
+		info.URL = s.baseURL + "/ical/" + token + ".ics"
+	}
+	return info
+}
+
+// newFeedToken returns a random token for a feed URL
+func newFeedToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate calendar feed token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashFeedToken returns the hash a feed token is stored and looked up by.
+// Tokens are random, so an unsalted hash is enough.
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// leaveUID is the calendar UID of a leave request. It never changes, so
+// every update and cancellation replaces the event created on approval.
+func leaveUID(id uuid.UUID) string {
+	return id.String() + "@leave-management-system"
+}
+
+// leaveEvent returns leave as an all-day calendar event. Its sequence is the
+// version of the request, which grows with every change.
+func leaveEvent(leave *models.LeaveRequest) ical.Event {
+	leaveType := string(leave.LeaveType)
+	if leaveType != "" {
+		leaveType = strings.ToUpper(leaveType[:1]) + leaveType[1:]
+	}
+	return ical.Event{
+		UID:         leaveUID(leave.ID),
+		Sequence:    leave.Version,
+		Start:       leave.StartDate,
+		End:         leave.EndDate,
+		Summary:     strings.TrimSpace(leaveType + " leave"),
+		Description: leave.Reason,
+		Free:        true,
+	}
+}
+
+// calendarChange returns how the change of leave from before to after
+// affects the calendar event sent when it was approved, if at all
+func calendarChange(before, after *models.LeaveRequest) (models.CalendarChangeAction, bool) {
+	if before.Status != models.LeaveStatusApproved {
+		return "", false
+	}
+	if after.Status != models.LeaveStatusApproved {
+		return models.CalendarChangeWithdrawn, true
+	}
+	if after.StartDate != before.StartDate || after.EndDate != before.EndDate {
+		return models.CalendarChangeRescheduled, true
+	}
+	return "", false
+}
+
+// queueCalendarChange queues a calendar update for the employee when a
+// change of leave from before to after withdraws or moves approved leave
+func queueCalendarChange(ctx context.Context, tx repository.Store, before, after *models.LeaveRequest) error {
+	action, changed := calendarChange(before, after)
+	if !changed {
+		return nil
+	}
+	return enqueue(ctx, tx.Outbox(), models.OutboxTopicLeaveCalendar, "", &models.CalendarChange{Action: action, Leave: after}, 0)
+}
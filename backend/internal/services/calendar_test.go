@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"leave-management-system/internal/config"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+)
+
+func TestParseHolidays(t *testing.T) {
+	holidays, err := ParseHolidays(" 2024-04-13=Songkran ; 2024-01-01=New Year's Day;")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(holidays) != 2 || holidays[0].Date != models.NewDate(2024, 1, 1) || holidays[1].Name != "Songkran" {
+		t.Errorf("expected the holidays by date, got %+v", holidays)
+	}
+
+	for _, invalid := range []string{"2024-01-01", "2024-01-01=", "01/01/2024=New Year's Day"} {
+		if _, err := ParseHolidays(invalid); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestCalendarService_Feeds(t *testing.T) {
+	service, err := NewCalendarService(repository.NewMockCalendarFeedRepository(),
+		NewLeaveService(repository.NewMockUnitOfWork(nil, nil), DefaultLeavePolicy()),
+		"https://leave.example.com", config.CalendarConfig{HistoryDays: 365})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	employee := &models.Employee{ID: "emp-1", Name: "John Doe", Department: "Engineering"}
+
+	if _, err := service.SaveFeed(ctx, "everyone", &models.UpdateCalendarFeedRequest{}, employee, nil); !errors.Is(err, ErrCalendarFeedNotFound) {
+		t.Fatalf("expected ErrCalendarFeedNotFound for an unknown kind, got %v", err)
+	}
+
+	// Team feeds of employees hide the type of their colleagues' leave
+	feed, err := service.SaveFeed(ctx, models.CalendarFeedTeam, &models.UpdateCalendarFeedRequest{}, employee, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if feed.Privacy != models.CalendarPrivacyBusy || !strings.HasPrefix(feed.URL, "https://leave.example.com/ical/") || !strings.HasSuffix(feed.URL, ".ics") {
+		t.Errorf("unexpected feed %+v", feed)
+	}
+	if _, err := service.SaveFeed(ctx, models.CalendarFeedTeam, &models.UpdateCalendarFeedRequest{Privacy: models.CalendarPrivacyDetails}, employee, nil); !errors.Is(err, ErrTeamFeedDetails) {
+		t.Errorf("expected ErrTeamFeedDetails for an employee, got %v", err)
+	}
+
+	// Changing the privacy keeps the URL without showing it again;
+	// regenerating replaces it
+	details, err := service.SaveFeed(ctx, models.CalendarFeedTeam, &models.UpdateCalendarFeedRequest{Privacy: models.CalendarPrivacyDetails}, employee, []string{RoleManager})
+	if err != nil || details.URL != "" || details.Privacy != models.CalendarPrivacyDetails {
+		t.Fatalf("expected the same feed with details, got %+v (%v)", details, err)
+	}
+	if _, _, err := service.RenderFeed(ctx, feedToken(feed.URL)); err != nil {
+		t.Errorf("expected the URL to keep working, got %v", err)
+	}
+
+	// Saving again without the role drops the details
+	busy, err := service.SaveFeed(ctx, models.CalendarFeedTeam, &models.UpdateCalendarFeedRequest{}, employee, nil)
+	if err != nil || busy.Privacy != models.CalendarPrivacyBusy {
+		t.Fatalf("expected busy privacy, got %+v (%v)", busy, err)
+	}
+	regenerated, err := service.RegenerateFeed(ctx, models.CalendarFeedTeam, employee.ID)
+	if err != nil || regenerated.URL == "" || regenerated.URL == feed.URL || regenerated.Privacy != models.CalendarPrivacyBusy {
+		t.Fatalf("expected a new URL, got %+v (%v)", regenerated, err)
+	}
+	if _, _, err := service.RenderFeed(ctx, feedToken(feed.URL)); !errors.Is(err, ErrCalendarFeedNotFound) {
+		t.Errorf("expected the old URL to stop working, got %v", err)
+	}
+	if _, err := service.RegenerateFeed(ctx, models.CalendarFeedPersonal, employee.ID); !errors.Is(err, ErrCalendarFeedNotFound) {
+		t.Errorf("expected ErrCalendarFeedNotFound regenerating a feed that does not exist, got %v", err)
+	}
+
+	feeds, err := service.ListFeeds(ctx, employee.ID)
+	if err != nil || len(feeds) != 1 || feeds[0].Kind != models.CalendarFeedTeam || feeds[0].URL != "" {
+		t.Fatalf("expected the team feed without its URL, got %+v (%v)", feeds, err)
+	}
+	if feeds, _ := service.ListFeeds(ctx, "emp-2"); len(feeds) != 0 {
+		t.Errorf("expected no feeds for another employee, got %d", len(feeds))
+	}
+
+	if err := service.DeleteFeed(ctx, models.CalendarFeedTeam, employee.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := service.RenderFeed(ctx, feedToken(regenerated.URL)); !errors.Is(err, ErrCalendarFeedNotFound) {
+		t.Errorf("expected a deleted feed to stop working, got %v", err)
+	}
+	if err := service.DeleteFeed(ctx, models.CalendarFeedTeam, employee.ID); !errors.Is(err, ErrCalendarFeedNotFound) {
+		t.Errorf("expected ErrCalendarFeedNotFound deleting twice, got %v", err)
+	}
+}
+
+func TestCalendarService_RenderFeed(t *testing.T) {
+	repo := repository.NewMockLeaveRepository()
+	service, err := NewCalendarService(repository.NewMockCalendarFeedRepository(),
+		NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy()),
+		"https://leave.example.com", config.CalendarConfig{Holidays: "2024-04-13=Songkran", HistoryDays: 30})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	today := models.Today(time.UTC)
+
+	mine := &models.LeaveRequest{EmployeeID: "emp-1", EmployeeName: "John Doe", Department: "Engineering", LeaveType: models.LeaveTypeSick,
+		Reason: "Flu", Status: models.LeaveStatusApproved, StartDate: today, EndDate: today.AddDays(1)}
+	for _, leave := range []*models.LeaveRequest{
+		mine,
+		{EmployeeID: "emp-2", EmployeeName: "Jane Roe", Department: "Engineering", LeaveType: models.LeaveTypeAnnual,
+			Reason: "Wedding", Status: models.LeaveStatusApproved, StartDate: today.AddDays(7), EndDate: today.AddDays(9)},
+		// Pending, in another team, and long past leave are not listed
+		{EmployeeID: "emp-2", EmployeeName: "Jane Roe", Department: "Engineering", Status: models.LeaveStatusPending, StartDate: today, EndDate: today},
+		{EmployeeID: "emp-3", EmployeeName: "Raj", Department: "Sales", Status: models.LeaveStatusApproved, StartDate: today, EndDate: today},
+		{EmployeeID: "emp-1", EmployeeName: "John Doe", Department: "Engineering", Status: models.LeaveStatusApproved, StartDate: today.AddDays(-60), EndDate: today.AddDays(-60)},
+	} {
+		leave.ID = uuid.New()
+		repo.Create(ctx, leave)
+	}
+
+	// Each render gets a new URL, as a saved feed's URL is not shown again
+	render := func(kind models.CalendarFeedKind, privacy models.CalendarPrivacy) string {
+		t.Helper()
+		employee := &models.Employee{ID: "emp-1", Name: "John Doe", Department: "Engineering"}
+		service.DeleteFeed(ctx, kind, employee.ID)
+		info, err := service.SaveFeed(ctx, kind, &models.UpdateCalendarFeedRequest{Privacy: privacy}, employee, []string{RoleHR})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		feed, body, err := service.RenderFeed(ctx, feedToken(info.URL))
+		if err != nil || feed.Kind != kind {
+			t.Fatalf("expected the %s feed, got %+v (%v)", kind, feed, err)
+		}
+		return strings.ReplaceAll(string(body), "\r\n ", "")
+	}
+
+	personal := render(models.CalendarFeedPersonal, models.CalendarPrivacyDetails)
+	if strings.Count(personal, "BEGIN:VEVENT") != 1 || !strings.Contains(personal, "UID:"+leaveUID(mine.ID)) ||
+		!strings.Contains(personal, "SUMMARY:Sick leave") || !strings.Contains(personal, "METHOD:PUBLISH") {
+		t.Errorf("expected John's current sick leave, got\n%s", personal)
+	}
+
+	team := render(models.CalendarFeedTeam, models.CalendarPrivacyDetails)
+	if strings.Count(team, "BEGIN:VEVENT") != 2 || !strings.Contains(team, "SUMMARY:Jane Roe: Annual leave") || !strings.Contains(team, `X-WR-CALNAME:Team leave (Engineering)`) {
+		t.Errorf("expected the team's approved leave, got\n%s", team)
+	}
+	if strings.Contains(team, "Wedding") || strings.Contains(team, "Flu") {
+		t.Errorf("expected no reasons in a feed, got\n%s", team)
+	}
+
+	busy := render(models.CalendarFeedTeam, models.CalendarPrivacyBusy)
+	if !strings.Contains(busy, "SUMMARY:Jane Roe: Out of office") || strings.Contains(busy, "Annual") {
+		t.Errorf("expected leave types hidden, got\n%s", busy)
+	}
+
+	holidays := render(models.CalendarFeedHolidays, "")
+	if !strings.Contains(holidays, "SUMMARY:Songkran") || !strings.Contains(holidays, "DTSTART;VALUE=DATE:20240413") {
+		t.Errorf("expected the company holidays, got\n%s", holidays)
+	}
+}
+
+// feedToken returns the token in a feed URL
+func feedToken(url string) string {
+	return strings.TrimSuffix(url[strings.LastIndex(url, "/")+1:], ".ics")
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/utils"
+)
+
+var (
+	ErrCommentNotFound   = errors.New("comment not found")
+	ErrEditWindowExpired = errors.New("comment can no longer be edited")
+)
+
+// CommentService handles the discussion thread on leave requests
+type CommentService struct {
+	uow           repository.UnitOfWork
+	leaveService  *LeaveService
+	notifications *NotificationService
+	editWindow    time.Duration
+}
+
+// NewCommentService creates a new comment service. The approvers of a request,
+// as notifications resolves them, are notified of employee comments until an
+// approver has joined the thread.
+func NewCommentService(uow repository.UnitOfWork, leaveService *LeaveService, notifications *NotificationService, editWindow time.Duration) *CommentService {
+	return &CommentService{
+		uow:           uow,
+		leaveService:  leaveService,
+		notifications: notifications,
+		editWindow:    editWindow,
+	}
+}
+
+// AddComment posts a comment on a leave request. The owner, approvers and HR may
+// comment. The other party is notified through the outbox, queued in the same
+// transaction as the comment; AddComment returns the new comment and them.
+func (s *CommentService) AddComment(ctx context.Context, leaveID uuid.UUID, author *models.Employee, roles []string, body string) (*models.LeaveComment, []models.Recipient, error) {
+	leave, err := s.leaveService.GetLeaveRequestByID(ctx, leaveID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !canViewLeave(leave, author.ID, roles) {
+		return nil, nil, ErrUnauthorizedAction
+	}
+
+	body, err = validateCommentBody(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	comment := &models.LeaveComment{
+		ID:             uuid.New(),
+		LeaveRequestID: leaveID,
+		AuthorID:       author.ID,
+		AuthorName:     author.Name,
+		AuthorEmail:    author.Email,
+		Body:           body,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	var recipients []models.Recipient
+	err = s.uow.WithinTx(ctx, func(tx repository.Store) error {
+		if err := tx.Comments().Create(ctx, comment); err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+
+		recipients, err = s.recipients(ctx, tx.Comments(), leave, comment)
+		if err != nil || len(recipients) == 0 {
+			return err
+		}
+		notice := &models.CommentNotice{Comment: comment, Recipients: recipients}
+		return enqueue(ctx, tx.Outbox(), models.OutboxTopicLeaveComment, "", notice, 0)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return comment, recipients, nil
+}
+
+// GetComments lists the comments on a leave request, oldest first
+func (s *CommentService) GetComments(ctx context.Context, leaveID uuid.UUID, userID string, roles []string) ([]*models.LeaveComment, error) {
+	leave, err := s.leaveService.GetLeaveRequestByID(ctx, leaveID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canViewLeave(leave, userID, roles) {
+		return nil, ErrUnauthorizedAction
+	}
+
+	comments, err := s.uow.Comments().FindByLeaveRequestID(ctx, leaveID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	return comments, nil
+}
+
+// UpdateComment edits a comment. Only its author may edit it, and only within
+// the edit window after posting.
+func (s *CommentService) UpdateComment(ctx context.Context, leaveID, commentID uuid.UUID, userID string, body string) (*models.LeaveComment, error) {
+	comment, err := s.uow.Comments().FindByID(ctx, commentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	if comment.LeaveRequestID != leaveID {
+		return nil, ErrCommentNotFound
+	}
+
+	if comment.AuthorID != userID {
+		return nil, ErrUnauthorizedAction
+	}
+
+	if time.Since(comment.CreatedAt) > s.editWindow {
+		return nil, ErrEditWindowExpired
+	}
+
+	body, err = validateCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *comment
+	updated.Body = body
+	updated.UpdatedAt = time.Now()
+
+	if err := s.uow.Comments().Update(ctx, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// recipients returns who to notify about a new comment: nobody on a draft,
+// which approvers cannot see; the employee when an approver writes; otherwise
+// the approvers taking part in the thread, or the approvers of the request if
+// none has joined yet. The employee is written to in the locale of their
+// request.
+func (s *CommentService) recipients(ctx context.Context, repo repository.CommentRepository, leave *models.LeaveRequest, comment *models.LeaveComment) ([]models.Recipient, error) {
+	if leave.Status == models.LeaveStatusDraft {
+		return nil, nil
+	}
+	if comment.AuthorID != leave.EmployeeID {
+		return []models.Recipient{{Email: leave.EmployeeEmail, Locale: leave.Locale}}, nil
+	}
+
+	comments, err := repo.FindByLeaveRequestID(ctx, leave.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var recipients []models.Recipient
+	for _, c := range comments {
+		if c.AuthorID == leave.EmployeeID || c.AuthorEmail == "" || seen[c.AuthorEmail] {
+			continue
+		}
+		seen[c.AuthorEmail] = true
+		recipients = append(recipients, models.Recipient{Email: c.AuthorEmail})
+	}
+
+	if len(recipients) == 0 {
+		for _, email := range s.notifications.approvers(leave) {
+			recipients = append(recipients, models.Recipient{Email: email})
+		}
+	}
+
+	return recipients, nil
+}
+
+// validateCommentBody trims a comment and checks its length
+func validateCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", &utils.ValidationError{Message: "comment cannot be empty"}
+	}
+	if utf8.RuneCountInString(body) > models.MaxCommentLength {
+		return "", &utils.ValidationError{Message: fmt.Sprintf("comment cannot exceed %d characters", models.MaxCommentLength)}
+	}
+	return body, nil
+}
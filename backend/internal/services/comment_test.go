@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/utils"
+)
+
+func setupCommentService(t *testing.T) (*CommentService, *repository.MockUnitOfWork, uuid.UUID) {
+	t.Helper()
+
+	leaveRepo := repository.NewMockLeaveRepository()
+	leaveID := uuid.New()
+	leaveRepo.Create(context.Background(), &models.LeaveRequest{
+		ID:            leaveID,
+		EmployeeID:    "emp-1",
+		EmployeeEmail: "john@example.com",
+		LeaveType:     models.LeaveTypeAnnual,
+		Status:        models.LeaveStatusPending,
+		Locale:        "th",
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	})
+
+	uow := repository.NewMockUnitOfWork(leaveRepo, nil)
+	service := NewCommentService(uow, NewLeaveService(uow, DefaultLeavePolicy()), newTestNotificationService(&recordingNotifier{}, "approvers@example.com"), 15*time.Minute)
+	return service, uow, leaveID
+}
+
+func TestCommentService_AddComment(t *testing.T) {
+	service, uow, leaveID := setupCommentService(t)
+
+	employee := &models.Employee{ID: "emp-1", Name: "John Doe", Email: "john@example.com"}
+	manager := &models.Employee{ID: "mgr-1", Name: "Jane Smith", Email: "jane@example.com"}
+
+	// Before any approver joins, employee comments go to the approver address
+	_, recipients, err := service.AddComment(context.Background(), leaveID, employee, nil, "Can I take these days?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recipients) != 1 || recipients[0].Email != "approvers@example.com" {
+		t.Errorf("expected approver address, got %v", recipients)
+	}
+
+	// Approver comments go to the employee
+	comment, recipients, err := service.AddComment(context.Background(), leaveID, manager, []string{RoleManager}, "  Please add a handover note.  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comment.Body != "Please add a handover note." {
+		t.Errorf("expected trimmed body, got %q", comment.Body)
+	}
+	if len(recipients) != 1 || recipients[0].Email != "john@example.com" || recipients[0].Locale != "th" {
+		t.Errorf("expected employee address in their locale, got %v", recipients)
+	}
+
+	// Once an approver has joined, they are notified directly
+	_, recipients, err = service.AddComment(context.Background(), leaveID, employee, nil, "Added, thanks.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recipients) != 1 || recipients[0].Email != "jane@example.com" {
+		t.Errorf("expected approver in thread, got %v", recipients)
+	}
+
+	comments, err := service.GetComments(context.Background(), leaveID, "emp-1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 3 {
+		t.Errorf("expected 3 comments, got %d", len(comments))
+	}
+
+	// Each notification is queued in the outbox with its comment
+	messages, _ := uow.OutboxRepo.FindAll(context.Background(), models.OutboxFilter{Topic: models.OutboxTopicLeaveComment})
+	if len(messages) != 3 {
+		t.Errorf("expected 3 queued comment notifications, got %d", len(messages))
+	}
+
+	tests := []struct {
+		name    string
+		leaveID uuid.UUID
+		author  *models.Employee
+		roles   []string
+		body    string
+		errType error
+	}{
+		{"other employee", leaveID, &models.Employee{ID: "emp-2"}, nil, "Hello", ErrUnauthorizedAction},
+		{"unknown leave", uuid.New(), employee, nil, "Hello", ErrLeaveNotFound},
+		{"empty body", leaveID, employee, nil, "   ", nil},
+		{"body too long", leaveID, employee, nil, strings.Repeat("a", models.MaxCommentLength+1), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := service.AddComment(context.Background(), tt.leaveID, tt.author, tt.roles, tt.body)
+			if err == nil {
+				t.Fatalf("expected error but got none")
+			}
+			if tt.errType != nil && !errors.Is(err, tt.errType) {
+				t.Errorf("expected error %v, got %v", tt.errType, err)
+			}
+			var validationErr *utils.ValidationError
+			if tt.errType == nil && !errors.As(err, &validationErr) {
+				t.Errorf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCommentService_Recipients(t *testing.T) {
+	service, _, _ := setupCommentService(t)
+	employee := &models.Employee{ID: "emp-1", Email: "john@example.com"}
+
+	tests := []struct {
+		name   string
+		status models.LeaveStatus
+		author string
+		want   []string
+	}{
+		{"draft notifies nobody", models.LeaveStatusDraft, "emp-1", nil},
+		{"hr comment on draft notifies nobody", models.LeaveStatusDraft, "hr-1", nil},
+		{"falls back to assigned approver", models.LeaveStatusPending, "emp-1", []string{"boss@example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leave := &models.LeaveRequest{
+				ID:            uuid.New(),
+				EmployeeID:    employee.ID,
+				EmployeeEmail: employee.Email,
+				Status:        tt.status,
+				ApproverEmail: "boss@example.com",
+			}
+
+			recipients, err := service.recipients(context.Background(), repository.NewMockCommentRepository(), leave, &models.LeaveComment{AuthorID: tt.author})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, r := range recipients {
+				got = append(got, r.Email)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected recipients %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCommentService_UpdateComment(t *testing.T) {
+	service, uow, leaveID := setupCommentService(t)
+
+	comment, _, err := service.AddComment(context.Background(), leaveID, &models.Employee{ID: "emp-1"}, nil, "First draft of my note")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.UpdateComment(context.Background(), leaveID, comment.ID, "mgr-1", "Edited"); !errors.Is(err, ErrUnauthorizedAction) {
+		t.Errorf("expected ErrUnauthorizedAction, got %v", err)
+	}
+	if _, err := service.UpdateComment(context.Background(), uuid.New(), comment.ID, "emp-1", "Edited"); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("expected ErrCommentNotFound for another leave request, got %v", err)
+	}
+
+	updated, err := service.UpdateComment(context.Background(), leaveID, comment.ID, "emp-1", "Edited note")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Body != "Edited note" {
+		t.Errorf("expected edited body, got %q", updated.Body)
+	}
+
+	// Outside the edit window
+	stale, _ := uow.CommentRepo.FindByID(context.Background(), comment.ID)
+	stale.CreatedAt = time.Now().Add(-time.Hour)
+	if _, err := service.UpdateComment(context.Background(), leaveID, comment.ID, "emp-1", "Too late"); !errors.Is(err, ErrEditWindowExpired) {
+		t.Errorf("expected ErrEditWindowExpired, got %v", err)
+	}
+}
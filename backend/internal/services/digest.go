@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"leave-management-system/internal/logger"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/templates"
+)
+
+// digestCheckInterval is how often the digest scheduler looks for digests
+// that are due
+const digestCheckInterval = time.Minute
+
+// digestListLimit bounds how many pending requests, and how many absences, a
+// digest lists; the rest are counted
+const digestListLimit = 50
+
+// DigestService sends a daily digest to everyone who leaves approver events
+// to it: the requests awaiting their decision and the approved leave of
+// their department in the coming days. Each digest is queued in the outbox
+// once the recipient's digest time has passed in their time zone, and is
+// compiled when it is delivered.
+type DigestService struct {
+	uow           repository.UnitOfWork
+	prefs         *NotificationPreferenceService
+	leaveService  *LeaveService
+	notifications *NotificationService
+	// days is how many days ahead team absences are listed
+	days   int
+	logger *logger.Logger
+}
+
+// NewDigestService creates a new digest service listing team absences days
+// ahead
+func NewDigestService(uow repository.UnitOfWork, prefs *NotificationPreferenceService, leaveService *LeaveService, notifications *NotificationService, days int) *DigestService {
+	return &DigestService{
+		uow:           uow,
+		prefs:         prefs,
+		leaveService:  leaveService,
+		notifications: notifications,
+		days:          days,
+		logger:        logger.New().With("component", "digest"),
+	}
+}
+
+// Run queues due digests every minute until ctx is cancelled
+func (s *DigestService) Run(ctx context.Context) {
+	ticker := time.NewTicker(digestCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if queued, err := s.QueueDue(ctx, time.Now()); err != nil {
+			s.logger.Errorf("digest_queue_failed error=%v", err)
+		} else if queued > 0 {
+			s.logger.Infof("digest_queue_success queued=%d", queued)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// QueueDue queues the digest of each subscriber whose digest time has passed
+// today, in their time zone, as of now, unless theirs for today already was.
+// It returns how many digests were queued.
+func (s *DigestService) QueueDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.prefs.DueDigests(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for _, req := range due {
+		// The claim and the message commit together, so two servers never
+		// queue the same digest and a failed enqueue leaves it unclaimed
+		err := s.uow.WithinTx(ctx, func(tx repository.Store) error {
+			claimed, err := tx.Preferences().ClaimDigest(ctx, req.EmployeeID, req.Date)
+			if err != nil || !claimed {
+				return err
+			}
+			if err := enqueue(ctx, tx.Outbox(), models.OutboxTopicDigest, "", req, 0); err != nil {
+				return err
+			}
+			queued++
+			return nil
+		})
+		if err != nil {
+			return queued, err
+		}
+	}
+	return queued, nil
+}
+
+// DeliverDigests delivers OutboxTopicDigest messages
+func (s *DigestService) DeliverDigests(ctx context.Context, messages []*models.OutboxMessage) error {
+	for _, msg := range messages {
+		var req models.DigestRequest
+		if err := json.Unmarshal(msg.Payload, &req); err != nil {
+			return fmt.Errorf("failed to decode digest %s: %w", msg.ID, err)
+		}
+		if err := s.SendDigest(ctx, req.EmployeeID, req.Date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendDigest compiles and sends the employee's digest for date. Nothing is
+// sent when there is nothing to report, or when the employee no longer
+// wants a digest.
+func (s *DigestService) SendDigest(ctx context.Context, employeeID string, date models.Date) error {
+	prefs, err := s.prefs.FindPreferences(ctx, employeeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	if !prefs.WantsDigest() {
+		return nil
+	}
+
+	data, err := s.compile(ctx, prefs, date)
+	if err != nil {
+		return err
+	}
+	if len(data.Pending) == 0 && len(data.Absences) == 0 {
+		s.logger.Infof("digest_skipped reason=empty employee_id=%s date=%s", employeeID, date)
+		return nil
+	}
+	return s.notifications.NotifyDigest(ctx, prefs, data)
+}
+
+// compile gathers the digest of prefs' owner for date
+func (s *DigestService) compile(ctx context.Context, prefs *models.NotificationPreferences, date models.Date) (*templates.DigestData, error) {
+	data := &templates.DigestData{
+		Name:       prefs.EmployeeName,
+		Date:       date,
+		Department: prefs.Department,
+		Days:       s.days,
+	}
+
+	// The requests whose activity the recipient is notified of
+	if approvers := s.notifications.approvedBy(prefs.Email); len(approvers) > 0 {
+		pending, err := s.leaveService.GetPendingLeaveRequests(ctx, models.LeaveFilter{
+			Approvers: approvers,
+			Limit:     digestListLimit,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query pending leave for digest: %w", err)
+		}
+		for _, leave := range pending.Items {
+			data.Pending = append(data.Pending, &templates.DigestApproval{Leave: leave})
+		}
+		data.MorePending = pending.Total - len(pending.Items)
+	}
+
+	if prefs.Department != "" {
+		absences, err := s.leaveService.GetPendingLeaveRequests(ctx, models.LeaveFilter{
+			Status:     models.LeaveStatusApproved,
+			Department: prefs.Department,
+			From:       date,
+			To:         date.AddDays(s.days - 1),
+			Sort:       models.SortStartDate,
+			Limit:      digestListLimit,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query team leave for digest: %w", err)
+		}
+		data.Absences = absences.Items
+		data.MoreAbsences = absences.Total - len(absences.Items)
+	}
+	return data, nil
+}
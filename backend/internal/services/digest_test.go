@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/notify"
+	"leave-management-system/internal/repository"
+)
+
+func TestDigestService_QueueDue(t *testing.T) {
+	bangkok, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	ctx := context.Background()
+	uow := repository.NewMockUnitOfWork(repository.NewMockLeaveRepository(), nil)
+	prefRepo := uow.PrefRepo
+	prefs, _ := NewNotificationPreferenceService(prefRepo, DefaultLeavePolicy(), "08:00")
+	service := NewDigestService(uow, prefs, NewLeaveService(uow, DefaultLeavePolicy()), newTestNotificationService(&recordingNotifier{}, ""), 7)
+
+	prefRepo.Save(ctx, &models.NotificationPreferences{EmployeeID: "mgr-1", Email: "jane@example.com", Timezone: "Asia/Bangkok",
+		Events: map[string]models.EventPreference{"leave.submitted": {Mode: models.NotificationDigest}}})
+	prefRepo.Save(ctx, &models.NotificationPreferences{EmployeeID: "mgr-2", Email: "raj@example.com", Timezone: "Asia/Bangkok", DigestTime: "09:30",
+		Events: map[string]models.EventPreference{"leave.submitted": {Mode: models.NotificationDigest}}})
+	prefRepo.Save(ctx, &models.NotificationPreferences{EmployeeID: "emp-3", Email: "john@example.com",
+		Events: map[string]models.EventPreference{"leave.approved": {Mode: models.NotificationOff}}})
+
+	steps := []struct {
+		at     time.Time
+		queued int
+	}{
+		{time.Date(2024, 3, 4, 7, 59, 0, 0, bangkok), 0},
+		{time.Date(2024, 3, 4, 8, 0, 0, 0, bangkok), 1},
+		{time.Date(2024, 3, 4, 9, 0, 0, 0, bangkok), 0},
+		{time.Date(2024, 3, 4, 9, 30, 0, 0, bangkok), 1},
+		{time.Date(2024, 3, 4, 23, 0, 0, 0, bangkok), 0},
+		// The next morning in Bangkok, though it is 5 March 01:00 in UTC
+		{time.Date(2024, 3, 5, 8, 0, 0, 0, bangkok), 1},
+	}
+	for _, step := range steps {
+		queued, err := service.QueueDue(ctx, step.at.UTC())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if queued != step.queued {
+			t.Errorf("at %s: expected %d digests queued, got %d", step.at, step.queued, queued)
+		}
+	}
+
+	messages, _ := uow.OutboxRepo.ClaimDue(ctx, time.Now(), 10, time.Minute)
+	if len(messages) != 3 || messages[0].Topic != models.OutboxTopicDigest {
+		t.Fatalf("expected 3 digests in the outbox, got %d", len(messages))
+	}
+	stored, _ := prefRepo.Find(ctx, "mgr-1")
+	if stored.LastDigestOn != models.NewDate(2024, 3, 5) {
+		t.Errorf("expected the digest of 5 March claimed, got %s", stored.LastDigestOn)
+	}
+}
+
+func TestDigestService_SendDigest(t *testing.T) {
+	ctx := context.Background()
+	leaveRepo := repository.NewMockLeaveRepository()
+	uow := repository.NewMockUnitOfWork(leaveRepo, nil)
+	prefRepo := uow.PrefRepo
+	notifier := &recordingNotifier{}
+	prefs, _ := NewNotificationPreferenceService(prefRepo, DefaultLeavePolicy(), "08:00")
+	service := NewDigestService(uow, prefs, NewLeaveService(uow, DefaultLeavePolicy()),
+		newTestNotificationService(notifier, "hr@example.com"), 7)
+	date := models.NewDate(2024, 3, 4)
+
+	prefRepo.Save(ctx, &models.NotificationPreferences{EmployeeID: "mgr-1", Email: "jane@example.com", EmployeeName: "Jane Smith", Department: "Engineering",
+		Events: map[string]models.EventPreference{"leave.submitted": {Mode: models.NotificationDigest}}})
+
+	// Nothing to report
+	if err := service.SendDigest(ctx, "mgr-1", date); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.sent) != 0 {
+		t.Fatalf("expected no empty digest, got %d", len(notifier.sent))
+	}
+
+	created := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	add := func(name, approver, department string, status models.LeaveStatus, start models.Date) {
+		leave := testLeave(status, "")
+		leave.ID = uuid.New()
+		leave.EmployeeName, leave.ApproverEmail, leave.Department = name, approver, department
+		leave.StartDate, leave.EndDate = start, start.AddDays(1)
+		created = created.Add(time.Minute)
+		leave.CreatedAt = created
+		leaveRepo.Create(ctx, leave)
+	}
+	add("Alice", "JANE@example.com", "Engineering", models.LeaveStatusPending, date.AddDays(14))
+	add("Bob", "raj@example.com", "Engineering", models.LeaveStatusPending, date.AddDays(14))
+	add("Frank", "", "Sales", models.LeaveStatusPending, date.AddDays(14))
+	add("Carol", "", "Engineering", models.LeaveStatusApproved, date.AddDays(2))
+	add("Dave", "", "Engineering", models.LeaveStatusApproved, date.AddDays(7))
+	add("Erin", "", "Sales", models.LeaveStatusApproved, date.AddDays(2))
+
+	if err := service.SendDigest(ctx, "mgr-1", date); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.sent) != 1 {
+		t.Fatalf("expected one digest, got %d", len(notifier.sent))
+	}
+	n := notifier.sent[0]
+	if n.Event != notify.EventDailyDigest || len(n.To) != 1 || n.To[0] != "jane@example.com" {
+		t.Errorf("expected the digest sent to Jane, got %s to %v", n.Event, n.To)
+	}
+	for _, want := range []string{"Alice", "Carol"} {
+		if !strings.Contains(n.Text, want) {
+			t.Errorf("expected %s in the digest, got:\n%s", want, n.Text)
+		}
+	}
+	for _, unwanted := range []string{"Bob", "Dave", "Erin", "Frank", "more"} {
+		if strings.Contains(n.Text, unwanted) {
+			t.Errorf("expected no %s in the digest, got:\n%s", unwanted, n.Text)
+		}
+	}
+
+	// Requests without an approver of their own go to the fallback address,
+	// and only the oldest are listed
+	prefRepo.Save(ctx, &models.NotificationPreferences{EmployeeID: "hr-1", Email: "HR@example.com", EmployeeName: "Hana",
+		Events: map[string]models.EventPreference{"leave.submitted": {Mode: models.NotificationDigest}}})
+	for i := 0; i < digestListLimit+1; i++ {
+		add("Grace", "", "Sales", models.LeaveStatusPending, date.AddDays(21))
+	}
+	if err := service.SendDigest(ctx, "hr-1", date); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.sent) != 2 {
+		t.Fatalf("expected a digest for HR, got %d", len(notifier.sent))
+	}
+	n = notifier.sent[1]
+	if want := fmt.Sprintf("%d Awaiting Approval", digestListLimit+2); !strings.Contains(n.Subject, want) {
+		t.Errorf("expected %q in the subject, got %q", want, n.Subject)
+	}
+	if !strings.Contains(n.Text, "Frank") || !strings.Contains(n.Text, "and 2 more") || strings.Contains(n.Text, "Alice") {
+		t.Errorf("expected Frank and 2 more requests unlisted, got:\n%s", n.Text)
+	}
+
+	// No longer wanted, or no preferences at all
+	prefRepo.Save(ctx, &models.NotificationPreferences{EmployeeID: "mgr-1", Email: "jane@example.com"})
+	for _, id := range []string{"mgr-1", "mgr-9"} {
+		if err := service.SendDigest(ctx, id, date); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(notifier.sent) != 2 {
+		t.Errorf("expected no further digests, got %d", len(notifier.sent))
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"leave-management-system/internal/config"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/utils"
+)
+
+var (
+	// ErrInvalidActionToken is returned for an email action token that is
+	// malformed or was not signed by this server
+	ErrInvalidActionToken = errors.New("invalid email action token")
+	// ErrActionTokenExpired is returned for an email action token past its expiry
+	ErrActionTokenExpired = errors.New("email action token expired")
+
+	errRejectCommentRequired = &utils.ValidationError{Message: fmt.Sprintf("a comment of at least %d characters is required to reject", minRejectionCommentLength)}
+)
+
+// EmailActionService lets approvers approve or reject leave from the links in
+// their notification emails. A link is an HMAC-signed token for one decision
+// on one version of a request. Any change to the request, including the
+// decision itself, moves it to a new version, so a link works at most once
+// and stops working when the email is out of date.
+type EmailActionService struct {
+	leaveService *LeaveService
+	secret       []byte
+	ttl          time.Duration
+	baseURL      string
+}
+
+// NewEmailActionService creates a new email action service. Links start with
+// baseURL, where clients reach the server; none are made without a secret.
+func NewEmailActionService(leaveService *LeaveService, baseURL string, cfg config.EmailConfig) *EmailActionService {
+	return &EmailActionService{
+		leaveService: leaveService,
+		secret:       []byte(cfg.ActionSecret),
+		ttl:          cfg.ActionTTL,
+		baseURL:      baseURL,
+	}
+}
+
+// Links returns the approve and reject links for leave, sent to approver.
+// Both are empty when email actions are off, including on a nil service.
+func (s *EmailActionService) Links(leave *models.LeaveRequest, approver string) (approveURL, rejectURL string) {
+	if s == nil || len(s.secret) == 0 {
+		return "", ""
+	}
+	expiresAt := time.Now().Add(s.ttl).Unix()
+	link := func(action models.EmailAction) string {
+		return s.baseURL + "/email-actions/" + s.sign(&models.EmailActionClaims{
+			LeaveID:   leave.ID,
+			Action:    action,
+			Version:   leave.Version,
+			Approver:  approver,
+			ExpiresAt: expiresAt,
+		})
+	}
+	return link(models.EmailActionApprove), link(models.EmailActionReject)
+}
+
+// Preview verifies token and returns its claims with the leave request it
+// decides, as long as the decision can still be taken. A request that was
+// decided or changed since the email was sent yields ErrInvalidStatus or
+// ErrPreconditionFailed along with the request.
+func (s *EmailActionService) Preview(ctx context.Context, token string) (*models.EmailActionClaims, *models.LeaveRequest, error) {
+	claims, err := s.verify(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	leave, err := s.leaveService.GetLeaveRequestByID(ctx, claims.LeaveID)
+	if err != nil {
+		return claims, nil, err
+	}
+	if leave.Status == models.LeaveStatusDraft {
+		return claims, nil, ErrLeaveNotFound
+	}
+	if leave.Status != models.LeaveStatusPending {
+		return claims, leave, fmt.Errorf("%w: request is already %s", ErrInvalidStatus, leave.Status)
+	}
+	if err := checkVersion(leave, claims.Version); err != nil {
+		return claims, leave, err
+	}
+	return claims, leave, nil
+}
+
+// Perform takes the decision token was signed for, with the same checks as
+// a decision made in the app. A rejection needs a comment of at least
+// minRejectionCommentLength characters, as in the app. On failure the
+// request as previewed is returned, so the page can still show it.
+func (s *EmailActionService) Perform(ctx context.Context, token, comment string) (*models.EmailActionClaims, *models.LeaveRequest, error) {
+	claims, leave, err := s.Preview(ctx, token)
+	if err != nil {
+		return claims, leave, err
+	}
+
+	comment = strings.TrimSpace(comment)
+	reviewer := &models.Employee{Email: claims.Approver}
+	var decided *models.LeaveRequest
+	switch claims.Action {
+	case models.EmailActionApprove:
+		decided, err = s.leaveService.ApproveLeaveRequest(ctx, claims.LeaveID, claims.Version, reviewer, comment)
+	case models.EmailActionReject:
+		if utf8.RuneCountInString(comment) < minRejectionCommentLength {
+			return claims, leave, errRejectCommentRequired
+		}
+		decided, err = s.leaveService.RejectLeaveRequest(ctx, claims.LeaveID, claims.Version, reviewer, comment)
+	}
+	if err != nil {
+		return claims, leave, err
+	}
+	return claims, decided, nil
+}
+
+// sign encodes claims as a token: the claims, then their HMAC-SHA256, both
+// base64url encoded and separated by a dot
+func (s *EmailActionService) sign(claims *models.EmailActionClaims) string {
+	payload, _ := json.Marshal(claims)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded))
+}
+
+// verify returns the claims of token if it was signed by s and has not expired
+func (s *EmailActionService) verify(token string) (*models.EmailActionClaims, error) {
+	if len(s.secret) == 0 {
+		return nil, ErrInvalidActionToken
+	}
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidActionToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.mac(encoded)) {
+		return nil, ErrInvalidActionToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidActionToken
+	}
+
+	var claims models.EmailActionClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidActionToken
+	}
+	if claims.Action != models.EmailActionApprove && claims.Action != models.EmailActionReject {
+		return nil, ErrInvalidActionToken
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrActionTokenExpired
+	}
+	return &claims, nil
+}
+
+func (s *EmailActionService) mac(encoded string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(encoded))
+	return h.Sum(nil)
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/config"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/utils"
+)
+
+func setupEmailActionService(ttl time.Duration) (*EmailActionService, *repository.MockLeaveRepository) {
+	repo := repository.NewMockLeaveRepository()
+	leaveService := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
+	service := NewEmailActionService(leaveService, "https://leave.example.com", config.EmailConfig{
+		ActionSecret: "test-secret",
+		ActionTTL:    ttl,
+	})
+	return service, repo
+}
+
+func pendingLeave(repo *repository.MockLeaveRepository) *models.LeaveRequest {
+	leave := &models.LeaveRequest{ID: uuid.New(), EmployeeID: "emp-1", Status: models.LeaveStatusPending, CreatedAt: time.Now()}
+	repo.Create(context.Background(), leave)
+	return leave
+}
+
+// actionToken returns the token of an email action link
+func actionToken(url string) string {
+	return strings.TrimPrefix(url, "https://leave.example.com/email-actions/")
+}
+
+func TestEmailActionService_Links(t *testing.T) {
+	service, repo := setupEmailActionService(time.Hour)
+	leave := pendingLeave(repo)
+
+	approveURL, rejectURL := service.Links(leave, "manager@example.com")
+	if !strings.HasPrefix(approveURL, "https://leave.example.com/email-actions/") || approveURL == rejectURL {
+		t.Fatalf("expected two different action links, got %q and %q", approveURL, rejectURL)
+	}
+
+	claims, got, err := service.Preview(context.Background(), actionToken(rejectURL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.Action != models.EmailActionReject || claims.LeaveID != leave.ID || claims.Approver != "manager@example.com" || got.ID != leave.ID {
+		t.Errorf("expected a rejection of the leave by its approver, got %+v", claims)
+	}
+
+	// Without a secret, or a service, no links are made and none are accepted
+	off := NewEmailActionService(nil, "https://leave.example.com", config.EmailConfig{})
+	if approveURL, rejectURL := off.Links(leave, "manager@example.com"); approveURL != "" || rejectURL != "" {
+		t.Errorf("expected no links without a secret, got %q and %q", approveURL, rejectURL)
+	}
+	if _, _, err := off.Preview(context.Background(), actionToken(approveURL)); !errors.Is(err, ErrInvalidActionToken) {
+		t.Errorf("expected ErrInvalidActionToken without a secret, got %v", err)
+	}
+	var none *EmailActionService
+	if approveURL, _ := none.Links(leave, "manager@example.com"); approveURL != "" {
+		t.Errorf("expected no links from a nil service, got %q", approveURL)
+	}
+}
+
+func TestEmailActionService_InvalidTokens(t *testing.T) {
+	service, repo := setupEmailActionService(time.Hour)
+	leave := pendingLeave(repo)
+	approveURL, _ := service.Links(leave, "manager@example.com")
+	token := actionToken(approveURL)
+
+	payload, signature, _ := strings.Cut(token, ".")
+	otherURL, _ := service.Links(pendingLeave(repo), "manager@example.com")
+	otherPayload, _, _ := strings.Cut(actionToken(otherURL), ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", payload},
+		{"tampered signature", payload + "." + strings.ToUpper(signature)},
+		{"claims of another request", otherPayload + "." + signature},
+		{"garbage", "not-a-token.at-all"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := service.Perform(context.Background(), tt.token, ""); !errors.Is(err, ErrInvalidActionToken) {
+				t.Errorf("expected ErrInvalidActionToken, got %v", err)
+			}
+		})
+	}
+
+	// A link signed with another secret
+	if _, _, err := NewEmailActionService(nil, "", config.EmailConfig{ActionSecret: "other-secret"}).Preview(context.Background(), token); !errors.Is(err, ErrInvalidActionToken) {
+		t.Errorf("expected ErrInvalidActionToken for another secret, got %v", err)
+	}
+
+	expired, repo := setupEmailActionService(-time.Minute)
+	approveURL, _ = expired.Links(pendingLeave(repo), "manager@example.com")
+	if _, _, err := expired.Perform(context.Background(), actionToken(approveURL), ""); !errors.Is(err, ErrActionTokenExpired) {
+		t.Errorf("expected ErrActionTokenExpired, got %v", err)
+	}
+}
+
+func TestEmailActionService_Perform(t *testing.T) {
+	service, repo := setupEmailActionService(time.Hour)
+	ctx := context.Background()
+
+	t.Run("approves once", func(t *testing.T) {
+		leave := pendingLeave(repo)
+		approveURL, rejectURL := service.Links(leave, "manager@example.com")
+
+		_, approved, err := service.Perform(ctx, actionToken(approveURL), "  Enjoy  ")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if approved.Status != models.LeaveStatusApproved || approved.GetManagerComment() != "Enjoy" {
+			t.Errorf("expected the request approved with the comment, got %s %q", approved.Status, approved.GetManagerComment())
+		}
+
+		// Neither link of the email works any more
+		if _, _, err := service.Perform(ctx, actionToken(approveURL), ""); !errors.Is(err, ErrInvalidStatus) {
+			t.Errorf("expected ErrInvalidStatus reusing the link, got %v", err)
+		}
+		if _, current, err := service.Preview(ctx, actionToken(rejectURL)); !errors.Is(err, ErrInvalidStatus) || current.Status != models.LeaveStatusApproved {
+			t.Errorf("expected ErrInvalidStatus with the approved request, got %v", err)
+		}
+	})
+
+	t.Run("rejects with a comment", func(t *testing.T) {
+		leave := pendingLeave(repo)
+		_, rejectURL := service.Links(leave, "manager@example.com")
+
+		var validationErr *utils.ValidationError
+		if _, _, err := service.Perform(ctx, actionToken(rejectURL), "No"); !errors.As(err, &validationErr) {
+			t.Fatalf("expected a validation error for a short comment, got %v", err)
+		}
+		_, rejected, err := service.Perform(ctx, actionToken(rejectURL), "Team is short-staffed that week")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rejected.Status != models.LeaveStatusRejected {
+			t.Errorf("expected the request rejected, got %s", rejected.Status)
+		}
+	})
+
+	t.Run("refuses a changed request", func(t *testing.T) {
+		leave := pendingLeave(repo)
+		approveURL, _ := service.Links(leave, "manager@example.com")
+
+		// The employee edits the request after the email was sent
+		if _, err := service.leaveService.UpdateLeaveRequest(ctx, leave.ID, AnyVersion, "emp-1", &models.UpdateLeaveRequest{Reason: "Longer trip"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, _, err := service.Perform(ctx, actionToken(approveURL), ""); !errors.Is(err, ErrPreconditionFailed) {
+			t.Errorf("expected ErrPreconditionFailed, got %v", err)
+		}
+		current, _ := service.leaveService.GetLeaveRequestByID(ctx, leave.ID)
+		if current.Status != models.LeaveStatusPending {
+			t.Errorf("expected the request to stay pending, got %s", current.Status)
+		}
+	})
+
+	t.Run("refuses the approver's own request", func(t *testing.T) {
+		leave := &models.LeaveRequest{ID: uuid.New(), EmployeeID: "mgr-1", EmployeeEmail: "Manager@example.com", Status: models.LeaveStatusPending, CreatedAt: time.Now()}
+		repo.Create(ctx, leave)
+		approveURL, _ := service.Links(leave, "manager@example.com")
+
+		_, shown, err := service.Perform(ctx, actionToken(approveURL), "")
+		if !errors.Is(err, ErrSelfReview) {
+			t.Errorf("expected ErrSelfReview, got %v", err)
+		}
+		if shown == nil || shown.ID != leave.ID {
+			t.Errorf("expected the previewed request kept for the page, got %+v", shown)
+		}
+	})
+
+	t.Run("hides drafts", func(t *testing.T) {
+		draft := &models.LeaveRequest{ID: uuid.New(), EmployeeID: "emp-1", Status: models.LeaveStatusDraft, CreatedAt: time.Now()}
+		repo.Create(ctx, draft)
+		approveURL, _ := service.Links(draft, "manager@example.com")
+		if _, _, err := service.Perform(ctx, actionToken(approveURL), ""); !errors.Is(err, ErrLeaveNotFound) {
+			t.Errorf("expected ErrLeaveNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"sync"
+
+	"leave-management-system/internal/logger"
+	"leave-management-system/internal/models"
+)
+
+// leaveEventBuffer is how many events a subscriber can fall behind by before
+// its subscription is closed
+const leaveEventBuffer = 32
+
+// LeaveEventBroker fans the leave changes committed by any server out to the
+// real-time subscribers of this one, each getting only the changes to
+// requests they can see. A subscription that cannot be kept up to date, as
+// its subscriber falls behind or changes may have been missed, is closed
+// instead; the subscriber reconnects and reloads what it shows.
+type LeaveEventBroker struct {
+	mu          sync.Mutex
+	subscribers map[*LeaveSubscription]struct{}
+	closed      bool
+	logger      *logger.Logger
+}
+
+// LeaveSubscription receives the leave events one user can see
+type LeaveSubscription struct {
+	userID string
+	roles  []string
+	events chan *models.LeaveEvent
+}
+
+// Events returns the subscription's events. It is closed when the
+// subscription ends.
+func (s *LeaveSubscription) Events() <-chan *models.LeaveEvent {
+	return s.events
+}
+
+// NewLeaveEventBroker creates a new leave event broker
+func NewLeaveEventBroker() *LeaveEventBroker {
+	return &LeaveEventBroker{
+		subscribers: make(map[*LeaveSubscription]struct{}),
+		logger:      logger.New().With("component", "events"),
+	}
+}
+
+// Subscribe subscribes the user with roles to the changes they can see
+func (b *LeaveEventBroker) Subscribe(userID string, roles []string) *LeaveSubscription {
+	sub := &LeaveSubscription{
+		userID: userID,
+		roles:  roles,
+		events: make(chan *models.LeaveEvent, leaveEventBuffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(sub.events)
+		return sub
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe ends sub; ending it again does nothing
+func (b *LeaveEventBroker) Unsubscribe(sub *LeaveSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub)
+}
+
+// Publish sends change to the subscribers who can see the request
+func (b *LeaveEventBroker) Publish(change *models.LeaveChange) {
+	// Approvers never saw a draft that was cancelled
+	visible := &models.LeaveRequest{EmployeeID: change.EmployeeID, Status: change.Status}
+	if change.Status == models.LeaveStatusCancelled && change.PreviousStatus == models.LeaveStatusDraft {
+		visible.Status = models.LeaveStatusDraft
+	}
+	event := change.Event()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers {
+		if !canViewLeave(visible, sub.userID, sub.roles) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			b.logger.Warnf("leave_subscription_closed reason=slow_subscriber user_id=%s", sub.userID)
+			b.remove(sub)
+		}
+	}
+}
+
+// Reset closes every subscription, as changes may have been missed
+func (b *LeaveEventBroker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.subscribers) > 0 {
+		b.logger.Infof("leave_subscriptions_reset count=%d", len(b.subscribers))
+	}
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+// Close closes every subscription, and those made later, so the streams
+// serving them end on shutdown
+func (b *LeaveEventBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+// remove ends sub if it is still subscribed; b.mu must be held
+func (b *LeaveEventBroker) remove(sub *LeaveSubscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
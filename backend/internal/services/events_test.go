@@ -0,0 +1,92 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+)
+
+func TestLeaveChange_Event(t *testing.T) {
+	tests := []struct {
+		op       string
+		from, to models.LeaveStatus
+		want     models.LeaveEventType
+	}{
+		{"INSERT", "", models.LeaveStatusPending, models.LeaveEventCreated},
+		{"INSERT", "", models.LeaveStatusDraft, models.LeaveEventCreated},
+		{"UPDATE", models.LeaveStatusDraft, models.LeaveStatusPending, models.LeaveEventUpdated},
+		{"UPDATE", models.LeaveStatusPending, models.LeaveStatusPending, models.LeaveEventUpdated},
+		{"UPDATE", models.LeaveStatusPending, models.LeaveStatusApproved, models.LeaveEventApproved},
+		{"UPDATE", models.LeaveStatusPending, models.LeaveStatusRejected, models.LeaveEventRejected},
+		{"UPDATE", models.LeaveStatusApproved, models.LeaveStatusCancelled, models.LeaveEventCancelled},
+		{"UPDATE", models.LeaveStatusApproved, models.LeaveStatusApproved, models.LeaveEventUpdated},
+	}
+	for _, tt := range tests {
+		change := &models.LeaveChange{Operation: tt.op, ID: uuid.New(), PreviousStatus: tt.from, Status: tt.to, Version: 2}
+		event := change.Event()
+		if event.Type != tt.want || event.LeaveRequestID != change.ID || event.Version != 2 {
+			t.Errorf("%s %s to %s: expected %s, got %+v", tt.op, tt.from, tt.to, tt.want, event)
+		}
+	}
+}
+
+func TestLeaveEventBroker(t *testing.T) {
+	broker := NewLeaveEventBroker()
+	owner := broker.Subscribe("emp-1", nil)
+	colleague := broker.Subscribe("emp-2", nil)
+	manager := broker.Subscribe("mgr-1", []string{RoleManager})
+	hr := broker.Subscribe("hr-1", []string{RoleHR})
+
+	received := func(sub *LeaveSubscription) []models.LeaveEventType {
+		var types []models.LeaveEventType
+		for {
+			select {
+			case event, ok := <-sub.Events():
+				if !ok {
+					return append(types, "closed")
+				}
+				types = append(types, event.Type)
+			default:
+				return types
+			}
+		}
+	}
+
+	id := uuid.New()
+	broker.Publish(&models.LeaveChange{Operation: "INSERT", ID: id, EmployeeID: "emp-1", Status: models.LeaveStatusDraft})
+	broker.Publish(&models.LeaveChange{Operation: "UPDATE", ID: id, EmployeeID: "emp-1", PreviousStatus: models.LeaveStatusDraft, Status: models.LeaveStatusCancelled})
+	broker.Publish(&models.LeaveChange{Operation: "INSERT", ID: uuid.New(), EmployeeID: "emp-1", Status: models.LeaveStatusPending})
+	broker.Publish(&models.LeaveChange{Operation: "UPDATE", ID: uuid.New(), EmployeeID: "emp-3", PreviousStatus: models.LeaveStatusPending, Status: models.LeaveStatusApproved})
+
+	if got := received(owner); len(got) != 3 || got[0] != models.LeaveEventCreated || got[1] != models.LeaveEventCancelled {
+		t.Errorf("expected the owner to see their draft and request, got %v", got)
+	}
+	if got := received(colleague); len(got) != 0 {
+		t.Errorf("expected a colleague to see nothing, got %v", got)
+	}
+	for name, sub := range map[string]*LeaveSubscription{"manager": manager, "hr": hr} {
+		if got := received(sub); len(got) != 2 || got[0] != models.LeaveEventCreated || got[1] != models.LeaveEventApproved {
+			t.Errorf("expected %s to see everything but the draft, got %v", name, got)
+		}
+	}
+
+	// A subscriber that falls behind is dropped rather than holding others up
+	for i := 0; i <= leaveEventBuffer; i++ {
+		broker.Publish(&models.LeaveChange{Operation: "UPDATE", ID: id, EmployeeID: "emp-2", Status: models.LeaveStatusPending})
+	}
+	if got := received(colleague); len(got) != leaveEventBuffer+1 || got[leaveEventBuffer] != "closed" {
+		t.Errorf("expected the slow subscription closed after %d events, got %d", leaveEventBuffer, len(got))
+	}
+	broker.Unsubscribe(colleague)
+
+	// Missed changes close every subscription, and so does shutdown
+	broker.Reset()
+	if got := received(owner); len(got) != 1 || got[0] != "closed" {
+		t.Errorf("expected the subscription closed on reset, got %v", got)
+	}
+	broker.Close()
+	if got := received(broker.Subscribe("emp-1", nil)); len(got) != 1 || got[0] != "closed" {
+		t.Errorf("expected subscriptions after shutdown closed, got %v", got)
+	}
+}
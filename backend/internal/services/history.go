@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+)
+
+// recordHistory appends a history entry for a change to leave made by actor
+func recordHistory(ctx context.Context, history repository.HistoryRepository, leave *models.LeaveRequest, actor *models.Employee, action models.HistoryAction, note string) error {
+	entry := &models.LeaveHistoryEntry{
+		ID:             uuid.New(),
+		LeaveRequestID: leave.ID,
+		EmployeeID:     leave.EmployeeID,
+		ActorID:        actor.ID,
+		ActorName:      actor.Name,
+		Action:         action,
+		Status:         leave.Status,
+		Note:           note,
+		CreatedAt:      time.Now(),
+	}
+
+	if err := history.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record leave history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/utils"
+)
+
+// HRLeaveService lets HR and admins create, edit and cancel leave on behalf of
+// any employee. Every change is recorded in the leave history with the acting
+// user kept separate from the employee.
+type HRLeaveService struct {
+	leaveService *LeaveService
+	history      repository.HistoryRepository
+}
+
+// NewHRLeaveService creates a new HR leave service sharing the unit of work of leaveService
+func NewHRLeaveService(leaveService *LeaveService) *HRLeaveService {
+	return &HRLeaveService{
+		leaveService: leaveService,
+		history:      leaveService.uow.History(),
+	}
+}
+
+// CreateLeaveRequest records leave for an employee, optionally already
+// approved and optionally back-dated
+func (s *HRLeaveService) CreateLeaveRequest(ctx context.Context, req *models.OnBehalfCreateLeaveRequest, actor *models.Employee) (*models.LeaveRequest, error) {
+	if strings.TrimSpace(req.EmployeeID) == "" || strings.TrimSpace(req.EmployeeEmail) == "" {
+		return nil, &utils.ValidationError{Message: "employeeId and employeeEmail are required"}
+	}
+
+	leaveType := models.LeaveType(req.LeaveType)
+	if !leaveType.IsValid() {
+		return nil, errInvalidLeaveType
+	}
+
+	loc := s.leaveService.policy.Location(req.EmployeeTimezone)
+	days, err := s.validateDates(leaveType, req.StartDate, req.EndDate, loc, req.AllowPastDates)
+	if err != nil {
+		return nil, err
+	}
+
+	status := models.LeaveStatusPending
+	if req.Approved {
+		status = models.LeaveStatusApproved
+	}
+
+	now := time.Now()
+	leave := &models.LeaveRequest{
+		ID:            uuid.New(),
+		EmployeeID:    req.EmployeeID,
+		EmployeeName:  req.EmployeeName,
+		EmployeeEmail: req.EmployeeEmail,
+		Department:    req.Department,
+		LeaveType:     leaveType,
+		Reason:        req.Reason,
+		StartDate:     req.StartDate,
+		EndDate:       req.EndDate,
+		Timezone:      loc.String(),
+		Days:          days,
+		Status:        status,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if comment := strings.TrimSpace(req.Comment); comment != "" {
+		leave.ManagerComment.String, leave.ManagerComment.Valid = comment, true
+	}
+
+	err = s.leaveService.uow.WithinTx(ctx, func(tx repository.Store) error {
+		if err := tx.Leaves().Create(ctx, leave); err != nil {
+			return fmt.Errorf("failed to create leave request: %w", err)
+		}
+		return recordHistory(ctx, tx.History(), leave, actor, models.HistoryActionCreated, leave.GetManagerComment())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return leave, nil
+}
+
+// UpdateLeaveRequest edits an employee's pending or approved leave. The
+// employee's calendar is updated when approved leave is moved.
+func (s *HRLeaveService) UpdateLeaveRequest(ctx context.Context, id uuid.UUID, req *models.OnBehalfUpdateLeaveRequest, actor *models.Employee) (*models.LeaveRequest, error) {
+	existing, err := s.leaveService.GetLeaveRequestByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.Status != models.LeaveStatusPending && existing.Status != models.LeaveStatusApproved {
+		return nil, fmt.Errorf("%w: cannot update %s request", ErrInvalidStatus, existing.Status)
+	}
+
+	updated := *existing
+	if req.LeaveType != "" {
+		updated.LeaveType = models.LeaveType(req.LeaveType)
+		if !updated.LeaveType.IsValid() {
+			return nil, errInvalidLeaveType
+		}
+	}
+	if req.Reason != "" {
+		updated.Reason = req.Reason
+	}
+	if req.StartDate != nil {
+		updated.StartDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		updated.EndDate = *req.EndDate
+	}
+
+	if req.StartDate != nil || req.EndDate != nil {
+		loc := s.leaveService.policy.Location(existing.Timezone)
+		updated.Days, err = s.validateDates(updated.LeaveType, updated.StartDate, updated.EndDate, loc, req.AllowPastDates)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.leaveService.uow.WithinTx(ctx, func(tx repository.Store) error {
+		if err := tx.Leaves().Update(ctx, &updated); err != nil {
+			return writeError(err, "update")
+		}
+		if err := recordHistory(ctx, tx.History(), &updated, actor, models.HistoryActionUpdated, ""); err != nil {
+			return err
+		}
+		return queueCalendarChange(ctx, tx, existing, &updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// CancelLeaveRequest cancels an employee's draft, pending or approved leave.
+// Approved leave is removed from the employee's calendar.
+func (s *HRLeaveService) CancelLeaveRequest(ctx context.Context, id uuid.UUID, reason string, actor *models.Employee) error {
+	existing, err := s.leaveService.GetLeaveRequestByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	cancellable := []models.LeaveStatus{models.LeaveStatusDraft, models.LeaveStatusPending, models.LeaveStatusApproved}
+	if !statusIn(existing.Status, cancellable) {
+		return fmt.Errorf("%w: cannot cancel %s request", ErrInvalidStatus, existing.Status)
+	}
+
+	reason = strings.TrimSpace(reason)
+	return s.leaveService.uow.WithinTx(ctx, func(tx repository.Store) error {
+		cancelled, err := tx.Leaves().TransitionStatus(ctx, id, existing.Version, cancellable, models.LeaveStatusCancelled, reason)
+		if err != nil {
+			return writeError(err, "cancel")
+		}
+		if err := recordHistory(ctx, tx.History(), cancelled, actor, models.HistoryActionCancelled, reason); err != nil {
+			return err
+		}
+		return queueCalendarChange(ctx, tx, existing, cancelled)
+	})
+}
+
+// GetHistory returns the recorded history of a leave request, oldest first
+func (s *HRLeaveService) GetHistory(ctx context.Context, id uuid.UUID) ([]*models.LeaveHistoryEntry, error) {
+	if _, err := s.leaveService.GetLeaveRequestByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.history.FindByLeaveRequestID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leave history: %w", err)
+	}
+	return entries, nil
+}
+
+// validateDates checks a date range entered by HR. Back-dated leave skips the
+// past-date and notice checks but must still be a valid range with working days.
+func (s *HRLeaveService) validateDates(leaveType models.LeaveType, start, end models.Date, loc *time.Location, allowPast bool) (int, error) {
+	if !allowPast {
+		return s.leaveService.validateForSubmission(leaveType, start, end, loc)
+	}
+
+	if start.IsZero() || end.IsZero() {
+		return 0, errDatesRequired
+	}
+	if err := utils.ValidateDateRange(start, end); err != nil {
+		return 0, fmt.Errorf("invalid date range: %w", err)
+	}
+
+	days := models.CalculateDays(start, end)
+	if days <= 0 {
+		return 0, errNoWorkingDays
+	}
+	return days, nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/utils"
+)
+
+func TestHRLeaveService(t *testing.T) {
+	history := repository.NewMockHistoryRepository()
+	service := NewHRLeaveService(NewLeaveService(repository.NewMockUnitOfWork(nil, history), DefaultLeavePolicy()))
+	actor := &models.Employee{ID: "hr-1", Name: "Helen HR"}
+
+	lastWeek := models.Today(time.UTC).AddDays(-7)
+	for lastWeek.Weekday() != time.Monday {
+		lastWeek = lastWeek.AddDays(-1)
+	}
+
+	req := &models.OnBehalfCreateLeaveRequest{
+		EmployeeID:    "emp-1",
+		EmployeeName:  "John Doe",
+		EmployeeEmail: "john@example.com",
+		LeaveType:     "sick",
+		Reason:        "Phoned in sick",
+		StartDate:     lastWeek,
+		EndDate:       lastWeek.AddDays(1),
+		Approved:      true,
+	}
+
+	// Back-dating must be requested explicitly
+	if _, err := service.CreateLeaveRequest(context.Background(), req, actor); !errors.Is(err, utils.ErrDateInPast) {
+		t.Fatalf("expected ErrDateInPast, got %v", err)
+	}
+
+	req.AllowPastDates = true
+	leave, err := service.CreateLeaveRequest(context.Background(), req, actor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if leave.EmployeeID != "emp-1" || leave.Status != models.LeaveStatusApproved || leave.Days != 2 {
+		t.Errorf("unexpected leave: employee=%s status=%s days=%d", leave.EmployeeID, leave.Status, leave.Days)
+	}
+
+	reason := "Returned a day early"
+	if _, err := service.UpdateLeaveRequest(context.Background(), leave.ID, &models.OnBehalfUpdateLeaveRequest{
+		UpdateLeaveRequest: models.UpdateLeaveRequest{Reason: reason, EndDate: datePtr(lastWeek)},
+		AllowPastDates:     true,
+	}, actor); err != nil {
+		t.Fatalf("unexpected error updating: %v", err)
+	}
+
+	if err := service.CancelLeaveRequest(context.Background(), leave.ID, "Entered for the wrong employee", actor); err != nil {
+		t.Fatalf("unexpected error cancelling: %v", err)
+	}
+	if err := service.CancelLeaveRequest(context.Background(), leave.ID, "", actor); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("expected ErrInvalidStatus cancelling twice, got %v", err)
+	}
+
+	entries, err := service.GetHistory(context.Background(), leave.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantActions := []models.HistoryAction{models.HistoryActionCreated, models.HistoryActionUpdated, models.HistoryActionCancelled}
+	if len(entries) != len(wantActions) {
+		t.Fatalf("expected %d history entries, got %d", len(wantActions), len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Action != wantActions[i] {
+			t.Errorf("entry %d: expected action %s, got %s", i, wantActions[i], entry.Action)
+		}
+		if entry.ActorID != "hr-1" || entry.EmployeeID != "emp-1" || !entry.OnBehalf() {
+			t.Errorf("entry %d: expected actor hr-1 on behalf of emp-1, got actor %s employee %s", i, entry.ActorID, entry.EmployeeID)
+		}
+	}
+}
+
+func TestHRLeaveService_QueuesCalendarChanges(t *testing.T) {
+	uow := repository.NewMockUnitOfWork(nil, nil)
+	service := NewHRLeaveService(NewLeaveService(uow, DefaultLeavePolicy()))
+	actor := &models.Employee{ID: "hr-1", Name: "Helen HR"}
+	ctx := context.Background()
+	monday := nextMonday()
+
+	leave, err := service.CreateLeaveRequest(ctx, &models.OnBehalfCreateLeaveRequest{
+		EmployeeID:    "emp-1",
+		EmployeeName:  "John Doe",
+		EmployeeEmail: "john@example.com",
+		LeaveType:     "annual",
+		StartDate:     monday,
+		EndDate:       monday.AddDays(2),
+		Approved:      true,
+	}, actor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Changing only the reason leaves the calendar event as it is
+	if _, err := service.UpdateLeaveRequest(ctx, leave.ID, &models.OnBehalfUpdateLeaveRequest{
+		UpdateLeaveRequest: models.UpdateLeaveRequest{Reason: "Trip"},
+	}, actor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.UpdateLeaveRequest(ctx, leave.ID, &models.OnBehalfUpdateLeaveRequest{
+		UpdateLeaveRequest: models.UpdateLeaveRequest{EndDate: datePtr(monday.AddDays(1))},
+	}, actor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.CancelLeaveRequest(ctx, leave.ID, "Trip called off", actor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	messages, _ := uow.OutboxRepo.FindAll(ctx, models.OutboxFilter{Topic: models.OutboxTopicLeaveCalendar})
+	if len(messages) != 2 {
+		t.Fatalf("expected two queued calendar changes, got %d", len(messages))
+	}
+	// Newest first
+	want := []models.CalendarChangeAction{models.CalendarChangeWithdrawn, models.CalendarChangeRescheduled}
+	for i, msg := range messages {
+		var change models.CalendarChange
+		if err := json.Unmarshal(msg.Payload, &change); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		if change.Action != want[i] || change.Leave.ID != leave.ID || change.Leave.EndDate != monday.AddDays(1) {
+			t.Errorf("message %d: expected %s of the moved leave, got %s ending %s", i, want[i], change.Action, change.Leave.EndDate)
+		}
+	}
+}
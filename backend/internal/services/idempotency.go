@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+)
+
+var (
+	ErrInvalidIdempotencyKey    = errors.New("Idempotency-Key must be 1 to 255 printable ASCII characters")
+	ErrIdempotencyKeyReused     = errors.New("Idempotency-Key was already used for a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this Idempotency-Key is still being processed")
+)
+
+// IdempotencyService lets clients retry a request safely by sending the same
+// Idempotency-Key header. The first request with a key is processed and its
+// response stored; retries within the TTL receive the stored response.
+type IdempotencyService struct {
+	repo  repository.IdempotencyRepository
+	ttl   time.Duration
+	lease time.Duration
+}
+
+// NewIdempotencyService creates a new idempotency service that keeps
+// responses for ttl. A key claimed by a request that neither completed nor
+// released it within lease can be claimed again.
+func NewIdempotencyService(repo repository.IdempotencyRepository, ttl, lease time.Duration) *IdempotencyService {
+	return &IdempotencyService{repo: repo, ttl: ttl, lease: lease}
+}
+
+// Begin claims key for a request whose method, path and body hash to
+// requestHash. It returns nil when the caller should process the request and
+// then call Complete or Release, or the completed key whose response should be
+// replayed.
+func (s *IdempotencyService) Begin(ctx context.Context, userID, key, requestHash string) (*models.IdempotencyKey, error) {
+	if !validIdempotencyKey(key) {
+		return nil, ErrInvalidIdempotencyKey
+	}
+
+	now := time.Now()
+	reserved, err := s.repo.Reserve(ctx, &models.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+	}, now.Add(-s.ttl), now.Add(-s.lease))
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	existing, err := s.repo.Find(ctx, userID, key)
+	if errors.Is(err, repository.ErrNotFound) {
+		// The original request failed and released the key while we looked
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !existing.Completed() {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	return existing, nil
+}
+
+// Complete stores the response to the request that claimed key
+func (s *IdempotencyService) Complete(ctx context.Context, userID, key, requestHash string, statusCode int, headers map[string]string, body []byte) error {
+	return s.repo.Complete(ctx, &models.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+		StatusCode:  statusCode,
+		Headers:     headers,
+		Body:        body,
+	})
+}
+
+// Release gives up a claimed key without storing a response, so the request
+// can be retried with the same key after it failed
+func (s *IdempotencyService) Release(ctx context.Context, userID, key string) error {
+	return s.repo.Delete(ctx, userID, key)
+}
+
+// PruneExpired removes keys older than the TTL and returns how many were removed
+func (s *IdempotencyService) PruneExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, time.Now().Add(-s.ttl))
+}
+
+// validIdempotencyKey reports whether key is a usable Idempotency-Key value
+func validIdempotencyKey(key string) bool {
+	if key == "" || len(key) > models.MaxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
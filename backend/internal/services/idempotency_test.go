@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+)
+
+func TestIdempotencyService_BeginAndReplay(t *testing.T) {
+	repo := repository.NewMockIdempotencyRepository()
+	service := NewIdempotencyService(repo, 24*time.Hour, 2*time.Minute)
+	ctx := context.Background()
+
+	stored, err := service.Begin(ctx, "emp-1", "key-1", "hash-a")
+	if err != nil || stored != nil {
+		t.Fatalf("expected the first request to claim the key, got %v, %v", stored, err)
+	}
+
+	if _, err := service.Begin(ctx, "emp-1", "key-1", "hash-a"); !errors.Is(err, ErrIdempotencyKeyInProgress) {
+		t.Errorf("expected ErrIdempotencyKeyInProgress while the first request runs, got %v", err)
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	if err := service.Complete(ctx, "emp-1", "key-1", "hash-a", 201, headers, []byte(`{"id":"1"}`)); err != nil {
+		t.Fatalf("unexpected error completing: %v", err)
+	}
+
+	stored, err = service.Begin(ctx, "emp-1", "key-1", "hash-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored == nil || stored.StatusCode != 201 || string(stored.Body) != `{"id":"1"}` {
+		t.Fatalf("expected the stored 201 response, got %+v", stored)
+	}
+
+	if _, err := service.Begin(ctx, "emp-1", "key-1", "hash-b"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("expected ErrIdempotencyKeyReused for a different body, got %v", err)
+	}
+
+	// Keys are scoped to the user who sent them
+	if stored, err := service.Begin(ctx, "emp-2", "key-1", "hash-b"); err != nil || stored != nil {
+		t.Errorf("expected another user to claim the same key, got %v, %v", stored, err)
+	}
+}
+
+func TestIdempotencyService_Release(t *testing.T) {
+	service := NewIdempotencyService(repository.NewMockIdempotencyRepository(), 24*time.Hour, 2*time.Minute)
+	ctx := context.Background()
+
+	if _, err := service.Begin(ctx, "emp-1", "key-1", "hash-a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.Release(ctx, "emp-1", "key-1"); err != nil {
+		t.Fatalf("unexpected error releasing: %v", err)
+	}
+
+	// A failed request can be retried, even with a corrected body
+	if stored, err := service.Begin(ctx, "emp-1", "key-1", "hash-b"); err != nil || stored != nil {
+		t.Errorf("expected the released key to be claimable, got %v, %v", stored, err)
+	}
+}
+
+func TestIdempotencyService_Expiry(t *testing.T) {
+	repo := repository.NewMockIdempotencyRepository()
+	service := NewIdempotencyService(repo, 24*time.Hour, 2*time.Minute)
+	ctx := context.Background()
+
+	old := &models.IdempotencyKey{UserID: "emp-1", Key: "key-1", RequestHash: "hash-a", CreatedAt: time.Now().Add(-25 * time.Hour)}
+	repo.Reserve(ctx, old, old.CreatedAt, old.CreatedAt)
+	repo.Complete(ctx, &models.IdempotencyKey{UserID: "emp-1", Key: "key-1", RequestHash: "hash-a", StatusCode: 201})
+
+	if stored, err := service.Begin(ctx, "emp-1", "key-1", "hash-b"); err != nil || stored != nil {
+		t.Fatalf("expected an expired key to be claimable, got %v, %v", stored, err)
+	}
+
+	stale := &models.IdempotencyKey{UserID: "emp-1", Key: "key-2", RequestHash: "hash-a", CreatedAt: time.Now().Add(-48 * time.Hour)}
+	repo.Reserve(ctx, stale, stale.CreatedAt, stale.CreatedAt)
+	removed, err := service.PruneExpired(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error pruning: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("expected 1 expired key removed, got %d", removed)
+	}
+}
+
+func TestIdempotencyService_AbandonedKey(t *testing.T) {
+	repo := repository.NewMockIdempotencyRepository()
+	service := NewIdempotencyService(repo, 24*time.Hour, 2*time.Minute)
+	ctx := context.Background()
+
+	// A request claimed the key and never finished, e.g. the server crashed
+	abandoned := &models.IdempotencyKey{UserID: "emp-1", Key: "key-1", RequestHash: "hash-a", CreatedAt: time.Now().Add(-5 * time.Minute)}
+	repo.Reserve(ctx, abandoned, abandoned.CreatedAt, abandoned.CreatedAt)
+
+	if stored, err := service.Begin(ctx, "emp-1", "key-1", "hash-a"); err != nil || stored != nil {
+		t.Fatalf("expected the abandoned key to be claimable, got %v, %v", stored, err)
+	}
+
+	// A completed response of the same age is still replayed
+	completed := &models.IdempotencyKey{UserID: "emp-1", Key: "key-2", RequestHash: "hash-a", CreatedAt: time.Now().Add(-5 * time.Minute)}
+	repo.Reserve(ctx, completed, completed.CreatedAt, completed.CreatedAt)
+	repo.Complete(ctx, &models.IdempotencyKey{UserID: "emp-1", Key: "key-2", RequestHash: "hash-a", StatusCode: 201})
+
+	if stored, err := service.Begin(ctx, "emp-1", "key-2", "hash-a"); err != nil || stored == nil || stored.StatusCode != 201 {
+		t.Errorf("expected the completed response to be replayed, got %v, %v", stored, err)
+	}
+}
+
+func TestIdempotencyService_InvalidKey(t *testing.T) {
+	service := NewIdempotencyService(repository.NewMockIdempotencyRepository(), 24*time.Hour, 2*time.Minute)
+
+	for _, key := range []string{"", "has space", "tab\there", "ключ", strings.Repeat("k", models.MaxIdempotencyKeyLength+1)} {
+		if _, err := service.Begin(context.Background(), "emp-1", key, "hash"); !errors.Is(err, ErrInvalidIdempotencyKey) {
+			t.Errorf("expected ErrInvalidIdempotencyKey for %q, got %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/notify"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/utils"
+)
+
+// ErrNotificationNotFound is returned for notifications missing from the caller's inbox
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// InboxService serves each user's in-app notifications. Inboxes are keyed by
+// email address, as notifications are addressed.
+type InboxService struct {
+	repo repository.NotificationRepository
+}
+
+// NewInboxService creates a new inbox service
+func NewInboxService(repo repository.NotificationRepository) *InboxService {
+	return &InboxService{repo: repo}
+}
+
+// deliveryRetentionMargin is how much longer than the outbox may retry a
+// notification its deliveries are kept
+const deliveryRetentionMargin = 24 * time.Hour
+
+// PruneDeliveries removes the delivery records of notifications the outbox
+// can no longer retry, given its retryHorizon, and returns how many were
+// removed
+func (s *InboxService) PruneDeliveries(ctx context.Context, retryHorizon time.Duration) (int64, error) {
+	return s.repo.PruneDeliveries(ctx, time.Now().Add(-retryHorizon-deliveryRetentionMargin))
+}
+
+// ListNotifications returns a page of the employee's inbox, newest first
+func (s *InboxService) ListNotifications(ctx context.Context, employee *models.Employee, filter models.NotificationFilter) (*models.NotificationPage, error) {
+	filter.Recipient = inboxOf(employee.Email)
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageLimit
+	}
+	if filter.Limit > MaxPageLimit {
+		filter.Limit = MaxPageLimit
+	}
+
+	page, err := s.repo.List(ctx, filter)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, &utils.ValidationError{Message: "invalid cursor"}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	return page, nil
+}
+
+// UnreadCount counts the unread notifications in the employee's inbox
+func (s *InboxService) UnreadCount(ctx context.Context, employee *models.Employee) (*models.UnreadCount, error) {
+	count, err := s.repo.CountUnread(ctx, inboxOf(employee.Email))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return &models.UnreadCount{Unread: count}, nil
+}
+
+// MarkRead marks one of the employee's notifications as read. Marking a
+// notification already read keeps when it was first read.
+func (s *InboxService) MarkRead(ctx context.Context, employee *models.Employee, id uuid.UUID) (*models.Notification, error) {
+	notification, err := s.repo.MarkRead(ctx, inboxOf(employee.Email), id, time.Now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotificationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	return notification, nil
+}
+
+// MarkAllRead marks every unread notification of the employee as read
+func (s *InboxService) MarkAllRead(ctx context.Context, employee *models.Employee) (*models.MarkedRead, error) {
+	n, err := s.repo.MarkAllRead(ctx, inboxOf(employee.Email), time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return &models.MarkedRead{Updated: n}, nil
+}
+
+// inboxOf returns the inbox of email. Nothing is addressed to the empty
+// inbox, so people without an email address have no notifications.
+func inboxOf(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// InboxNotifier keeps a copy of each notification in the in-app inbox of
+// every recipient, then hands it to the next notifier. The inbox gets
+// notifications however they are routed and whatever the recipients'
+// channel preferences, except the daily digest, which only repeats them.
+type InboxNotifier struct {
+	next notify.Notifier
+	repo repository.NotificationRepository
+}
+
+// NewInboxNotifier creates a notifier keeping the notifications delivered by
+// next in repo
+func NewInboxNotifier(next notify.Notifier, repo repository.NotificationRepository) *InboxNotifier {
+	return &InboxNotifier{next: next, repo: repo}
+}
+
+// Notify stores n in its recipients' inboxes and delivers it. Delivery goes
+// ahead when storing fails, so the two fail independently; a redelivery
+// after either failure does not add n to an inbox twice.
+func (i *InboxNotifier) Notify(ctx context.Context, n *notify.Notification) error {
+	var stored error
+	if n.Event != notify.EventDailyDigest {
+		stored = i.store(ctx, n)
+	}
+	return errors.Join(stored, i.next.Notify(ctx, n))
+}
+
+// store adds n to the inbox of each of its recipients
+func (i *InboxNotifier) store(ctx context.Context, n *notify.Notification) error {
+	key, err := n.Key()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	leaveID := leaveRequestOf(n.Data)
+	for _, to := range n.To {
+		recipient := inboxOf(to)
+		if recipient == "" {
+			continue
+		}
+		err := i.repo.Create(ctx, &models.Notification{
+			ID:             uuid.New(),
+			Recipient:      recipient,
+			Event:          string(n.Event),
+			Subject:        n.Subject,
+			Summary:        n.Summary,
+			LeaveRequestID: leaveID,
+			CreatedAt:      now,
+			Key:            key,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to store in-app notification: %w", err)
+		}
+	}
+	return nil
+}
+
+// leaveRequestOf returns the ID of the leave request a notification's data
+// is about, or nil when it is about none or several
+func leaveRequestOf(data interface{}) *uuid.UUID {
+	var id uuid.UUID
+	switch d := data.(type) {
+	case *models.LeaveRequest:
+		id = d.ID
+	case []*models.LeaveRequest:
+		if len(d) == 1 {
+			id = d[0].ID
+		}
+	case *models.LeaveActivity:
+		if d.Leave != nil {
+			id = d.Leave.ID
+		}
+	case *models.CalendarChange:
+		if d.Leave != nil {
+			id = d.Leave.ID
+		}
+	case *models.LeaveComment:
+		id = d.LeaveRequestID
+	}
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
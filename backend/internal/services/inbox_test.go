@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/notify"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/utils"
+)
+
+func TestInboxNotifier(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMockNotificationRepository()
+	next := &recordingNotifier{err: errors.New("smtp down")}
+	notifier := NewInboxNotifier(next, repo)
+	inbox := NewInboxService(repo)
+
+	leave := testLeave(models.LeaveStatusApproved, "")
+	leave.ID = uuid.New()
+	approved := &notify.Notification{Event: notify.EventLeaveApproved, To: []string{"John@Example.com"}, Subject: "Leave Approved", Summary: "John's leave was approved", Data: leave}
+
+	// Kept in the inbox even when delivery fails, and only once when redelivered
+	for i := 0; i < 2; i++ {
+		if err := notifier.Notify(ctx, approved); err == nil {
+			t.Fatal("expected the delivery error")
+		}
+	}
+	if len(next.sent) != 2 {
+		t.Errorf("expected both deliveries attempted, got %d", len(next.sent))
+	}
+
+	next.err = nil
+	comment := &models.LeaveComment{ID: uuid.New(), LeaveRequestID: leave.ID, Body: "Enjoy"}
+	notifier.Notify(ctx, &notify.Notification{Event: notify.EventCommentAdded, To: []string{"john@example.com", "jane@example.com"}, Subject: "New Comment", Data: comment})
+	notifier.Notify(ctx, &notify.Notification{Event: notify.EventLeaveReviewed, To: []string{"john@example.com"}, Subject: "Leave Reviewed", Data: []*models.LeaveRequest{leave, testLeave(models.LeaveStatusRejected, "")}})
+	notifier.Notify(ctx, &notify.Notification{Event: notify.EventDailyDigest, To: []string{"john@example.com"}, Subject: "Digest"})
+	notifier.Notify(ctx, &notify.Notification{Event: notify.EventLeaveSubmitted, Subject: "Shared mailbox only"})
+
+	john := &models.Employee{ID: "emp-1", Email: "john@example.com"}
+	page, err := inbox.ListNotifications(ctx, john, models.NotificationFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total != 3 || len(page.Items) != 3 {
+		t.Fatalf("expected 3 notifications for John, got %d", page.Total)
+	}
+	byEvent := make(map[string]*models.Notification)
+	for _, n := range page.Items {
+		byEvent[n.Event] = n
+	}
+	if n := byEvent["leave.approved"]; n == nil || n.Summary != "John's leave was approved" || n.LeaveRequestID == nil || *n.LeaveRequestID != leave.ID {
+		t.Errorf("expected the approval about the leave, got %+v", n)
+	}
+	if n := byEvent["comment.added"]; n == nil || n.LeaveRequestID == nil || *n.LeaveRequestID != leave.ID {
+		t.Errorf("expected the comment about the leave, got %+v", n)
+	}
+	if n := byEvent["leave.reviewed"]; n == nil || n.LeaveRequestID != nil {
+		t.Errorf("expected a review of several requests about none, got %+v", n)
+	}
+
+	jane := &models.Employee{ID: "mgr-1", Email: "jane@example.com"}
+	if count, _ := inbox.UnreadCount(ctx, jane); count.Unread != 1 {
+		t.Errorf("expected 1 unread notification for Jane, got %d", count.Unread)
+	}
+	if page, _ := inbox.ListNotifications(ctx, &models.Employee{ID: "emp-9"}, models.NotificationFilter{}); page.Total != 0 {
+		t.Errorf("expected no notifications without an email address, got %d", page.Total)
+	}
+}
+
+func TestInboxService_Read(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMockNotificationRepository()
+	notifier := NewInboxNotifier(&recordingNotifier{}, repo)
+	inbox := NewInboxService(repo)
+	john := &models.Employee{ID: "emp-1", Email: "john@example.com"}
+
+	for _, subject := range []string{"First", "Second", "Third"} {
+		notifier.Notify(ctx, &notify.Notification{Event: notify.EventCommentAdded, To: []string{"john@example.com"}, Subject: subject})
+	}
+
+	// Paging walks the whole inbox once
+	page, err := inbox.ListNotifications(ctx, john, models.NotificationFilter{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Items) != 2 || page.Total != 3 || page.NextCursor == "" {
+		t.Fatalf("expected a first page of 2 of 3, got %d of %d", len(page.Items), page.Total)
+	}
+	next, err := inbox.ListNotifications(ctx, john, models.NotificationFilter{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(next.Items) != 1 || next.NextCursor != "" || next.Items[0].ID == page.Items[0].ID || next.Items[0].ID == page.Items[1].ID {
+		t.Errorf("expected the last notification on the second page, got %+v", next.Items)
+	}
+
+	var validationErr *utils.ValidationError
+	if _, err := inbox.ListNotifications(ctx, john, models.NotificationFilter{Cursor: "bogus"}); !errors.As(err, &validationErr) {
+		t.Errorf("expected a validation error for an invalid cursor, got %v", err)
+	}
+
+	read, err := inbox.MarkRead(ctx, john, page.Items[0].ID)
+	if err != nil || read.ReadAt == nil {
+		t.Fatalf("expected the notification marked read, got %+v, %v", read, err)
+	}
+	again, _ := inbox.MarkRead(ctx, john, page.Items[0].ID)
+	if !again.ReadAt.Equal(*read.ReadAt) {
+		t.Errorf("expected the first read time kept, got %s", again.ReadAt)
+	}
+	if _, err := inbox.MarkRead(ctx, &models.Employee{ID: "mgr-1", Email: "jane@example.com"}, page.Items[1].ID); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("expected someone else's notification not found, got %v", err)
+	}
+
+	unread, _ := inbox.ListNotifications(ctx, john, models.NotificationFilter{Unread: true})
+	if unread.Total != 2 {
+		t.Errorf("expected 2 unread notifications, got %d", unread.Total)
+	}
+	marked, _ := inbox.MarkAllRead(ctx, john)
+	if count, _ := inbox.UnreadCount(ctx, john); marked.Updated != 2 || count.Unread != 0 {
+		t.Errorf("expected 2 marked read and none left, got %d and %d", marked.Updated, count.Unread)
+	}
+}
+
+func TestInboxService_PruneDeliveries(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMockNotificationRepository()
+	inbox := NewInboxService(repo)
+	repo.RecordDelivery(ctx, "key-1", "email", "john@example.com")
+
+	// Kept while the outbox may still retry the notification
+	if removed, err := inbox.PruneDeliveries(ctx, time.Hour); err != nil || removed != 0 {
+		t.Fatalf("expected nothing pruned, got %d (err=%v)", removed, err)
+	}
+	// Pruned once that is over, here by a negative horizon
+	if removed, err := inbox.PruneDeliveries(ctx, -deliveryRetentionMargin-time.Minute); err != nil || removed != 1 {
+		t.Fatalf("expected one delivery pruned, got %d (err=%v)", removed, err)
+	}
+	if delivered, _ := repo.Delivered(ctx, "key-1", "email", "john@example.com"); delivered {
+		t.Errorf("expected the delivery forgotten")
+	}
+}
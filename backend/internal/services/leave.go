@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -9,70 +10,189 @@ import (
 	"github.com/google/uuid"
 	"leave-management-system/internal/models"
 	"leave-management-system/internal/repository"
+	"leave-management-system/internal/utils"
 )
 
 var (
 	ErrLeaveNotFound      = errors.New("leave request not found")
 	ErrUnauthorizedAction = errors.New("unauthorized action")
 	ErrInvalidStatus      = errors.New("invalid status transition")
+	ErrConflict           = errors.New("leave request was changed by another request")
+	// ErrPreconditionFailed is returned when the caller's version of a request is out of date
+	ErrPreconditionFailed = errors.New("leave request has been modified; reload it and try again")
+	// ErrSelfReview is returned when an approver reviews their own request
+	ErrSelfReview = errors.New("approvers cannot review their own leave requests")
+	// ErrNotAssignedApprover is returned when a request assigned to one
+	// approver is reviewed by another
+	ErrNotAssignedApprover = errors.New("leave request is assigned to another approver")
+)
+
+// AnyVersion skips the version check of a write, like If-Match: *
+const AnyVersion = 0
+
+const (
+	// DefaultPageLimit is the page size of listings when no limit is given
+	DefaultPageLimit = 100
+	// MaxPageLimit caps the page size of listings
+	MaxPageLimit = 500
+)
+
+var (
+	// errNoWorkingDays is returned when a date range covers only weekend days
+	errNoWorkingDays = &utils.ValidationError{Message: "invalid date range: leave must include at least one working day"}
+	// errDatesRequired is returned when a request is submitted without dates
+	errDatesRequired = &utils.ValidationError{Message: "start date and end date are required"}
+	// errInvalidLeaveType is returned for a leave type outside models.LeaveType
+	errInvalidLeaveType = &utils.ValidationError{Message: "invalid leave type"}
 )
 
 // LeaveService handles business logic for leave requests
 type LeaveService struct {
-	repo repository.LeaveRepository
+	repo   repository.LeaveRepository
+	uow    repository.UnitOfWork
+	policy LeavePolicy
 }
 
 // NewLeaveService creates a new leave service
-func NewLeaveService(repo repository.LeaveRepository) *LeaveService {
+func NewLeaveService(uow repository.UnitOfWork, policy LeavePolicy) *LeaveService {
 	return &LeaveService{
-		repo: repo,
+		repo:   uow.Leaves(),
+		uow:    uow,
+		policy: policy,
 	}
 }
 
-// CreateLeaveRequest creates a new leave request
-func (s *LeaveService) CreateLeaveRequest(req *models.CreateLeaveRequest, employeeID, employeeName, employeeEmail string) (*models.LeaveRequest, error) {
-	// Calculate leave days (excluding weekends)
-	days := models.CalculateDays(req.StartDate, req.EndDate)
-	if days <= 0 {
-		return nil, errors.New("invalid date range")
+// CreateLeaveRequest creates a new leave request. Drafts skip the date and
+// policy checks, which run when the draft is submitted instead. Approvers are
+// notified of requests created for approval.
+func (s *LeaveService) CreateLeaveRequest(ctx context.Context, req *models.CreateLeaveRequest, employee *models.Employee) (*models.LeaveRequest, error) {
+	loc := s.policy.Location(employee.Timezone)
+	leaveType := models.LeaveType(req.LeaveType)
+	if !leaveType.IsValid() {
+		return nil, errInvalidLeaveType
+	}
+
+	status := models.LeaveStatusPending
+	var days int
+	if req.Draft {
+		status = models.LeaveStatusDraft
+		days = models.CalculateDays(req.StartDate, req.EndDate)
+	} else {
+		var err error
+		days, err = s.validateForSubmission(leaveType, req.StartDate, req.EndDate, loc)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	leaveRequest := &models.LeaveRequest{
 		ID:             uuid.New(),
-		EmployeeID:     employeeID,
-		EmployeeName:   employeeName,
-		EmployeeEmail:  employeeEmail,
-		LeaveType:      models.LeaveType(req.LeaveType),
+		EmployeeID:     employee.ID,
+		EmployeeName:   employee.Name,
+		EmployeeEmail:  employee.Email,
+		Department:     employee.Department,
+		LeaveType:      leaveType,
 		Reason:         req.Reason,
 		StartDate:      req.StartDate,
 		EndDate:        req.EndDate,
+		Timezone:       loc.String(),
+		Locale:         employee.Locale,
 		Days:           days,
-		Status:         models.LeaveStatusPending,
+		Status:         status,
 		ManagerComment: sql.NullString{Valid: false}, // NULL for new requests
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
 
-	if err := s.repo.Create(leaveRequest); err != nil {
-		return nil, fmt.Errorf("failed to create leave request: %w", err)
+	err := s.uow.WithinTx(ctx, func(tx repository.Store) error {
+		if err := tx.Leaves().Create(ctx, leaveRequest); err != nil {
+			return fmt.Errorf("failed to create leave request: %w", err)
+		}
+		if leaveRequest.Status == models.LeaveStatusDraft {
+			return nil
+		}
+		return announce(ctx, tx, models.LeaveActivitySubmitted, leaveRequest)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return leaveRequest, nil
 }
 
-// GetLeaveRequestsByEmployeeID gets all leave requests for an employee
-func (s *LeaveService) GetLeaveRequestsByEmployeeID(employeeID string) ([]*models.LeaveRequest, error) {
-	requests, err := s.repo.FindByEmployeeID(employeeID)
+// SubmitLeaveRequest submits a draft for approval, running the same date and
+// policy checks as CreateLeaveRequest in the employee's current time zone.
+// Approvers are notified once the submission is committed.
+func (s *LeaveService) SubmitLeaveRequest(ctx context.Context, id uuid.UUID, employee *models.Employee) (*models.LeaveRequest, error) {
+	existing, err := s.GetLeaveRequestByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query leave requests: %w", err)
+		return nil, err
 	}
-	return requests, nil
+
+	// Verify ownership
+	if existing.EmployeeID != employee.ID {
+		return nil, ErrUnauthorizedAction
+	}
+
+	if existing.Status != models.LeaveStatusDraft {
+		return nil, fmt.Errorf("%w: cannot submit %s request", ErrInvalidStatus, existing.Status)
+	}
+
+	loc := s.policy.Location(employee.Timezone)
+	days, err := s.validateForSubmission(existing.LeaveType, existing.StartDate, existing.EndDate, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	submitted := *existing
+	submitted.Timezone = loc.String()
+	submitted.Locale = employee.Locale
+	submitted.Days = days
+	submitted.Status = models.LeaveStatusPending
+
+	err = s.uow.WithinTx(ctx, func(tx repository.Store) error {
+		if err := tx.Leaves().Update(ctx, &submitted); err != nil {
+			return writeError(err, "submit")
+		}
+		return announce(ctx, tx, models.LeaveActivitySubmitted, &submitted)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &submitted, nil
+}
+
+// validateForSubmission runs the checks a request must pass before it can be
+// sent for approval and returns the number of working days it covers
+func (s *LeaveService) validateForSubmission(leaveType models.LeaveType, start, end models.Date, loc *time.Location) (int, error) {
+	if start.IsZero() || end.IsZero() {
+		return 0, errDatesRequired
+	}
+
+	if err := s.policy.ValidateDates(leaveType, start, end, loc); err != nil {
+		return 0, err
+	}
+
+	// Calculate leave days (excluding weekends)
+	days := models.CalculateDays(start, end)
+	if days <= 0 {
+		return 0, errNoWorkingDays
+	}
+
+	return days, nil
+}
+
+// GetLeaveRequestsByEmployeeID lists an employee's own leave requests, newest first by default
+func (s *LeaveService) GetLeaveRequestsByEmployeeID(ctx context.Context, employeeID string, filter models.LeaveFilter) (*models.LeavePage, error) {
+	filter.EmployeeID = employeeID
+	return s.listLeaveRequests(ctx, filter, models.SortCreatedAt, true)
 }
 
 // GetLeaveRequestByID gets a leave request by ID
-func (s *LeaveService) GetLeaveRequestByID(id uuid.UUID) (*models.LeaveRequest, error) {
-	req, err := s.repo.FindByID(id)
-	if err == sql.ErrNoRows {
+func (s *LeaveService) GetLeaveRequestByID(ctx context.Context, id uuid.UUID) (*models.LeaveRequest, error) {
+	req, err := s.repo.FindByID(ctx, id)
+	if err == sql.ErrNoRows || errors.Is(err, repository.ErrNotFound) {
 		return nil, ErrLeaveNotFound
 	}
 	if err != nil {
@@ -81,10 +201,12 @@ func (s *LeaveService) GetLeaveRequestByID(id uuid.UUID) (*models.LeaveRequest,
 	return req, nil
 }
 
-// UpdateLeaveRequest updates a leave request (only if pending)
-func (s *LeaveService) UpdateLeaveRequest(id uuid.UUID, employeeID string, req *models.UpdateLeaveRequest) (*models.LeaveRequest, error) {
+// UpdateLeaveRequest updates a leave request (only if draft or pending) the
+// caller last saw at version. Drafts can be edited freely; pending requests
+// are re-validated when their dates change, and their approvers notified.
+func (s *LeaveService) UpdateLeaveRequest(ctx context.Context, id uuid.UUID, version int, employeeID string, req *models.UpdateLeaveRequest) (*models.LeaveRequest, error) {
 	// Get existing request
-	existing, err := s.GetLeaveRequestByID(id)
+	existing, err := s.GetLeaveRequestByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -94,8 +216,12 @@ func (s *LeaveService) UpdateLeaveRequest(id uuid.UUID, employeeID string, req *
 		return nil, ErrUnauthorizedAction
 	}
 
-	// Only allow updates if status is pending
-	if existing.Status != models.LeaveStatusPending {
+	if err := checkVersion(existing, version); err != nil {
+		return nil, err
+	}
+
+	// Only allow updates if status is draft or pending
+	if existing.Status != models.LeaveStatusPending && existing.Status != models.LeaveStatusDraft {
 		return nil, fmt.Errorf("%w: cannot update %s request", ErrInvalidStatus, existing.Status)
 	}
 
@@ -103,6 +229,9 @@ func (s *LeaveService) UpdateLeaveRequest(id uuid.UUID, employeeID string, req *
 	updated := *existing
 	if req.LeaveType != "" {
 		updated.LeaveType = models.LeaveType(req.LeaveType)
+		if !updated.LeaveType.IsValid() {
+			return nil, errInvalidLeaveType
+		}
 	}
 	if req.Reason != "" {
 		updated.Reason = req.Reason
@@ -114,11 +243,19 @@ func (s *LeaveService) UpdateLeaveRequest(id uuid.UUID, employeeID string, req *
 		updated.EndDate = *req.EndDate
 	}
 
-	// Recalculate days if dates changed
-	if req.StartDate != nil || req.EndDate != nil {
+	// Re-validate and recalculate days if the dates or the leave type, which
+	// sets the notice period, changed
+	if existing.Status == models.LeaveStatusDraft {
+		updated.Days = models.CalculateDays(updated.StartDate, updated.EndDate)
+	} else if req.StartDate != nil || req.EndDate != nil || updated.LeaveType != existing.LeaveType {
+		loc := s.policy.Location(existing.Timezone)
+		if err := s.policy.ValidateDates(updated.LeaveType, updated.StartDate, updated.EndDate, loc); err != nil {
+			return nil, err
+		}
+
 		updated.Days = models.CalculateDays(updated.StartDate, updated.EndDate)
 		if updated.Days <= 0 {
-			return nil, errors.New("invalid date range")
+			return nil, errNoWorkingDays
 		}
 	}
 
@@ -127,17 +264,28 @@ func (s *LeaveService) UpdateLeaveRequest(id uuid.UUID, employeeID string, req *
 		return existing, nil
 	}
 
-	if err := s.repo.Update(&updated); err != nil {
-		return nil, fmt.Errorf("failed to update leave request: %w", err)
+	err = s.uow.WithinTx(ctx, func(tx repository.Store) error {
+		if err := tx.Leaves().Update(ctx, &updated); err != nil {
+			return writeError(err, "update")
+		}
+		if updated.Status == models.LeaveStatusDraft {
+			return nil
+		}
+		return announce(ctx, tx, models.LeaveActivityUpdated, &updated)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &updated, nil
 }
 
-// CancelLeaveRequest cancels a leave request (only if pending)
-func (s *LeaveService) CancelLeaveRequest(id uuid.UUID, employeeID string) error {
+// CancelLeaveRequest cancels a leave request (only if draft or pending) the
+// caller last saw at version. Approvers are notified of cancelled pending
+// requests.
+func (s *LeaveService) CancelLeaveRequest(ctx context.Context, id uuid.UUID, version int, employeeID string) error {
 	// Get existing request
-	existing, err := s.GetLeaveRequestByID(id)
+	existing, err := s.GetLeaveRequestByID(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -147,75 +295,248 @@ func (s *LeaveService) CancelLeaveRequest(id uuid.UUID, employeeID string) error
 		return ErrUnauthorizedAction
 	}
 
-	// Only allow cancellation if status is pending
-	if existing.Status != models.LeaveStatusPending {
-		return fmt.Errorf("%w: cannot cancel %s request", ErrInvalidStatus, existing.Status)
+	if err := checkVersion(existing, version); err != nil {
+		return err
 	}
 
-	if err := s.repo.UpdateStatus(id, models.LeaveStatusCancelled, ""); err != nil {
-		return fmt.Errorf("failed to cancel leave request: %w", err)
+	// Only allow cancellation if status is draft or pending
+	if existing.Status != models.LeaveStatusPending && existing.Status != models.LeaveStatusDraft {
+		return fmt.Errorf("%w: cannot cancel %s request", ErrInvalidStatus, existing.Status)
 	}
 
+	from := []models.LeaveStatus{models.LeaveStatusDraft, models.LeaveStatusPending}
+	err = s.uow.WithinTx(ctx, func(tx repository.Store) error {
+		cancelled, err := tx.Leaves().TransitionStatus(ctx, existing.ID, existing.Version, from, models.LeaveStatusCancelled, "")
+		if err != nil || existing.Status == models.LeaveStatusDraft {
+			return err
+		}
+		return announce(ctx, tx, models.LeaveActivityCancelled, cancelled)
+	})
+	if err != nil {
+		return writeError(err, "cancel")
+	}
 	return nil
 }
 
-// GetPendingLeaveRequests gets all pending leave requests (for managers)
-func (s *LeaveService) GetPendingLeaveRequests() ([]*models.LeaveRequest, error) {
-	requests, err := s.repo.FindPending()
+// GetPendingLeaveRequests lists leave requests for approvers, oldest first by
+// default. Only pending requests are listed unless the filter asks for another
+// status; drafts are never visible to approvers.
+func (s *LeaveService) GetPendingLeaveRequests(ctx context.Context, filter models.LeaveFilter) (*models.LeavePage, error) {
+	if filter.Status == "" {
+		filter.Status = models.LeaveStatusPending
+	}
+	if filter.Status == models.LeaveStatusDraft {
+		return nil, &utils.ValidationError{Message: "drafts are not visible to approvers"}
+	}
+	return s.listLeaveRequests(ctx, filter, models.SortCreatedAt, false)
+}
+
+// listLeaveRequests validates a filter, applies the default order and page
+// size, and returns one page of results
+func (s *LeaveService) listLeaveRequests(ctx context.Context, filter models.LeaveFilter, defaultSort models.LeaveSort, defaultDescending bool) (*models.LeavePage, error) {
+	if filter.Sort == "" {
+		filter.Sort = defaultSort
+		filter.Descending = defaultDescending
+	}
+	if !filter.Sort.IsValid() {
+		return nil, &utils.ValidationError{Message: fmt.Sprintf("invalid sort field %q", filter.Sort)}
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, &utils.ValidationError{Message: fmt.Sprintf("invalid status %q", filter.Status)}
+	}
+	if filter.LeaveType != "" && !filter.LeaveType.IsValid() {
+		return nil, errInvalidLeaveType
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		return nil, &utils.ValidationError{Message: "from must not be after to"}
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageLimit
+	}
+	if filter.Limit > MaxPageLimit {
+		filter.Limit = MaxPageLimit
+	}
+
+	page, err := s.repo.Search(ctx, filter)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, &utils.ValidationError{Message: "invalid cursor"}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to query pending leave requests: %w", err)
+		return nil, fmt.Errorf("failed to query leave requests: %w", err)
 	}
-	return requests, nil
+	return page, nil
+}
+
+// decisionNotice describes how the employee is told about a review decision.
+// Decisions sharing a group key are announced in a single notification.
+type decisionNotice struct {
+	groupKey string
+	delay    time.Duration
 }
 
-// ApproveLeaveRequest approves a leave request
-func (s *LeaveService) ApproveLeaveRequest(id uuid.UUID, comment string) (*models.LeaveRequest, error) {
+// ApproveLeaveRequest approves a leave request reviewer last saw at version.
+// The employee is notified once the approval is committed.
+func (s *LeaveService) ApproveLeaveRequest(ctx context.Context, id uuid.UUID, version int, reviewer *models.Employee, comment string) (*models.LeaveRequest, error) {
+	return s.approve(ctx, id, version, reviewer, comment, decisionNotice{})
+}
+
+func (s *LeaveService) approve(ctx context.Context, id uuid.UUID, version int, reviewer *models.Employee, comment string, notice decisionNotice) (*models.LeaveRequest, error) {
 	// Get existing request
-	existing, err := s.GetLeaveRequestByID(id)
+	existing, err := s.GetLeaveRequestByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Only allow approval if status is pending
-	if existing.Status != models.LeaveStatusPending {
-		return nil, fmt.Errorf("%w: cannot approve %s request", ErrInvalidStatus, existing.Status)
+	// Drafts are invisible to approvers
+	if existing.Status == models.LeaveStatusDraft {
+		return nil, ErrLeaveNotFound
 	}
 
-	if err := s.repo.UpdateStatus(id, models.LeaveStatusApproved, comment); err != nil {
-		return nil, fmt.Errorf("failed to approve leave request: %w", err)
+	if err := checkReviewer(existing, reviewer); err != nil {
+		return nil, err
 	}
 
-	// Fetch the updated request
-	updated, err := s.repo.FindByID(id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch updated leave request: %w", err)
+	if err := checkVersion(existing, version); err != nil {
+		return nil, err
 	}
 
-	return updated, nil
+	// Only allow approval if status is pending
+	if existing.Status != models.LeaveStatusPending {
+		return nil, fmt.Errorf("%w: cannot approve %s request", ErrInvalidStatus, existing.Status)
+	}
+
+	return s.transition(ctx, existing, []models.LeaveStatus{models.LeaveStatusPending}, models.LeaveStatusApproved, comment, "approve", &notice)
+}
+
+// RejectLeaveRequest rejects a leave request reviewer last saw at version.
+// The employee is notified once the rejection is committed.
+func (s *LeaveService) RejectLeaveRequest(ctx context.Context, id uuid.UUID, version int, reviewer *models.Employee, comment string) (*models.LeaveRequest, error) {
+	return s.reject(ctx, id, version, reviewer, comment, decisionNotice{})
 }
 
-// RejectLeaveRequest rejects a leave request
-func (s *LeaveService) RejectLeaveRequest(id uuid.UUID, comment string) (*models.LeaveRequest, error) {
+func (s *LeaveService) reject(ctx context.Context, id uuid.UUID, version int, reviewer *models.Employee, comment string, notice decisionNotice) (*models.LeaveRequest, error) {
 	// Get existing request
-	existing, err := s.GetLeaveRequestByID(id)
+	existing, err := s.GetLeaveRequestByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	// Drafts are invisible to approvers
+	if existing.Status == models.LeaveStatusDraft {
+		return nil, ErrLeaveNotFound
+	}
+
+	if err := checkReviewer(existing, reviewer); err != nil {
+		return nil, err
+	}
+
+	if err := checkVersion(existing, version); err != nil {
+		return nil, err
+	}
+
 	// Only allow rejection if status is pending
 	if existing.Status != models.LeaveStatusPending {
 		return nil, fmt.Errorf("%w: cannot reject %s request", ErrInvalidStatus, existing.Status)
 	}
 
-	if err := s.repo.UpdateStatus(id, models.LeaveStatusRejected, comment); err != nil {
-		return nil, fmt.Errorf("failed to reject leave request: %w", err)
+	return s.transition(ctx, existing, []models.LeaveStatus{models.LeaveStatusPending}, models.LeaveStatusRejected, comment, "reject", &notice)
+}
+
+// transition moves leave from one of the from statuses to status inside a
+// unit of work. The write is conditional on the version that was read, so a
+// request changed by someone else in the meantime yields ErrConflict instead
+// of being silently overwritten. A non-nil notice queues the decision
+// notification in the same transaction.
+func (s *LeaveService) transition(ctx context.Context, leave *models.LeaveRequest, from []models.LeaveStatus, status models.LeaveStatus, comment, action string, notice *decisionNotice) (*models.LeaveRequest, error) {
+	var updated *models.LeaveRequest
+	err := s.uow.WithinTx(ctx, func(tx repository.Store) error {
+		var err error
+		updated, err = tx.Leaves().TransitionStatus(ctx, leave.ID, leave.Version, from, status, comment)
+		if err != nil || notice == nil {
+			return err
+		}
+		return enqueue(ctx, tx.Outbox(), models.OutboxTopicLeaveDecided, notice.groupKey, updated, notice.delay)
+	})
+	if err != nil {
+		return nil, writeError(err, action)
 	}
+	return updated, nil
+}
 
-	// Fetch the updated request
-	updated, err := s.repo.FindByID(id)
+// maxTeamAbsences caps the team absences listed in a notification
+const maxTeamAbsences = 20
+
+// announce queues a notification to approvers about an employee's change to
+// leave, along with who else in the team is already off on those dates
+func announce(ctx context.Context, tx repository.Store, action models.LeaveActivityAction, leave *models.LeaveRequest) error {
+	absences, err := teamAbsences(ctx, tx.Leaves(), leave)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch updated leave request: %w", err)
+		return err
 	}
+	activity := &models.LeaveActivity{Action: action, Leave: leave, TeamAbsences: absences}
+	return enqueue(ctx, tx.Outbox(), models.OutboxTopicLeaveActivity, "", activity, 0)
+}
 
-	return updated, nil
+// teamAbsences returns the approved leaves of others in leave's department
+// that overlap it, by start date. Employees without a department have no team.
+func teamAbsences(ctx context.Context, repo repository.LeaveRepository, leave *models.LeaveRequest) ([]*models.LeaveRequest, error) {
+	if leave.Department == "" || leave.StartDate.IsZero() || leave.EndDate.IsZero() {
+		return nil, nil
+	}
+
+	page, err := repo.Search(ctx, models.LeaveFilter{
+		Status:     models.LeaveStatusApproved,
+		Department: leave.Department,
+		From:       leave.StartDate,
+		To:         leave.EndDate,
+		Sort:       models.SortStartDate,
+		// The employee's own leaves are skipped below
+		Limit: maxTeamAbsences * 2,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query team absences: %w", err)
+	}
+
+	var absences []*models.LeaveRequest
+	for _, other := range page.Items {
+		if other.EmployeeID == leave.EmployeeID {
+			continue
+		}
+		absences = append(absences, other)
+		if len(absences) == maxTeamAbsences {
+			break
+		}
+	}
+	return absences, nil
+}
+
+// checkVersion returns ErrPreconditionFailed unless leave is at version
+func checkVersion(leave *models.LeaveRequest, version int) error {
+	if version != AnyVersion && leave.Version != version {
+		return ErrPreconditionFailed
+	}
+	return nil
+}
+
+// statusIn reports whether status is one of allowed
+func statusIn(status models.LeaveStatus, allowed []models.LeaveStatus) bool {
+	for _, s := range allowed {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// writeError maps the error of a conditional write to the service errors
+// handlers understand
+func writeError(err error, action string) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return ErrLeaveNotFound
+	case errors.Is(err, repository.ErrConflict):
+		return ErrConflict
+	}
+	return fmt.Errorf("failed to %s leave request: %w", action, err)
 }
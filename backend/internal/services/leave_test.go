@@ -1,18 +1,21 @@
 package services
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"leave-management-system/internal/models"
 	"leave-management-system/internal/repository"
+	"leave-management-system/internal/utils"
 )
 
 func TestLeaveService_CreateLeaveRequest(t *testing.T) {
 	repo := repository.NewMockLeaveRepository()
-	service := NewLeaveService(repo)
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
+	monday := nextMonday()
 
 	tests := []struct {
 		name        string
@@ -28,8 +31,8 @@ func TestLeaveService_CreateLeaveRequest(t *testing.T) {
 			req: &models.CreateLeaveRequest{
 				LeaveType: "annual",
 				Reason:    "Vacation time",
-				StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+				StartDate: monday,
+				EndDate:   monday.AddDays(4),
 			},
 			employeeID:    "emp-1",
 			employeeName:  "John Doe",
@@ -41,8 +44,8 @@ func TestLeaveService_CreateLeaveRequest(t *testing.T) {
 			req: &models.CreateLeaveRequest{
 				LeaveType: "annual",
 				Reason:    "Vacation time",
-				StartDate: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				StartDate: monday.AddDays(4),
+				EndDate:   monday,
 			},
 			employeeID:    "emp-1",
 			employeeName:  "John Doe",
@@ -55,20 +58,52 @@ func TestLeaveService_CreateLeaveRequest(t *testing.T) {
 			req: &models.CreateLeaveRequest{
 				LeaveType: "sick",
 				Reason:    "Feeling unwell",
-				StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				StartDate: monday,
+				EndDate:   monday,
 			},
 			employeeID:    "emp-1",
 			employeeName:  "John Doe",
 			employeeEmail: "john@example.com",
 			wantErr:       false,
 		},
+		{
+			name: "start date in the past",
+			req: &models.CreateLeaveRequest{
+				LeaveType: "sick",
+				Reason:    "Feeling unwell",
+				StartDate: models.Today(time.UTC).AddDays(-1),
+				EndDate:   models.Today(time.UTC).AddDays(1),
+			},
+			employeeID:    "emp-1",
+			employeeName:  "John Doe",
+			employeeEmail: "john@example.com",
+			wantErr:       true,
+			errContains:   "past",
+		},
+		{
+			name: "weekend only",
+			req: &models.CreateLeaveRequest{
+				LeaveType: "personal",
+				Reason:    "Family event",
+				StartDate: monday.AddDays(5),
+				EndDate:   monday.AddDays(6),
+			},
+			employeeID:    "emp-1",
+			employeeName:  "John Doe",
+			employeeEmail: "john@example.com",
+			wantErr:       true,
+			errContains:   "invalid date range",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo.Clear()
-			leave, err := service.CreateLeaveRequest(tt.req, tt.employeeID, tt.employeeName, tt.employeeEmail)
+			leave, err := service.CreateLeaveRequest(context.Background(), tt.req, &models.Employee{
+				ID:    tt.employeeID,
+				Name:  tt.employeeName,
+				Email: tt.employeeEmail,
+			})
 
 			if tt.wantErr {
 				if err == nil {
@@ -108,7 +143,7 @@ func TestLeaveService_CreateLeaveRequest(t *testing.T) {
 
 func TestLeaveService_GetLeaveRequestByID(t *testing.T) {
 	repo := repository.NewMockLeaveRepository()
-	service := NewLeaveService(repo)
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
 
 	// Create a test leave request
 	leaveID := uuid.New()
@@ -119,14 +154,14 @@ func TestLeaveService_GetLeaveRequestByID(t *testing.T) {
 		EmployeeEmail: "john@example.com",
 		LeaveType:     models.LeaveTypeAnnual,
 		Reason:        "Vacation",
-		StartDate:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-		EndDate:       time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		StartDate:     models.NewDate(2024, 1, 1),
+		EndDate:       models.NewDate(2024, 1, 5),
 		Days:          5,
 		Status:        models.LeaveStatusPending,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
-	repo.Create(testLeave)
+	repo.Create(context.Background(), testLeave)
 
 	tests := []struct {
 		name    string
@@ -149,14 +184,14 @@ func TestLeaveService_GetLeaveRequestByID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			leave, err := service.GetLeaveRequestByID(tt.id)
+			leave, err := service.GetLeaveRequestByID(context.Background(), tt.id)
 
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("expected error but got none")
 					return
 				}
-				if tt.errType != nil && err != tt.errType {
+				if tt.errType != nil && !errors.Is(err, tt.errType) {
 					t.Errorf("expected error %v, got %v", tt.errType, err)
 				}
 				return
@@ -176,7 +211,7 @@ func TestLeaveService_GetLeaveRequestByID(t *testing.T) {
 
 func TestLeaveService_UpdateLeaveRequest(t *testing.T) {
 	repo := repository.NewMockLeaveRepository()
-	service := NewLeaveService(repo)
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
 
 	// Create a test leave request
 	leaveID := uuid.New()
@@ -187,14 +222,14 @@ func TestLeaveService_UpdateLeaveRequest(t *testing.T) {
 		EmployeeEmail: "john@example.com",
 		LeaveType:     models.LeaveTypeAnnual,
 		Reason:        "Vacation",
-		StartDate:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-		EndDate:       time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		StartDate:     models.NewDate(2024, 1, 1),
+		EndDate:       models.NewDate(2024, 1, 5),
 		Days:          5,
 		Status:        models.LeaveStatusPending,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
-	repo.Create(testLeave)
+	repo.Create(context.Background(), testLeave)
 
 	tests := []struct {
 		name        string
@@ -219,8 +254,8 @@ func TestLeaveService_UpdateLeaveRequest(t *testing.T) {
 			id:         leaveID,
 			employeeID: "emp-1",
 			req: &models.UpdateLeaveRequest{
-				StartDate: timePtr(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
-				EndDate:   timePtr(time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)),
+				StartDate: datePtr(nextMonday().AddDays(1)),
+				EndDate:   datePtr(nextMonday().AddDays(5)),
 			},
 			wantErr: false,
 		},
@@ -240,7 +275,8 @@ func TestLeaveService_UpdateLeaveRequest(t *testing.T) {
 			id:         leaveID,
 			employeeID: "emp-1",
 			req:        &models.UpdateLeaveRequest{},
-			wantErr:    false,
+			wantErr:    true,
+			errType:    ErrInvalidStatus,
 		},
 	}
 
@@ -248,22 +284,22 @@ func TestLeaveService_UpdateLeaveRequest(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Reset status to pending for each test
 			testLeave.Status = models.LeaveStatusPending
-			repo.Update(testLeave)
+			repo.Create(context.Background(), testLeave)
 
 			// For the "cannot update approved" test, set status to approved
 			if tt.name == "cannot update approved request" {
 				testLeave.Status = models.LeaveStatusApproved
-				repo.Update(testLeave)
+				repo.Create(context.Background(), testLeave)
 			}
 
-			leave, err := service.UpdateLeaveRequest(tt.id, tt.employeeID, tt.req)
+			leave, err := service.UpdateLeaveRequest(context.Background(), tt.id, testLeave.Version, tt.employeeID, tt.req)
 
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("expected error but got none")
 					return
 				}
-				if tt.errType != nil && err != tt.errType {
+				if tt.errType != nil && !errors.Is(err, tt.errType) {
 					t.Errorf("expected error %v, got %v", tt.errType, err)
 				}
 				if tt.errContains != "" && !contains(err.Error(), tt.errContains) {
@@ -287,7 +323,7 @@ func TestLeaveService_UpdateLeaveRequest(t *testing.T) {
 
 func TestLeaveService_CancelLeaveRequest(t *testing.T) {
 	repo := repository.NewMockLeaveRepository()
-	service := NewLeaveService(repo)
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
 
 	// Create a test leave request
 	leaveID := uuid.New()
@@ -298,7 +334,7 @@ func TestLeaveService_CancelLeaveRequest(t *testing.T) {
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
-	repo.Create(testLeave)
+	repo.Create(context.Background(), testLeave)
 
 	tests := []struct {
 		name        string
@@ -336,22 +372,22 @@ func TestLeaveService_CancelLeaveRequest(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Reset status
 			testLeave.Status = models.LeaveStatusPending
-			repo.Update(testLeave)
+			repo.Create(context.Background(), testLeave)
 
 			// For the "cannot cancel approved" test, set status to approved
 			if tt.name == "cannot cancel approved request" {
 				testLeave.Status = models.LeaveStatusApproved
-				repo.Update(testLeave)
+				repo.Create(context.Background(), testLeave)
 			}
 
-			err := service.CancelLeaveRequest(tt.id, tt.employeeID)
+			err := service.CancelLeaveRequest(context.Background(), tt.id, testLeave.Version, tt.employeeID)
 
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("expected error but got none")
 					return
 				}
-				if tt.errType != nil && err != tt.errType {
+				if tt.errType != nil && !errors.Is(err, tt.errType) {
 					t.Errorf("expected error %v, got %v", tt.errType, err)
 				}
 				if tt.errContains != "" && !contains(err.Error(), tt.errContains) {
@@ -366,7 +402,7 @@ func TestLeaveService_CancelLeaveRequest(t *testing.T) {
 			}
 
 			// Verify status was updated
-			updated, _ := repo.FindByID(tt.id)
+			updated, _ := repo.FindByID(context.Background(), tt.id)
 			if updated.Status != models.LeaveStatusCancelled {
 				t.Errorf("expected status %q, got %q", models.LeaveStatusCancelled, updated.Status)
 			}
@@ -376,7 +412,7 @@ func TestLeaveService_CancelLeaveRequest(t *testing.T) {
 
 func TestLeaveService_ApproveLeaveRequest(t *testing.T) {
 	repo := repository.NewMockLeaveRepository()
-	service := NewLeaveService(repo)
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
 
 	// Create a test leave request
 	leaveID := uuid.New()
@@ -387,7 +423,7 @@ func TestLeaveService_ApproveLeaveRequest(t *testing.T) {
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
-	repo.Create(testLeave)
+	repo.Create(context.Background(), testLeave)
 
 	tests := []struct {
 		name        string
@@ -417,22 +453,22 @@ func TestLeaveService_ApproveLeaveRequest(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Reset status
 			testLeave.Status = models.LeaveStatusPending
-			repo.Update(testLeave)
+			repo.Create(context.Background(), testLeave)
 
 			// For the "cannot approve already approved" test, set status to approved
 			if tt.name == "cannot approve already approved request" {
 				testLeave.Status = models.LeaveStatusApproved
-				repo.Update(testLeave)
+				repo.Create(context.Background(), testLeave)
 			}
 
-			leave, err := service.ApproveLeaveRequest(tt.id, tt.comment)
+			leave, err := service.ApproveLeaveRequest(context.Background(), tt.id, testLeave.Version, testReviewer, tt.comment)
 
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("expected error but got none")
 					return
 				}
-				if tt.errType != nil && err != tt.errType {
+				if tt.errType != nil && !errors.Is(err, tt.errType) {
 					t.Errorf("expected error %v, got %v", tt.errType, err)
 				}
 				return
@@ -454,9 +490,49 @@ func TestLeaveService_ApproveLeaveRequest(t *testing.T) {
 	}
 }
 
+func TestLeaveService_ReviewAssignedApprover(t *testing.T) {
+	repo := repository.NewMockLeaveRepository()
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
+
+	newAssigned := func() *models.LeaveRequest {
+		leave := &models.LeaveRequest{
+			ID:            uuid.New(),
+			EmployeeID:    "emp-1",
+			Status:        models.LeaveStatusPending,
+			ApproverID:    "mgr-2",
+			ApproverEmail: "boss@example.com",
+			CreatedAt:     time.Now(),
+		}
+		repo.Create(context.Background(), leave)
+		return leave
+	}
+
+	tests := []struct {
+		name     string
+		reviewer *models.Employee
+		wantErr  error
+	}{
+		{"other manager refused", testReviewer, ErrNotAssignedApprover},
+		{"assigned approver decides", &models.Employee{ID: "mgr-2"}, nil},
+		{"email link of assigned approver", &models.Employee{Email: "Boss@example.com"}, nil},
+		{"email link of someone else", &models.Employee{Email: "approvers@example.com"}, ErrNotAssignedApprover},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leave := newAssigned()
+
+			_, err := service.RejectLeaveRequest(context.Background(), leave.ID, AnyVersion, tt.reviewer, "Team is short-staffed that week")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestLeaveService_RejectLeaveRequest(t *testing.T) {
 	repo := repository.NewMockLeaveRepository()
-	service := NewLeaveService(repo)
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
 
 	// Create a test leave request
 	leaveID := uuid.New()
@@ -467,7 +543,7 @@ func TestLeaveService_RejectLeaveRequest(t *testing.T) {
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
-	repo.Create(testLeave)
+	repo.Create(context.Background(), testLeave)
 
 	tests := []struct {
 		name        string
@@ -497,22 +573,22 @@ func TestLeaveService_RejectLeaveRequest(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Reset status
 			testLeave.Status = models.LeaveStatusPending
-			repo.Update(testLeave)
+			repo.Create(context.Background(), testLeave)
 
 			// For the "cannot reject already rejected" test, set status to rejected
 			if tt.name == "cannot reject already rejected request" {
 				testLeave.Status = models.LeaveStatusRejected
-				repo.Update(testLeave)
+				repo.Create(context.Background(), testLeave)
 			}
 
-			leave, err := service.RejectLeaveRequest(tt.id, tt.comment)
+			leave, err := service.RejectLeaveRequest(context.Background(), tt.id, testLeave.Version, testReviewer, tt.comment)
 
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("expected error but got none")
 					return
 				}
-				if tt.errType != nil && err != tt.errType {
+				if tt.errType != nil && !errors.Is(err, tt.errType) {
 					t.Errorf("expected error %v, got %v", tt.errType, err)
 				}
 				return
@@ -554,7 +630,178 @@ func containsMiddle(s, substr string) bool {
 	return false
 }
 
-func timePtr(t time.Time) *time.Time {
-	return &t
+// testReviewer is the manager reviewing requests in tests
+var testReviewer = &models.Employee{ID: "mgr-1", Name: "Jane Smith", Email: "jane@example.com"}
+
+func datePtr(d models.Date) *models.Date {
+	return &d
+}
+
+// nextMonday returns the first Monday at least a week from today
+func nextMonday() models.Date {
+	d := models.Today(time.UTC).AddDays(7)
+	for d.Weekday() != time.Monday {
+		d = d.AddDays(1)
+	}
+	return d
+}
+
+
+func TestLeaveService_CreateLeaveRequest_Timezone(t *testing.T) {
+	bangkok, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	repo := repository.NewMockLeaveRepository()
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), LeavePolicy{DefaultLocation: bangkok})
+
+	t.Run("today in the employee's zone is accepted", func(t *testing.T) {
+		kiritimati, err := time.LoadLocation("Pacific/Kiritimati")
+		if err != nil {
+			t.Skipf("time zone data unavailable: %v", err)
+		}
+		today := models.Today(kiritimati)
+		leave, err := service.CreateLeaveRequest(context.Background(), &models.CreateLeaveRequest{
+			LeaveType: "sick",
+			StartDate: today,
+			EndDate:   today.AddDays(3),
+		}, &models.Employee{ID: "emp-1", Timezone: "Pacific/Kiritimati"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if leave.Timezone != "Pacific/Kiritimati" {
+			t.Errorf("expected timezone Pacific/Kiritimati, got %q", leave.Timezone)
+		}
+	})
+
+	t.Run("falls back to the office zone", func(t *testing.T) {
+		monday := nextMonday()
+		leave, err := service.CreateLeaveRequest(context.Background(), &models.CreateLeaveRequest{
+			LeaveType: "annual",
+			StartDate: monday,
+			EndDate:   monday,
+		}, &models.Employee{ID: "emp-1", Timezone: "Not/AZone"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if leave.Timezone != "Asia/Bangkok" {
+			t.Errorf("expected timezone Asia/Bangkok, got %q", leave.Timezone)
+		}
+	})
+}
+
+func TestLeavePolicy_NoticeDays(t *testing.T) {
+	policy := LeavePolicy{
+		DefaultLocation: time.UTC,
+		MinNoticeDays:   map[models.LeaveType]int{models.LeaveTypeAnnual: 14},
+	}
+	today := models.Today(time.UTC)
+
+	if err := policy.ValidateDates(models.LeaveTypeAnnual, today.AddDays(3), today.AddDays(5), time.UTC); err == nil {
+		t.Errorf("expected notice error for annual leave starting in 3 days")
+	}
+	if err := policy.ValidateDates(models.LeaveTypeAnnual, today.AddDays(14), today.AddDays(15), time.UTC); err != nil {
+		t.Errorf("unexpected error with sufficient notice: %v", err)
+	}
+	if err := policy.ValidateDates(models.LeaveTypeSick, today, today, time.UTC); err != nil {
+		t.Errorf("sick leave should not require notice: %v", err)
+	}
+}
+
+func TestLeaveService_UpdateLeaveTypeRevalidates(t *testing.T) {
+	repo := repository.NewMockLeaveRepository()
+	policy := DefaultLeavePolicy()
+	policy.MinNoticeDays = map[models.LeaveType]int{models.LeaveTypeAnnual: 14}
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), policy)
+
+	start := models.Today(time.UTC).AddDays(3)
+	leave := &models.LeaveRequest{
+		ID:         uuid.New(),
+		EmployeeID: "emp-1",
+		LeaveType:  models.LeaveTypeSick,
+		StartDate:  start,
+		EndDate:    start,
+		Days:       1,
+		Status:     models.LeaveStatusPending,
+	}
+	repo.Create(context.Background(), leave)
+
+	// Sick leave needs no notice, but annual leave starting in 3 days does
+	_, err := service.UpdateLeaveRequest(context.Background(), leave.ID, AnyVersion, "emp-1", &models.UpdateLeaveRequest{LeaveType: "annual"})
+	var validationErr *utils.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected notice validation error, got %v", err)
+	}
+
+	stored, _ := repo.FindByID(context.Background(), leave.ID)
+	if stored.LeaveType != models.LeaveTypeSick {
+		t.Errorf("expected leave type to stay sick, got %s", stored.LeaveType)
+	}
 }
 
+func TestLeaveService_Drafts(t *testing.T) {
+	repo := repository.NewMockLeaveRepository()
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
+	employee := &models.Employee{ID: "emp-1", Name: "John Doe", Email: "john@example.com"}
+
+	// Drafts may be saved without dates and are not validated
+	draft, err := service.CreateLeaveRequest(context.Background(), &models.CreateLeaveRequest{
+		LeaveType: "annual",
+		Draft:     true,
+	}, employee)
+	if err != nil {
+		t.Fatalf("unexpected error creating draft: %v", err)
+	}
+	if draft.Status != models.LeaveStatusDraft {
+		t.Errorf("expected status draft, got %s", draft.Status)
+	}
+
+	pending, _ := service.GetPendingLeaveRequests(context.Background(), models.LeaveFilter{})
+	if pending.Total != 0 {
+		t.Errorf("drafts must not be visible to approvers, got %d pending", pending.Total)
+	}
+	if _, err := service.ApproveLeaveRequest(context.Background(), draft.ID, AnyVersion, testReviewer, ""); !errors.Is(err, ErrLeaveNotFound) {
+		t.Errorf("expected ErrLeaveNotFound approving a draft, got %v", err)
+	}
+
+	// Submitting runs the full validation
+	if _, err := service.SubmitLeaveRequest(context.Background(), draft.ID, employee); !errors.Is(err, errDatesRequired) {
+		t.Errorf("expected errDatesRequired, got %v", err)
+	}
+
+	past := models.Today(time.UTC).AddDays(-7)
+	if _, err := service.UpdateLeaveRequest(context.Background(), draft.ID, AnyVersion, employee.ID, &models.UpdateLeaveRequest{
+		StartDate: datePtr(past),
+		EndDate:   datePtr(past.AddDays(2)),
+	}); err != nil {
+		t.Fatalf("drafts should accept any dates, got %v", err)
+	}
+	if _, err := service.SubmitLeaveRequest(context.Background(), draft.ID, employee); !errors.Is(err, utils.ErrDateInPast) {
+		t.Errorf("expected ErrDateInPast, got %v", err)
+	}
+
+	monday := nextMonday()
+	if _, err := service.UpdateLeaveRequest(context.Background(), draft.ID, AnyVersion, employee.ID, &models.UpdateLeaveRequest{
+		StartDate: datePtr(monday),
+		EndDate:   datePtr(monday.AddDays(4)),
+	}); err != nil {
+		t.Fatalf("unexpected error updating draft: %v", err)
+	}
+
+	if _, err := service.SubmitLeaveRequest(context.Background(), draft.ID, &models.Employee{ID: "emp-2"}); !errors.Is(err, ErrUnauthorizedAction) {
+		t.Errorf("expected ErrUnauthorizedAction, got %v", err)
+	}
+
+	submitted, err := service.SubmitLeaveRequest(context.Background(), draft.ID, employee)
+	if err != nil {
+		t.Fatalf("unexpected error submitting draft: %v", err)
+	}
+	if submitted.Status != models.LeaveStatusPending || submitted.Days != 5 {
+		t.Errorf("expected pending request of 5 days, got %s with %d days", submitted.Status, submitted.Days)
+	}
+
+	if _, err := service.SubmitLeaveRequest(context.Background(), draft.ID, employee); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("expected ErrInvalidStatus submitting twice, got %v", err)
+	}
+}
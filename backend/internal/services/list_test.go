@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/utils"
+)
+
+func TestLeaveService_GetLeaveRequestsByEmployeeID_Pagination(t *testing.T) {
+	repo := repository.NewMockLeaveRepository()
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
+
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	start := models.NewDate(2025, 2, 3)
+	for i := 0; i < 7; i++ {
+		repo.Create(context.Background(), &models.LeaveRequest{
+			ID:           uuid.New(),
+			EmployeeID:   "emp-1",
+			EmployeeName: fmt.Sprintf("Employee %d", i),
+			LeaveType:    models.LeaveTypeAnnual,
+			Status:       models.LeaveStatusPending,
+			StartDate:    start.AddDays(7 * (6 - i)),
+			EndDate:      start.AddDays(7*(6-i) + 1),
+			Days:         i%3 + 1,
+			CreatedAt:    base.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	repo.Create(context.Background(), &models.LeaveRequest{ID: uuid.New(), EmployeeID: "emp-2", CreatedAt: base})
+
+	// collect pages through every result, following the cursor
+	collect := func(t *testing.T, filter models.LeaveFilter) []*models.LeaveRequest {
+		t.Helper()
+		var all []*models.LeaveRequest
+		for pages := 0; ; pages++ {
+			if pages > 10 {
+				t.Fatalf("pagination did not terminate")
+			}
+			page, err := service.GetLeaveRequestsByEmployeeID(context.Background(), "emp-1", filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if page.Total != 7 {
+				t.Errorf("expected total 7, got %d", page.Total)
+			}
+			all = append(all, page.Items...)
+			if page.NextCursor == "" {
+				return all
+			}
+			filter.Cursor = page.NextCursor
+		}
+	}
+
+	t.Run("default order is newest first", func(t *testing.T) {
+		all := collect(t, models.LeaveFilter{Limit: 3})
+		if len(all) != 7 {
+			t.Fatalf("expected 7 requests, got %d", len(all))
+		}
+		for i := 1; i < len(all); i++ {
+			if all[i].CreatedAt.After(all[i-1].CreatedAt) {
+				t.Errorf("requests not ordered newest first at %d", i)
+			}
+		}
+	})
+
+	t.Run("sort by start date ascending", func(t *testing.T) {
+		all := collect(t, models.LeaveFilter{Sort: models.SortStartDate, Limit: 2})
+		if len(all) != 7 {
+			t.Fatalf("expected 7 requests, got %d", len(all))
+		}
+		for i := 1; i < len(all); i++ {
+			if all[i].StartDate.Before(all[i-1].StartDate) {
+				t.Errorf("requests not ordered by start date at %d", i)
+			}
+		}
+	})
+
+	t.Run("sort by days with ties", func(t *testing.T) {
+		all := collect(t, models.LeaveFilter{Sort: models.SortDays, Descending: true, Limit: 2})
+		seen := make(map[uuid.UUID]bool)
+		for i, leave := range all {
+			if seen[leave.ID] {
+				t.Errorf("request %s returned twice", leave.ID)
+			}
+			seen[leave.ID] = true
+			if i > 0 && leave.Days > all[i-1].Days {
+				t.Errorf("requests not ordered by days at %d", i)
+			}
+		}
+		if len(seen) != 7 {
+			t.Errorf("expected 7 distinct requests, got %d", len(seen))
+		}
+	})
+
+	t.Run("date overlap filter", func(t *testing.T) {
+		page, err := service.GetLeaveRequestsByEmployeeID(context.Background(), "emp-1", models.LeaveFilter{From: start.AddDays(1), To: start.AddDays(8)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if page.Total != 2 {
+			t.Errorf("expected 2 overlapping requests, got %d", page.Total)
+		}
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		filters := []models.LeaveFilter{
+			{Sort: "reason"},
+			{Status: "archived"},
+			{LeaveType: "sabbatical"},
+			{From: start.AddDays(1), To: start},
+			{Cursor: "not-a-cursor"},
+		}
+		for _, filter := range filters {
+			var validationErr *utils.ValidationError
+			if _, err := service.GetLeaveRequestsByEmployeeID(context.Background(), "emp-1", filter); !errors.As(err, &validationErr) {
+				t.Errorf("expected validation error for %+v, got %v", filter, err)
+			}
+		}
+
+		// A cursor is only valid for the order it was issued for
+		page, _ := service.GetLeaveRequestsByEmployeeID(context.Background(), "emp-1", models.LeaveFilter{Limit: 1})
+		if _, err := service.GetLeaveRequestsByEmployeeID(context.Background(), "emp-1", models.LeaveFilter{Sort: models.SortDays, Cursor: page.NextCursor}); err == nil {
+			t.Errorf("expected error reusing a cursor with a different sort")
+		}
+	})
+}
+
+func TestLeaveService_GetPendingLeaveRequests_Filters(t *testing.T) {
+	repo := repository.NewMockLeaveRepository()
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
+
+	repo.Create(context.Background(), &models.LeaveRequest{ID: uuid.New(), EmployeeID: "emp-1", Department: "engineering", Status: models.LeaveStatusPending, CreatedAt: time.Now()})
+	repo.Create(context.Background(), &models.LeaveRequest{ID: uuid.New(), EmployeeID: "emp-2", Department: "sales", Status: models.LeaveStatusPending, CreatedAt: time.Now()})
+	repo.Create(context.Background(), &models.LeaveRequest{ID: uuid.New(), EmployeeID: "emp-3", Department: "sales", Status: models.LeaveStatusApproved, CreatedAt: time.Now()})
+	repo.Create(context.Background(), &models.LeaveRequest{ID: uuid.New(), EmployeeID: "emp-4", Department: "sales", Status: models.LeaveStatusDraft, CreatedAt: time.Now()})
+
+	page, err := service.GetPendingLeaveRequests(context.Background(), models.LeaveFilter{Department: "sales"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total != 1 || page.Items[0].EmployeeID != "emp-2" {
+		t.Errorf("expected only emp-2's pending request, got %d", page.Total)
+	}
+
+	page, _ = service.GetPendingLeaveRequests(context.Background(), models.LeaveFilter{Status: models.LeaveStatusApproved})
+	if page.Total != 1 {
+		t.Errorf("expected 1 approved request, got %d", page.Total)
+	}
+
+	if _, err := service.GetPendingLeaveRequests(context.Background(), models.LeaveFilter{Status: models.LeaveStatusDraft}); err == nil {
+		t.Errorf("expected error listing drafts as an approver")
+	}
+}
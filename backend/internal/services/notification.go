@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"leave-management-system/internal/ical"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/notify"
+	"leave-management-system/internal/templates"
+)
+
+// NotificationService composes notifications about leave requests from
+// templates, in each recipient's locale, and hands them to a notifier, which
+// decides the channels they are delivered on
+type NotificationService struct {
+	notifier notify.Notifier
+	renderer *templates.Renderer
+	// approverAddress receives approver notifications for requests without
+	// an assigned approver
+	approverAddress string
+	// organizer is the organizer of calendar invites; may be nil
+	organizer *ical.Person
+	// actions makes the approve and reject links of approver emails; may be nil
+	actions *EmailActionService
+}
+
+// NewNotificationService creates a new notification service. sender, the
+// address email is sent from, organizes the calendar invites sent to
+// employees. Approver emails link to actions when it is not nil.
+func NewNotificationService(notifier notify.Notifier, renderer *templates.Renderer, approverAddress, sender string, actions *EmailActionService) *NotificationService {
+	s := &NotificationService{
+		notifier:        notifier,
+		renderer:        renderer,
+		approverAddress: approverAddress,
+		actions:         actions,
+	}
+	if addr, err := mail.ParseAddress(sender); err == nil {
+		s.organizer = &ical.Person{Name: addr.Name, Email: addr.Address}
+	}
+	return s
+}
+
+// NotifyLeaveApproved notifies the employee that their leave request was
+// approved, with a calendar invite for it
+func (s *NotificationService) NotifyLeaveApproved(ctx context.Context, leave *models.LeaveRequest) error {
+	return s.send(ctx, notify.EventLeaveApproved, []string{leave.EmployeeEmail}, leave.Locale,
+		templates.LeaveApproved, &templates.LeaveData{Leave: leave}, leave,
+		s.calendar(ical.MethodRequest, leave))
+}
+
+// NotifyLeaveRejected notifies the employee that their leave request was rejected
+func (s *NotificationService) NotifyLeaveRejected(ctx context.Context, leave *models.LeaveRequest) error {
+	return s.send(ctx, notify.EventLeaveRejected, []string{leave.EmployeeEmail}, leave.Locale,
+		templates.LeaveRejected, &templates.LeaveData{Leave: leave}, leave)
+}
+
+// NotifyLeaveReviewed sends one notification summarising several decisions
+// on the leave requests of a single employee, with calendar invites for the
+// approved ones
+func (s *NotificationService) NotifyLeaveReviewed(ctx context.Context, leaves []*models.LeaveRequest) error {
+	if len(leaves) == 0 {
+		return nil
+	}
+
+	// The approved leaves are attached as calendar invites
+	var attachments []notify.Attachment
+	var approved []*models.LeaveRequest
+	for _, leave := range leaves {
+		if leave.Status == models.LeaveStatusApproved {
+			approved = append(approved, leave)
+		}
+	}
+	if len(approved) > 0 {
+		attachments = append(attachments, s.calendar(ical.MethodRequest, approved...))
+	}
+
+	employee := leaves[0]
+	return s.send(ctx, notify.EventLeaveReviewed, []string{employee.EmployeeEmail}, employee.Locale,
+		templates.LeaveReviewed, &templates.ReviewData{EmployeeName: employee.EmployeeName, Leaves: leaves}, leaves,
+		attachments...)
+}
+
+// DeliverLeaveDecisions delivers OutboxTopicLeaveDecided messages. A single
+// decision gets its own approval or rejection notification; decisions grouped
+// by a bulk review are summarised in one.
+func (s *NotificationService) DeliverLeaveDecisions(ctx context.Context, messages []*models.OutboxMessage) error {
+	leaves := make([]*models.LeaveRequest, len(messages))
+	for i, msg := range messages {
+		if err := json.Unmarshal(msg.Payload, &leaves[i]); err != nil {
+			return fmt.Errorf("failed to decode leave decision %s: %w", msg.ID, err)
+		}
+	}
+
+	if len(leaves) > 1 {
+		return s.NotifyLeaveReviewed(ctx, leaves)
+	}
+	switch leaves[0].Status {
+	case models.LeaveStatusApproved:
+		return s.NotifyLeaveApproved(ctx, leaves[0])
+	case models.LeaveStatusRejected:
+		return s.NotifyLeaveRejected(ctx, leaves[0])
+	}
+	return fmt.Errorf("leave decision %s has status %s", messages[0].ID, leaves[0].Status)
+}
+
+// DeliverLeaveActivity delivers OutboxTopicLeaveActivity messages. Approvers
+// are told about each submission, edit and cancellation; the employee also
+// gets a confirmation of their submission.
+func (s *NotificationService) DeliverLeaveActivity(ctx context.Context, messages []*models.OutboxMessage) error {
+	for _, msg := range messages {
+		var activity models.LeaveActivity
+		if err := json.Unmarshal(msg.Payload, &activity); err != nil {
+			return fmt.Errorf("failed to decode leave activity %s: %w", msg.ID, err)
+		}
+		if activity.Leave == nil {
+			return fmt.Errorf("leave activity %s has no leave request", msg.ID)
+		}
+		if err := s.NotifyLeaveActivity(ctx, &activity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyLeaveActivity notifies approvers of activity, and the employee when a
+// request was submitted
+func (s *NotificationService) NotifyLeaveActivity(ctx context.Context, activity *models.LeaveActivity) error {
+	leave := activity.Leave
+
+	var event notify.Event
+	var name string
+	switch activity.Action {
+	case models.LeaveActivitySubmitted:
+		event, name = notify.EventLeaveSubmitted, templates.LeaveSubmitted
+	case models.LeaveActivityUpdated:
+		event, name = notify.EventLeaveUpdated, templates.LeaveUpdated
+	case models.LeaveActivityCancelled:
+		event, name = notify.EventLeaveCancelled, templates.LeaveCancelled
+	default:
+		return fmt.Errorf("unknown leave activity %q", activity.Action)
+	}
+
+	// Approvers are written to in the default locale, and can decide a
+	// pending request from the email
+	to := s.approvers(leave)
+	data := &templates.LeaveData{Leave: leave, TeamAbsences: activity.TeamAbsences}
+	if activity.Action != models.LeaveActivityCancelled && len(to) > 0 {
+		data.ApproveURL, data.RejectURL = s.actions.Links(leave, strings.Join(to, ","))
+	}
+	if err := s.send(ctx, event, to, "", name, data, activity); err != nil {
+		return err
+	}
+
+	if activity.Action != models.LeaveActivitySubmitted {
+		return nil
+	}
+	return s.send(ctx, notify.EventLeaveReceived, []string{leave.EmployeeEmail}, leave.Locale,
+		templates.LeaveReceived, &templates.LeaveData{Leave: leave}, leave)
+}
+
+// NotifyDigest sends the daily digest in data to the owner of prefs, with
+// links deciding each pending request when email actions are on
+func (s *NotificationService) NotifyDigest(ctx context.Context, prefs *models.NotificationPreferences, data *templates.DigestData) error {
+	for _, item := range data.Pending {
+		item.ApproveURL, item.RejectURL = s.actions.Links(item.Leave, prefs.Email)
+	}
+	return s.send(ctx, notify.EventDailyDigest, []string{prefs.Email}, prefs.Locale,
+		templates.DailyDigest, data, data)
+}
+
+// DeliverCalendarChanges delivers OutboxTopicLeaveCalendar messages
+func (s *NotificationService) DeliverCalendarChanges(ctx context.Context, messages []*models.OutboxMessage) error {
+	for _, msg := range messages {
+		var change models.CalendarChange
+		if err := json.Unmarshal(msg.Payload, &change); err != nil {
+			return fmt.Errorf("failed to decode calendar change %s: %w", msg.ID, err)
+		}
+		if change.Leave == nil {
+			return fmt.Errorf("calendar change %s has no leave request", msg.ID)
+		}
+		if err := s.NotifyCalendarChange(ctx, &change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyCalendarChange tells the employee their approved leave was withdrawn
+// or moved. A withdrawal carries a cancellation of the calendar event sent on
+// approval; a move carries an update of it.
+func (s *NotificationService) NotifyCalendarChange(ctx context.Context, change *models.CalendarChange) error {
+	leave := change.Leave
+	to := []string{leave.EmployeeEmail}
+	data := &templates.LeaveData{Leave: leave}
+
+	switch change.Action {
+	case models.CalendarChangeWithdrawn:
+		return s.send(ctx, notify.EventLeaveWithdrawn, to, leave.Locale, templates.LeaveWithdrawn, data, change,
+			s.calendar(ical.MethodCancel, leave))
+	case models.CalendarChangeRescheduled:
+		return s.send(ctx, notify.EventLeaveRescheduled, to, leave.Locale, templates.LeaveRescheduled, data, change,
+			s.calendar(ical.MethodRequest, leave))
+	}
+	return fmt.Errorf("unknown calendar change %q", change.Action)
+}
+
+// calendar returns an iCalendar attachment of leaves for their employee
+func (s *NotificationService) calendar(method ical.Method, leaves ...*models.LeaveRequest) notify.Attachment {
+	cal := &ical.Calendar{Method: method}
+	for _, leave := range leaves {
+		event := leaveEvent(leave)
+		event.Organizer = s.organizer
+		event.Attendees = []ical.Person{{Name: leave.EmployeeName, Email: leave.EmployeeEmail}}
+		cal.Events = append(cal.Events, event)
+	}
+
+	filename := "invite.ics"
+	if method == ical.MethodCancel {
+		filename = "cancel.ics"
+	}
+	return notify.Attachment{
+		Filename:    filename,
+		ContentType: fmt.Sprintf("%s; charset=utf-8; method=%s", ical.ContentType, method),
+		Data:        cal.Encode(),
+	}
+}
+
+// approvers returns who to notify about activity on leave: its assigned
+// approver, otherwise the configured approver address
+func (s *NotificationService) approvers(leave *models.LeaveRequest) []string {
+	if leave.ApproverEmail != "" {
+		return []string{leave.ApproverEmail}
+	}
+	if s.approverAddress != "" {
+		return []string{s.approverAddress}
+	}
+	return nil
+}
+
+// approvedBy returns the approver emails of the requests whose approver
+// notifications reach email: its own, and none at all when email is the
+// fallback approver address
+func (s *NotificationService) approvedBy(email string) []string {
+	if email == "" {
+		return nil
+	}
+	emails := []string{email}
+	if s.approverAddress != "" && strings.EqualFold(email, s.approverAddress) {
+		emails = append(emails, "")
+	}
+	return emails
+}
+
+// DeliverComments delivers OutboxTopicLeaveComment messages
+func (s *NotificationService) DeliverComments(ctx context.Context, messages []*models.OutboxMessage) error {
+	for _, msg := range messages {
+		var notice models.CommentNotice
+		if err := json.Unmarshal(msg.Payload, &notice); err != nil {
+			return fmt.Errorf("failed to decode comment notice %s: %w", msg.ID, err)
+		}
+		if notice.Comment == nil {
+			return fmt.Errorf("comment notice %s has no comment", msg.ID)
+		}
+		if err := s.NotifyNewComment(ctx, notice.Recipients, notice.Comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyNewComment notifies the other party when a comment is posted on a
+// leave request, with one notification per locale among the recipients
+func (s *NotificationService) NotifyNewComment(ctx context.Context, recipients []models.Recipient, comment *models.LeaveComment) error {
+	var locales []string
+	byLocale := make(map[string][]string)
+	for _, r := range recipients {
+		locale := s.renderer.Locale(r.Locale)
+		if _, seen := byLocale[locale]; !seen {
+			locales = append(locales, locale)
+		}
+		byLocale[locale] = append(byLocale[locale], r.Email)
+	}
+
+	data := &templates.CommentData{Comment: comment}
+	for _, locale := range locales {
+		if err := s.send(ctx, notify.EventCommentAdded, byLocale[locale], locale, templates.CommentAdded, data, comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send renders message name in locale and notifies to of event. payload is
+// what the event is about, for webhook consumers.
+func (s *NotificationService) send(ctx context.Context, event notify.Event, to []string, locale, name string, data, payload interface{}, attachments ...notify.Attachment) error {
+	msg, err := s.renderer.Render(ctx, name, locale, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %s notification: %w", name, err)
+	}
+	return s.notifier.Notify(ctx, &notify.Notification{
+		Event:       event,
+		To:          to,
+		Subject:     msg.Subject,
+		Text:        msg.Text,
+		HTML:        msg.HTML,
+		Summary:     msg.Summary,
+		Data:        payload,
+		Attachments: attachments,
+	})
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"leave-management-system/internal/config"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/notify"
+	"leave-management-system/internal/templates"
+)
+
+// recordingNotifier records notifications instead of delivering them
+type recordingNotifier struct {
+	sent []*notify.Notification
+	err  error
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, n *notify.Notification) error {
+	r.sent = append(r.sent, n)
+	return r.err
+}
+
+// newTestNotificationService creates a notification service rendering the built-in templates
+func newTestNotificationService(notifier notify.Notifier, approverAddress string) *NotificationService {
+	renderer, _ := templates.NewRenderer(nil, "en")
+	return NewNotificationService(notifier, renderer, approverAddress, "Leave Management System <noreply@company.com>", nil)
+}
+
+func testLeave(status models.LeaveStatus, comment string) *models.LeaveRequest {
+	return &models.LeaveRequest{
+		EmployeeName:   "John Doe",
+		EmployeeEmail:  "john@example.com",
+		LeaveType:      models.LeaveTypeAnnual,
+		StartDate:      models.NewDate(2024, 1, 1),
+		EndDate:        models.NewDate(2024, 1, 5),
+		Days:           5,
+		Reason:         "Vacation",
+		Status:         status,
+		ManagerComment: sql.NullString{String: comment, Valid: comment != ""},
+	}
+}
+
+func TestNotificationService_NotifyLeaveApproved(t *testing.T) {
+	notifier := &recordingNotifier{}
+	service := newTestNotificationService(notifier, "")
+	leave := testLeave(models.LeaveStatusApproved, "Enjoy your vacation!")
+
+	if err := service.NotifyLeaveApproved(context.Background(), leave); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(notifier.sent) != 1 {
+		t.Fatalf("expected one notification, got %d", len(notifier.sent))
+	}
+	n := notifier.sent[0]
+	if n.Event != notify.EventLeaveApproved || len(n.To) != 1 || n.To[0] != "john@example.com" || n.Data != leave {
+		t.Errorf("unexpected notification: %+v", n)
+	}
+	if !strings.Contains(n.Text, "has been approved") || !strings.Contains(n.Text, "Enjoy your vacation!") {
+		t.Errorf("expected the email body to include the decision and comment, got %q", n.Text)
+	}
+	if n.Summary != "John Doe's annual leave was approved: January 1, 2024 to January 5, 2024 (5 days)" {
+		t.Errorf("unexpected summary %q", n.Summary)
+	}
+	if !strings.Contains(n.HTML, "<strong>approved</strong>") || !strings.Contains(n.HTML, "Enjoy your vacation!") {
+		t.Errorf("expected an HTML version of the email, got %q", n.HTML)
+	}
+
+	// The approval carries a calendar invite that later changes replace
+	if len(n.Attachments) != 1 {
+		t.Fatalf("expected a calendar invite, got %d attachments", len(n.Attachments))
+	}
+	invite := n.Attachments[0]
+	ics := strings.ReplaceAll(string(invite.Data), "\r\n ", "")
+	if invite.Filename != "invite.ics" || invite.ContentType != "text/calendar; charset=utf-8; method=REQUEST" {
+		t.Errorf("unexpected attachment %s (%s)", invite.Filename, invite.ContentType)
+	}
+	for _, want := range []string{"METHOD:REQUEST", "UID:" + leaveUID(leave.ID), "SEQUENCE:0", "DTSTART;VALUE=DATE:20240101", "DTEND;VALUE=DATE:20240106", "TRANSP:TRANSPARENT", "mailto:noreply@company.com", "mailto:john@example.com"} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("expected %q in the invite, got\n%s", want, ics)
+		}
+	}
+
+	// The employee is written to in the locale of their request
+	notifier.sent = nil
+	leave.Locale = "th"
+	if err := service.NotifyLeaveApproved(context.Background(), leave); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := notifier.sent[0]; n.Subject != "คำขอลาได้รับการอนุมัติ" || !strings.Contains(n.Text, "1 มกราคม 2567") {
+		t.Errorf("expected a Thai email, got %+v", n)
+	}
+}
+
+func TestNotificationService_NotifyLeaveRejected(t *testing.T) {
+	notifier := &recordingNotifier{}
+	service := newTestNotificationService(notifier, "")
+
+	if err := service.NotifyLeaveRejected(context.Background(), testLeave(models.LeaveStatusRejected, "Not enough leave balance")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	n := notifier.sent[0]
+	if n.Event != notify.EventLeaveRejected || !strings.Contains(n.Text, "Not enough leave balance") {
+		t.Errorf("unexpected notification: %+v", n)
+	}
+}
+
+func TestNotificationService_NotifyError(t *testing.T) {
+	service := newTestNotificationService(&recordingNotifier{err: errors.New("slack: unexpected response 500")}, "")
+
+	if err := service.NotifyLeaveApproved(context.Background(), testLeave(models.LeaveStatusApproved, "")); err == nil {
+		t.Error("expected the notifier error to be returned")
+	}
+}
+
+func TestNotificationService_DeliverLeaveDecisions(t *testing.T) {
+	notifier := &recordingNotifier{}
+	service := newTestNotificationService(notifier, "")
+
+	message := func(leave *models.LeaveRequest) *models.OutboxMessage {
+		payload, _ := json.Marshal(leave)
+		return &models.OutboxMessage{ID: uuid.New(), Topic: models.OutboxTopicLeaveDecided, Payload: payload}
+	}
+	approved := message(testLeave(models.LeaveStatusApproved, ""))
+	rejected := message(testLeave(models.LeaveStatusRejected, "Team is short-staffed"))
+
+	if err := service.DeliverLeaveDecisions(context.Background(), []*models.OutboxMessage{approved}); err != nil {
+		t.Errorf("unexpected error for a single decision: %v", err)
+	}
+	if err := service.DeliverLeaveDecisions(context.Background(), []*models.OutboxMessage{approved, rejected}); err != nil {
+		t.Errorf("unexpected error for grouped decisions: %v", err)
+	}
+	if len(notifier.sent) != 2 || notifier.sent[0].Event != notify.EventLeaveApproved || notifier.sent[1].Event != notify.EventLeaveReviewed {
+		t.Fatalf("expected an approval then a review summary, got %+v", notifier.sent)
+	}
+	if review := notifier.sent[1]; review.Subject != "2 Leave Requests Reviewed" || !strings.Contains(review.Text, "Team is short-staffed") {
+		t.Errorf("unexpected review summary: %+v", review)
+	}
+
+	// Undeliverable messages fail so they are dead-lettered rather than dropped
+	pending := message(&models.LeaveRequest{Status: models.LeaveStatusPending})
+	if err := service.DeliverLeaveDecisions(context.Background(), []*models.OutboxMessage{pending}); err == nil {
+		t.Errorf("expected an error for a leave that was not decided")
+	}
+	corrupt := &models.OutboxMessage{ID: uuid.New(), Payload: []byte(`"oops"`)}
+	if err := service.DeliverLeaveDecisions(context.Background(), []*models.OutboxMessage{corrupt}); err == nil {
+		t.Errorf("expected an error for an undecodable payload")
+	}
+}
+
+func TestNotificationService_DeliverCalendarChanges(t *testing.T) {
+	notifier := &recordingNotifier{}
+	service := newTestNotificationService(notifier, "")
+
+	message := func(action models.CalendarChangeAction, leave *models.LeaveRequest) *models.OutboxMessage {
+		payload, _ := json.Marshal(&models.CalendarChange{Action: action, Leave: leave})
+		return &models.OutboxMessage{ID: uuid.New(), Topic: models.OutboxTopicLeaveCalendar, Payload: payload}
+	}
+	leave := testLeave(models.LeaveStatusApproved, "")
+	leave.ID = uuid.New()
+	leave.Version = 4
+	withdrawn := testLeave(models.LeaveStatusCancelled, "")
+	withdrawn.ID = leave.ID
+	withdrawn.Version = 5
+
+	if err := service.DeliverCalendarChanges(context.Background(), []*models.OutboxMessage{
+		message(models.CalendarChangeRescheduled, leave),
+		message(models.CalendarChangeWithdrawn, withdrawn),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.sent) != 2 {
+		t.Fatalf("expected two notifications, got %d", len(notifier.sent))
+	}
+
+	moved, cancelled := notifier.sent[0], notifier.sent[1]
+	if moved.Event != notify.EventLeaveRescheduled || moved.Subject != "Approved Leave Rescheduled" || len(moved.Attachments) != 1 {
+		t.Fatalf("unexpected notification: %+v", moved)
+	}
+	if ics := string(moved.Attachments[0].Data); !strings.Contains(ics, "METHOD:REQUEST") || !strings.Contains(ics, "UID:"+leaveUID(leave.ID)) || !strings.Contains(ics, "SEQUENCE:4") {
+		t.Errorf("expected an update of the event, got\n%s", ics)
+	}
+	if cancelled.Event != notify.EventLeaveWithdrawn || cancelled.Subject != "Approved Leave Withdrawn" || len(cancelled.Attachments) != 1 {
+		t.Fatalf("unexpected notification: %+v", cancelled)
+	}
+	if a := cancelled.Attachments[0]; a.Filename != "cancel.ics" || !strings.Contains(string(a.Data), "METHOD:CANCEL") ||
+		!strings.Contains(string(a.Data), "UID:"+leaveUID(leave.ID)) || !strings.Contains(string(a.Data), "SEQUENCE:5") {
+		t.Errorf("expected a cancellation of the event, got %s\n%s", a.Filename, a.Data)
+	}
+
+	if err := service.DeliverCalendarChanges(context.Background(), []*models.OutboxMessage{message("moved", leave)}); err == nil {
+		t.Errorf("expected an error for an unknown change")
+	}
+}
+
+func TestNotificationService_NotifyLeaveActivity(t *testing.T) {
+	notifier := &recordingNotifier{}
+	service := newTestNotificationService(notifier, "approvers@example.com")
+
+	leave := testLeave(models.LeaveStatusPending, "")
+	leave.Department = "Engineering"
+	jane := &models.LeaveRequest{EmployeeName: "Jane Smith", LeaveType: models.LeaveTypeSick, StartDate: models.NewDate(2024, 1, 2), EndDate: models.NewDate(2024, 1, 3)}
+
+	submitted := &models.LeaveActivity{Action: models.LeaveActivitySubmitted, Leave: leave, TeamAbsences: []*models.LeaveRequest{jane}}
+	if err := service.NotifyLeaveActivity(context.Background(), submitted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Approvers, then the employee's confirmation
+	if len(notifier.sent) != 2 {
+		t.Fatalf("expected two notifications, got %d", len(notifier.sent))
+	}
+	approvers, receipt := notifier.sent[0], notifier.sent[1]
+	if approvers.Event != notify.EventLeaveSubmitted || len(approvers.To) != 1 || approvers.To[0] != "approvers@example.com" {
+		t.Errorf("expected the configured approver address to be notified, got %+v", approvers)
+	}
+	if !strings.Contains(approvers.Text, "Already off in Engineering on these dates:\n- Jane Smith: sick leave, January 2, 2024 to January 3, 2024") {
+		t.Errorf("expected the team absences in the email, got %q", approvers.Text)
+	}
+	if !strings.HasSuffix(approvers.Summary, "(1 already off in Engineering)") {
+		t.Errorf("expected the team absences in the summary, got %q", approvers.Summary)
+	}
+	if receipt.Event != notify.EventLeaveReceived || receipt.To[0] != "john@example.com" {
+		t.Errorf("expected a confirmation to the employee, got %+v", receipt)
+	}
+
+	// An assigned approver is notified instead, and only submissions are confirmed
+	notifier.sent = nil
+	leave.ApproverEmail = "manager@example.com"
+	cancelled := &models.LeaveActivity{Action: models.LeaveActivityCancelled, Leave: leave}
+	if err := service.NotifyLeaveActivity(context.Background(), cancelled); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0].Event != notify.EventLeaveCancelled || notifier.sent[0].To[0] != "manager@example.com" {
+		t.Fatalf("expected one cancellation to the assigned approver, got %+v", notifier.sent)
+	}
+	if !strings.Contains(notifier.sent[0].Text, "No one else in Engineering is off on these dates.") {
+		t.Errorf("expected a clear team calendar to be mentioned, got %q", notifier.sent[0].Text)
+	}
+
+	if err := service.NotifyLeaveActivity(context.Background(), &models.LeaveActivity{Action: "archived", Leave: leave}); err == nil {
+		t.Error("expected an error for an unknown activity")
+	}
+}
+
+func TestNotificationService_NotifyLeaveActivityActionLinks(t *testing.T) {
+	notifier := &recordingNotifier{}
+	renderer, _ := templates.NewRenderer(nil, "en")
+	actions := NewEmailActionService(nil, "https://leave.example.com", config.EmailConfig{ActionSecret: "test-secret", ActionTTL: time.Hour})
+	service := NewNotificationService(notifier, renderer, "approvers@example.com", "Leave Management System <noreply@company.com>", actions)
+
+	leave := testLeave(models.LeaveStatusPending, "")
+	leave.ID = uuid.New()
+	if err := service.NotifyLeaveActivity(context.Background(), &models.LeaveActivity{Action: models.LeaveActivityUpdated, Leave: leave}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	approvers := notifier.sent[0]
+	if !strings.Contains(approvers.Text, "- Approve: https://leave.example.com/email-actions/") || !strings.Contains(approvers.HTML, "https://leave.example.com/email-actions/") {
+		t.Errorf("expected the action links in the approver email, got %q", approvers.Text)
+	}
+
+	// A cancelled request has nothing left to decide
+	notifier.sent = nil
+	if err := service.NotifyLeaveActivity(context.Background(), &models.LeaveActivity{Action: models.LeaveActivityCancelled, Leave: leave}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(notifier.sent[0].Text, "email-actions") {
+		t.Errorf("expected no action links for a cancellation, got %q", notifier.sent[0].Text)
+	}
+}
+
+func TestNotificationService_NotifyNewComment(t *testing.T) {
+	notifier := &recordingNotifier{}
+	service := newTestNotificationService(notifier, "")
+	comment := &models.LeaveComment{LeaveRequestID: uuid.New(), AuthorName: "Jane Smith", Body: "Please add a handover note."}
+
+	recipients := []models.Recipient{
+		{Email: "john@example.com", Locale: "th-TH"},
+		{Email: "raj@example.com"},
+		{Email: "somchai@example.com", Locale: "th"},
+	}
+	if err := service.NotifyNewComment(context.Background(), recipients, comment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// One notification per locale
+	if len(notifier.sent) != 2 {
+		t.Fatalf("expected two notifications, got %d", len(notifier.sent))
+	}
+	thai, english := notifier.sent[0], notifier.sent[1]
+	if len(thai.To) != 2 || !strings.HasPrefix(thai.Subject, "ความคิดเห็นใหม่จาก Jane Smith") {
+		t.Errorf("unexpected Thai notification: %+v", thai)
+	}
+	if len(english.To) != 1 || english.To[0] != "raj@example.com" || english.Subject != "New comment from Jane Smith on a leave request" {
+		t.Errorf("unexpected English notification: %+v", english)
+	}
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/config"
+	"leave-management-system/internal/logger"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+)
+
+// outboxLease is how long a claimed message is reserved for the dispatcher
+// that claimed it. A message still processing after its lease, e.g. because
+// the process stopped mid-delivery, is claimed again.
+const outboxLease = 5 * time.Minute
+
+// outboxLeaseMargin is left of the lease to record the outcome of deliveries,
+// which must finish before the lease expires
+const outboxLeaseMargin = 30 * time.Second
+
+var (
+	ErrOutboxMessageNotFound = errors.New("outbox message not found")
+	ErrOutboxNotReplayable   = errors.New("only dead messages can be replayed")
+)
+
+// DeliverFunc delivers outbox messages of one topic. Messages sharing a group
+// key are passed together; others are passed one at a time. An error fails
+// every message passed, so they are retried together.
+type DeliverFunc func(ctx context.Context, messages []*models.OutboxMessage) error
+
+// enqueue records a notification in outbox to be delivered after delay. It is
+// meant to be called with the outbox of the transaction making the change the
+// notification announces, so one is never committed without the other.
+func enqueue(ctx context.Context, outbox repository.OutboxRepository, topic models.OutboxTopic, groupKey string, payload interface{}, delay time.Duration) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s notification: %w", topic, err)
+	}
+
+	now := time.Now()
+	msg := &models.OutboxMessage{
+		ID:            uuid.New(),
+		Topic:         topic,
+		GroupKey:      groupKey,
+		Payload:       data,
+		Status:        models.OutboxStatusPending,
+		NextAttemptAt: now.Add(delay),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := outbox.Create(ctx, msg); err != nil {
+		return fmt.Errorf("failed to queue %s notification: %w", topic, err)
+	}
+	return nil
+}
+
+// OutboxDispatcher delivers outbox messages in the background. Failed
+// deliveries are retried with exponential backoff until MaxAttempts, after
+// which the message is dead-lettered for an admin to inspect and replay.
+type OutboxDispatcher struct {
+	repo     repository.OutboxRepository
+	cfg      config.OutboxConfig
+	handlers map[models.OutboxTopic]DeliverFunc
+	logger   *logger.Logger
+}
+
+// NewOutboxDispatcher creates a new outbox dispatcher with the given retry policy
+func NewOutboxDispatcher(repo repository.OutboxRepository, cfg config.OutboxConfig) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		repo:     repo,
+		cfg:      cfg,
+		handlers: make(map[models.OutboxTopic]DeliverFunc),
+		logger:   logger.New().With("component", "outbox"),
+	}
+}
+
+// Handle registers deliver for messages of topic. Messages of a topic without
+// a handler fail and are eventually dead-lettered.
+func (d *OutboxDispatcher) Handle(topic models.OutboxTopic, deliver DeliverFunc) {
+	d.handlers[topic] = deliver
+}
+
+// Run polls for due messages until ctx is cancelled. A batch already claimed
+// is delivered to the end, so Run returns only once in-flight deliveries
+// have been recorded.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			// Deliveries are not interrupted by shutdown
+			claimed, err := d.DispatchDue(context.WithoutCancel(ctx))
+			if err != nil {
+				d.logger.Errorf("outbox_dispatch_failed error=%v", err)
+			}
+			// A full batch suggests more are due
+			if err != nil || claimed < d.cfg.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue claims one batch of due messages and delivers them. It returns
+// how many messages were claimed.
+func (d *OutboxDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	claimedAt := time.Now()
+	messages, err := d.repo.ClaimDue(ctx, claimedAt, d.cfg.BatchSize, outboxLease)
+	if err != nil {
+		return 0, err
+	}
+
+	deadline := claimedAt.Add(outboxLease - outboxLeaseMargin)
+	for _, group := range groupMessages(messages) {
+		d.deliver(ctx, deadline, group)
+	}
+	return len(messages), nil
+}
+
+// deliver hands a group of messages of one topic to its handler, which must
+// finish by deadline, and records the outcome
+func (d *OutboxDispatcher) deliver(ctx context.Context, deadline time.Time, group []*models.OutboxMessage) {
+	topic := group[0].Topic
+	deliver, ok := d.handlers[topic]
+	var err error
+	if !ok {
+		err = fmt.Errorf("no handler for topic %s", topic)
+	} else {
+		deliverCtx, cancel := context.WithDeadline(ctx, deadline)
+		err = deliver(deliverCtx, group)
+		cancel()
+	}
+
+	now := time.Now()
+	for _, msg := range group {
+		if err == nil {
+			if markErr := d.repo.MarkDelivered(ctx, msg.ID, now); markErr != nil {
+				// The lease expires and the message is delivered again
+				d.logger.Errorf("outbox_mark_failed message_id=%s topic=%s error=%v", msg.ID, topic, markErr)
+				continue
+			}
+			d.logger.Debugf("outbox_delivered message_id=%s topic=%s attempts=%d", msg.ID, topic, msg.Attempts)
+			continue
+		}
+
+		msg.LastError = err.Error()
+		msg.UpdatedAt = now
+		if msg.Attempts >= d.cfg.MaxAttempts {
+			msg.Status = models.OutboxStatusDead
+			msg.NextAttemptAt = now
+			d.logger.Errorf("outbox_message_dead message_id=%s topic=%s attempts=%d error=%v", msg.ID, topic, msg.Attempts, err)
+		} else {
+			msg.Status = models.OutboxStatusPending
+			msg.NextAttemptAt = now.Add(d.backoff(msg.Attempts))
+			d.logger.Warnf("outbox_delivery_failed message_id=%s topic=%s attempts=%d next_attempt_at=%s error=%v", msg.ID, topic, msg.Attempts, msg.NextAttemptAt.Format(time.RFC3339), err)
+		}
+		if markErr := d.repo.MarkFailed(ctx, msg); markErr != nil {
+			d.logger.Errorf("outbox_mark_failed message_id=%s topic=%s error=%v", msg.ID, topic, markErr)
+		}
+	}
+}
+
+// RetryHorizon is how long after it was queued a message may still be
+// delivered before it is dead-lettered: the backoff before each retry, and a
+// lease for every attempt
+func (d *OutboxDispatcher) RetryHorizon() time.Duration {
+	horizon := time.Duration(d.cfg.MaxAttempts) * outboxLease
+	for attempts := 1; attempts < d.cfg.MaxAttempts; attempts++ {
+		horizon += d.backoff(attempts)
+	}
+	return horizon
+}
+
+// backoff returns the delay before the retry following attempt number
+// attempts: BaseBackoff doubled for each earlier attempt, capped at MaxBackoff
+func (d *OutboxDispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < d.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.cfg.MaxBackoff {
+		delay = d.cfg.MaxBackoff
+	}
+	return delay
+}
+
+// groupMessages splits claimed messages into delivery groups by topic and
+// group key, keeping the order they were claimed in
+func groupMessages(messages []*models.OutboxMessage) [][]*models.OutboxMessage {
+	var groups [][]*models.OutboxMessage
+	index := make(map[string]int)
+	for _, msg := range messages {
+		if msg.GroupKey == "" {
+			groups = append(groups, []*models.OutboxMessage{msg})
+			continue
+		}
+		key := string(msg.Topic) + "\x00" + msg.GroupKey
+		if i, ok := index[key]; ok {
+			groups[i] = append(groups[i], msg)
+			continue
+		}
+		index[key] = len(groups)
+		groups = append(groups, []*models.OutboxMessage{msg})
+	}
+	return groups
+}
+
+// OutboxService lets admins inspect outbox messages and replay dead ones
+type OutboxService struct {
+	repo repository.OutboxRepository
+}
+
+// NewOutboxService creates a new outbox service
+func NewOutboxService(repo repository.OutboxRepository) *OutboxService {
+	return &OutboxService{repo: repo}
+}
+
+// ListMessages lists outbox messages matching filter, newest first
+func (s *OutboxService) ListMessages(ctx context.Context, filter models.OutboxFilter) ([]*models.OutboxMessage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageLimit
+	}
+	if filter.Limit > MaxPageLimit {
+		filter.Limit = MaxPageLimit
+	}
+
+	messages, err := s.repo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox messages: %w", err)
+	}
+	return messages, nil
+}
+
+// ReplayMessage makes a dead message due again with a fresh set of attempts
+func (s *OutboxService) ReplayMessage(ctx context.Context, id uuid.UUID) (*models.OutboxMessage, error) {
+	existing, err := s.repo.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrOutboxMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find outbox message: %w", err)
+	}
+	if existing.Status != models.OutboxStatusDead {
+		return nil, fmt.Errorf("%w: message is %s", ErrOutboxNotReplayable, existing.Status)
+	}
+
+	msg, err := s.repo.Replay(ctx, id, time.Now())
+	if errors.Is(err, repository.ErrConflict) {
+		return nil, ErrConflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay outbox message: %w", err)
+	}
+	return msg, nil
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/config"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+)
+
+func testOutboxConfig() config.OutboxConfig {
+	return config.OutboxConfig{
+		PollInterval: time.Second,
+		BatchSize:    10,
+		MaxAttempts:  3,
+		BaseBackoff:  time.Minute,
+		MaxBackoff:   3 * time.Minute,
+	}
+}
+
+// queue adds a due message to repo
+func queue(t *testing.T, repo *repository.MockOutboxRepository, topic models.OutboxTopic, groupKey string) uuid.UUID {
+	t.Helper()
+	if err := enqueue(context.Background(), repo, topic, groupKey, map[string]string{"hello": "world"}, 0); err != nil {
+		t.Fatalf("failed to queue message: %v", err)
+	}
+	messages, _ := repo.FindAll(context.Background(), models.OutboxFilter{Limit: 1})
+	return messages[0].ID
+}
+
+func findMessage(t *testing.T, repo *repository.MockOutboxRepository, id uuid.UUID) *models.OutboxMessage {
+	t.Helper()
+	msg, err := repo.FindByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to find message: %v", err)
+	}
+	return msg
+}
+
+func TestOutboxDispatcher_DeliversGroupsTogether(t *testing.T) {
+	repo := repository.NewMockOutboxRepository()
+	dispatcher := NewOutboxDispatcher(repo, testOutboxConfig())
+
+	var deliveries [][]uuid.UUID
+	dispatcher.Handle(models.OutboxTopicLeaveDecided, func(ctx context.Context, messages []*models.OutboxMessage) error {
+		var ids []uuid.UUID
+		for _, msg := range messages {
+			ids = append(ids, msg.ID)
+		}
+		deliveries = append(deliveries, ids)
+		return nil
+	})
+
+	single := queue(t, repo, models.OutboxTopicLeaveDecided, "")
+	first := queue(t, repo, models.OutboxTopicLeaveDecided, "batch:emp-1")
+	// Grouped messages are claimed with the group even before they are due
+	if err := enqueue(context.Background(), repo, models.OutboxTopicLeaveDecided, "batch:emp-1", nil, time.Hour); err != nil {
+		t.Fatalf("failed to queue message: %v", err)
+	}
+
+	claimed, err := dispatcher.DispatchDue(context.Background())
+	if err != nil || claimed != 3 {
+		t.Fatalf("expected 3 claimed messages, got %d (%v)", claimed, err)
+	}
+	if len(deliveries) != 2 || len(deliveries[0]) != 1 || deliveries[0][0] != single || len(deliveries[1]) != 2 || deliveries[1][0] != first {
+		t.Fatalf("expected the single message and the group delivered separately, got %v", deliveries)
+	}
+
+	msg := findMessage(t, repo, first)
+	if msg.Status != models.OutboxStatusDelivered || msg.DeliveredAt == nil || msg.Attempts != 1 {
+		t.Errorf("expected the message delivered after one attempt, got %+v", msg)
+	}
+	if claimed, _ := dispatcher.DispatchDue(context.Background()); claimed != 0 {
+		t.Errorf("expected delivered messages not to be claimed again, got %d", claimed)
+	}
+}
+
+func TestOutboxDispatcher_RetriesWithBackoffThenDeadLetters(t *testing.T) {
+	repo := repository.NewMockOutboxRepository()
+	dispatcher := NewOutboxDispatcher(repo, testOutboxConfig())
+	dispatcher.Handle(models.OutboxTopicLeaveDecided, func(ctx context.Context, messages []*models.OutboxMessage) error {
+		return errors.New("smtp unavailable")
+	})
+	id := queue(t, repo, models.OutboxTopicLeaveDecided, "")
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		before := time.Now()
+		if claimed, err := dispatcher.DispatchDue(context.Background()); err != nil || claimed != 1 {
+			t.Fatalf("attempt %d: expected the message to be due, got %d (%v)", attempt, claimed, err)
+		}
+
+		msg := findMessage(t, repo, id)
+		if msg.Attempts != attempt || msg.LastError != "smtp unavailable" {
+			t.Fatalf("attempt %d: unexpected message %+v", attempt, msg)
+		}
+		if attempt == 3 {
+			if msg.Status != models.OutboxStatusDead {
+				t.Fatalf("expected the message dead-lettered after %d attempts, got %s", attempt, msg.Status)
+			}
+			break
+		}
+
+		// 1m then 2m; the message is not due again until then
+		wantDelay := time.Duration(attempt) * time.Minute
+		if msg.Status != models.OutboxStatusPending || msg.NextAttemptAt.Before(before.Add(wantDelay)) || msg.NextAttemptAt.After(time.Now().Add(wantDelay)) {
+			t.Fatalf("attempt %d: expected a retry in %s, got %+v", attempt, wantDelay, msg)
+		}
+		if claimed, _ := dispatcher.DispatchDue(context.Background()); claimed != 0 {
+			t.Fatalf("attempt %d: expected no retry before the backoff elapsed", attempt)
+		}
+		// Make the retry due
+		repo.MarkFailed(context.Background(), &models.OutboxMessage{ID: id, Status: models.OutboxStatusPending, LastError: msg.LastError, NextAttemptAt: time.Now()})
+	}
+
+	if claimed, _ := dispatcher.DispatchDue(context.Background()); claimed != 0 {
+		t.Errorf("expected dead messages not to be claimed")
+	}
+}
+
+func TestOutboxDispatcher_UnknownTopicFails(t *testing.T) {
+	repo := repository.NewMockOutboxRepository()
+	dispatcher := NewOutboxDispatcher(repo, testOutboxConfig())
+	id := queue(t, repo, "leave.unknown", "")
+
+	dispatcher.DispatchDue(context.Background())
+
+	if msg := findMessage(t, repo, id); msg.Status != models.OutboxStatusPending || msg.LastError == "" {
+		t.Errorf("expected a failed attempt for a topic without a handler, got %+v", msg)
+	}
+}
+
+func TestOutboxDispatcher_Backoff(t *testing.T) {
+	dispatcher := NewOutboxDispatcher(repository.NewMockOutboxRepository(), config.OutboxConfig{
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  time.Hour,
+	})
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{5, 8 * time.Minute},
+		{8, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := dispatcher.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestOutboxDispatcher_RetryHorizon(t *testing.T) {
+	dispatcher := NewOutboxDispatcher(repository.NewMockOutboxRepository(), config.OutboxConfig{
+		MaxAttempts: 3,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  time.Hour,
+	})
+
+	// Two backoffs between three attempts, each of which may hold a lease
+	want := 30*time.Second + time.Minute + 3*outboxLease
+	if got := dispatcher.RetryHorizon(); got != want {
+		t.Errorf("RetryHorizon() = %s, want %s", got, want)
+	}
+}
+
+func TestOutboxDispatcher_RunStopsOnCancel(t *testing.T) {
+	repo := repository.NewMockOutboxRepository()
+	dispatcher := NewOutboxDispatcher(repo, testOutboxConfig())
+	delivered := make(chan struct{}, 1)
+	dispatcher.Handle(models.OutboxTopicLeaveDecided, func(ctx context.Context, messages []*models.OutboxMessage) error {
+		delivered <- struct{}{}
+		return nil
+	})
+	queue(t, repo, models.OutboxTopicLeaveDecided, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		dispatcher.Run(ctx)
+	}()
+
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("expected the due message to be delivered on start")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Run to return after cancellation")
+	}
+}
+
+func TestOutboxService_ReplayMessage(t *testing.T) {
+	repo := repository.NewMockOutboxRepository()
+	service := NewOutboxService(repo)
+	id := queue(t, repo, models.OutboxTopicLeaveDecided, "")
+
+	if _, err := service.ReplayMessage(context.Background(), uuid.New()); !errors.Is(err, ErrOutboxMessageNotFound) {
+		t.Errorf("expected ErrOutboxMessageNotFound, got %v", err)
+	}
+	if _, err := service.ReplayMessage(context.Background(), id); !errors.Is(err, ErrOutboxNotReplayable) {
+		t.Errorf("expected pending messages not to be replayable, got %v", err)
+	}
+
+	repo.MarkFailed(context.Background(), &models.OutboxMessage{ID: id, Status: models.OutboxStatusDead, LastError: "smtp unavailable", NextAttemptAt: time.Now()})
+	dead, _ := service.ListMessages(context.Background(), models.OutboxFilter{Status: models.OutboxStatusDead})
+	if len(dead) != 1 || dead[0].ID != id {
+		t.Fatalf("expected the dead message listed, got %v", dead)
+	}
+
+	msg, err := service.ReplayMessage(context.Background(), id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Status != models.OutboxStatusPending || msg.Attempts != 0 || msg.NextAttemptAt.After(time.Now()) {
+		t.Errorf("expected the message pending with attempts reset, got %+v", msg)
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"leave-management-system/internal/config"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/utils"
+)
+
+// LeavePolicy holds the organisation's rules for when leave may be requested.
+// Dates are always judged in the employee's own time zone.
+type LeavePolicy struct {
+	DefaultLocation *time.Location
+	MinNoticeDays   map[models.LeaveType]int
+}
+
+// DefaultLeavePolicy returns a policy with UTC as the office time zone and no notice rules
+func DefaultLeavePolicy() LeavePolicy {
+	return LeavePolicy{DefaultLocation: time.UTC}
+}
+
+// NewLeavePolicy builds the leave policy from configuration
+func NewLeavePolicy(cfg *config.Config) (LeavePolicy, error) {
+	loc, err := time.LoadLocation(cfg.Leave.DefaultTimezone)
+	if err != nil {
+		return LeavePolicy{}, fmt.Errorf("invalid office timezone %q: %w", cfg.Leave.DefaultTimezone, err)
+	}
+
+	return LeavePolicy{
+		DefaultLocation: loc,
+		MinNoticeDays: map[models.LeaveType]int{
+			models.LeaveTypeAnnual:   cfg.Leave.AnnualNoticeDays,
+			models.LeaveTypePersonal: cfg.Leave.PersonalNoticeDays,
+		},
+	}, nil
+}
+
+// Location resolves an employee's IANA time zone, falling back to the office default
+func (p LeavePolicy) Location(timezone string) *time.Location {
+	if timezone != "" {
+		if loc, err := time.LoadLocation(timezone); err == nil {
+			return loc
+		}
+	}
+	if p.DefaultLocation == nil {
+		return time.UTC
+	}
+	return p.DefaultLocation
+}
+
+// ValidateDates checks the date range, that it does not start in the past and
+// that the notice period for the leave type is respected, all relative to
+// today in loc.
+func (p LeavePolicy) ValidateDates(leaveType models.LeaveType, start, end models.Date, loc *time.Location) error {
+	if err := utils.ValidateDateRange(start, end); err != nil {
+		return fmt.Errorf("invalid date range: %w", err)
+	}
+
+	if err := utils.ValidateDateNotPast(start, loc); err != nil {
+		return err
+	}
+
+	if notice := p.MinNoticeDays[leaveType]; notice > 0 {
+		if start.DaysSince(models.Today(loc)) < notice {
+			return &utils.ValidationError{
+				Message: fmt.Sprintf("%s leave must be requested at least %d days in advance", leaveType, notice),
+			}
+		}
+	}
+
+	return nil
+}
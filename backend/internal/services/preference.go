@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/notify"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/utils"
+)
+
+// preferenceEvents are the events people can configure: those addressed to
+// them by email
+var preferenceEvents = []notify.Event{
+	notify.EventLeaveSubmitted,
+	notify.EventLeaveUpdated,
+	notify.EventLeaveCancelled,
+	notify.EventLeaveReceived,
+	notify.EventLeaveApproved,
+	notify.EventLeaveRejected,
+	notify.EventLeaveReviewed,
+	notify.EventLeaveWithdrawn,
+	notify.EventLeaveRescheduled,
+	notify.EventCommentAdded,
+}
+
+// digestEvents can be left to the daily digest, which lists what is pending
+// approval; other events would be lost in it
+var digestEvents = []notify.Event{
+	notify.EventLeaveSubmitted,
+	notify.EventLeaveUpdated,
+	notify.EventLeaveCancelled,
+}
+
+// NotificationPreferenceService manages how each person wants to be notified
+type NotificationPreferenceService struct {
+	prefs  repository.NotificationPreferenceRepository
+	policy LeavePolicy
+	// digestTime is when digests are sent to people who did not pick a time
+	digestTime string
+}
+
+// NewNotificationPreferenceService creates a new notification preference
+// service. digestTime, as "15:04", is the default local time of digests.
+func NewNotificationPreferenceService(prefs repository.NotificationPreferenceRepository, policy LeavePolicy, digestTime string) (*NotificationPreferenceService, error) {
+	at, err := time.Parse("15:04", digestTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid digest time %q: expected HH:MM", digestTime)
+	}
+	return &NotificationPreferenceService{
+		prefs:      prefs,
+		policy:     policy,
+		digestTime: at.Format("15:04"),
+	}, nil
+}
+
+// GetPreferences returns the employee's preferences for every event, with
+// the defaults for events they never changed
+func (s *NotificationPreferenceService) GetPreferences(ctx context.Context, employee *models.Employee) (*models.NotificationPreferencesInfo, error) {
+	prefs, err := s.prefs.Find(ctx, employee.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		prefs = &models.NotificationPreferences{Timezone: employee.Timezone}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	return s.info(prefs), nil
+}
+
+// UpdatePreferences changes the employee's preferences for the events in
+// req, leaving the others as they are. The employee's email address,
+// department, time zone and locale are recorded along with them.
+func (s *NotificationPreferenceService) UpdatePreferences(ctx context.Context, employee *models.Employee, req *models.UpdateNotificationPreferencesRequest) (*models.NotificationPreferencesInfo, error) {
+	if employee.Email == "" {
+		return nil, &utils.ValidationError{Message: "notification preferences need the email address notifications are sent to, and your token has none"}
+	}
+	for event, pref := range req.Events {
+		if !slices.Contains(preferenceEvents, notify.Event(event)) {
+			return nil, &utils.ValidationError{Message: fmt.Sprintf("unknown notification event %q", event)}
+		}
+		if pref.Mode == models.NotificationDigest && !slices.Contains(digestEvents, notify.Event(event)) {
+			return nil, &utils.ValidationError{Message: fmt.Sprintf("%s cannot be left to the digest, which only covers requests awaiting approval", event)}
+		}
+	}
+
+	now := time.Now()
+	prefs, err := s.prefs.Find(ctx, employee.ID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		prefs = &models.NotificationPreferences{
+			EmployeeID: employee.ID,
+			Events:     make(map[string]models.EventPreference),
+			CreatedAt:  now,
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+
+	for event, pref := range req.Events {
+		prefs.Events[event] = pref
+	}
+	if req.DigestTime != "" {
+		// Stored as HH:MM, so times compare as strings
+		at, err := time.Parse("15:04", req.DigestTime)
+		if err != nil {
+			return nil, &utils.ValidationError{Message: fmt.Sprintf("invalid digest time %q: expected HH:MM", req.DigestTime)}
+		}
+		prefs.DigestTime = at.Format("15:04")
+	}
+	prefs.Email = employee.Email
+	prefs.EmployeeName = employee.Name
+	prefs.Department = employee.Department
+	prefs.Timezone = employee.Timezone
+	prefs.Locale = employee.Locale
+	prefs.UpdatedAt = now
+
+	if err := s.prefs.Save(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return s.info(prefs), nil
+}
+
+// DueDigests returns the digests due as of now: one for each subscriber
+// whose digest time has passed today, in their time zone, unless theirs for
+// today already was queued
+func (s *NotificationPreferenceService) DueDigests(ctx context.Context, now time.Time) ([]*models.DigestRequest, error) {
+	subscribers, err := s.prefs.FindDigestSubscribers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list digest subscribers: %w", err)
+	}
+
+	var due []*models.DigestRequest
+	for _, prefs := range subscribers {
+		local := now.In(s.policy.Location(prefs.Timezone))
+		today := models.DateOf(local)
+		if !prefs.LastDigestOn.IsZero() && !prefs.LastDigestOn.Before(today) {
+			continue
+		}
+		if local.Format("15:04") < s.digestTimeOf(prefs) {
+			continue
+		}
+		due = append(due, &models.DigestRequest{EmployeeID: prefs.EmployeeID, Date: today})
+	}
+	return due, nil
+}
+
+// FindPreferences returns the employee's stored preferences, or
+// repository.ErrNotFound if they never set any
+func (s *NotificationPreferenceService) FindPreferences(ctx context.Context, employeeID string) (*models.NotificationPreferences, error) {
+	return s.prefs.Find(ctx, employeeID)
+}
+
+// info describes prefs to their owner
+func (s *NotificationPreferenceService) info(prefs *models.NotificationPreferences) *models.NotificationPreferencesInfo {
+	events := make(map[string]models.EventPreference, len(preferenceEvents))
+	for _, event := range preferenceEvents {
+		events[string(event)] = eventPreference(prefs, event)
+	}
+	info := &models.NotificationPreferencesInfo{
+		Events:     events,
+		DigestTime: s.digestTimeOf(prefs),
+		Timezone:   s.policy.Location(prefs.Timezone).String(),
+	}
+	if !prefs.UpdatedAt.IsZero() {
+		info.UpdatedAt = &prefs.UpdatedAt
+	}
+	return info
+}
+
+// digestTimeOf returns the local time prefs' digest is sent at
+func (s *NotificationPreferenceService) digestTimeOf(prefs *models.NotificationPreferences) string {
+	if prefs.DigestTime != "" {
+		return prefs.DigestTime
+	}
+	return s.digestTime
+}
+
+// eventPreference returns how prefs' owner wants event: immediately on every
+// channel unless they said otherwise
+func eventPreference(prefs *models.NotificationPreferences, event notify.Event) models.EventPreference {
+	if pref, ok := prefs.Events[string(event)]; ok {
+		return pref
+	}
+	return models.EventPreference{Mode: models.NotificationImmediate}
+}
+
+// RoutedNotifier is a notifier that tells which channels an event is routed
+// to, such as notify.Router
+type RoutedNotifier interface {
+	notify.Notifier
+	Channels(event notify.Event) []string
+}
+
+// PreferenceNotifier applies the recipients' preferences to notifications
+// before handing them to a router. Recipients who turned an event off, or
+// left it to the digest, are dropped; email goes to those who want it by
+// email, and other channels are used when any recipient wants them. People
+// without preferences, such as a shared approver mailbox, get everything.
+type PreferenceNotifier struct {
+	next  RoutedNotifier
+	prefs repository.NotificationPreferenceRepository
+}
+
+// NewPreferenceNotifier creates a notifier applying the preferences in prefs
+// to the notifications delivered by next
+func NewPreferenceNotifier(next RoutedNotifier, prefs repository.NotificationPreferenceRepository) *PreferenceNotifier {
+	return &PreferenceNotifier{next: next, prefs: prefs}
+}
+
+// Notify delivers n to the recipients who want it, on the channels they
+// want; nothing is delivered if no one does
+func (p *PreferenceNotifier) Notify(ctx context.Context, n *notify.Notification) error {
+	if len(n.To) == 0 || !slices.Contains(preferenceEvents, n.Event) {
+		return p.next.Notify(ctx, n)
+	}
+
+	stored, err := p.prefs.FindByEmails(ctx, n.To)
+	if err != nil {
+		return fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	byEmail := make(map[string]*models.NotificationPreferences, len(stored))
+	for _, prefs := range stored {
+		byEmail[strings.ToLower(prefs.Email)] = prefs
+	}
+
+	routed := p.next.Channels(n.Event)
+	wanted := make(map[string]bool, len(routed))
+	var to []string
+	for _, recipient := range n.To {
+		pref := models.EventPreference{Mode: models.NotificationImmediate}
+		if prefs, ok := byEmail[strings.ToLower(recipient)]; ok {
+			pref = eventPreference(prefs, n.Event)
+		}
+		if pref.Mode != models.NotificationImmediate {
+			continue
+		}
+		for _, channel := range routed {
+			if len(pref.Channels) > 0 && !slices.Contains(pref.Channels, channel) {
+				continue
+			}
+			wanted[channel] = true
+			if channel == notify.ChannelEmail {
+				to = append(to, recipient)
+			}
+		}
+	}
+
+	channels := []string{}
+	for _, channel := range routed {
+		if wanted[channel] {
+			channels = append(channels, channel)
+		}
+	}
+	if len(channels) == 0 {
+		return nil
+	}
+
+	filtered := *n
+	filtered.To = to
+	filtered.Channels = channels
+	return p.next.Notify(ctx, &filtered)
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/notify"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/utils"
+)
+
+func TestNotificationPreferenceService(t *testing.T) {
+	repo := repository.NewMockNotificationPreferenceRepository()
+	service, err := NewNotificationPreferenceService(repo, DefaultLeavePolicy(), "8:00")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	manager := &models.Employee{ID: "mgr-1", Name: "Jane Smith", Email: "jane@example.com", Department: "Engineering", Timezone: "Asia/Bangkok", Locale: "th"}
+
+	prefs, err := service.GetPreferences(ctx, manager)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prefs.Events) != len(preferenceEvents) || prefs.Events["leave.submitted"].Mode != models.NotificationImmediate {
+		t.Errorf("expected every event immediate by default, got %+v", prefs.Events)
+	}
+	if prefs.DigestTime != "08:00" || prefs.Timezone != "Asia/Bangkok" || prefs.UpdatedAt != nil {
+		t.Errorf("expected the default digest time in the employee's zone, got %+v", prefs)
+	}
+
+	prefs, err = service.UpdatePreferences(ctx, manager, &models.UpdateNotificationPreferencesRequest{
+		Events: map[string]models.EventPreference{
+			"leave.submitted": {Mode: models.NotificationDigest},
+			"comment.added":   {Mode: models.NotificationImmediate, Channels: []string{notify.ChannelSlack}},
+		},
+		DigestTime: "7:30",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prefs.Events["leave.submitted"].Mode != models.NotificationDigest || prefs.Events["leave.updated"].Mode != models.NotificationImmediate || prefs.DigestTime != "07:30" || prefs.UpdatedAt == nil {
+		t.Errorf("expected the submitted event left to a 07:30 digest, got %+v", prefs)
+	}
+
+	// Later changes keep the events not listed
+	if _, err := service.UpdatePreferences(ctx, manager, &models.UpdateNotificationPreferencesRequest{
+		Events: map[string]models.EventPreference{"leave.approved": {Mode: models.NotificationOff}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, _ := repo.Find(ctx, "mgr-1")
+	if len(stored.Events) != 3 || stored.DigestTime != "07:30" || stored.Email != "jane@example.com" || stored.Department != "Engineering" || stored.Locale != "th" || !stored.WantsDigest() {
+		t.Errorf("expected the merged preferences with the employee's details, got %+v", stored)
+	}
+
+	invalid := []struct {
+		name     string
+		employee *models.Employee
+		req      *models.UpdateNotificationPreferencesRequest
+	}{
+		{"unknown event", manager, &models.UpdateNotificationPreferencesRequest{Events: map[string]models.EventPreference{"leave.archived": {Mode: models.NotificationOff}}}},
+		{"digest of an employee event", manager, &models.UpdateNotificationPreferencesRequest{Events: map[string]models.EventPreference{"leave.approved": {Mode: models.NotificationDigest}}}},
+		{"no email address", &models.Employee{ID: "emp-2"}, &models.UpdateNotificationPreferencesRequest{}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			var validationErr *utils.ValidationError
+			if _, err := service.UpdatePreferences(ctx, tt.employee, tt.req); !errors.As(err, &validationErr) {
+				t.Errorf("expected a validation error, got %v", err)
+			}
+		})
+	}
+
+	if _, err := NewNotificationPreferenceService(repo, DefaultLeavePolicy(), "noon"); err == nil {
+		t.Error("expected an error for an invalid digest time")
+	}
+}
+
+func TestPreferenceNotifier(t *testing.T) {
+	email, slack := &recordingNotifier{}, &recordingNotifier{}
+	router, _ := notify.NewRouter(map[string]notify.Notifier{notify.ChannelEmail: email, notify.ChannelSlack: slack}, notify.Routes{"*": {"email", "slack"}}, nil)
+	repo := repository.NewMockNotificationPreferenceRepository()
+	notifier := NewPreferenceNotifier(router, repo)
+	ctx := context.Background()
+
+	repo.Save(ctx, &models.NotificationPreferences{EmployeeID: "mgr-1", Email: "Jane@example.com", Events: map[string]models.EventPreference{
+		"leave.submitted": {Mode: models.NotificationDigest},
+		"leave.updated":   {Mode: models.NotificationImmediate, Channels: []string{notify.ChannelEmail}},
+		"comment.added":   {Mode: models.NotificationImmediate, Channels: []string{notify.ChannelSlack}},
+	}})
+	repo.Save(ctx, &models.NotificationPreferences{EmployeeID: "mgr-2", Email: "raj@example.com", Events: map[string]models.EventPreference{
+		"leave.updated": {Mode: models.NotificationOff},
+		"comment.added": {Mode: models.NotificationOff},
+	}})
+
+	send := func(event notify.Event, to ...string) {
+		t.Helper()
+		email.sent, slack.sent = nil, nil
+		if err := notifier.Notify(ctx, &notify.Notification{Event: event, To: to}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Left to the digest, so no one is notified now
+	send(notify.EventLeaveSubmitted, "jane@example.com")
+	if len(email.sent) != 0 || len(slack.sent) != 0 {
+		t.Errorf("expected nothing sent for a digest event, got %d emails and %d posts", len(email.sent), len(slack.sent))
+	}
+
+	// Jane wants email only and Raj nothing
+	send(notify.EventLeaveUpdated, "jane@example.com", "raj@example.com")
+	if len(email.sent) != 1 || len(email.sent[0].To) != 1 || email.sent[0].To[0] != "jane@example.com" || len(slack.sent) != 0 {
+		t.Errorf("expected one email to Jane only, got %+v and %d posts", email.sent, len(slack.sent))
+	}
+
+	// Jane wants chat only; an address without preferences gets every channel
+	send(notify.EventCommentAdded, "jane@example.com", "hr@example.com")
+	if len(email.sent) != 1 || len(email.sent[0].To) != 1 || email.sent[0].To[0] != "hr@example.com" || len(slack.sent) != 1 {
+		t.Errorf("expected an email to HR and a chat post, got %+v and %d posts", email.sent, len(slack.sent))
+	}
+
+	// Events without recipients or preferences pass through
+	send(notify.EventDailyDigest, "jane@example.com")
+	if len(email.sent) != 1 || len(slack.sent) != 1 {
+		t.Errorf("expected the digest on every routed channel, got %d emails and %d posts", len(email.sent), len(slack.sent))
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+	"leave-management-system/internal/templates"
+	"leave-management-system/internal/utils"
+)
+
+// ErrTemplateNotFound is returned for a locale or message name without a template
+var ErrTemplateNotFound = errors.New("notification template not found")
+
+// TemplateService lets admins override the built-in notification templates
+type TemplateService struct {
+	repo repository.TemplateRepository
+}
+
+// NewTemplateService creates a new template service
+func NewTemplateService(repo repository.TemplateRepository) *TemplateService {
+	return &TemplateService{repo: repo}
+}
+
+// ListTemplates lists every notification template by name then locale
+func (s *TemplateService) ListTemplates(ctx context.Context) ([]*models.TemplateInfo, error) {
+	overrides, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query templates: %w", err)
+	}
+	overridden := make(map[string]*models.NotificationTemplate, len(overrides))
+	for _, o := range overrides {
+		overridden[o.Locale+"/"+o.Name] = o
+	}
+
+	infos := make([]*models.TemplateInfo, 0, len(templates.Names)*len(templates.Locales))
+	for _, name := range templates.Names {
+		for _, locale := range templates.Locales {
+			infos = append(infos, templateInfo(locale, name, overridden[locale+"/"+name]))
+		}
+	}
+	return infos, nil
+}
+
+// GetTemplate returns the template rendered for message name in locale
+func (s *TemplateService) GetTemplate(ctx context.Context, locale, name string) (*models.TemplateDetail, error) {
+	builtin, err := templates.Builtin(locale, name)
+	if errors.Is(err, templates.ErrUnknownTemplate) {
+		return nil, ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	override, err := s.repo.Find(ctx, locale, name)
+	switch {
+	case err == nil:
+		return templateDetail(override, true), nil
+	case errors.Is(err, repository.ErrNotFound):
+		return templateDetail(builtin, false), nil
+	}
+	return nil, fmt.Errorf("failed to get template: %w", err)
+}
+
+// SaveTemplate overrides the template of message name in locale. The
+// templates must render the message's sample data; those that do not are
+// rejected with a validation error so a broken override cannot stop delivery.
+func (s *TemplateService) SaveTemplate(ctx context.Context, locale, name string, req *models.UpdateTemplateRequest, actor *models.Employee) (*models.TemplateDetail, error) {
+	err := templates.Validate(locale, name, req.Text, req.HTML)
+	switch {
+	case errors.Is(err, templates.ErrUnknownTemplate):
+		return nil, ErrTemplateNotFound
+	case errors.Is(err, templates.ErrInvalidTemplate):
+		return nil, &utils.ValidationError{Message: err.Error()}
+	case err != nil:
+		return nil, err
+	}
+
+	tmpl := &models.NotificationTemplate{
+		Locale:    locale,
+		Name:      name,
+		Text:      req.Text,
+		HTML:      req.HTML,
+		UpdatedBy: actor.ID,
+		UpdatedAt: time.Now(),
+	}
+	if err := s.repo.Save(ctx, tmpl); err != nil {
+		return nil, fmt.Errorf("failed to save template: %w", err)
+	}
+	return templateDetail(tmpl, true), nil
+}
+
+// ResetTemplate removes the override of message name in locale, restoring
+// the built-in template. Resetting a template that is not overridden does
+// nothing.
+func (s *TemplateService) ResetTemplate(ctx context.Context, locale, name string) error {
+	if _, err := templates.Builtin(locale, name); err != nil {
+		if errors.Is(err, templates.ErrUnknownTemplate) {
+			return ErrTemplateNotFound
+		}
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, locale, name); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	return nil
+}
+
+func templateInfo(locale, name string, override *models.NotificationTemplate) *models.TemplateInfo {
+	info := &models.TemplateInfo{Locale: locale, Name: name}
+	if override != nil {
+		updatedAt := override.UpdatedAt
+		info.Overridden = true
+		info.UpdatedBy = override.UpdatedBy
+		info.UpdatedAt = &updatedAt
+	}
+	return info
+}
+
+func templateDetail(tmpl *models.NotificationTemplate, overridden bool) *models.TemplateDetail {
+	var override *models.NotificationTemplate
+	if overridden {
+		override = tmpl
+	}
+	return &models.TemplateDetail{
+		TemplateInfo: *templateInfo(tmpl.Locale, tmpl.Name, override),
+		Text:         tmpl.Text,
+		HTML:         tmpl.HTML,
+	}
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+)
+
+func TestLeaveService_ConcurrentTransitions(t *testing.T) {
+	repo := repository.NewMockLeaveRepository()
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
+
+	for round := 0; round < 20; round++ {
+		leave := &models.LeaveRequest{
+			ID:         uuid.New(),
+			EmployeeID: "emp-1",
+			Status:     models.LeaveStatusPending,
+			CreatedAt:  time.Now(),
+		}
+		repo.Create(context.Background(), leave)
+
+		// Two approvers and the employee act on the same request at once
+		actions := []func() error{
+			func() error {
+				_, err := service.ApproveLeaveRequest(context.Background(), leave.ID, leave.Version, testReviewer, "approved")
+				return err
+			},
+			func() error {
+				_, err := service.RejectLeaveRequest(context.Background(), leave.ID, leave.Version, testReviewer, "rejected")
+				return err
+			},
+			func() error {
+				return service.CancelLeaveRequest(context.Background(), leave.ID, leave.Version, "emp-1")
+			},
+		}
+
+		errs := make([]error, len(actions))
+		var wg sync.WaitGroup
+		for i, action := range actions {
+			wg.Add(1)
+			go func(i int, action func() error) {
+				defer wg.Done()
+				errs[i] = action()
+			}(i, action)
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for _, err := range errs {
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, ErrConflict), errors.Is(err, ErrPreconditionFailed):
+			default:
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if succeeded != 1 {
+			t.Fatalf("expected exactly one transition to succeed, got %d", succeeded)
+		}
+	}
+}
+
+func TestLeaveService_StaleWritesConflict(t *testing.T) {
+	history := repository.NewMockHistoryRepository()
+	repo := repository.NewMockLeaveRepository()
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, history), DefaultLeavePolicy())
+	admin := NewAdminService(service)
+	actor := &models.Employee{ID: "admin-1", Name: "Admin"}
+
+	leave := &models.LeaveRequest{
+		ID:         uuid.New(),
+		EmployeeID: "emp-1",
+		Status:     models.LeaveStatusPending,
+		Days:       2,
+		CreatedAt:  time.Now(),
+	}
+	repo.Create(context.Background(), leave)
+
+	// A copy read before the request was approved can no longer be saved
+	stale := *leave
+	if _, err := service.ApproveLeaveRequest(context.Background(), leave.ID, leave.Version, testReviewer, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	changed := stale
+	changed.Days = 3
+	_, err := admin.save(context.Background(), &stale, &changed, actor, models.HistoryActionDaysCorrected, "2 -> 3: typo")
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if entries, _ := history.FindByLeaveRequestID(context.Background(), leave.ID); len(entries) != 0 {
+		t.Errorf("expected no history for a conflicting change, got %d entries", len(entries))
+	}
+
+	current, _ := service.GetLeaveRequestByID(context.Background(), leave.ID)
+	if current.Status != models.LeaveStatusApproved || current.Days != 2 {
+		t.Errorf("expected the approved request to be unchanged, got %s with %d days", current.Status, current.Days)
+	}
+}
+
+func TestLeaveService_StaleVersionPreconditionFailed(t *testing.T) {
+	repo := repository.NewMockLeaveRepository()
+	service := NewLeaveService(repository.NewMockUnitOfWork(repo, nil), DefaultLeavePolicy())
+
+	leave := &models.LeaveRequest{
+		ID:         uuid.New(),
+		EmployeeID: "emp-1",
+		Status:     models.LeaveStatusPending,
+		Reason:     "Holiday",
+		CreatedAt:  time.Now(),
+	}
+	repo.Create(context.Background(), leave)
+
+	updated, err := service.UpdateLeaveRequest(context.Background(), leave.ID, 1, "emp-1", &models.UpdateLeaveRequest{Reason: "Family holiday"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Fatalf("expected version 2 after an update, got %d", updated.Version)
+	}
+
+	// The employee's first edit bumped the version, so a manager acting on the
+	// version they loaded earlier is refused before anything is written
+	if _, err := service.ApproveLeaveRequest(context.Background(), leave.ID, 1, testReviewer, ""); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+	if err := service.CancelLeaveRequest(context.Background(), leave.ID, 1, "emp-1"); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+
+	approved, err := service.ApproveLeaveRequest(context.Background(), leave.ID, AnyVersion, testReviewer, "")
+	if err != nil {
+		t.Fatalf("unexpected error approving any version: %v", err)
+	}
+	if approved.Version != 3 {
+		t.Errorf("expected version 3 after approval, got %d", approved.Version)
+	}
+}
+
+func TestLeaveService_DecisionQueuesNotification(t *testing.T) {
+	repo := repository.NewMockLeaveRepository()
+	uow := repository.NewMockUnitOfWork(repo, nil)
+	service := NewLeaveService(uow, DefaultLeavePolicy())
+
+	leave := &models.LeaveRequest{ID: uuid.New(), EmployeeID: "emp-1", Status: models.LeaveStatusPending, CreatedAt: time.Now()}
+	repo.Create(context.Background(), leave)
+
+	if _, err := service.ApproveLeaveRequest(context.Background(), leave.ID, leave.Version, testReviewer, "Enjoy"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A failed decision queues nothing
+	if _, err := service.RejectLeaveRequest(context.Background(), leave.ID, AnyVersion, testReviewer, "Too late"); !errors.Is(err, ErrInvalidStatus) {
+		t.Fatalf("expected ErrInvalidStatus, got %v", err)
+	}
+
+	messages, _ := uow.OutboxRepo.FindAll(context.Background(), models.OutboxFilter{})
+	if len(messages) != 1 {
+		t.Fatalf("expected one queued notification, got %d", len(messages))
+	}
+	msg := messages[0]
+	if msg.Topic != models.OutboxTopicLeaveDecided || msg.Status != models.OutboxStatusPending || msg.GroupKey != "" || msg.NextAttemptAt.After(time.Now()) {
+		t.Errorf("expected an ungrouped decision due now, got %+v", msg)
+	}
+	var decided models.LeaveRequest
+	if err := json.Unmarshal(msg.Payload, &decided); err != nil || decided.Status != models.LeaveStatusApproved || decided.GetManagerComment() != "Enjoy" {
+		t.Errorf("expected the approved leave as payload, got %+v (%v)", decided, err)
+	}
+}
+
+func TestLeaveService_ActivityQueuesNotification(t *testing.T) {
+	repo := repository.NewMockLeaveRepository()
+	uow := repository.NewMockUnitOfWork(repo, nil)
+	service := NewLeaveService(uow, DefaultLeavePolicy())
+	ctx := context.Background()
+	monday := nextMonday()
+
+	// Jane from the same team is off on Tuesday; Raj from another team and Jane's pending request are not listed
+	for _, other := range []*models.LeaveRequest{
+		{EmployeeID: "emp-2", EmployeeName: "Jane", Department: "Engineering", Status: models.LeaveStatusApproved, StartDate: monday.AddDays(1), EndDate: monday.AddDays(1)},
+		{EmployeeID: "emp-2", EmployeeName: "Jane", Department: "Engineering", Status: models.LeaveStatusPending, StartDate: monday, EndDate: monday},
+		{EmployeeID: "emp-3", EmployeeName: "Raj", Department: "Sales", Status: models.LeaveStatusApproved, StartDate: monday, EndDate: monday},
+	} {
+		other.ID = uuid.New()
+		repo.Create(ctx, other)
+	}
+
+	employee := &models.Employee{ID: "emp-1", Name: "John", Department: "Engineering"}
+	draft, err := service.CreateLeaveRequest(ctx, &models.CreateLeaveRequest{LeaveType: "annual", StartDate: monday, EndDate: monday.AddDays(2), Draft: true}, employee)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Drafts are invisible to approvers, so creating one announces nothing
+	if _, err := service.UpdateLeaveRequest(ctx, draft.ID, AnyVersion, "emp-1", &models.UpdateLeaveRequest{Reason: "Trip"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if messages, _ := uow.OutboxRepo.FindAll(ctx, models.OutboxFilter{}); len(messages) != 0 {
+		t.Fatalf("expected nothing queued for a draft, got %d", len(messages))
+	}
+
+	submitted, err := service.SubmitLeaveRequest(ctx, draft.ID, employee)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.UpdateLeaveRequest(ctx, submitted.ID, AnyVersion, "emp-1", &models.UpdateLeaveRequest{Reason: "Family trip"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.CancelLeaveRequest(ctx, submitted.ID, AnyVersion, "emp-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	messages, _ := uow.OutboxRepo.FindAll(ctx, models.OutboxFilter{Topic: models.OutboxTopicLeaveActivity})
+	if len(messages) != 3 {
+		t.Fatalf("expected three queued notifications, got %d", len(messages))
+	}
+	// Newest first
+	want := []models.LeaveActivityAction{models.LeaveActivityCancelled, models.LeaveActivityUpdated, models.LeaveActivitySubmitted}
+	for i, msg := range messages {
+		var activity models.LeaveActivity
+		if err := json.Unmarshal(msg.Payload, &activity); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		if activity.Action != want[i] || activity.Leave.ID != draft.ID {
+			t.Errorf("expected %s of the request, got %s of %s", want[i], activity.Action, activity.Leave.ID)
+		}
+		if len(activity.TeamAbsences) != 1 || activity.TeamAbsences[0].EmployeeName != "Jane" || activity.TeamAbsences[0].Status != models.LeaveStatusApproved {
+			t.Errorf("expected Jane's approved leave as the only team absence, got %+v", activity.TeamAbsences)
+		}
+	}
+}
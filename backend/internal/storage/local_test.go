@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	key := "leave/abc/def"
+	content := "certificate contents"
+
+	t.Run("Put and Get", func(t *testing.T) {
+		if err := store.Put(context.Background(), key, strings.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		body, err := store.Get(context.Background(), key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer body.Close()
+
+		got, _ := io.ReadAll(body)
+		if string(got) != content {
+			t.Errorf("expected %q, got %q", content, string(got))
+		}
+	})
+
+	t.Run("Get missing object", func(t *testing.T) {
+		_, err := store.Get(context.Background(), "leave/abc/missing")
+		if err != ErrObjectNotFound {
+			t.Errorf("expected ErrObjectNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := store.Delete(context.Background(), key); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := store.Get(context.Background(), key); err != ErrObjectNotFound {
+			t.Errorf("expected ErrObjectNotFound after delete, got %v", err)
+		}
+		// Deleting again is not an error
+		if err := store.Delete(context.Background(), key); err != nil {
+			t.Errorf("unexpected error deleting missing object: %v", err)
+		}
+	})
+
+	t.Run("rejects keys escaping the base directory", func(t *testing.T) {
+		for _, key := range []string{"../outside", "/etc/passwd", ""} {
+			if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+				t.Errorf("expected error for key %q", key)
+			}
+		}
+	})
+}
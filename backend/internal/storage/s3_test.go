@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"leave-management-system/internal/config"
+)
+
+// fakeS3 is a minimal in-memory stand-in for an S3-compatible object store
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=test-key/") ||
+		!strings.Contains(auth, "/us-east-1/s3/aws4_request") ||
+		!strings.Contains(auth, "Signature=") ||
+		r.Header.Get("X-Amz-Date") == "" {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store := NewS3Storage(config.S3Config{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		Bucket:          "attachments",
+		AccessKeyID:     "test-key",
+		SecretAccessKey: "test-secret",
+	})
+
+	key := "leave/abc/def"
+	content := "%PDF-1.4 certificate"
+
+	t.Run("Put", func(t *testing.T) {
+		if err := store.Put(context.Background(), key, strings.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := fake.objects["/attachments/leave/abc/def"]; !ok {
+			t.Errorf("expected object stored under bucket path, got %v", fake.objects)
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		body, err := store.Get(context.Background(), key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer body.Close()
+
+		got, _ := io.ReadAll(body)
+		if string(got) != content {
+			t.Errorf("expected %q, got %q", content, string(got))
+		}
+	})
+
+	t.Run("Get missing object", func(t *testing.T) {
+		if _, err := store.Get(context.Background(), "leave/abc/missing"); err != ErrObjectNotFound {
+			t.Errorf("expected ErrObjectNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := store.Delete(context.Background(), key); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := store.Get(context.Background(), key); err != ErrObjectNotFound {
+			t.Errorf("expected ErrObjectNotFound after delete, got %v", err)
+		}
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		denied := NewS3Storage(config.S3Config{
+			Endpoint:        server.URL,
+			Region:          "us-east-1",
+			Bucket:          "attachments",
+			AccessKeyID:     "wrong-key",
+			SecretAccessKey: "test-secret",
+		})
+		if err := denied.Put(context.Background(), key, strings.NewReader(content), int64(len(content)), "application/pdf"); err == nil {
+			t.Errorf("expected error but got none")
+		}
+	})
+
+	t.Run("cancelled request", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "application/pdf"); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+// Package storage provides pluggable blob storage for leave request attachments.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"leave-management-system/internal/config"
+)
+
+// ErrObjectNotFound is returned when a requested object does not exist
+var ErrObjectNotFound = errors.New("object not found")
+
+// Storage defines the interface for storing attachment contents
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates the storage backend selected by configuration
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Driver {
+	case "", "local":
+		return NewLocalStorage(cfg.Storage.LocalPath)
+	case "s3":
+		return NewS3Storage(cfg.Storage.S3), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
@@ -0,0 +1,132 @@
+package templates
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+)
+
+// LeaveData is the data of messages about one leave request: approvals,
+// rejections, submission receipts and the approver messages
+type LeaveData struct {
+	Leave *models.LeaveRequest
+	// TeamAbsences are the approved leaves of the employee's department
+	// overlapping Leave; set for approver messages only
+	TeamAbsences []*models.LeaveRequest
+	// ApproveURL and RejectURL decide Leave from an approver message; empty
+	// when email actions are off
+	ApproveURL string
+	RejectURL  string
+}
+
+// ReviewData is the data of a summary of several decisions on the leave
+// requests of one employee
+type ReviewData struct {
+	EmployeeName string
+	Leaves       []*models.LeaveRequest
+}
+
+// CommentData is the data of a new comment message
+type CommentData struct {
+	Comment *models.LeaveComment
+}
+
+// DigestData is the data of an approver's daily digest
+type DigestData struct {
+	Name string
+	// Date is the recipient's local date the digest is for
+	Date models.Date
+	// Pending are the oldest requests awaiting the recipient's decision;
+	// MorePending is how many others are not listed
+	Pending     []*DigestApproval
+	MorePending int
+	// Absences are the approved leaves of the recipient's Department in the
+	// Days days from Date; none without a department. MoreAbsences is how
+	// many others are not listed.
+	Department   string
+	Absences     []*models.LeaveRequest
+	MoreAbsences int
+	Days         int
+}
+
+// PendingCount is the number of requests awaiting the recipient's decision
+func (d *DigestData) PendingCount() int {
+	return len(d.Pending) + d.MorePending
+}
+
+// AbsenceCount is the number of upcoming absences in the department
+func (d *DigestData) AbsenceCount() int {
+	return len(d.Absences) + d.MoreAbsences
+}
+
+// DigestApproval is a request awaiting a decision in a digest, with the
+// links deciding it from the email; empty when email actions are off
+type DigestApproval struct {
+	Leave      *models.LeaveRequest
+	ApproveURL string
+	RejectURL  string
+}
+
+// samples are rendered to validate each message's templates
+var samples = func() map[string]interface{} {
+	leave := &models.LeaveRequest{
+		ID:             uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		EmployeeID:     "emp-1",
+		EmployeeName:   "John Doe",
+		EmployeeEmail:  "john@example.com",
+		Department:     "Engineering",
+		LeaveType:      models.LeaveTypeAnnual,
+		Reason:         "Family vacation",
+		StartDate:      models.NewDate(2024, 1, 1),
+		EndDate:        models.NewDate(2024, 1, 5),
+		Days:           5,
+		Status:         models.LeaveStatusApproved,
+		ManagerComment: sql.NullString{String: "Enjoy!", Valid: true},
+		ApproverName:   "Jane Smith",
+		ApproverEmail:  "jane@example.com",
+		Version:        2,
+	}
+	absence := &models.LeaveRequest{
+		EmployeeName: "Raj Patel",
+		LeaveType:    models.LeaveTypeSick,
+		StartDate:    models.NewDate(2024, 1, 2),
+		EndDate:      models.NewDate(2024, 1, 3),
+		Status:       models.LeaveStatusApproved,
+	}
+	approver := &LeaveData{
+		Leave:        leave,
+		TeamAbsences: []*models.LeaveRequest{absence},
+		ApproveURL:   "https://leave.example.com/email-actions/approve-token",
+		RejectURL:    "https://leave.example.com/email-actions/reject-token",
+	}
+
+	return map[string]interface{}{
+		LeaveApproved:    &LeaveData{Leave: leave},
+		LeaveRejected:    &LeaveData{Leave: leave},
+		LeaveReviewed:    &ReviewData{EmployeeName: leave.EmployeeName, Leaves: []*models.LeaveRequest{leave, leave}},
+		LeaveSubmitted:   approver,
+		LeaveUpdated:     approver,
+		LeaveCancelled:   approver,
+		LeaveReceived:    &LeaveData{Leave: leave},
+		LeaveWithdrawn:   &LeaveData{Leave: leave},
+		LeaveRescheduled: &LeaveData{Leave: leave},
+		DailyDigest: &DigestData{
+			Name:         "Jane Smith",
+			Date:         models.NewDate(2024, 1, 1),
+			Pending:      []*DigestApproval{{Leave: leave, ApproveURL: approver.ApproveURL, RejectURL: approver.RejectURL}},
+			MorePending:  2,
+			Department:   "Engineering",
+			Absences:     []*models.LeaveRequest{absence},
+			MoreAbsences: 1,
+			Days:         7,
+		},
+		CommentAdded: &CommentData{Comment: &models.LeaveComment{
+			LeaveRequestID: leave.ID,
+			AuthorName:     "Jane Smith",
+			Body:           "Please add a handover note.",
+			CreatedAt:      time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		}},
+	}
+}()
@@ -0,0 +1,72 @@
+package templates
+
+import (
+	"fmt"
+
+	"leave-management-system/internal/models"
+)
+
+// thaiMonths are the Thai month names, January first
+var thaiMonths = [...]string{
+	"มกราคม", "กุมภาพันธ์", "มีนาคม", "เมษายน", "พฤษภาคม", "มิถุนายน",
+	"กรกฎาคม", "สิงหาคม", "กันยายน", "ตุลาคม", "พฤศจิกายน", "ธันวาคม",
+}
+
+// leaveTypeNames and statusNames name leave types and statuses per locale;
+// English uses the values themselves
+var leaveTypeNames = map[string]map[models.LeaveType]string{
+	"th": {
+		models.LeaveTypeAnnual:   "ลาพักร้อน",
+		models.LeaveTypeSick:     "ลาป่วย",
+		models.LeaveTypePersonal: "ลากิจ",
+		models.LeaveTypeOther:    "ลาอื่น ๆ",
+	},
+}
+
+var statusNames = map[string]map[models.LeaveStatus]string{
+	"th": {
+		models.LeaveStatusDraft:     "ฉบับร่าง",
+		models.LeaveStatusPending:   "รออนุมัติ",
+		models.LeaveStatusApproved:  "อนุมัติ",
+		models.LeaveStatusRejected:  "ไม่อนุมัติ",
+		models.LeaveStatusCancelled: "ยกเลิก",
+	},
+}
+
+// funcMap returns the functions available to templates in locale:
+//
+//	date      formats a models.Date, e.g. "January 2, 2006" or "2 มกราคม 2549"
+//	leaveType names a leave type
+//	status    names a leave status
+func funcMap(locale string) map[string]interface{} {
+	return map[string]interface{}{
+		"date": func(d models.Date) string {
+			return formatDate(locale, d)
+		},
+		"leaveType": func(t models.LeaveType) string {
+			if name, ok := leaveTypeNames[locale][t]; ok {
+				return name
+			}
+			return string(t)
+		},
+		"status": func(s models.LeaveStatus) string {
+			if name, ok := statusNames[locale][s]; ok {
+				return name
+			}
+			return string(s)
+		},
+	}
+}
+
+// formatDate formats d the way locale writes dates in full. Thai dates use
+// the Buddhist Era, 543 years ahead of the Gregorian calendar.
+func formatDate(locale string, d models.Date) string {
+	if d.IsZero() {
+		return ""
+	}
+	switch locale {
+	case "th":
+		return fmt.Sprintf("%d %s %d", d.Day, thaiMonths[d.Month-1], d.Year+543)
+	}
+	return d.Format("January 2, 2006")
+}
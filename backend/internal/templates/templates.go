@@ -0,0 +1,224 @@
+// Package templates renders notification messages from text/template and
+// html/template files. Every message exists in each supported locale, and
+// admins can override any of them; overrides are stored in the database and
+// take precedence over the files embedded here.
+package templates
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+)
+
+//go:embed files
+var files embed.FS
+
+// Message names. Each has a <name>.txt and <name>.html file per locale.
+const (
+	LeaveApproved    = "leave_approved"
+	LeaveRejected    = "leave_rejected"
+	LeaveReviewed    = "leave_reviewed"
+	LeaveSubmitted   = "leave_submitted"
+	LeaveUpdated     = "leave_updated"
+	LeaveCancelled   = "leave_cancelled"
+	LeaveReceived    = "leave_received"
+	LeaveWithdrawn   = "leave_withdrawn"
+	LeaveRescheduled = "leave_rescheduled"
+	CommentAdded     = "comment_added"
+	DailyDigest      = "daily_digest"
+)
+
+// Names lists every message
+var Names = []string{LeaveApproved, LeaveRejected, LeaveReviewed, LeaveSubmitted, LeaveUpdated, LeaveCancelled, LeaveReceived, LeaveWithdrawn, LeaveRescheduled, CommentAdded, DailyDigest}
+
+// Locales lists the supported locales
+var Locales = []string{"en", "th"}
+
+var (
+	ErrUnknownTemplate = errors.New("unknown notification template")
+	ErrInvalidTemplate = errors.New("invalid notification template")
+)
+
+// Message is a rendered notification
+type Message struct {
+	Subject string
+	// Summary is the one-line version for chat channels; may be empty
+	Summary string
+	Text    string
+	HTML    string
+}
+
+// Renderer renders messages in the recipient's locale
+type Renderer struct {
+	overrides     repository.TemplateRepository
+	defaultLocale string
+}
+
+// NewRenderer creates a renderer that prefers the overrides in repo (which
+// may be nil) to the built-in templates. Recipients without a supported
+// locale get defaultLocale.
+func NewRenderer(repo repository.TemplateRepository, defaultLocale string) (*Renderer, error) {
+	locale, ok := match(defaultLocale)
+	if !ok {
+		return nil, fmt.Errorf("unsupported default locale %q: expected one of %s", defaultLocale, strings.Join(Locales, ", "))
+	}
+	return &Renderer{overrides: repo, defaultLocale: locale}, nil
+}
+
+// Locale returns the supported locale best matching a BCP 47 tag such as
+// "th-TH", or the default locale
+func (r *Renderer) Locale(tag string) string {
+	if locale, ok := match(tag); ok {
+		return locale
+	}
+	return r.defaultLocale
+}
+
+// match returns the supported locale with tag's language
+func match(tag string) (string, bool) {
+	lang := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	for _, locale := range Locales {
+		if lang == locale {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Render renders message name for a recipient with the given locale tag
+func (r *Renderer) Render(ctx context.Context, name, localeTag string, data interface{}) (*Message, error) {
+	locale := r.Locale(localeTag)
+
+	tmpl, err := Builtin(locale, name)
+	if err != nil {
+		return nil, err
+	}
+	if r.overrides != nil {
+		override, err := r.overrides.Find(ctx, locale, name)
+		switch {
+		case err == nil:
+			tmpl = override
+		case !errors.Is(err, repository.ErrNotFound):
+			return nil, fmt.Errorf("failed to load %s template: %w", name, err)
+		}
+	}
+
+	return render(locale, tmpl.Text, tmpl.HTML, data)
+}
+
+// Builtin returns the built-in template for name in locale
+func Builtin(locale, name string) (*models.NotificationTemplate, error) {
+	if !known(locale, name) {
+		return nil, ErrUnknownTemplate
+	}
+	text, err := files.ReadFile("files/" + locale + "/" + name + ".txt")
+	if err != nil {
+		return nil, err
+	}
+	html, err := files.ReadFile("files/" + locale + "/" + name + ".html")
+	if err != nil {
+		return nil, err
+	}
+	return &models.NotificationTemplate{Locale: locale, Name: name, Text: string(text), HTML: string(html)}, nil
+}
+
+// Validate checks that text and html parse and render the sample data of
+// message name, so an override cannot break delivery
+func Validate(locale, name, text, html string) error {
+	if !known(locale, name) {
+		return ErrUnknownTemplate
+	}
+	_, err := render(locale, text, html, samples[name])
+	return err
+}
+
+// known reports whether name is a message and locale a supported locale
+func known(locale, name string) bool {
+	return contains(Locales, locale) && contains(Names, name)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// render renders a message from its text and HTML templates. The text
+// template must define "subject" and may define "summary"; its top level is
+// the plain-text body. The HTML template is the content of the locale's layout.
+func render(locale, text, html string, data interface{}) (*Message, error) {
+	funcs := funcMap(locale)
+
+	partials, err := files.ReadFile("files/" + locale + "/partials.txt")
+	if err != nil {
+		return nil, err
+	}
+	textTmpl, err := texttemplate.New("partials.txt").Funcs(texttemplate.FuncMap(funcs)).Parse(string(partials))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := textTmpl.New("body").Parse(text); err != nil {
+		return nil, fmt.Errorf("%w: text: %v", ErrInvalidTemplate, err)
+	}
+	if textTmpl.Lookup("subject") == nil {
+		return nil, fmt.Errorf("%w: text: the subject template is not defined", ErrInvalidTemplate)
+	}
+
+	layout, err := files.ReadFile("files/" + locale + "/layout.html")
+	if err != nil {
+		return nil, err
+	}
+	htmlTmpl, err := htmltemplate.New("layout.html").Funcs(htmltemplate.FuncMap(funcs)).Parse(string(layout))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := htmlTmpl.New("content").Parse(html); err != nil {
+		return nil, fmt.Errorf("%w: html: %v", ErrInvalidTemplate, err)
+	}
+
+	var msg Message
+	execute := func(name string, dst *string) error {
+		var buf bytes.Buffer
+		if err := textTmpl.ExecuteTemplate(&buf, name, data); err != nil {
+			return fmt.Errorf("%w: text: %v", ErrInvalidTemplate, err)
+		}
+		*dst = strings.TrimSpace(buf.String())
+		return nil
+	}
+	if err := execute("subject", &msg.Subject); err != nil {
+		return nil, err
+	}
+	// Subjects are single header lines
+	msg.Subject = strings.Join(strings.Fields(msg.Subject), " ")
+	if textTmpl.Lookup("summary") != nil {
+		if err := execute("summary", &msg.Summary); err != nil {
+			return nil, err
+		}
+	}
+	if err := execute("body", &msg.Text); err != nil {
+		return nil, err
+	}
+	msg.Text += "\n"
+
+	var buf bytes.Buffer
+	if err := htmlTmpl.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return nil, fmt.Errorf("%w: html: %v", ErrInvalidTemplate, err)
+	}
+	msg.HTML = buf.String()
+
+	return &msg, nil
+}
@@ -0,0 +1,145 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"leave-management-system/internal/models"
+	"leave-management-system/internal/repository"
+)
+
+func TestBuiltinTemplatesRender(t *testing.T) {
+	for _, locale := range Locales {
+		for _, name := range Names {
+			tmpl, err := Builtin(locale, name)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", locale, name, err)
+			}
+			msg, err := render(locale, tmpl.Text, tmpl.HTML, samples[name])
+			if err != nil {
+				t.Fatalf("%s/%s: %v", locale, name, err)
+			}
+			if msg.Subject == "" || msg.Summary == "" || strings.TrimSpace(msg.Text) == "" {
+				t.Errorf("%s/%s: expected a subject, summary and text, got %+v", locale, name, msg)
+			}
+			if !strings.Contains(msg.HTML, `<html lang="`+locale+`">`) {
+				t.Errorf("%s/%s: expected the HTML to use the %s layout, got %q", locale, name, locale, msg.HTML)
+			}
+		}
+	}
+}
+
+func TestRenderer_Render(t *testing.T) {
+	renderer, err := NewRenderer(nil, "en")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := samples[LeaveApproved]
+
+	msg, err := renderer.Render(context.Background(), LeaveApproved, "", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Subject != "Leave Request Approved" || !strings.Contains(msg.Text, "Start Date: January 1, 2024") {
+		t.Errorf("unexpected English message: %+v", msg)
+	}
+	if msg.Summary != "John Doe's annual leave was approved: January 1, 2024 to January 5, 2024 (5 days)" {
+		t.Errorf("unexpected summary %q", msg.Summary)
+	}
+
+	// Regional tags match their language, and Thai dates use the Buddhist Era
+	msg, err = renderer.Render(context.Background(), LeaveApproved, "th-TH", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Subject != "คำขอลาได้รับการอนุมัติ" || !strings.Contains(msg.Text, "วันที่เริ่ม: 1 มกราคม 2567") || !strings.Contains(msg.Text, "ลาพักร้อน") {
+		t.Errorf("unexpected Thai message: %+v", msg)
+	}
+
+	// Unsupported locales fall back to the default
+	if locale := renderer.Locale("fr-FR"); locale != "en" {
+		t.Errorf("expected the default locale, got %q", locale)
+	}
+
+	if _, err := renderer.Render(context.Background(), "leave_archived", "en", data); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("expected ErrUnknownTemplate, got %v", err)
+	}
+}
+
+func TestRenderer_HTMLEscapesData(t *testing.T) {
+	renderer, _ := NewRenderer(nil, "en")
+	leave := *samples[LeaveApproved].(*LeaveData).Leave
+	leave.Reason = `<script>alert("hi")</script>`
+
+	msg, err := renderer.Render(context.Background(), LeaveApproved, "en", &LeaveData{Leave: &leave})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(msg.HTML, "<script>") || !strings.Contains(msg.HTML, "&lt;script&gt;") {
+		t.Errorf("expected the reason to be escaped, got %q", msg.HTML)
+	}
+	if !strings.Contains(msg.Text, `<script>alert("hi")</script>`) {
+		t.Errorf("expected the plain text to be left as is, got %q", msg.Text)
+	}
+}
+
+func TestRenderer_Overrides(t *testing.T) {
+	repo := repository.NewMockTemplateRepository()
+	renderer, _ := NewRenderer(repo, "en")
+	repo.Save(context.Background(), &models.NotificationTemplate{
+		Locale: "th",
+		Name:   LeaveApproved,
+		Text:   `{{define "subject"}}อนุมัติแล้ว{{end}}สวัสดี {{.Leave.EmployeeName}}`,
+		HTML:   `<p>สวัสดี {{.Leave.EmployeeName}}</p>`,
+	})
+
+	msg, err := renderer.Render(context.Background(), LeaveApproved, "th", samples[LeaveApproved])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Subject != "อนุมัติแล้ว" || msg.Text != "สวัสดี John Doe\n" || msg.Summary != "" || !strings.Contains(msg.HTML, "<p>สวัสดี John Doe</p>") {
+		t.Errorf("expected the override to be rendered, got %+v", msg)
+	}
+
+	// Other locales keep the built-in template
+	msg, _ = renderer.Render(context.Background(), LeaveApproved, "en", samples[LeaveApproved])
+	if msg.Subject != "Leave Request Approved" {
+		t.Errorf("expected the built-in English template, got %q", msg.Subject)
+	}
+}
+
+func TestNewRenderer_UnsupportedDefault(t *testing.T) {
+	if _, err := NewRenderer(nil, "fr"); err == nil {
+		t.Error("expected an error for an unsupported default locale")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := `{{define "subject"}}Approved{{end}}Hello {{.Leave.EmployeeName}}`
+	tests := []struct {
+		name   string
+		locale string
+		tmpl   string
+		text   string
+		html   string
+		want   error
+	}{
+		{"valid", "en", LeaveApproved, valid, `<p>{{.Leave.Reason}}</p>`, nil},
+		{"unknown message", "en", "leave_archived", valid, "", ErrUnknownTemplate},
+		{"unsupported locale", "fr", LeaveApproved, valid, "", ErrUnknownTemplate},
+		{"syntax error", "en", LeaveApproved, valid + "{{if}}", "", ErrInvalidTemplate},
+		{"missing subject", "en", LeaveApproved, "Hello", "", ErrInvalidTemplate},
+		{"unknown field", "en", LeaveApproved, valid + "{{.Leave.Salary}}", "", ErrInvalidTemplate},
+		{"html error", "en", LeaveApproved, valid, "<p>{{.Comment.Body}}</p>", ErrInvalidTemplate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.locale, tt.tmpl, tt.text, tt.html)
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -4,13 +4,17 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"testing"
 
 	_ "github.com/lib/pq"
 	"leave-management-system/internal/config"
-	"leave-management-system/internal/database"
+	"leave-management-system/internal/notify"
 	"leave-management-system/internal/repository"
 	"leave-management-system/internal/services"
+	"leave-management-system/internal/templates"
 )
 
 // SetupTestDB creates a test database connection
@@ -43,7 +47,8 @@ func SetupTestDB(t *testing.T) *sql.DB {
 	}
 
 	if err := db.Ping(); err != nil {
-		t.Fatalf("Failed to ping test database: %v", err)
+		db.Close()
+		t.Skipf("Skipping: test database unavailable: %v", err)
 	}
 
 	return db
@@ -53,7 +58,7 @@ func SetupTestDB(t *testing.T) *sql.DB {
 func CleanupTestDB(t *testing.T, db *sql.DB) {
 	t.Helper()
 
-	tables := []string{"leave_requests"}
+	tables := []string{"leave_request_history", "leave_comments", "leave_attachments", "leave_requests", "notification_templates", "calendar_feeds", "notification_preferences", "notifications"}
 	for _, table := range tables {
 		_, err := db.Exec(fmt.Sprintf("TRUNCATE TABLE %s CASCADE", table))
 		if err != nil {
@@ -62,52 +67,47 @@ func CleanupTestDB(t *testing.T, db *sql.DB) {
 	}
 }
 
-// SetupTestServices creates test services with a real database
-func SetupTestServices(t *testing.T, db *sql.DB) (*services.LeaveService, *services.EmailService) {
+// SetupTestServices creates test services with a real database. Notifications
+// go to email, which is not configured and drops them.
+func SetupTestServices(t *testing.T, db *sql.DB) (*services.LeaveService, *services.NotificationService) {
 	t.Helper()
 
-	repo := repository.NewLeaveRepository(db)
-	leaveService := services.NewLeaveService(repo)
+	leaveService := services.NewLeaveService(repository.NewUnitOfWork(db, 0), services.DefaultLeavePolicy())
 
-	cfg := &config.Config{
-		Email: config.EmailConfig{
-			Host:     "", // Not configured for tests
-			Port:     587,
-			User:     "",
-			Password: "",
-			From:     "test@example.com",
-		},
+	notifier := notify.NewSMTPNotifier(config.EmailConfig{
+		Host: "", // Not configured for tests
+		From: "test@example.com",
+	})
+	renderer, err := templates.NewRenderer(repository.NewTemplateRepository(db, 0), "en")
+	if err != nil {
+		t.Fatalf("Failed to create template renderer: %v", err)
 	}
-	emailService := services.NewEmailService(cfg)
+	notificationService := services.NewNotificationService(notifier, renderer, "", "test@example.com", nil)
 
-	return leaveService, emailService
+	return leaveService, notificationService
 }
 
-// RunMigrations runs database migrations for testing
+// RunMigrations applies every up migration in the migrations directory, in order
 func RunMigrations(t *testing.T, db *sql.DB) {
 	t.Helper()
 
-	migrationSQL := `
-		CREATE TABLE IF NOT EXISTS leave_requests (
-			id UUID PRIMARY KEY,
-			employee_id VARCHAR(255) NOT NULL,
-			employee_name VARCHAR(255) NOT NULL,
-			employee_email VARCHAR(255) NOT NULL,
-			leave_type VARCHAR(50) NOT NULL,
-			reason TEXT NOT NULL,
-			start_date TIMESTAMP NOT NULL,
-			end_date TIMESTAMP NOT NULL,
-			days INTEGER NOT NULL,
-			status VARCHAR(50) NOT NULL DEFAULT 'pending',
-			manager_comment TEXT,
-			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-		);
-	`
-
-	_, err := db.Exec(migrationSQL)
+	_, thisFile, _, _ := runtime.Caller(0)
+	migrationsDir := filepath.Join(filepath.Dir(thisFile), "..", "..", "migrations")
+
+	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.up.sql"))
 	if err != nil {
-		t.Fatalf("Failed to run migrations: %v", err)
+		t.Fatalf("Failed to list migrations: %v", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		migrationSQL, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read migration %s: %v", file, err)
+		}
+		if _, err := db.Exec(string(migrationSQL)); err != nil {
+			t.Fatalf("Failed to run migration %s: %v", file, err)
+		}
 	}
 }
 
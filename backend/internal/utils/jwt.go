@@ -14,6 +14,12 @@ type UserInfo struct {
 	Email  string
 	Name   string
 	Roles  []string
+	// Timezone is the OIDC zoneinfo claim (an IANA zone name), if present
+	Timezone string
+	// Department is the department claim, if present
+	Department string
+	// Locale is the OIDC locale claim (a BCP 47 tag such as "th-TH"), if present
+	Locale string
 }
 
 // ExtractUserInfoFromToken extracts user information from a JWT token
@@ -53,6 +59,18 @@ func ExtractUserInfoFromToken(tokenString string) (*UserInfo, error) {
 		userInfo.Name = name
 	}
 
+	if zoneinfo, ok := claims["zoneinfo"].(string); ok {
+		userInfo.Timezone = zoneinfo
+	}
+
+	if department, ok := claims["department"].(string); ok {
+		userInfo.Department = department
+	}
+
+	if locale, ok := claims["locale"].(string); ok {
+		userInfo.Locale = locale
+	}
+
 	// Extract roles from session (if available)
 	if roles, ok := claims["roles"].([]interface{}); ok {
 		userInfo.Roles = make([]string, 0, len(roles))
@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"leave-management-system/internal/models"
+)
+
+// FieldError describes why one field of a request failed validation. Field is
+// the JSON path of the field, e.g. "items[0].id".
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// FieldErrors is returned by RequestValidator when a request fails validation
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+type timezoneKey struct{}
+
+// WithTimezone returns a copy of ctx carrying the IANA time zone of the user
+// making the request, in which the notpast rule judges dates
+func WithTimezone(ctx context.Context, timezone string) context.Context {
+	return context.WithValue(ctx, timezoneKey{}, timezone)
+}
+
+// RequestValidator checks request payloads against their `validate` struct
+// tags. Besides the standard rules it understands:
+//
+//   - leavetype: the value is a known leave type
+//   - notpast: the date is not before today in the user's time zone
+//   - notpast_unless=Field: as notpast, unless the bool Field is true
+//   - onorafter=Field: the date is not before the date in Field, if set
+type RequestValidator struct {
+	validate *validator.Validate
+	location func(timezone string) *time.Location
+}
+
+// NewRequestValidator creates a request validator. location resolves the
+// user's time zone, falling back to a default when it is empty or unknown.
+func NewRequestValidator(location func(timezone string) *time.Location) *RequestValidator {
+	v := &RequestValidator{validate: validator.New(), location: location}
+
+	// Report fields by their JSON names
+	v.validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	// Dates are validated as YYYY-MM-DD strings, empty when unset
+	v.validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(models.Date).String()
+	}, models.Date{})
+
+	v.validate.RegisterValidation("leavetype", func(fl validator.FieldLevel) bool {
+		return models.LeaveType(fl.Field().String()).IsValid()
+	})
+	v.validate.RegisterValidationCtx("notpast", v.notPast)
+	v.validate.RegisterValidationCtx("notpast_unless", func(ctx context.Context, fl validator.FieldLevel) bool {
+		if waived, _, _, ok := fl.GetStructFieldOK2(); ok && waived.Kind() == reflect.Bool && waived.Bool() {
+			return true
+		}
+		return v.notPast(ctx, fl)
+	})
+	v.validate.RegisterValidation("onorafter", func(fl validator.FieldLevel) bool {
+		other, _, _, ok := fl.GetStructFieldOK2()
+		if !ok || other.Kind() != reflect.String || other.String() == "" || fl.Field().String() == "" {
+			return true
+		}
+		// YYYY-MM-DD strings order the same way as the dates
+		return fl.Field().String() >= other.String()
+	})
+
+	return v
+}
+
+// notPast reports whether the date in fl is today or later in the time zone carried by ctx
+func (v *RequestValidator) notPast(ctx context.Context, fl validator.FieldLevel) bool {
+	if fl.Field().String() == "" {
+		return true
+	}
+	date, err := models.ParseDate(fl.Field().String())
+	if err != nil {
+		return false
+	}
+	timezone, _ := ctx.Value(timezoneKey{}).(string)
+	return ValidateDateNotPast(date, v.location(timezone)) == nil
+}
+
+// Validate implements echo.Validator. Dates are judged in the default time zone.
+func (v *RequestValidator) Validate(i interface{}) error {
+	return v.ValidateCtx(context.Background(), i)
+}
+
+// ValidateCtx validates i, judging dates in the time zone set on ctx by
+// WithTimezone. It returns FieldErrors when i fails validation.
+func (v *RequestValidator) ValidateCtx(ctx context.Context, i interface{}) error {
+	err := v.validate.StructCtx(ctx, i)
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	fieldErrs := make(FieldErrors, len(validationErrs))
+	for i, fe := range validationErrs {
+		fieldErrs[i] = FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: fieldMessage(fe),
+		}
+	}
+	return fieldErrs
+}
+
+// fieldPath returns the JSON path of the failing field. The top-level struct
+// and embedded structs, which are named by their Go type rather than a JSON
+// name, are left out.
+func fieldPath(fe validator.FieldError) string {
+	segments := strings.Split(fe.Namespace(), ".")
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments[1:] {
+		if segment != "" && unicode.IsUpper(rune(segment[0])) {
+			continue
+		}
+		path = append(path, segment)
+	}
+	return strings.Join(path, ".")
+}
+
+// fieldMessage describes a validation failure in words
+func fieldMessage(fe validator.FieldError) string {
+	field := fieldPath(fe)
+	switch fe.Tag() {
+	case "required", "required_unless":
+		return fmt.Sprintf("%s is required", field)
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+	case "min", "max":
+		bound := "at least"
+		if fe.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("%s must be %s %s characters", field, bound, fe.Param())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("%s must have %s %s items", field, bound, fe.Param())
+		default:
+			return fmt.Sprintf("%s must be %s %s", field, bound, fe.Param())
+		}
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "leavetype":
+		return fmt.Sprintf("%s must be one of: annual, sick, personal, other", field)
+	case "notpast", "notpast_unless":
+		return fmt.Sprintf("%s cannot be in the past", field)
+	case "onorafter":
+		return fmt.Sprintf("%s must not be before %s", field, lowerFirst(fe.Param()))
+	default:
+		return fmt.Sprintf("%s is invalid", field)
+	}
+}
+
+// lowerFirst converts a Go field name such as StartDate to its JSON name
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
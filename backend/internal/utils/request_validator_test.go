@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"leave-management-system/internal/models"
+)
+
+func testLocation(timezone string) *time.Location {
+	if loc, err := time.LoadLocation(timezone); err == nil && timezone != "" {
+		return loc
+	}
+	return time.UTC
+}
+
+// fieldRules maps each failing field to the rule it failed
+func fieldRules(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("expected FieldErrors, got %v", err)
+	}
+	rules := make(map[string]string)
+	for _, fieldErr := range fieldErrs {
+		rules[fieldErr.Field] = fieldErr.Rule
+	}
+	return rules
+}
+
+func TestRequestValidator_CreateLeaveRequest(t *testing.T) {
+	v := NewRequestValidator(testLocation)
+	today := models.Today(time.UTC)
+	yesterday := today.AddDays(-1)
+
+	tests := []struct {
+		name string
+		req  models.CreateLeaveRequest
+		want map[string]string
+	}{
+		{
+			name: "valid",
+			req:  models.CreateLeaveRequest{LeaveType: "annual", Reason: "Family holiday", StartDate: today.AddDays(7), EndDate: today.AddDays(7)},
+		},
+		{
+			name: "missing fields",
+			req:  models.CreateLeaveRequest{},
+			want: map[string]string{"leaveType": "required", "startDate": "required_unless", "endDate": "required_unless"},
+		},
+		{
+			name: "unknown leave type and short reason",
+			req:  models.CreateLeaveRequest{LeaveType: "sabbatical", Reason: "Rest", StartDate: today, EndDate: today},
+			want: map[string]string{"leaveType": "leavetype", "reason": "min"},
+		},
+		{
+			name: "past start and reversed range",
+			req:  models.CreateLeaveRequest{LeaveType: "sick", StartDate: yesterday, EndDate: yesterday.AddDays(-1)},
+			want: map[string]string{"startDate": "notpast_unless", "endDate": "onorafter"},
+		},
+		{
+			name: "drafts may be undated or in the past",
+			req:  models.CreateLeaveRequest{LeaveType: "sick", Draft: true, StartDate: yesterday, EndDate: yesterday},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(&tt.req)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			rules := fieldRules(t, err)
+			if len(rules) != len(tt.want) {
+				t.Errorf("expected %d field errors, got %v", len(tt.want), rules)
+			}
+			for field, rule := range tt.want {
+				if rules[field] != rule {
+					t.Errorf("expected %s to fail %q, got %q", field, rule, rules[field])
+				}
+			}
+		})
+	}
+}
+
+func TestRequestValidator_NotPastUsesUserTimezone(t *testing.T) {
+	v := NewRequestValidator(testLocation)
+
+	// Kiritimati is UTC+14 and Pago Pago UTC-11, so at any instant their
+	// calendar dates differ by at least one day
+	ahead, _ := time.LoadLocation("Pacific/Kiritimati")
+	req := models.CreateLeaveRequest{LeaveType: "annual", StartDate: models.Today(ahead), EndDate: models.Today(ahead)}
+	if err := v.ValidateCtx(WithTimezone(context.Background(), "Pacific/Kiritimati"), &req); err != nil {
+		t.Errorf("expected today in the user's zone to be accepted, got %v", err)
+	}
+
+	behind, _ := time.LoadLocation("Pacific/Pago_Pago")
+	req = models.CreateLeaveRequest{LeaveType: "annual", StartDate: models.Today(behind), EndDate: models.Today(ahead)}
+	rules := fieldRules(t, v.ValidateCtx(WithTimezone(context.Background(), "Pacific/Kiritimati"), &req))
+	if rules["startDate"] != "notpast_unless" {
+		t.Errorf("expected yesterday in the user's zone to be rejected, got %v", rules)
+	}
+}
+
+func TestRequestValidator_UpdateLeaveRequest(t *testing.T) {
+	v := NewRequestValidator(testLocation)
+	start := models.NewDate(2030, 1, 10)
+	before := models.NewDate(2030, 1, 9)
+
+	if err := v.Validate(&models.UpdateLeaveRequest{EndDate: &before}); err != nil {
+		t.Errorf("expected an end date alone to be accepted, got %v", err)
+	}
+	if err := v.Validate(&models.UpdateLeaveRequest{StartDate: &start, EndDate: &start}); err != nil {
+		t.Errorf("expected a single-day range to be accepted, got %v", err)
+	}
+
+	rules := fieldRules(t, v.Validate(&models.OnBehalfUpdateLeaveRequest{
+		UpdateLeaveRequest: models.UpdateLeaveRequest{LeaveType: "other", StartDate: &start, EndDate: &before},
+	}))
+	if rules["endDate"] != "onorafter" {
+		t.Errorf("expected endDate to fail onorafter, got %v", rules)
+	}
+}
+
+func TestRequestValidator_NestedFieldPaths(t *testing.T) {
+	v := NewRequestValidator(testLocation)
+
+	err := v.Validate(&models.BulkLeaveRequest{
+		Action: "escalate",
+		Items:  []models.BulkLeaveItem{{ID: uuid.New()}, {}},
+	})
+	rules := fieldRules(t, err)
+	if rules["action"] != "oneof" || rules["items[1].id"] != "required" || rules["items[1].version"] != "required" {
+		t.Errorf("unexpected field errors: %v", rules)
+	}
+
+	var fieldErrs FieldErrors
+	errors.As(err, &fieldErrs)
+	for _, fieldErr := range fieldErrs {
+		if fieldErr.Field == "action" && fieldErr.Message != "action must be one of: approve, reject" {
+			t.Errorf("unexpected message: %q", fieldErr.Message)
+		}
+	}
+}
@@ -2,20 +2,21 @@ package utils
 
 import (
 	"time"
+
+	"leave-management-system/internal/models"
 )
 
 // ValidateDateRange validates that start date is before end date
-func ValidateDateRange(startDate, endDate time.Time) error {
+func ValidateDateRange(startDate, endDate models.Date) error {
 	if startDate.After(endDate) {
 		return ErrInvalidDateRange
 	}
 	return nil
 }
 
-// ValidateDateNotPast validates that the date is not in the past
-func ValidateDateNotPast(date time.Time) error {
-	today := time.Now().Truncate(24 * time.Hour)
-	if date.Before(today) {
+// ValidateDateNotPast validates that the date is not before today in loc
+func ValidateDateNotPast(date models.Date, loc *time.Location) error {
+	if date.Before(models.Today(loc)) {
 		return ErrDateInPast
 	}
 	return nil
@@ -3,31 +3,33 @@ package utils
 import (
 	"testing"
 	"time"
+
+	"leave-management-system/internal/models"
 )
 
 func TestValidateDateRange(t *testing.T) {
 	tests := []struct {
 		name      string
-		startDate time.Time
-		endDate   time.Time
+		startDate models.Date
+		endDate   models.Date
 		wantErr   bool
 	}{
 		{
 			name:      "valid range",
-			startDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			endDate:   time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+			startDate: models.NewDate(2024, 1, 1),
+			endDate:   models.NewDate(2024, 1, 5),
 			wantErr:   false,
 		},
 		{
 			name:      "same date",
-			startDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			endDate:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			startDate: models.NewDate(2024, 1, 1),
+			endDate:   models.NewDate(2024, 1, 1),
 			wantErr:   false,
 		},
 		{
 			name:      "invalid range - end before start",
-			startDate: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
-			endDate:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			startDate: models.NewDate(2024, 1, 5),
+			endDate:   models.NewDate(2024, 1, 1),
 			wantErr:   true,
 		},
 	}
@@ -55,34 +57,62 @@ func TestValidateDateRange(t *testing.T) {
 }
 
 func TestValidateDateNotPast(t *testing.T) {
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	bangkok, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	kiritimati, err := time.LoadLocation("Pacific/Kiritimati") // UTC+14
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
 
 	tests := []struct {
 		name    string
-		date    time.Time
+		date    models.Date
+		loc     *time.Location
 		wantErr bool
 	}{
 		{
 			name:    "today",
-			date:    today,
+			date:    models.Today(time.UTC),
+			loc:     time.UTC,
 			wantErr: false,
 		},
 		{
 			name:    "future date",
-			date:    today.AddDate(0, 0, 1),
+			date:    models.Today(time.UTC).AddDays(1),
+			loc:     time.UTC,
 			wantErr: false,
 		},
 		{
 			name:    "past date",
-			date:    today.AddDate(0, 0, -1),
+			date:    models.Today(time.UTC).AddDays(-1),
+			loc:     time.UTC,
+			wantErr: true,
+		},
+		{
+			name:    "today in Bangkok",
+			date:    models.Today(bangkok),
+			loc:     bangkok,
+			wantErr: false,
+		},
+		{
+			name:    "today far east of UTC is not past there",
+			date:    models.Today(kiritimati),
+			loc:     kiritimati,
+			wantErr: false,
+		},
+		{
+			name:    "yesterday in Bangkok",
+			date:    models.Today(bangkok).AddDays(-1),
+			loc:     bangkok,
 			wantErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ValidateDateNotPast(tt.date)
+			err := ValidateDateNotPast(tt.date, tt.loc)
 
 			if tt.wantErr {
 				if err == nil {
@@ -101,4 +131,3 @@ func TestValidateDateNotPast(t *testing.T) {
 		})
 	}
 }
-
@@ -30,6 +30,7 @@ END;
 $$ language 'plpgsql';
 
 -- Create trigger to automatically update updated_at
+DROP TRIGGER IF EXISTS update_leave_requests_updated_at ON leave_requests;
 CREATE TRIGGER update_leave_requests_updated_at
     BEFORE UPDATE ON leave_requests
     FOR EACH ROW
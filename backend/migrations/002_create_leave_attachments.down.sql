@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_leave_attachments_leave_request_id;
+
+-- Drop table
+DROP TABLE IF EXISTS leave_attachments;
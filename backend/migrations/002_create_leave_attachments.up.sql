@@ -0,0 +1,14 @@
+-- Create leave_attachments table
+CREATE TABLE IF NOT EXISTS leave_attachments (
+    id UUID PRIMARY KEY,
+    leave_request_id UUID NOT NULL REFERENCES leave_requests(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    storage_key VARCHAR(512) NOT NULL UNIQUE,
+    uploaded_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_leave_attachments_leave_request_id ON leave_attachments(leave_request_id);
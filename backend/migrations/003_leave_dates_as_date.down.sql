@@ -0,0 +1,6 @@
+-- Drop timezone column
+ALTER TABLE leave_requests DROP COLUMN IF EXISTS timezone;
+
+-- Restore timestamp columns
+ALTER TABLE leave_requests ALTER COLUMN start_date TYPE TIMESTAMP USING start_date::timestamp;
+ALTER TABLE leave_requests ALTER COLUMN end_date TYPE TIMESTAMP USING end_date::timestamp;
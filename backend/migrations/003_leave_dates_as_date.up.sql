@@ -0,0 +1,6 @@
+-- Leave dates are calendar days, not instants
+ALTER TABLE leave_requests ALTER COLUMN start_date TYPE DATE USING start_date::date;
+ALTER TABLE leave_requests ALTER COLUMN end_date TYPE DATE USING end_date::date;
+
+-- IANA time zone of the employee, used to decide what "today" means for them
+ALTER TABLE leave_requests ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,14 @@
+-- Drafts cannot be represented without the relaxed constraints
+DELETE FROM leave_requests WHERE status = 'draft' OR start_date IS NULL OR end_date IS NULL OR days <= 0;
+
+ALTER TABLE leave_requests DROP CONSTRAINT IF EXISTS leave_requests_dates_check;
+
+ALTER TABLE leave_requests ALTER COLUMN start_date SET NOT NULL;
+ALTER TABLE leave_requests ALTER COLUMN end_date SET NOT NULL;
+
+ALTER TABLE leave_requests DROP CONSTRAINT IF EXISTS leave_requests_days_check;
+ALTER TABLE leave_requests ADD CONSTRAINT leave_requests_days_check CHECK (days > 0);
+
+ALTER TABLE leave_requests DROP CONSTRAINT IF EXISTS leave_requests_status_check;
+ALTER TABLE leave_requests ADD CONSTRAINT leave_requests_status_check
+    CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled'));
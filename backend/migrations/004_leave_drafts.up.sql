@@ -0,0 +1,16 @@
+-- Allow draft leave requests: a new status, and dates/days that may be
+-- incomplete until the draft is submitted
+ALTER TABLE leave_requests DROP CONSTRAINT IF EXISTS leave_requests_status_check;
+ALTER TABLE leave_requests ADD CONSTRAINT leave_requests_status_check
+    CHECK (status IN ('draft', 'pending', 'approved', 'rejected', 'cancelled'));
+
+ALTER TABLE leave_requests DROP CONSTRAINT IF EXISTS leave_requests_days_check;
+ALTER TABLE leave_requests ADD CONSTRAINT leave_requests_days_check
+    CHECK (days > 0 OR status IN ('draft', 'cancelled'));
+
+ALTER TABLE leave_requests ALTER COLUMN start_date DROP NOT NULL;
+ALTER TABLE leave_requests ALTER COLUMN end_date DROP NOT NULL;
+
+ALTER TABLE leave_requests DROP CONSTRAINT IF EXISTS leave_requests_dates_check;
+ALTER TABLE leave_requests ADD CONSTRAINT leave_requests_dates_check
+    CHECK (status IN ('draft', 'cancelled') OR (start_date IS NOT NULL AND end_date IS NOT NULL));
@@ -0,0 +1,14 @@
+-- Create leave_comments table for the discussion thread on a leave request
+CREATE TABLE IF NOT EXISTS leave_comments (
+    id UUID PRIMARY KEY,
+    leave_request_id UUID NOT NULL REFERENCES leave_requests(id) ON DELETE CASCADE,
+    author_id VARCHAR(255) NOT NULL,
+    author_name VARCHAR(255) NOT NULL,
+    author_email VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL CHECK (length(body) > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_leave_comments_leave_request_id ON leave_comments(leave_request_id, created_at);
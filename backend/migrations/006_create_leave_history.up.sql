@@ -0,0 +1,17 @@
+-- Create leave_request_history table recording who changed a leave request.
+-- actor_id differs from employee_id when HR or an admin acts on an employee's behalf.
+CREATE TABLE IF NOT EXISTS leave_request_history (
+    id UUID PRIMARY KEY,
+    leave_request_id UUID NOT NULL REFERENCES leave_requests(id) ON DELETE CASCADE,
+    employee_id VARCHAR(255) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL,
+    actor_name VARCHAR(255) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_leave_request_history_leave_request_id ON leave_request_history(leave_request_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_leave_request_history_actor_id ON leave_request_history(actor_id);
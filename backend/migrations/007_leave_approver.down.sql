@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_leave_request_history_action;
+DROP INDEX IF EXISTS idx_leave_requests_approver_id;
+
+ALTER TABLE leave_requests DROP COLUMN IF EXISTS approver_email;
+ALTER TABLE leave_requests DROP COLUMN IF EXISTS approver_name;
+ALTER TABLE leave_requests DROP COLUMN IF EXISTS approver_id;
@@ -0,0 +1,7 @@
+-- Track the approver assigned to a leave request
+ALTER TABLE leave_requests ADD COLUMN IF NOT EXISTS approver_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE leave_requests ADD COLUMN IF NOT EXISTS approver_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE leave_requests ADD COLUMN IF NOT EXISTS approver_email VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_leave_requests_approver_id ON leave_requests(approver_id);
+CREATE INDEX IF NOT EXISTS idx_leave_request_history_action ON leave_request_history(action, created_at DESC);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_leave_requests_dates;
+DROP INDEX IF EXISTS idx_leave_requests_department;
+DROP INDEX IF EXISTS idx_leave_requests_status_created;
+DROP INDEX IF EXISTS idx_leave_requests_employee_created;
+
+ALTER TABLE leave_requests DROP COLUMN IF EXISTS employee_department;
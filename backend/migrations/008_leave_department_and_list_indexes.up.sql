@@ -0,0 +1,8 @@
+-- Department of the employee, for filtering team leave
+ALTER TABLE leave_requests ADD COLUMN IF NOT EXISTS employee_department VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Indexes backing the filtered, keyset-paginated leave listings
+CREATE INDEX IF NOT EXISTS idx_leave_requests_employee_created ON leave_requests(employee_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_leave_requests_status_created ON leave_requests(status, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_leave_requests_department ON leave_requests(employee_department);
+CREATE INDEX IF NOT EXISTS idx_leave_requests_dates ON leave_requests(start_date, end_date);
@@ -0,0 +1,2 @@
+-- Row version for optimistic concurrency; bumped on every change and exposed as the ETag
+ALTER TABLE leave_requests ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
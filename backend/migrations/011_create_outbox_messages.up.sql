@@ -0,0 +1,22 @@
+-- Create outbox_messages table holding notifications written in the same
+-- transaction as the change they announce, delivered later by the dispatcher.
+-- next_attempt_at is when a pending message is due, or when the lease of a
+-- message being processed expires so a crashed dispatcher's work is retried.
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id UUID PRIMARY KEY,
+    topic VARCHAR(100) NOT NULL,
+    group_key VARCHAR(255),
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP
+);
+
+-- The dispatcher polls for due messages; admins list dead ones
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_due ON outbox_messages(next_attempt_at) WHERE status IN ('pending', 'processing');
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_group_key ON outbox_messages(group_key) WHERE group_key IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_status ON outbox_messages(status, created_at);
@@ -0,0 +1,2 @@
+-- Language of the employee's notifications, from the token's locale claim; empty for the default
+ALTER TABLE leave_requests ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT '';
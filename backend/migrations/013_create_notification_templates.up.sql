@@ -0,0 +1,10 @@
+-- Admin overrides of the built-in notification templates, per locale
+CREATE TABLE IF NOT EXISTS notification_templates (
+    locale VARCHAR(35) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    text_body TEXT NOT NULL,
+    html_body TEXT NOT NULL,
+    updated_by VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (locale, name)
+);
@@ -0,0 +1,12 @@
+-- Secret-token iCalendar feeds employees subscribe to, one per kind
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    employee_id VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    employee_name VARCHAR(255) NOT NULL,
+    department VARCHAR(255) NOT NULL DEFAULT '',
+    privacy VARCHAR(20) NOT NULL DEFAULT 'details',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (employee_id, kind)
+);
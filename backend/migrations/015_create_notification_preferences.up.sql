@@ -0,0 +1,22 @@
+-- Per-employee notification settings. events maps a notification event to
+-- {"mode": "immediate" | "digest" | "off", "channels": [...]}; employees
+-- without a row get every event immediately on every channel.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    employee_id VARCHAR(255) PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    employee_name VARCHAR(255) NOT NULL DEFAULT '',
+    department VARCHAR(255) NOT NULL DEFAULT '',
+    timezone VARCHAR(100) NOT NULL DEFAULT '',
+    locale VARCHAR(35) NOT NULL DEFAULT '',
+    events JSONB NOT NULL DEFAULT '{}',
+    digest_time VARCHAR(5) NOT NULL DEFAULT '',
+    -- Whether any event is left to the daily digest, kept in step with events
+    digest BOOLEAN NOT NULL DEFAULT FALSE,
+    last_digest_on DATE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Notifications are addressed by email; the digest job lists its subscribers
+CREATE INDEX IF NOT EXISTS idx_notification_preferences_email ON notification_preferences(LOWER(email));
+CREATE INDEX IF NOT EXISTS idx_notification_preferences_digest ON notification_preferences(employee_id) WHERE digest;
@@ -0,0 +1,19 @@
+-- In-app inbox: one row per recipient of every notification sent.
+-- dedupe_key identifies the content, so the outbox redelivering a
+-- notification does not add it twice.
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY,
+    recipient VARCHAR(255) NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    subject TEXT NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    leave_request_id UUID,
+    dedupe_key VARCHAR(64) NOT NULL,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (recipient, dedupe_key)
+);
+
+-- Inboxes are listed newest first and their unread notifications counted
+CREATE INDEX IF NOT EXISTS idx_notifications_recipient ON notifications(recipient, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(recipient) WHERE read_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS notify_leave_requests_change ON leave_requests;
+DROP FUNCTION IF EXISTS notify_leave_change();
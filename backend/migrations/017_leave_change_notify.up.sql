@@ -0,0 +1,29 @@
+-- Publish every committed write to a leave request on the leave_changes
+-- channel, so each server can stream it to the clients it serves. NOTIFY is
+-- delivered on commit, and not at all if the transaction rolls back.
+CREATE OR REPLACE FUNCTION notify_leave_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    previous_status VARCHAR(50);
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        previous_status := OLD.status;
+    END IF;
+    PERFORM pg_notify('leave_changes', json_build_object(
+        'op', TG_OP,
+        'id', NEW.id,
+        'employeeId', NEW.employee_id,
+        'status', NEW.status,
+        'previousStatus', previous_status,
+        'version', NEW.version,
+        'at', now()
+    )::text);
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS notify_leave_requests_change ON leave_requests;
+CREATE TRIGGER notify_leave_requests_change
+    AFTER INSERT OR UPDATE ON leave_requests
+    FOR EACH ROW
+    EXECUTE FUNCTION notify_leave_change();
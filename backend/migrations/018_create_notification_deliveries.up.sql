@@ -0,0 +1,11 @@
+-- Channels and recipients each notification reached, by the same content key
+-- as the inbox, so the outbox redelivering a notification after a partial
+-- failure only retries the deliveries that failed. Chat and webhook
+-- deliveries have an empty recipient.
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    dedupe_key VARCHAR(64) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    recipient VARCHAR(255) NOT NULL DEFAULT '',
+    delivered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (dedupe_key, channel, recipient)
+);
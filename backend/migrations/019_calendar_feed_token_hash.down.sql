@@ -0,0 +1,7 @@
+-- The tokens cannot be recovered from their hashes: every feed gets a new
+-- random token, and its owner must regenerate it to learn the URL.
+ALTER TABLE calendar_feeds ADD COLUMN IF NOT EXISTS token VARCHAR(64);
+UPDATE calendar_feeds SET token = md5(random()::text || employee_id || kind) || md5(random()::text);
+ALTER TABLE calendar_feeds ALTER COLUMN token SET NOT NULL;
+ALTER TABLE calendar_feeds ADD CONSTRAINT calendar_feeds_token_key UNIQUE (token);
+ALTER TABLE calendar_feeds DROP COLUMN IF EXISTS token_hash;
@@ -0,0 +1,11 @@
+-- Feed tokens are stored as their hex SHA-256, so reading the table does not
+-- give access to the feeds. Existing feed URLs keep working.
+ALTER TABLE calendar_feeds ADD COLUMN IF NOT EXISTS token_hash VARCHAR(64);
+UPDATE calendar_feeds SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex');
+ALTER TABLE calendar_feeds ALTER COLUMN token_hash SET NOT NULL;
+ALTER TABLE calendar_feeds ADD CONSTRAINT calendar_feeds_token_hash_key UNIQUE (token_hash);
+ALTER TABLE calendar_feeds DROP COLUMN IF EXISTS token;
+
+-- Team feeds only show the type of leave to approvers and HR, who save their
+-- feed again to get it back
+UPDATE calendar_feeds SET privacy = 'busy' WHERE kind = 'team';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_notification_deliveries_delivered_at;
@@ -0,0 +1,3 @@
+-- Deliveries are pruned once the outbox can no longer retry their
+-- notification, by when they were recorded
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_delivered_at ON notification_deliveries(delivered_at);
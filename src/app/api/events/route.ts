@@ -0,0 +1,62 @@
+import { NextRequest, NextResponse } from "next/server";
+import { withErrorHandling, errorResponse, backendErrorResponse } from "@/lib/api/utils";
+import { auth } from "@/app/api/auth/[...nextauth]/route";
+
+const BACKEND_URL = process.env.BACKEND_URL || "http://localhost:8081";
+
+// The stream stays open for as long as the browser keeps it
+export const dynamic = "force-dynamic";
+
+/**
+ * Relays the backend's leave event stream. Browsers cannot set an
+ * Authorization header on EventSource, so the session's access token is
+ * added here.
+ */
+async function handler(request: NextRequest) {
+  const session = await auth();
+
+  if (!session) {
+    return errorResponse("Unauthorized", 401);
+  }
+
+  const accessToken = session.accessToken as string;
+  if (!accessToken) {
+    return errorResponse("Missing access token", 401);
+  }
+
+  const url = `${BACKEND_URL}/api/v1/events`;
+
+  try {
+    const response = await fetch(url, {
+      method: "GET",
+      headers: {
+        Accept: "text/event-stream",
+        Authorization: `Bearer ${accessToken}`,
+      },
+      // Closing the browser's stream closes the backend's
+      signal: request.signal,
+      cache: "no-store",
+    });
+
+    if (!response.ok || !response.body) {
+      return backendErrorResponse(response);
+    }
+
+    return new NextResponse(response.body, {
+      status: 200,
+      headers: {
+        "Content-Type": "text/event-stream",
+        "Cache-Control": "no-cache, no-transform",
+        Connection: "keep-alive",
+        "X-Accel-Buffering": "no",
+      },
+    });
+  } catch (error) {
+    return errorResponse(
+      error instanceof Error ? error.message : "Internal server error",
+      500
+    );
+  }
+}
+
+export const GET = withErrorHandling(handler);
@@ -1,5 +1,5 @@
 import { NextRequest } from "next/server";
-import { withErrorHandling, errorResponse, successResponse } from "@/lib/api/utils";
+import { withErrorHandling, errorResponse, backendErrorResponse, successResponse, ifMatchHeader } from "@/lib/api/utils";
 import { auth } from "@/app/api/auth/[...nextauth]/route";
 
 const BACKEND_URL = process.env.BACKEND_URL || "http://localhost:8081";
@@ -29,20 +29,13 @@ async function handler(
       headers: {
         "Content-Type": "application/json",
         Authorization: `Bearer ${accessToken}`,
+        ...ifMatchHeader(request),
       },
       body: method !== "GET" && method !== "DELETE" ? await request.text() : undefined,
     });
 
     if (!response.ok) {
-      let errorMessage = "Request failed";
-      try {
-        const error = await response.json();
-        // Echo returns errors in format: { message: "..." }
-        errorMessage = error.message || error.error || response.statusText;
-      } catch {
-        errorMessage = response.statusText;
-      }
-      return errorResponse(errorMessage, response.status);
+      return backendErrorResponse(response);
     }
 
     if (method === "DELETE") {
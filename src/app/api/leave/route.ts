@@ -1,5 +1,5 @@
 import { NextRequest } from "next/server";
-import { withErrorHandling, errorResponse, successResponse } from "@/lib/api/utils";
+import { withErrorHandling, errorResponse, backendErrorResponse, successResponse, idempotencyKeyHeader } from "@/lib/api/utils";
 import { auth } from "@/app/api/auth/[...nextauth]/route";
 
 const BACKEND_URL = process.env.BACKEND_URL || "http://localhost:8081";
@@ -25,20 +25,13 @@ async function handler(request: NextRequest) {
       headers: {
         "Content-Type": "application/json",
         Authorization: `Bearer ${accessToken}`,
+        ...idempotencyKeyHeader(request),
       },
       body: method !== "GET" ? await request.text() : undefined,
     });
 
     if (!response.ok) {
-      let errorMessage = "Request failed";
-      try {
-        const error = await response.json();
-        // Echo returns errors in format: { message: "..." }
-        errorMessage = error.message || error.error || response.statusText;
-      } catch {
-        errorMessage = response.statusText;
-      }
-      return errorResponse(errorMessage, response.status);
+      return backendErrorResponse(response);
     }
 
     const data = await response.json();
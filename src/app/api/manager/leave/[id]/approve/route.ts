@@ -1,5 +1,5 @@
 import { NextRequest } from "next/server";
-import { withErrorHandling, errorResponse, successResponse } from "@/lib/api/utils";
+import { withErrorHandling, errorResponse, backendErrorResponse, successResponse, ifMatchHeader } from "@/lib/api/utils";
 import { auth } from "@/app/api/auth/[...nextauth]/route";
 import { hasAnyRole } from "@/lib/permissions/roles";
 
@@ -35,13 +35,13 @@ async function handler(
       headers: {
         "Content-Type": "application/json",
         Authorization: `Bearer ${accessToken}`,
+        ...ifMatchHeader(request),
       },
       body,
     });
 
     if (!response.ok) {
-      const error = await response.json().catch(() => ({ message: response.statusText }));
-      return errorResponse(error.message || error.error || "Request failed", response.status);
+      return backendErrorResponse(response);
     }
 
     const data = await response.json();
@@ -1,5 +1,5 @@
 import { NextRequest } from "next/server";
-import { withErrorHandling, errorResponse, successResponse } from "@/lib/api/utils";
+import { withErrorHandling, errorResponse, backendErrorResponse, successResponse } from "@/lib/api/utils";
 import { auth } from "@/app/api/auth/[...nextauth]/route";
 import { hasAnyRole } from "@/lib/permissions/roles";
 
@@ -34,8 +34,7 @@ async function handler(request: NextRequest) {
     });
 
     if (!response.ok) {
-      const error = await response.json().catch(() => ({ message: response.statusText }));
-      return errorResponse(error.message || error.error || "Request failed", response.status);
+      return backendErrorResponse(response);
     }
 
     const data = await response.json();
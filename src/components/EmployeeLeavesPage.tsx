@@ -26,6 +26,8 @@ export default function EmployeeLeavesPage() {
   const [editingId, setEditingId] = useState<string | null>(null);
   const [showForm, setShowForm] = useState(false);
   const [confirmCancel, setConfirmCancel] = useState<{ id: string; employeeName: string } | null>(null);
+  // Reused when a submission is retried so it cannot create a duplicate request
+  const [submissionKey, setSubmissionKey] = useState(() => crypto.randomUUID());
 
   useEffect(() => {
     if (status === "authenticated") {
@@ -48,6 +50,9 @@ export default function EmployeeLeavesPage() {
     }
   };
 
+  // The version the page last loaded, sent back so stale edits are refused
+  const versionOf = (id: string) => leaves.find((l) => l.id === id)?.version ?? 0;
+
   const handleEdit = (id: string) => {
     setEditingId(id);
     setShowForm(true);
@@ -62,7 +67,7 @@ export default function EmployeeLeavesPage() {
     if (!confirmCancel) return;
 
     try {
-      await cancelLeaveRequest(confirmCancel.id);
+      await cancelLeaveRequest(confirmCancel.id, versionOf(confirmCancel.id));
       toast.showSuccess("Leave request cancelled successfully");
       setConfirmCancel(null);
       await loadLeaves();
@@ -75,10 +80,11 @@ export default function EmployeeLeavesPage() {
   const handleFormSubmit = async (data: CreateLeaveRequest | UpdateLeaveRequest) => {
     try {
       if (editingId) {
-        await updateLeaveRequest(editingId, data as UpdateLeaveRequest);
+        await updateLeaveRequest(editingId, versionOf(editingId), data as UpdateLeaveRequest);
         toast.showSuccess("Leave request updated successfully");
       } else {
-        await createLeaveRequest(data as CreateLeaveRequest);
+        await createLeaveRequest(data as CreateLeaveRequest, submissionKey);
+        setSubmissionKey(crypto.randomUUID());
         toast.showSuccess("Leave request created successfully");
       }
       setEditingId(null);
@@ -8,6 +8,7 @@ import { ManagerLeaveCard, ApprovalModal } from "@/components/leave";
 import { useToast } from "@/contexts/ToastContext";
 import {
   getPendingLeaveRequests,
+  subscribeToLeaveEvents,
   approveLeaveRequest,
   rejectLeaveRequest,
   type LeaveRequest,
@@ -34,9 +35,17 @@ export default function ManagerLeavesPage() {
     }
   }, [status]);
 
-  const loadLeaves = async () => {
+  // Reload when any request changes, and whenever the stream (re)connects,
+  // as changes made while it was down are not replayed
+  useEffect(() => {
+    if (status !== "authenticated") return;
+    const reload = () => loadLeaves(false);
+    return subscribeToLeaveEvents(reload, reload);
+  }, [status]);
+
+  const loadLeaves = async (showLoading = true) => {
     try {
-      setLoading(true);
+      if (showLoading) setLoading(true);
       const data = await getPendingLeaveRequests();
       // Ensure data is always an array
       setLeaves(Array.isArray(data) ? data : []);
@@ -49,6 +58,9 @@ export default function ManagerLeavesPage() {
     }
   };
 
+  // The version the page last loaded, sent back so stale decisions are refused
+  const versionOf = (id: string) => leaves.find((l) => l.id === id)?.version ?? 0;
+
   const handleApprove = (id: string, employeeName: string) => {
     setModalState({
       show: true,
@@ -74,10 +86,10 @@ export default function ManagerLeavesPage() {
       setProcessingId(modalState.leaveId);
 
       if (modalState.type === "approve") {
-        await approveLeaveRequest(modalState.leaveId, { comment });
+        await approveLeaveRequest(modalState.leaveId, versionOf(modalState.leaveId), { comment });
         toast.showSuccess(`Leave request from ${modalState.employeeName} has been approved`);
       } else {
-        await rejectLeaveRequest(modalState.leaveId, { comment });
+        await rejectLeaveRequest(modalState.leaveId, versionOf(modalState.leaveId), { comment });
         toast.showSuccess(`Leave request from ${modalState.employeeName} has been rejected`);
       }
 
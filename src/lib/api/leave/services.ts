@@ -12,6 +12,7 @@ export interface LeaveRequest {
   days: number;
   status: "pending" | "approved" | "rejected" | "cancelled";
   managerComment?: string;
+  version: number;
   createdAt: string;
   updatedAt: string;
 }
@@ -30,6 +31,33 @@ export interface UpdateLeaveRequest {
   endDate?: string;
 }
 
+export type LeaveEventType =
+  | "leave.created"
+  | "leave.updated"
+  | "leave.approved"
+  | "leave.rejected"
+  | "leave.cancelled";
+
+/**
+ * A change to a leave request, as streamed by the backend
+ */
+export interface LeaveEvent {
+  type: LeaveEventType;
+  leaveRequestId: string;
+  employeeId: string;
+  status: LeaveRequest["status"] | "draft";
+  version: number;
+  at: string;
+}
+
+export const LEAVE_EVENT_TYPES: LeaveEventType[] = [
+  "leave.created",
+  "leave.updated",
+  "leave.approved",
+  "leave.rejected",
+  "leave.cancelled",
+];
+
 export interface ApproveLeaveRequest {
   comment?: string;
 }
@@ -55,11 +83,24 @@ async function apiRequest(
   });
 }
 
+/**
+ * Writes to a leave request must name the version they were based on, so a
+ * change made in the meantime is reported instead of overwritten
+ */
+function ifMatch(version: number): HeadersInit {
+  return { "If-Match": `"${version}"` };
+}
+
 /**
  * Employee Leave Services
  */
+/**
+ * Creates a leave request. Retrying with the same idempotencyKey returns the
+ * request created by the first attempt instead of creating another.
+ */
 export async function createLeaveRequest(
-  data: CreateLeaveRequest
+  data: CreateLeaveRequest,
+  idempotencyKey?: string
 ): Promise<LeaveRequest> {
   // Convert date strings to ISO 8601 format (with time) for Go backend
   const payload = {
@@ -74,6 +115,7 @@ export async function createLeaveRequest(
 
   const response = await apiRequest(API_ROUTES.LEAVE.BASE, {
     method: "POST",
+    headers: idempotencyKey ? { "Idempotency-Key": idempotencyKey } : undefined,
     body: JSON.stringify(payload),
   });
 
@@ -124,6 +166,7 @@ export async function getLeaveRequest(id: string): Promise<LeaveRequest> {
 
 export async function updateLeaveRequest(
   id: string,
+  version: number,
   data: UpdateLeaveRequest
 ): Promise<LeaveRequest> {
   // Convert date strings to ISO 8601 format (with time) for Go backend
@@ -137,6 +180,7 @@ export async function updateLeaveRequest(
 
   const response = await apiRequest(API_ROUTES.LEAVE.DETAIL(id), {
     method: "PUT",
+    headers: ifMatch(version),
     body: JSON.stringify(payload),
   });
 
@@ -153,9 +197,10 @@ export async function updateLeaveRequest(
   return result;
 }
 
-export async function cancelLeaveRequest(id: string): Promise<void> {
+export async function cancelLeaveRequest(id: string, version: number): Promise<void> {
   const response = await apiRequest(API_ROUTES.LEAVE.DETAIL(id), {
     method: "DELETE",
+    headers: ifMatch(version),
   });
 
   if (!response.ok) {
@@ -189,10 +234,12 @@ export async function getPendingLeaveRequests(): Promise<LeaveRequest[]> {
 
 export async function approveLeaveRequest(
   id: string,
+  version: number,
   data: ApproveLeaveRequest
 ): Promise<LeaveRequest> {
   const response = await apiRequest(API_ROUTES.MANAGER.LEAVE.APPROVE(id), {
     method: "PUT",
+    headers: ifMatch(version),
     body: JSON.stringify(data),
   });
 
@@ -211,10 +258,12 @@ export async function approveLeaveRequest(
 
 export async function rejectLeaveRequest(
   id: string,
+  version: number,
   data: RejectLeaveRequest
 ): Promise<LeaveRequest> {
   const response = await apiRequest(API_ROUTES.MANAGER.LEAVE.REJECT(id), {
     method: "PUT",
+    headers: ifMatch(version),
     body: JSON.stringify(data),
   });
 
@@ -231,3 +280,35 @@ export async function rejectLeaveRequest(
   return result;
 }
 
+
+/**
+ * Leave Events
+ */
+/**
+ * Streams the changes to the leave requests the user can see through the
+ * Next.js proxy, which adds the access token EventSource cannot send.
+ * onOpen is called on every (re)connect, since changes made while
+ * disconnected are not replayed. Returns a function closing the stream.
+ */
+export function subscribeToLeaveEvents(
+  onEvent: (event: LeaveEvent) => void,
+  onOpen?: () => void
+): () => void {
+  const source = new EventSource(API_ROUTES.EVENTS.STREAM, { withCredentials: true });
+
+  if (onOpen) {
+    source.onopen = () => onOpen();
+  }
+  const listener = (message: MessageEvent<string>) => {
+    try {
+      onEvent(JSON.parse(message.data) as LeaveEvent);
+    } catch {
+      // Ignore malformed events; the next reload catches up
+    }
+  };
+  for (const type of LEAVE_EVENT_TYPES) {
+    source.addEventListener(type, listener);
+  }
+
+  return () => source.close();
+}
@@ -13,6 +13,8 @@ export interface ApiError {
   error: string;
   message?: string;
   statusCode?: number;
+  // Machine-readable error code from the backend, e.g. "LEAVE_NOT_FOUND"
+  code?: string;
 }
 
 export interface PaginatedResponse<T> {
@@ -28,18 +28,36 @@ export function successResponse<T>(
 export function errorResponse(
   error: string,
   status: number = 400,
-  message?: string
+  message?: string,
+  code?: string
 ): NextResponse<ApiError> {
   return NextResponse.json(
     {
       error,
       message,
       statusCode: status,
+      code,
     },
     { status }
   );
 }
 
+/**
+ * Relays a failed backend response. The backend reports errors as RFC 7807
+ * problem details: { title, status, detail, code, requestId, errors? }
+ */
+export async function backendErrorResponse(
+  response: Response
+): Promise<NextResponse<ApiError>> {
+  const problem = await response.json().catch(() => ({}));
+  return errorResponse(
+    problem.detail || problem.message || response.statusText || "Request failed",
+    response.status,
+    undefined,
+    problem.code
+  );
+}
+
 /**
  * Creates an unauthorized response
  */
@@ -89,3 +107,21 @@ export function withErrorHandling<T extends unknown[]>(
   };
 }
 
+
+/**
+ * Returns the If-Match header of an incoming request so it can be forwarded
+ * to the backend, which requires it on writes to a leave request
+ */
+export function ifMatchHeader(request: Request): Record<string, string> {
+  const ifMatch = request.headers.get("If-Match");
+  return ifMatch ? { "If-Match": ifMatch } : {};
+}
+
+/**
+ * Returns the Idempotency-Key header of an incoming request so retried
+ * submissions can be recognised by the backend
+ */
+export function idempotencyKeyHeader(request: Request): Record<string, string> {
+  const key = request.headers.get("Idempotency-Key");
+  return key ? { "Idempotency-Key": key } : {};
+}
@@ -24,6 +24,9 @@ export const API_ROUTES = {
       REJECT: (id: string) => `/api/manager/leave/${id}/reject`,
     },
   },
+  EVENTS: {
+    STREAM: "/api/events",
+  },
 } as const;
 
 export type ApiRoute = typeof API_ROUTES[keyof typeof API_ROUTES][keyof typeof API_ROUTES[keyof typeof API_ROUTES]];
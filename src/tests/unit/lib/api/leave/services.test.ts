@@ -8,6 +8,7 @@ import {
   getPendingLeaveRequests,
   approveLeaveRequest,
   rejectLeaveRequest,
+  subscribeToLeaveEvents,
   type LeaveRequest,
 } from "@/lib/api/leave/services";
 import { API_ROUTES } from "@/lib/routes";
@@ -33,6 +34,7 @@ describe("Leave Services", () => {
         endDate: "2024-01-05T00:00:00Z",
         days: 5,
         status: "pending",
+        version: 1,
         createdAt: "2024-01-01T00:00:00Z",
         updatedAt: "2024-01-01T00:00:00Z",
       };
@@ -42,12 +44,15 @@ describe("Leave Services", () => {
         json: async () => ({ success: true, data: mockLeave }),
       });
 
-      const result = await createLeaveRequest({
-        leaveType: "annual",
-        reason: "Vacation",
-        startDate: "2024-01-01",
-        endDate: "2024-01-05",
-      });
+      const result = await createLeaveRequest(
+        {
+          leaveType: "annual",
+          reason: "Vacation",
+          startDate: "2024-01-01",
+          endDate: "2024-01-05",
+        },
+        "key-1"
+      );
 
       expect(result).toEqual(mockLeave);
       expect(global.fetch).toHaveBeenCalledWith(
@@ -56,6 +61,7 @@ describe("Leave Services", () => {
           method: "POST",
           headers: expect.objectContaining({
             "Content-Type": "application/json",
+            "Idempotency-Key": "key-1",
           }),
         })
       );
@@ -94,7 +100,8 @@ describe("Leave Services", () => {
           endDate: "2024-01-05T00:00:00Z",
           days: 5,
           status: "pending",
-          createdAt: "2024-01-01T00:00:00Z",
+          version: 1,
+        createdAt: "2024-01-01T00:00:00Z",
           updatedAt: "2024-01-01T00:00:00Z",
         },
       ];
@@ -131,6 +138,7 @@ describe("Leave Services", () => {
         endDate: "2024-01-05T00:00:00Z",
         days: 5,
         status: "pending",
+        version: 1,
         createdAt: "2024-01-01T00:00:00Z",
         updatedAt: "2024-01-01T00:00:00Z",
       };
@@ -167,6 +175,7 @@ describe("Leave Services", () => {
         endDate: "2024-01-05T00:00:00Z",
         days: 5,
         status: "pending",
+        version: 1,
         createdAt: "2024-01-01T00:00:00Z",
         updatedAt: "2024-01-01T00:00:00Z",
       };
@@ -176,7 +185,7 @@ describe("Leave Services", () => {
         json: async () => ({ success: true, data: mockLeave }),
       });
 
-      const result = await updateLeaveRequest("123", {
+      const result = await updateLeaveRequest("123", 1, {
         leaveType: "sick",
         reason: "Updated reason",
       });
@@ -186,6 +195,7 @@ describe("Leave Services", () => {
         API_ROUTES.LEAVE.DETAIL("123"),
         expect.objectContaining({
           method: "PUT",
+          headers: expect.objectContaining({ "If-Match": '"1"' }),
         })
       );
     });
@@ -198,12 +208,13 @@ describe("Leave Services", () => {
         status: 204,
       });
 
-      await cancelLeaveRequest("123");
+      await cancelLeaveRequest("123", 1);
 
       expect(global.fetch).toHaveBeenCalledWith(
         API_ROUTES.LEAVE.DETAIL("123"),
         expect.objectContaining({
           method: "DELETE",
+          headers: expect.objectContaining({ "If-Match": '"1"' }),
         })
       );
     });
@@ -223,7 +234,8 @@ describe("Leave Services", () => {
           endDate: "2024-01-05T00:00:00Z",
           days: 5,
           status: "pending",
-          createdAt: "2024-01-01T00:00:00Z",
+          version: 1,
+        createdAt: "2024-01-01T00:00:00Z",
           updatedAt: "2024-01-01T00:00:00Z",
         },
       ];
@@ -261,6 +273,7 @@ describe("Leave Services", () => {
         days: 5,
         status: "approved",
         managerComment: "Approved",
+        version: 1,
         createdAt: "2024-01-01T00:00:00Z",
         updatedAt: "2024-01-01T00:00:00Z",
       };
@@ -270,13 +283,14 @@ describe("Leave Services", () => {
         json: async () => ({ success: true, data: mockLeave }),
       });
 
-      const result = await approveLeaveRequest("123", { comment: "Approved" });
+      const result = await approveLeaveRequest("123", 1, { comment: "Approved" });
 
       expect(result).toEqual(mockLeave);
       expect(global.fetch).toHaveBeenCalledWith(
         API_ROUTES.MANAGER.LEAVE.APPROVE("123"),
         expect.objectContaining({
           method: "PUT",
+          headers: expect.objectContaining({ "If-Match": '"1"' }),
         })
       );
     });
@@ -296,6 +310,7 @@ describe("Leave Services", () => {
         days: 5,
         status: "rejected",
         managerComment: "Not enough leave balance",
+        version: 1,
         createdAt: "2024-01-01T00:00:00Z",
         updatedAt: "2024-01-01T00:00:00Z",
       };
@@ -305,7 +320,7 @@ describe("Leave Services", () => {
         json: async () => ({ success: true, data: mockLeave }),
       });
 
-      const result = await rejectLeaveRequest("123", {
+      const result = await rejectLeaveRequest("123", 1, {
         comment: "Not enough leave balance",
       });
 
@@ -314,9 +329,61 @@ describe("Leave Services", () => {
         API_ROUTES.MANAGER.LEAVE.REJECT("123"),
         expect.objectContaining({
           method: "PUT",
+          headers: expect.objectContaining({ "If-Match": '"1"' }),
         })
       );
     });
   });
-});
 
+  describe("subscribeToLeaveEvents", () => {
+    class FakeEventSource {
+      static last: FakeEventSource;
+      onopen: (() => void) | null = null;
+      listeners: Record<string, (message: { data: string }) => void> = {};
+      closed = false;
+
+      constructor(public url: string, public init?: EventSourceInit) {
+        FakeEventSource.last = this;
+      }
+
+      addEventListener(type: string, listener: (message: { data: string }) => void) {
+        this.listeners[type] = listener;
+      }
+
+      close() {
+        this.closed = true;
+      }
+    }
+
+    beforeEach(() => {
+      (global as unknown as { EventSource: unknown }).EventSource = FakeEventSource;
+    });
+
+    it("should stream leave events through the proxy", () => {
+      const onEvent = jest.fn();
+      const onOpen = jest.fn();
+
+      const close = subscribeToLeaveEvents(onEvent, onOpen);
+      const source = FakeEventSource.last;
+
+      expect(source.url).toBe(API_ROUTES.EVENTS.STREAM);
+      expect(source.init).toEqual({ withCredentials: true });
+
+      source.onopen?.();
+      expect(onOpen).toHaveBeenCalledTimes(1);
+
+      source.listeners["leave.approved"]({
+        data: JSON.stringify({ type: "leave.approved", leaveRequestId: "123", status: "approved", version: 2 }),
+      });
+      expect(onEvent).toHaveBeenCalledWith(
+        expect.objectContaining({ type: "leave.approved", leaveRequestId: "123", version: 2 })
+      );
+
+      source.listeners["leave.created"]({ data: "not json" });
+      expect(onEvent).toHaveBeenCalledTimes(1);
+
+      close();
+      expect(source.closed).toBe(true);
+    });
+  });
+});
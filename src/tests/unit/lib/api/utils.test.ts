@@ -2,6 +2,7 @@ import { describe, it, expect } from "@jest/globals";
 import {
   successResponse,
   errorResponse,
+  backendErrorResponse,
   unauthorizedResponse,
   notFoundResponse,
   internalErrorResponse,
@@ -50,6 +51,36 @@ describe("API utils", () => {
     });
   });
 
+  describe("backendErrorResponse", () => {
+    it("should relay the detail and code of a problem response", async () => {
+      const backend = new Response(
+        JSON.stringify({
+          type: "about:blank",
+          title: "Not Found",
+          status: 404,
+          detail: "Leave request not found",
+          code: "LEAVE_NOT_FOUND",
+          requestId: "req-1",
+        }),
+        { status: 404, headers: { "Content-Type": "application/problem+json" } }
+      );
+
+      const response = await backendErrorResponse(backend);
+      expect(response.status).toBe(404);
+
+      const json = await response.json();
+      expect(json.error).toBe("Leave request not found");
+      expect(json.code).toBe("LEAVE_NOT_FOUND");
+    });
+
+    it("should fall back to the status text for a non-JSON body", async () => {
+      const backend = new Response("upstream down", { status: 502, statusText: "Bad Gateway" });
+
+      const json = await (await backendErrorResponse(backend)).json();
+      expect(json.error).toBe("Bad Gateway");
+    });
+  });
+
   describe("unauthorizedResponse", () => {
     it("should create a 401 response", async () => {
       const response = unauthorizedResponse("Please log in");
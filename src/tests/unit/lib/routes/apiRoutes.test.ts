@@ -9,6 +9,7 @@ describe("apiRoutes", () => {
       expect(API_ROUTES.AUTH.FEDERATED_LOGOUT).toBe("/api/auth/federated-logout");
       expect(API_ROUTES.AUTH.TOKEN_DETAILS).toBe("/api/auth/token-details");
       expect(API_ROUTES.AUTH.KEYCLOAK_CONFIG).toBe("/api/auth/keycloak-config");
+      expect(API_ROUTES.EVENTS.STREAM).toBe("/api/events");
     });
   });
 